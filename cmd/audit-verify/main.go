@@ -0,0 +1,87 @@
+// audit-verify walks the audit hash chains and reports for each the first
+// event that no longer matches its hash, its predecessor or a signed
+// checkpoint. It exits with status 1 when a chain is broken.
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"log"
+	"my-go-api/internal/config"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	"my-go-api/pkg/database"
+	"os"
+)
+
+func main() {
+	publicKeyFlag := flag.String("public-key", "", "base64 Ed25519 public key to check checkpoints with, instead of the configured signing key")
+	chain := flag.String("chain", "", "verify only this chain instead of every chain")
+	checkpoint := flag.Bool("checkpoint", false, "sign the head of every chain that verifies")
+	flag.Parse()
+
+	cfg, err := config.LoadEnv()
+	if err != nil {
+		log.Fatalf("Could not load config: %v", err)
+	}
+	var publicKey ed25519.PublicKey
+	if *publicKeyFlag != "" {
+		key, err := base64.StdEncoding.DecodeString(*publicKeyFlag)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			log.Fatalf("-public-key must be a base64 encoded %d byte Ed25519 key", ed25519.PublicKeySize)
+		}
+		publicKey = key
+	}
+
+	db, err := database.Connect(cfg.DB.DbUrl, cfg.DB.MaxIdleTime, cfg.DB.MaxOpenConns, cfg.DB.MaxIdleConns)
+	if err != nil {
+		log.Fatalf("Could not connect to the database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	auditService := services.NewAuditService(cfg.Audit, repositories.NewAuditEventRepository(db))
+	chains := []string{*chain}
+	if *chain == "" {
+		if chains, err = auditService.Chains(ctx); err != nil {
+			log.Fatalf("Could not list the audit chains: %v", err)
+		}
+	}
+	reports := []*services.AuditChainReport{}
+	broken := false
+	for _, name := range chains {
+		report, err := auditService.VerifyChain(ctx, name, publicKey)
+		if err != nil {
+			log.Fatalf("Could not verify the audit chain %s: %v", name, err)
+		}
+		if report.Break != nil {
+			log.Printf("audit chain %s broken at event %d: %s", name, report.Break.EventId, report.Break.Reason)
+			broken = true
+		}
+		reports = append(reports, report)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(reports)
+
+	if broken {
+		os.Exit(1)
+	}
+	if publicKey == nil && cfg.Audit.SigningKey == nil {
+		log.Println("no public key given, checkpoint signatures were not checked")
+	}
+	if *checkpoint {
+		for _, name := range chains {
+			signed, err := auditService.Checkpoint(ctx, name)
+			if err != nil {
+				log.Fatalf("Could not write a checkpoint of chain %s: %v", name, err)
+			}
+			if signed != nil {
+				log.Printf("signed checkpoint %d of chain %s at event %d", signed.ID, name, signed.LastEventId)
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// signing-keys manages the keyring access tokens are signed with:
+//
+//	signing-keys list
+//	signing-keys [-algorithm ES256] introduce
+//	signing-keys [-force] promote <kid>
+//	signing-keys [-force] retire <kid>
+//	signing-keys rotate
+//
+// A rotation by hand introduces a key, promotes it once verifiers fetched the
+// new JWKS and retires the previous key once the tokens it signed expired.
+// Running instances pick the changes up on their next keyring refresh.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"my-go-api/internal/config"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	"my-go-api/pkg/database"
+	"os"
+)
+
+func main() {
+	algorithm := flag.String("algorithm", "", "algorithm of an introduced key, the configured one by default")
+	force := flag.Bool("force", false, "promote before the publish delay or retire before the tokens drained")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: signing-keys [flags] list|introduce|promote <kid>|retire <kid>|rotate")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	command, kid := flag.Arg(0), flag.Arg(1)
+	if command == "" || (command == "promote" || command == "retire") && kid == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadEnv()
+	if err != nil {
+		log.Fatalf("Could not load config: %v", err)
+	}
+	db, err := database.Connect(cfg.DB.DbUrl, cfg.DB.MaxIdleTime, cfg.DB.MaxOpenConns, cfg.DB.MaxIdleConns)
+	if err != nil {
+		log.Fatalf("Could not connect to the database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	signingKeyService, err := services.NewSigningKeyService(ctx, cfg.Jwt, repositories.NewSigningKeyRepository(db))
+	if err != nil {
+		log.Fatalf("Could not load the signing keys: %v", err)
+	}
+
+	var result any
+	switch command {
+	case "list":
+		result, err = signingKeyService.List(ctx)
+	case "introduce":
+		result, err = signingKeyService.Introduce(ctx, *algorithm)
+	case "promote":
+		result, err = signingKeyService.Promote(ctx, kid, *force)
+	case "retire":
+		result, err = signingKeyService.Retire(ctx, kid, *force)
+	case "rotate":
+		if cfg.Jwt.RotationInterval <= 0 {
+			log.Fatal("JWT_ROTATION_INTERVAL is not set")
+		}
+		if err = signingKeyService.Rotate(ctx); err == nil {
+			result, err = signingKeyService.List(ctx)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("%s failed: %v", command, err)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(result)
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"os"
+)
+
+// AuditConfig controls the signed checkpoints of the audit hash chain. Without
+// a signing key the chain is still kept but no checkpoints are written.
+type AuditConfig struct {
+	SigningKey         ed25519.PrivateKey
+	CheckpointInterval int
+}
+
+// loadAudit reads AUDIT_SIGNING_KEY, a base64 encoded 32 byte Ed25519 seed,
+// and AUDIT_CHECKPOINT_INTERVAL, the number of events between checkpoints.
+func loadAudit() (AuditConfig, error) {
+	var cfg AuditConfig
+	var err error
+	if cfg.CheckpointInterval, err = parseInt(os.Getenv("AUDIT_CHECKPOINT_INTERVAL"), 1000); err != nil {
+		return cfg, err
+	}
+	if cfg.CheckpointInterval <= 0 {
+		return cfg, fmt.Errorf("AUDIT_CHECKPOINT_INTERVAL must be positive")
+	}
+	if value := os.Getenv("AUDIT_SIGNING_KEY"); value != "" {
+		seed, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return cfg, fmt.Errorf("AUDIT_SIGNING_KEY must be a base64 encoded %d byte seed", ed25519.SeedSize)
+		}
+		cfg.SigningKey = ed25519.NewKeyFromSeed(seed)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// AuthzSchema maps each namespace of the relationship store to the relations
+// its objects can have.
+type AuthzSchema map[string]AuthzNamespace
+
+type AuthzNamespace struct {
+	Relations map[string]AuthzRelation `json:"relations"`
+}
+
+// AuthzRelation describes where the subjects of a relation come from. Without
+// a union only tuples written for the relation itself count.
+type AuthzRelation struct {
+	Union []AuthzUserset `json:"union,omitempty"`
+}
+
+// AuthzUserset is one source of subjects. This takes the tuples written for
+// the relation; ComputedUserset takes the subjects of another relation of the
+// same object; TupleToUserset follows the objects found under one relation
+// and takes the subjects of a relation on each of them.
+type AuthzUserset struct {
+	This            bool                 `json:"this,omitempty"`
+	ComputedUserset string               `json:"computed_userset,omitempty"`
+	TupleToUserset  *AuthzTupleToUserset `json:"tuple_to_userset,omitempty"`
+}
+
+type AuthzTupleToUserset struct {
+	Tupleset        string `json:"tupleset"`
+	ComputedUserset string `json:"computed_userset"`
+}
+
+// defaultAuthzSchema only knows groups, which other services refer to through
+// usersets such as group:eng#member.
+var defaultAuthzSchema = AuthzSchema{
+	"group": {
+		Relations: map[string]AuthzRelation{
+			"owner": {},
+			"member": {Union: []AuthzUserset{
+				{This: true},
+				{ComputedUserset: "owner"},
+			}},
+		},
+	},
+}
+
+// loadAuthzSchema reads the namespace schema from the JSON file named by
+// AUTHZ_SCHEMA_PATH, falling back to the default schema when it is unset.
+func loadAuthzSchema() (AuthzSchema, error) {
+	path := os.Getenv("AUTHZ_SCHEMA_PATH")
+	if path == "" {
+		return defaultAuthzSchema, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	schema := AuthzSchema{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid authz schema %s: %w", path, err)
+	}
+	return schema, nil
+}
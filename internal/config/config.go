@@ -1,19 +1,33 @@
 package config
 
 import (
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	DB           DbConfig
-	RDB          RedisConfig
-	Port         string
-	JWtSecretKey string
-	GoogleOAuth2 GoogleOAuth2Config
-	AppUri       string
+	DB                      DbConfig
+	RDB                     RedisConfig
+	Port                    string
+	JWtSecretKey            string
+	Jwt                     JwtConfig
+	GoogleOAuth2            GoogleOAuth2Config
+	AppUri                  string
+	RefreshTokenGracePeriod time.Duration
+	OAuthProviders          []OAuthProviderConfig
+	Mfa                     MfaConfig
+	Webauthn                WebauthnConfig
+	AuthzSchema             AuthzSchema
+	LoginProtection         LoginProtectionConfig
+	RateLimits              RateLimitConfig
+	Audit                   AuditConfig
+	Email                   EmailConfig
+	Oidc                    OidcConfig
 }
 
 type RedisConfig struct {
@@ -29,6 +43,32 @@ type DbConfig struct {
 	MaxIdleTime  string
 }
 
+type MfaConfig struct {
+	Issuer        string
+	EncryptionKey string
+}
+
+// WebauthnConfig identifies this server as a WebAuthn relying party. RPID
+// must be the domain of the origins passkeys are used from.
+type WebauthnConfig struct {
+	RPID          string
+	RPDisplayName string
+	RPOrigins     []string
+}
+
+// LoginProtectionConfig throttles password sign in. Every failed attempt on an
+// account doubles the wait before the next one, starting at BackoffBase and
+// capped at BackoffMax, and MaxAttempts failures in a row lock the account for
+// LockoutDuration. IpMaxAttempts failures from one address block it for
+// LockoutDuration, whatever accounts were tried.
+type LoginProtectionConfig struct {
+	MaxAttempts     int
+	IpMaxAttempts   int
+	LockoutDuration time.Duration
+	BackoffBase     time.Duration
+	BackoffMax      time.Duration
+}
+
 type GoogleOAuth2Config struct {
 	ProjectId    string
 	ClientId     string
@@ -57,6 +97,46 @@ func LoadEnv() (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	vRefreshTokenGracePeriod, err := parseDuration(os.Getenv("REFRESH_TOKEN_GRACE_PERIOD"), 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	vOAuthProviders, err := loadOAuthProviders(os.Getenv("APP_URI"))
+	if err != nil {
+		return nil, err
+	}
+	vWebauthn, err := loadWebauthn(os.Getenv("APP_URI"))
+	if err != nil {
+		return nil, err
+	}
+	vAuthzSchema, err := loadAuthzSchema()
+	if err != nil {
+		return nil, err
+	}
+	vLoginProtection, err := loadLoginProtection()
+	if err != nil {
+		return nil, err
+	}
+	vRateLimits, err := loadRateLimits()
+	if err != nil {
+		return nil, err
+	}
+	vAudit, err := loadAudit()
+	if err != nil {
+		return nil, err
+	}
+	vEmail, err := loadEmail()
+	if err != nil {
+		return nil, err
+	}
+	vJwt, err := loadJwt()
+	if err != nil {
+		return nil, err
+	}
+	vOidc, err := loadOidc(os.Getenv("APP_URI"), vJwt)
+	if err != nil {
+		return nil, err
+	}
 	cfg := &Config{
 		DB: DbConfig{
 			DbUrl:        os.Getenv("DB_URL"),
@@ -69,15 +149,101 @@ func LoadEnv() (*Config, error) {
 			Password: os.Getenv("REDIS_PWD"),
 			DB:       vRedisDb,
 		},
-		AppUri:       os.Getenv("APP_URI"),
-		Port:         os.Getenv("PORT"),
-		JWtSecretKey: os.Getenv("SECRET_KEY"),
+		AppUri:                  os.Getenv("APP_URI"),
+		Port:                    os.Getenv("PORT"),
+		JWtSecretKey:            os.Getenv("SECRET_KEY"),
+		Jwt:                     vJwt,
+		RefreshTokenGracePeriod: vRefreshTokenGracePeriod,
 		GoogleOAuth2: GoogleOAuth2Config{
 			ProjectId:    os.Getenv("GOOGLE_PROJECT_ID"),
 			ClientId:     os.Getenv("GOOGLE_CLIENT_ID"),
 			ClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
 			RefreshToken: os.Getenv("GOOGLE_REFRESH_TOKEN"),
 		},
+		OAuthProviders: vOAuthProviders,
+		Mfa: MfaConfig{
+			Issuer:        getEnv("MFA_ISSUER", "Go Auth API"),
+			EncryptionKey: getEnv("MFA_ENCRYPTION_KEY", os.Getenv("SECRET_KEY")),
+		},
+		Webauthn:        vWebauthn,
+		AuthzSchema:     vAuthzSchema,
+		LoginProtection: vLoginProtection,
+		RateLimits:      vRateLimits,
+		Audit:           vAudit,
+		Email:           vEmail,
+		Oidc:            vOidc,
+	}
+	return cfg, nil
+}
+
+// parseDuration parses an optional duration variable, falling back to def when
+// the variable is unset.
+func parseDuration(value string, def time.Duration) (time.Duration, error) {
+	if value == "" {
+		return def, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// parseInt parses an optional integer variable, falling back to def when the
+// variable is unset.
+func parseInt(value string, def int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// getEnv returns the value of an optional variable, falling back to def when
+// the variable is unset.
+func getEnv(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// loadWebauthn reads the relying party settings. The id and origin default to
+// the host and origin of appUri.
+func loadWebauthn(appUri string) (WebauthnConfig, error) {
+	origins := getEnv("WEBAUTHN_RP_ORIGINS", appUri)
+	rpId := os.Getenv("WEBAUTHN_RP_ID")
+	if rpId == "" {
+		parsed, err := url.Parse(appUri)
+		if err != nil {
+			return WebauthnConfig{}, err
+		}
+		rpId = parsed.Hostname()
+	}
+	cfg := WebauthnConfig{
+		RPID:          rpId,
+		RPDisplayName: getEnv("WEBAUTHN_RP_NAME", getEnv("MFA_ISSUER", "Go Auth API")),
+	}
+	for _, origin := range strings.Split(origins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			cfg.RPOrigins = append(cfg.RPOrigins, origin)
+		}
+	}
+	return cfg, nil
+}
+
+func loadLoginProtection() (LoginProtectionConfig, error) {
+	var cfg LoginProtectionConfig
+	var err error
+	if cfg.MaxAttempts, err = parseInt(os.Getenv("LOGIN_MAX_ATTEMPTS"), 5); err != nil {
+		return cfg, err
+	}
+	if cfg.IpMaxAttempts, err = parseInt(os.Getenv("LOGIN_IP_MAX_ATTEMPTS"), 50); err != nil {
+		return cfg, err
+	}
+	if cfg.LockoutDuration, err = parseDuration(os.Getenv("LOGIN_LOCKOUT_DURATION"), 15*time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.BackoffBase, err = parseDuration(os.Getenv("LOGIN_BACKOFF_BASE"), time.Second); err != nil {
+		return cfg, err
+	}
+	if cfg.BackoffMax, err = parseDuration(os.Getenv("LOGIN_BACKOFF_MAX"), 30*time.Second); err != nil {
+		return cfg, err
 	}
 	return cfg, nil
 }
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EmailTransportGmail  = "gmail"
+	EmailTransportSMTP   = "smtp"
+	EmailTransportFile   = "file"
+	EmailTransportMemory = "memory"
+
+	SmtpTLSStartTLS = "starttls"
+	SmtpTLSImplicit = "tls"
+	SmtpTLSNone     = "none"
+)
+
+// EmailConfig selects how emails are delivered. Gmail sends through the Gmail
+// API with the GOOGLE_* credentials, SMTP through any mail server, file writes
+// every message to a maildir for development and memory keeps them in the
+// process for tests.
+type EmailConfig struct {
+	Transport string
+	From      string
+	Smtp      SmtpConfig
+	FileDir   string
+	// TemplateDir holds templates overriding or adding to the embedded ones.
+	TemplateDir string
+	// Preview serves rendered templates under /dev/emails.
+	Preview bool
+	Outbox  EmailOutboxConfig
+}
+
+// EmailOutboxConfig tunes the workers delivering queued emails. A failed email
+// is tried again after BackoffBase, doubled for every further attempt up to
+// BackoffMax, until MaxAttempts leaves it dead. Lease is how long a worker
+// owns a claimed email before another one may pick it up.
+type EmailOutboxConfig struct {
+	Workers      int
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+	Lease        time.Duration
+}
+
+// SmtpConfig describes the mail server. TLS is starttls, which requires the
+// server to offer STARTTLS, tls for implicit TLS (usually port 465) or none
+// for local relays. Without a username the server is used unauthenticated.
+type SmtpConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	TLS      string
+}
+
+// loadEmail reads EMAIL_TRANSPORT, which defaults to gmail, EMAIL_FROM and the
+// SMTP_* or EMAIL_FILE_DIR settings of the chosen transport. Template previews
+// are on by default in development.
+func loadEmail() (EmailConfig, error) {
+	cfg := EmailConfig{
+		Transport: strings.ToLower(getEnv("EMAIL_TRANSPORT", EmailTransportGmail)),
+		From:      os.Getenv("EMAIL_FROM"),
+		Smtp: SmtpConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     getEnv("SMTP_PORT", "587"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+		},
+		FileDir:     getEnv("EMAIL_FILE_DIR", "./tmp/mail"),
+		TemplateDir: os.Getenv("EMAIL_TEMPLATE_DIR"),
+		Preview:     getEnv("EMAIL_PREVIEW", strconv.FormatBool(os.Getenv("GO_ENV") == "development")) == "true",
+	}
+	defaultTLS := SmtpTLSStartTLS
+	if cfg.Smtp.Port == "465" {
+		defaultTLS = SmtpTLSImplicit
+	}
+	cfg.Smtp.TLS = strings.ToLower(getEnv("SMTP_TLS", defaultTLS))
+
+	outbox, err := loadEmailOutbox()
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Outbox = outbox
+
+	switch cfg.Transport {
+	case EmailTransportGmail, EmailTransportFile, EmailTransportMemory:
+	case EmailTransportSMTP:
+		if cfg.Smtp.Host == "" {
+			return cfg, fmt.Errorf("SMTP_HOST is required for the smtp email transport")
+		}
+		if cfg.From == "" {
+			return cfg, fmt.Errorf("EMAIL_FROM is required for the smtp email transport")
+		}
+		switch cfg.Smtp.TLS {
+		case SmtpTLSStartTLS, SmtpTLSImplicit, SmtpTLSNone:
+		default:
+			return cfg, fmt.Errorf("SMTP_TLS must be starttls, tls or none, got %q", cfg.Smtp.TLS)
+		}
+	default:
+		return cfg, fmt.Errorf("unknown EMAIL_TRANSPORT %q", cfg.Transport)
+	}
+	return cfg, nil
+}
+
+func loadEmailOutbox() (EmailOutboxConfig, error) {
+	var cfg EmailOutboxConfig
+	var err error
+	if cfg.Workers, err = parseInt(os.Getenv("EMAIL_OUTBOX_WORKERS"), 2); err != nil {
+		return cfg, err
+	}
+	if cfg.PollInterval, err = parseDuration(os.Getenv("EMAIL_OUTBOX_POLL_INTERVAL"), time.Second); err != nil {
+		return cfg, err
+	}
+	if cfg.BatchSize, err = parseInt(os.Getenv("EMAIL_OUTBOX_BATCH_SIZE"), 10); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxAttempts, err = parseInt(os.Getenv("EMAIL_OUTBOX_MAX_ATTEMPTS"), 8); err != nil {
+		return cfg, err
+	}
+	if cfg.BackoffBase, err = parseDuration(os.Getenv("EMAIL_OUTBOX_BACKOFF_BASE"), 30*time.Second); err != nil {
+		return cfg, err
+	}
+	if cfg.BackoffMax, err = parseDuration(os.Getenv("EMAIL_OUTBOX_BACKOFF_MAX"), time.Hour); err != nil {
+		return cfg, err
+	}
+	if cfg.Lease, err = parseDuration(os.Getenv("EMAIL_OUTBOX_LEASE"), 2*time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.BatchSize < 1 || cfg.MaxAttempts < 1 || cfg.PollInterval <= 0 {
+		return cfg, fmt.Errorf("EMAIL_OUTBOX_BATCH_SIZE, EMAIL_OUTBOX_MAX_ATTEMPTS and EMAIL_OUTBOX_POLL_INTERVAL must be positive")
+	}
+	return cfg, nil
+}
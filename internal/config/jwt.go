@@ -0,0 +1,152 @@
+package config
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	JwtAlgorithmHS256 = "HS256"
+	JwtAlgorithmRS256 = "RS256"
+	JwtAlgorithmES256 = "ES256"
+	JwtAlgorithmEdDSA = "EdDSA"
+)
+
+// JwtConfig selects how access tokens are signed. HS256 signs with SecretKey,
+// so only holders of the secret can verify tokens. The asymmetric algorithms
+// sign with SigningKey and publish its public half under
+// /.well-known/jwks.json. KeyId is sent as the kid header; it defaults to the
+// RFC 7638 thumbprint of the key.
+//
+// The configured key only seeds the keyring in the signing_keys table, which
+// is kept in sync every KeyringRefresh. With a RotationInterval a new key of
+// Algorithm is introduced PublishDelay before the active key is that old,
+// which gives verifiers the time to fetch it, and promoted when it is. The key
+// it replaces is retired after DrainPeriod, when the tokens it signed expired.
+type JwtConfig struct {
+	Algorithm  string
+	SecretKey  string
+	SigningKey crypto.Signer
+	KeyId      string
+	// KeyEncryptionKey seals the private keys stored in the keyring. It is
+	// required and must differ from SecretKey, so a leaked HS256 secret does
+	// not also open the keyring.
+	KeyEncryptionKey string
+	KeyringRefresh   time.Duration
+	RotationInterval time.Duration
+	PublishDelay     time.Duration
+	DrainPeriod      time.Duration
+}
+
+// loadJwt reads JWT_ALGORITHM, which defaults to HS256, for the asymmetric
+// algorithms the PEM encoded private key in JWT_PRIVATE_KEY_FILE, and the
+// keyring settings. JWT_KEY_ENCRYPTION_KEY is required. Rotation is off unless
+// JWT_ROTATION_INTERVAL is set.
+func loadJwt() (JwtConfig, error) {
+	cfg := JwtConfig{
+		Algorithm:        getEnv("JWT_ALGORITHM", JwtAlgorithmHS256),
+		SecretKey:        os.Getenv("SECRET_KEY"),
+		KeyId:            os.Getenv("JWT_KEY_ID"),
+		KeyEncryptionKey: os.Getenv("JWT_KEY_ENCRYPTION_KEY"),
+	}
+	if cfg.KeyEncryptionKey == "" {
+		return cfg, fmt.Errorf("JWT_KEY_ENCRYPTION_KEY is required to seal the signing keyring")
+	}
+	if cfg.KeyEncryptionKey == cfg.SecretKey {
+		return cfg, fmt.Errorf("JWT_KEY_ENCRYPTION_KEY must differ from SECRET_KEY")
+	}
+	var err error
+	if cfg.KeyringRefresh, err = parseDuration(os.Getenv("JWT_KEYRING_REFRESH"), time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.RotationInterval, err = parseDuration(os.Getenv("JWT_ROTATION_INTERVAL"), 0); err != nil {
+		return cfg, err
+	}
+	if cfg.PublishDelay, err = parseDuration(os.Getenv("JWT_KEY_PUBLISH_DELAY"), 10*time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.DrainPeriod, err = parseDuration(os.Getenv("JWT_KEY_DRAIN_PERIOD"), 70*time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.KeyringRefresh <= 0 {
+		return cfg, fmt.Errorf("JWT_KEYRING_REFRESH must be positive")
+	}
+	if cfg.RotationInterval < 0 || cfg.RotationInterval > 0 && cfg.RotationInterval <= cfg.PublishDelay {
+		return cfg, fmt.Errorf("JWT_ROTATION_INTERVAL must be longer than JWT_KEY_PUBLISH_DELAY")
+	}
+	if strings.EqualFold(cfg.Algorithm, JwtAlgorithmEdDSA) {
+		cfg.Algorithm = JwtAlgorithmEdDSA
+	} else {
+		cfg.Algorithm = strings.ToUpper(cfg.Algorithm)
+	}
+	switch cfg.Algorithm {
+	case JwtAlgorithmHS256:
+		return cfg, nil
+	case JwtAlgorithmRS256, JwtAlgorithmES256, JwtAlgorithmEdDSA:
+	default:
+		return cfg, fmt.Errorf("JWT_ALGORITHM must be HS256, RS256, ES256 or EdDSA, got %q", cfg.Algorithm)
+	}
+
+	path := os.Getenv("JWT_PRIVATE_KEY_FILE")
+	if path == "" {
+		return cfg, fmt.Errorf("JWT_PRIVATE_KEY_FILE is required for %s", cfg.Algorithm)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if cfg.SigningKey, err = ParseJwtSigningKey(cfg.Algorithm, data); err != nil {
+		return cfg, fmt.Errorf("JWT_PRIVATE_KEY_FILE: %w", err)
+	}
+	return cfg, nil
+}
+
+// ParseJwtSigningKey parses a PKCS #8, PKCS #1 or SEC 1 PEM private key and
+// checks that it fits algorithm: RS256 takes RSA keys of at least 2048 bits,
+// ES256 P-256 keys and EdDSA Ed25519 keys.
+func ParseJwtSigningKey(algorithm string, data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	var key any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		if algorithm == JwtAlgorithmRS256 && key.N.BitLen() >= 2048 {
+			return key, nil
+		}
+	case *ecdsa.PrivateKey:
+		if algorithm == JwtAlgorithmES256 && key.Curve == elliptic.P256() {
+			return key, nil
+		}
+	case ed25519.PrivateKey:
+		if algorithm == JwtAlgorithmEdDSA {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("a %T does not fit %s", key, algorithm)
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	OAuthProviderTypeOIDC   = "oidc"
+	OAuthProviderTypeGithub = "github"
+)
+
+// OAuthProviderConfig describes one social login provider. OIDC providers only
+// need an issuer, the endpoints are discovered; plain OAuth2 providers such as
+// GitHub use the explicit endpoint URLs.
+type OAuthProviderConfig struct {
+	Name         string
+	Type         string
+	Issuer       string
+	AuthUrl      string
+	TokenUrl     string
+	UserInfoUrl  string
+	ClientId     string
+	ClientSecret string
+	RedirectUri  string
+	Scopes       []string
+	// TrustEmail treats the email returned by the provider as verified for
+	// providers that do not send an email_verified claim. It is refused for
+	// multi-tenant issuers, where any tenant can assert any email.
+	TrustEmail bool
+}
+
+// oauthProviderDefaults holds the well-known settings for the providers we
+// ship with. Any of them can be overridden through OAUTH_<NAME>_* variables.
+var oauthProviderDefaults = map[string]OAuthProviderConfig{
+	"google": {
+		Type:   OAuthProviderTypeOIDC,
+		Issuer: "https://accounts.google.com",
+		Scopes: []string{"openid", "email", "profile"},
+	},
+	"microsoft": {
+		Type:   OAuthProviderTypeOIDC,
+		Issuer: "https://login.microsoftonline.com/common/v2.0",
+		Scopes: []string{"openid", "email", "profile"},
+	},
+	"github": {
+		Type:        OAuthProviderTypeGithub,
+		AuthUrl:     "https://github.com/login/oauth/authorize",
+		TokenUrl:    "https://github.com/login/oauth/access_token",
+		UserInfoUrl: "https://api.github.com",
+		Scopes:      []string{"read:user", "user:email"},
+	},
+}
+
+// loadOAuthProviders reads the providers listed in OAUTH_PROVIDERS, e.g.
+// "google,github,acme". Unknown names are treated as generic OIDC issuers.
+// Google is enabled by default when the Google client credentials are set.
+func loadOAuthProviders(appUri string) ([]OAuthProviderConfig, error) {
+	names := os.Getenv("OAUTH_PROVIDERS")
+	if names == "" && os.Getenv("GOOGLE_CLIENT_ID") != "" {
+		names = "google"
+	}
+	providers := []OAuthProviderConfig{}
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OAUTH_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		def, ok := oauthProviderDefaults[name]
+		if !ok {
+			def = OAuthProviderConfig{Type: OAuthProviderTypeOIDC, Scopes: []string{"openid", "email", "profile"}}
+		}
+		if name == "google" {
+			def.ClientId = os.Getenv("GOOGLE_CLIENT_ID")
+			def.ClientSecret = os.Getenv("GOOGLE_CLIENT_SECRET")
+		}
+		provider := OAuthProviderConfig{
+			Name:         name,
+			Type:         getEnv(prefix+"TYPE", def.Type),
+			Issuer:       getEnv(prefix+"ISSUER", def.Issuer),
+			AuthUrl:      getEnv(prefix+"AUTH_URL", def.AuthUrl),
+			TokenUrl:     getEnv(prefix+"TOKEN_URL", def.TokenUrl),
+			UserInfoUrl:  getEnv(prefix+"USERINFO_URL", def.UserInfoUrl),
+			ClientId:     getEnv(prefix+"CLIENT_ID", def.ClientId),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", def.ClientSecret),
+			RedirectUri:  getEnv(prefix+"REDIRECT_URI", appUri+"/api/v1/auth/oauth/"+name+"/callback"),
+			Scopes:       def.Scopes,
+			TrustEmail:   getEnv(prefix+"TRUST_EMAIL", fmt.Sprint(def.TrustEmail)) == "true",
+		}
+		if scopes := os.Getenv(prefix + "SCOPES"); scopes != "" {
+			provider.Scopes = strings.Fields(strings.ReplaceAll(scopes, ",", " "))
+		}
+		switch provider.Type {
+		case OAuthProviderTypeOIDC:
+			if provider.Issuer == "" {
+				return nil, fmt.Errorf("%sISSUER is required for oidc provider %q", prefix, name)
+			}
+		case OAuthProviderTypeGithub:
+		default:
+			return nil, fmt.Errorf("unsupported oauth provider type %q for %q", provider.Type, name)
+		}
+		if provider.ClientId == "" {
+			return nil, fmt.Errorf("%sCLIENT_ID is required for oauth provider %q", prefix, name)
+		}
+		if provider.TrustEmail && isMultiTenantIssuer(provider.Issuer) {
+			return nil, fmt.Errorf("%sTRUST_EMAIL cannot be used with the multi-tenant issuer of %q", prefix, name)
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
+// isMultiTenantIssuer reports whether issuer is one of Microsoft's endpoints
+// that accept accounts from any tenant.
+func isMultiTenantIssuer(issuer string) bool {
+	for _, tenant := range []string{"common", "organizations"} {
+		if strings.Contains(issuer, "login.microsoftonline.com/"+tenant+"/") ||
+			strings.HasSuffix(strings.TrimSuffix(issuer, "/"), "login.microsoftonline.com/"+tenant) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// OidcConfig turns the API into an OpenID Connect provider when Issuer is
+// set. Issuer is the public base URL of the API, the discovery document is
+// served under it and ID tokens name it in their iss claim. Users approve
+// authorization requests on ConsentUri, a page of the front end that gets the
+// request id in its request query parameter.
+//
+// A request waits AuthorizationTTL for the user's decision, the authorization
+// code it yields is valid for CodeTTL and ID tokens for IdTokenTTL.
+type OidcConfig struct {
+	Issuer           string
+	ConsentUri       string
+	AuthorizationTTL time.Duration
+	CodeTTL          time.Duration
+	IdTokenTTL       time.Duration
+}
+
+// Enabled reports whether the provider routes are served.
+func (c OidcConfig) Enabled() bool {
+	return c.Issuer != ""
+}
+
+// loadOidc reads OIDC_ISSUER and the OIDC_* settings. Relying parties verify
+// ID tokens against the published keys, so the provider needs an asymmetric
+// JWT_ALGORITHM.
+func loadOidc(appUri string, jwt JwtConfig) (OidcConfig, error) {
+	cfg := OidcConfig{
+		Issuer:     strings.TrimSuffix(os.Getenv("OIDC_ISSUER"), "/"),
+		ConsentUri: getEnv("OIDC_CONSENT_URI", strings.TrimSuffix(appUri, "/")+"/oauth/consent"),
+	}
+	var err error
+	if cfg.AuthorizationTTL, err = parseDuration(os.Getenv("OIDC_AUTHORIZATION_TTL"), 10*time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.CodeTTL, err = parseDuration(os.Getenv("OIDC_CODE_TTL"), time.Minute); err != nil {
+		return cfg, err
+	}
+	if cfg.IdTokenTTL, err = parseDuration(os.Getenv("OIDC_ID_TOKEN_TTL"), time.Hour); err != nil {
+		return cfg, err
+	}
+	if !cfg.Enabled() {
+		return cfg, nil
+	}
+	if issuer, err := url.Parse(cfg.Issuer); err != nil || issuer.Scheme == "" || issuer.Host == "" || issuer.RawQuery != "" || issuer.Fragment != "" {
+		return cfg, fmt.Errorf("OIDC_ISSUER must be an absolute URL without query or fragment")
+	}
+	if cfg.AuthorizationTTL <= 0 || cfg.CodeTTL <= 0 || cfg.IdTokenTTL <= 0 {
+		return cfg, fmt.Errorf("OIDC_AUTHORIZATION_TTL, OIDC_CODE_TTL and OIDC_ID_TOKEN_TTL must be positive")
+	}
+	if jwt.Algorithm == JwtAlgorithmHS256 {
+		return cfg, fmt.Errorf("OIDC_ISSUER needs JWT_ALGORITHM RS256, ES256 or EdDSA")
+	}
+	return cfg, nil
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit allows Limit requests per Window for every key, such as a client
+// IP. A zero limit turns the limiter off.
+type RateLimit struct {
+	Limit  int
+	Window time.Duration
+}
+
+// RateLimitConfig maps the limiter names used by the routes to their limits.
+type RateLimitConfig map[string]RateLimit
+
+// defaultRateLimits keep the endpoints sending emails well below the mail
+// quota and slow down guessing of codes and tokens.
+var defaultRateLimits = RateLimitConfig{
+	// every /auth endpoint, per IP
+	"auth": {Limit: 120, Window: time.Minute},
+	// account creation, per IP
+	"register": {Limit: 10, Window: time.Hour},
+	// endpoints sending an email, per address and per IP
+	"email":    {Limit: 5, Window: time.Hour},
+	"email_ip": {Limit: 20, Window: time.Hour},
+	// endpoints redeeming a code or token, per IP
+	"verify": {Limit: 20, Window: 15 * time.Minute},
+	// signed in API routes, per user
+	"api": {Limit: 300, Window: time.Minute},
+}
+
+// loadRateLimits starts from the default limits, each of which can be
+// overridden with RATE_LIMIT_<NAME>="<limit>/<window>", e.g. "5/1h".
+func loadRateLimits() (RateLimitConfig, error) {
+	limits := RateLimitConfig{}
+	for name, def := range defaultRateLimits {
+		limit := def
+		if value := os.Getenv("RATE_LIMIT_" + strings.ToUpper(name)); value != "" {
+			parsed, err := parseRateLimit(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid RATE_LIMIT_%s: %w", strings.ToUpper(name), err)
+			}
+			limit = parsed
+		}
+		limits[name] = limit
+	}
+	return limits, nil
+}
+
+func parseRateLimit(value string) (RateLimit, error) {
+	count, window, ok := strings.Cut(value, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("expected <limit>/<window>, got %q", value)
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil {
+		return RateLimit{}, err
+	}
+	duration, err := time.ParseDuration(strings.TrimSpace(window))
+	if err != nil {
+		return RateLimit{}, err
+	}
+	if limit > 0 && duration <= 0 {
+		return RateLimit{}, fmt.Errorf("window must be positive, got %q", window)
+	}
+	return RateLimit{Limit: limit, Window: duration}, nil
+}
@@ -4,6 +4,9 @@ const (
 	COOKIE_REFRESH_TOKEN = "mygoapi-refresh-token"
 	COOKIE_DEVICE_ID     = "mygoapi-device-id"
 	COOKIE_USER_ID       = "mygoapi-user-id"
+	COOKIE_OAUTH_BINDING = "mygoapi-oauth-binding"
 	ACCESS_TOKEN_PAYLOAD = "accessTokenPayload"
+	CURRENT_USER         = "currentUser"
 	VALIDATED_BODY       = "validatedBody"
+	REQUEST_ID           = "requestId"
 )
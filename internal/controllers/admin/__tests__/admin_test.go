@@ -0,0 +1,457 @@
+package admin_test
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/controllers/admin"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	mockutils "my-go-api/mocks"
+	mockservices "my-go-api/mocks/mock_services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type adminMocks struct {
+	userService        *mockservices.MockIUserService
+	passwordService    *mockservices.MockIPasswordService
+	roleService        *mockservices.MockIRoleService
+	authService        *mockservices.MockIAuthService
+	sessionService     *mockservices.MockISessionService
+	adminActionService *mockservices.MockIAdminActionService
+	auditService       *mockservices.MockIAuditService
+	emailOutbox        *mockservices.MockIEmailOutboxService
+	signingKeys        *mockservices.MockISigningKeyService
+	oauthServer        *mockservices.MockIOAuthServerService
+	utils              *mockutils.MockIUtils
+}
+
+func newAdminController(t *testing.T) (admin.IAdminController, adminMocks) {
+	ctrl := gomock.NewController(t)
+	mocks := adminMocks{
+		userService:        mockservices.NewMockIUserService(ctrl),
+		passwordService:    mockservices.NewMockIPasswordService(ctrl),
+		roleService:        mockservices.NewMockIRoleService(ctrl),
+		authService:        mockservices.NewMockIAuthService(ctrl),
+		sessionService:     mockservices.NewMockISessionService(ctrl),
+		adminActionService: mockservices.NewMockIAdminActionService(ctrl),
+		auditService:       mockservices.NewMockIAuditService(ctrl),
+		emailOutbox:        mockservices.NewMockIEmailOutboxService(ctrl),
+		signingKeys:        mockservices.NewMockISigningKeyService(ctrl),
+		oauthServer:        mockservices.NewMockIOAuthServerService(ctrl),
+		utils:              mockutils.NewMockIUtils(ctrl),
+	}
+	controller := admin.NewAdminController(
+		mocks.userService,
+		mocks.passwordService,
+		mocks.authService,
+		mockservices.NewMockIRedisService(ctrl),
+		mocks.sessionService,
+		mockservices.NewMockIEmailService(ctrl),
+		mocks.roleService,
+		mocks.adminActionService,
+		mocks.auditService,
+		mockservices.NewMockILoginProtectionService(ctrl),
+		mocks.emailOutbox,
+		mocks.signingKeys,
+		mocks.oauthServer,
+		mocks.utils,
+	)
+	gin.SetMode(gin.TestMode)
+	return controller, mocks
+}
+
+func newContext(currentAdmin *models.User, userId uuid.UUID, body any) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/users/"+userId.String(), nil)
+	c.Params = gin.Params{{Key: "id", Value: userId.String()}}
+	c.Set(constants.CURRENT_USER, currentAdmin)
+	if body != nil {
+		c.Set(constants.VALIDATED_BODY, body)
+	}
+	return c, w
+}
+
+func TestLockUser_LocksRevokesAndRecords(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	currentAdmin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	user := &models.User{ID: uuid.New(), JwtVersion: "v1"}
+	lockedAt := "2026-01-01T00:00:00Z"
+	locked := &models.User{ID: user.ID, JwtVersion: "v1", LockedAt: &lockedAt, LockReason: "fraud"}
+	sessions := []models.Token{{ID: 1, Hash: "hashed", Jti: uuid.New()}}
+
+	mocks.userService.EXPECT().GetUserById(gomock.Any(), user.ID).Return(user, nil)
+	mocks.userService.EXPECT().SetLocked(gomock.Any(), user.ID, true, "fraud").Return(locked, nil)
+	mocks.utils.EXPECT().GenerateRandomBytes(8).Return("v2", nil)
+	mocks.userService.EXPECT().UpdateUser(gomock.Any(), gomock.Cond(func(x any) bool {
+		return x.(*models.User).JwtVersion == "v2"
+	})).Return(locked, nil)
+	mocks.sessionService.EXPECT().RevokeOtherSessions(gomock.Any(), user.ID, uuid.Nil).Return(sessions, nil)
+	mocks.authService.EXPECT().RevokeSessionTokens(sessions)
+	mocks.adminActionService.EXPECT().Record(gomock.Any(), currentAdmin.ID, user.ID, models.AdminActionLockUser, map[string]any{"reason": "fraud"})
+	mocks.auditService.EXPECT().Record(gomock.Any(), services.AuditParams{
+		Type:     models.AuditEventAdminAction,
+		ActorId:  currentAdmin.ID,
+		TargetId: user.ID,
+		Details:  map[string]any{"action": models.AdminActionLockUser, "reason": "fraud"},
+	})
+
+	c, w := newContext(currentAdmin, user.ID, dto.AdminLockUser{Reason: "fraud"})
+	controller.LockUser(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"lock_reason":"fraud"`)
+}
+
+func TestLockUser_RefusesOwnAccount(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	currentAdmin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+
+	mocks.userService.EXPECT().GetUserById(gomock.Any(), currentAdmin.ID).Return(currentAdmin, nil)
+
+	c, w := newContext(currentAdmin, currentAdmin.ID, dto.AdminLockUser{})
+	controller.LockUser(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRestoreUser_FindsDeletedUser(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	currentAdmin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	deletedAt := "2026-01-01T00:00:00Z"
+	deleted := &models.User{ID: uuid.New(), DeletedAt: &deletedAt}
+	restored := &models.User{ID: deleted.ID}
+
+	mocks.userService.EXPECT().GetUserByIdWithDeleted(gomock.Any(), deleted.ID).Return(deleted, nil)
+	mocks.userService.EXPECT().SetDeleted(gomock.Any(), deleted.ID, false).Return(restored, nil)
+	mocks.adminActionService.EXPECT().Record(gomock.Any(), currentAdmin.ID, deleted.ID, models.AdminActionRestoreUser, nil)
+	mocks.auditService.EXPECT().Record(gomock.Any(), gomock.Any())
+
+	c, w := newContext(currentAdmin, deleted.ID, nil)
+	controller.RestoreUser(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "deleted_at")
+}
+
+var auditEvents = []models.AuditEvent{
+	{ID: 7, Type: models.AuditEventLogin, Outcome: models.AuditOutcomeFailure, IpAddress: "203.0.113.9", UserAgent: "=HYPERLINK(\"x\")", Details: []byte(`{"reason":"invalid_credentials"}`), CreatedAt: "2026-01-01T00:00:00Z"},
+	{ID: 6, Type: models.AuditEventLogout, Outcome: models.AuditOutcomeSuccess, Details: []byte(`{}`), CreatedAt: "2026-01-01T00:00:00Z"},
+}
+
+func TestCreateUser_LinksCredentialsInTheSameInsert(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	currentAdmin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	created := &models.User{ID: uuid.New(), Email: "ari@mail.com", Role: models.RoleUser, IsVerified: true}
+
+	mocks.roleService.EXPECT().CheckRoleChange(gomock.Any(), currentAdmin.ID, []string{models.RoleUser}, nil).Return(nil)
+	mocks.passwordService.EXPECT().Hash("Secret123!").Return("hashed", nil)
+	mocks.utils.EXPECT().GenerateRandomBytes(8).Return("v1", nil)
+	mocks.userService.EXPECT().Store(gomock.Any(), repositories.CreateOneParams{
+		Name:            "Ari Test",
+		Username:        "ari08",
+		Email:           "ari@mail.com",
+		Password:        "hashed",
+		JWTVersion:      "v1",
+		IsVerified:      true,
+		Role:            models.RoleUser,
+		LinkCredentials: true,
+	}).Return(created, nil)
+	mocks.adminActionService.EXPECT().Record(gomock.Any(), currentAdmin.ID, created.ID, models.AdminActionCreateUser, gomock.Any())
+	mocks.auditService.EXPECT().Record(gomock.Any(), gomock.Any())
+
+	c, w := newContext(currentAdmin, uuid.Nil, dto.AdminCreateUser{
+		Name: "Ari Test", Username: "ari08", Email: "ari@mail.com", Password: "Secret123!",
+	})
+	controller.CreateUser(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestCreateUser_RefusesAdminForUsersManageOnlyCaller(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	currentAdmin := &models.User{ID: uuid.New(), Role: "user-manager"}
+
+	mocks.roleService.EXPECT().CheckRoleChange(gomock.Any(), currentAdmin.ID, []string{models.RoleAdmin}, nil).
+		Return(services.ErrRoleEscalation)
+
+	c, w := newContext(currentAdmin, uuid.Nil, dto.AdminCreateUser{
+		Name: "Ari Test", Username: "ari08", Email: "ari@mail.com", Password: "Secret123!", Role: models.RoleAdmin,
+	})
+	controller.CreateUser(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCreateUser_DuplicateIsConflict(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	currentAdmin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+
+	mocks.roleService.EXPECT().CheckRoleChange(gomock.Any(), currentAdmin.ID, []string{models.RoleUser}, nil).Return(nil)
+	mocks.passwordService.EXPECT().Hash("Secret123!").Return("hashed", nil)
+	mocks.utils.EXPECT().GenerateRandomBytes(8).Return("v1", nil)
+	mocks.userService.EXPECT().Store(gomock.Any(), gomock.Any()).Return(nil, repositories.ErrDuplicateUser)
+
+	c, w := newContext(currentAdmin, uuid.Nil, dto.AdminCreateUser{
+		Name: "Ari Test", Username: "ari08", Email: "ari@mail.com", Password: "Secret123!",
+	})
+	controller.CreateUser(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"error":"username or email is already taken"}`, w.Body.String())
+}
+
+func TestCreateUser_DatabaseFailureIsNotConflict(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	currentAdmin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+
+	mocks.roleService.EXPECT().CheckRoleChange(gomock.Any(), currentAdmin.ID, []string{models.RoleUser}, nil).Return(nil)
+	mocks.passwordService.EXPECT().Hash("Secret123!").Return("hashed", nil)
+	mocks.utils.EXPECT().GenerateRandomBytes(8).Return("v1", nil)
+	mocks.userService.EXPECT().Store(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+
+	c, w := newContext(currentAdmin, uuid.Nil, dto.AdminCreateUser{
+		Name: "Ari Test", Username: "ari08", Email: "ari@mail.com", Password: "Secret123!",
+	})
+	controller.CreateUser(c)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "connection refused")
+}
+
+func exportAuditEvents(t *testing.T, query dto.ListAuditEvents) *httptest.ResponseRecorder {
+	controller, mocks := newAdminController(t)
+	mocks.auditService.EXPECT().Export(gomock.Any(), repositories.ListAuditEventsParams{Outcome: query.Outcome}, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ repositories.ListAuditEventsParams, fn func(models.AuditEvent) error) error {
+			for _, event := range auditEvents {
+				if err := fn(event); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	c, w := newContext(&models.User{ID: uuid.New()}, uuid.New(), query)
+	controller.ExportAuditEvents(c)
+	return w
+}
+
+func TestExportAuditEvents_Csv(t *testing.T) {
+	w := exportAuditEvents(t, dto.ListAuditEvents{})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `attachment; filename="audit-events.csv"`, w.Header().Get("Content-Disposition"))
+	records, err := csv.NewReader(w.Body).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+	assert.Equal(t, "id", records[0][0])
+	// client supplied text must not be read as a spreadsheet formula
+	assert.Equal(t, `'=HYPERLINK("x")`, records[1][7])
+	assert.Equal(t, `{"reason":"invalid_credentials"}`, records[1][9])
+}
+
+func TestExportAuditEvents_JsonLines(t *testing.T) {
+	w := exportAuditEvents(t, dto.ListAuditEvents{Outcome: models.AuditOutcomeFailure, Format: "jsonl"})
+
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	assert.Len(t, lines, 2)
+	var event models.AuditEvent
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &event))
+	assert.Equal(t, int64(7), event.ID)
+	assert.Equal(t, `=HYPERLINK("x")`, event.UserAgent)
+}
+
+func TestListAuditEvents_PagesWithBefore(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	mocks.auditService.EXPECT().List(gomock.Any(), repositories.ListAuditEventsParams{Limit: 2}).Return(append(auditEvents, models.AuditEvent{ID: 5}), nil)
+
+	c, w := newContext(&models.User{ID: uuid.New()}, uuid.New(), dto.ListAuditEvents{Limit: 1})
+	controller.ListAuditEvents(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var page struct {
+		Events     []models.AuditEvent `json:"events"`
+		NextBefore *int64              `json:"next_before"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Len(t, page.Events, 1)
+	assert.Equal(t, int64(7), *page.NextBefore)
+}
+
+func TestListOutboxEmails_PagesWithBefore(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	mocks.emailOutbox.EXPECT().List(gomock.Any(), repositories.ListOutboxEmailsParams{Status: models.OutboxEmailDead, BeforeId: 9, Limit: 3}).
+		Return([]models.OutboxEmail{{ID: 8, Data: []byte(`{"Code":"123456"}`)}, {ID: 6}, {ID: 3}}, nil)
+
+	c, w := newContext(&models.User{ID: uuid.New()}, uuid.New(), dto.ListOutboxEmails{Status: models.OutboxEmailDead, Before: 9, Limit: 2})
+	controller.ListOutboxEmails(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var page struct {
+		Emails     []models.OutboxEmail `json:"emails"`
+		NextBefore *int64               `json:"next_before"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Len(t, page.Emails, 2)
+	assert.Equal(t, int64(6), *page.NextBefore)
+	// template data holds codes and links
+	assert.NotContains(t, w.Body.String(), "123456")
+}
+
+func TestRetryOutboxEmail_QueuesAndRecords(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	currentAdmin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	mocks.emailOutbox.EXPECT().Retry(gomock.Any(), int64(4)).Return(&models.OutboxEmail{ID: 4, Status: models.OutboxEmailPending}, nil)
+	mocks.auditService.EXPECT().Record(gomock.Any(), services.AuditParams{
+		Type:    models.AuditEventAdminAction,
+		ActorId: currentAdmin.ID,
+		Details: map[string]any{"action": models.AdminActionRetryEmail, "email_id": int64(4)},
+	})
+
+	c, w := newContext(currentAdmin, uuid.Nil, nil)
+	c.Params = gin.Params{{Key: "id", Value: "4"}}
+	controller.RetryOutboxEmail(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"pending"`)
+}
+
+func TestRetryOutboxEmail_RefusesLiveEmails(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	mocks.emailOutbox.EXPECT().Retry(gomock.Any(), int64(4)).Return(nil, services.ErrOutboxEmailNotDead)
+
+	c, w := newContext(&models.User{ID: uuid.New()}, uuid.Nil, nil)
+	c.Params = gin.Params{{Key: "id", Value: "4"}}
+	controller.RetryOutboxEmail(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestIntroduceSigningKey_RecordsWithoutThePrivateKey(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	currentAdmin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	key := &models.SigningKey{ID: "kid-2", Algorithm: "ES256", Status: models.SigningKeyVerify, PrivateKey: "sealed"}
+	mocks.signingKeys.EXPECT().Introduce(gomock.Any(), "ES256").Return(key, nil)
+	mocks.auditService.EXPECT().Record(gomock.Any(), services.AuditParams{
+		Type:    models.AuditEventAdminAction,
+		ActorId: currentAdmin.ID,
+		Details: map[string]any{"action": models.AdminActionIntroduceSigningKey, "kid": "kid-2", "algorithm": "ES256"},
+	})
+
+	c, w := newContext(currentAdmin, uuid.Nil, dto.IntroduceSigningKey{Algorithm: "ES256"})
+	controller.IntroduceSigningKey(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"kid":"kid-2"`)
+	assert.NotContains(t, w.Body.String(), "sealed")
+}
+
+func TestPromoteSigningKey_PassesForce(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	currentAdmin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	mocks.signingKeys.EXPECT().Promote(gomock.Any(), "kid-2", true).Return(&models.SigningKey{ID: "kid-2", Status: models.SigningKeyActive}, nil)
+	mocks.auditService.EXPECT().Record(gomock.Any(), gomock.Any())
+
+	c, w := newContext(currentAdmin, uuid.Nil, nil)
+	c.Request = httptest.NewRequest(http.MethodPost, "/admin/signing-keys/kid-2/promote?force=true", nil)
+	c.Params = gin.Params{{Key: "kid", Value: "kid-2"}}
+	controller.PromoteSigningKey(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRetireSigningKey_RefusesWhileDraining(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	mocks.signingKeys.EXPECT().Retire(gomock.Any(), "kid-1", false).Return(nil, services.ErrSigningKeyDraining)
+
+	c, w := newContext(&models.User{ID: uuid.New()}, uuid.Nil, nil)
+	c.Params = gin.Params{{Key: "kid", Value: "kid-1"}}
+	controller.RetireSigningKey(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestRegisterOAuthClient_ReturnsTheSecretOnce(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	currentAdmin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	client := &models.OAuthClient{ID: "client-1", Name: "Wiki", Confidential: true, RedirectUris: []string{"https://wiki.example.com/callback"}}
+	mocks.oauthServer.EXPECT().RegisterClient(gomock.Any(), services.RegisterOAuthClientParams{
+		Name:         "Wiki",
+		RedirectUris: []string{"https://wiki.example.com/callback"},
+		Confidential: true,
+		CreatedBy:    currentAdmin.ID,
+	}).Return(client, "s3cret", nil)
+	mocks.auditService.EXPECT().Record(gomock.Any(), services.AuditParams{
+		Type:    models.AuditEventAdminAction,
+		ActorId: currentAdmin.ID,
+		Details: map[string]any{"action": models.AdminActionRegisterOAuthClient, "client_id": "client-1", "name": "Wiki"},
+	})
+
+	c, w := newContext(currentAdmin, uuid.Nil, dto.RegisterOAuthClient{
+		Name:         "Wiki",
+		RedirectUris: []string{"https://wiki.example.com/callback"},
+		Confidential: true,
+	})
+	controller.RegisterOAuthClient(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"client_secret":"s3cret"`)
+	assert.Contains(t, w.Body.String(), `"client_id":"client-1"`)
+}
+
+func TestRegisterOAuthClient_RejectsInvalidRedirectUris(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	mocks.oauthServer.EXPECT().RegisterClient(gomock.Any(), gomock.Any()).Return(nil, "", services.ErrInvalidRedirectUri)
+
+	c, w := newContext(&models.User{ID: uuid.New()}, uuid.Nil, dto.RegisterOAuthClient{
+		Name:         "Wiki",
+		RedirectUris: []string{"http://wiki.example.com/callback"},
+	})
+	controller.RegisterOAuthClient(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDeleteOAuthClient_NotFound(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	mocks.oauthServer.EXPECT().DeleteClient(gomock.Any(), "missing").Return(services.ErrOAuthClientNotFound)
+
+	c, w := newContext(&models.User{ID: uuid.New()}, uuid.Nil, nil)
+	c.Params = gin.Params{{Key: "clientId", Value: "missing"}}
+	controller.DeleteOAuthClient(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRevokeUserSession_RevokesAndRecords(t *testing.T) {
+	controller, mocks := newAdminController(t)
+	currentAdmin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	user := &models.User{ID: uuid.New()}
+	session := &models.Token{ID: 7, UserId: user.ID}
+
+	mocks.userService.EXPECT().GetUserById(gomock.Any(), user.ID).Return(user, nil)
+	mocks.sessionService.EXPECT().RevokeSessionById(gomock.Any(), user.ID, 7).Return(session, nil)
+	mocks.authService.EXPECT().RevokeSessionTokens([]models.Token{*session})
+	mocks.adminActionService.EXPECT().Record(gomock.Any(), currentAdmin.ID, user.ID, models.AdminActionRevokeSession, map[string]any{"session_id": 7})
+	mocks.auditService.EXPECT().Record(gomock.Any(), gomock.Any())
+
+	c, w := newContext(currentAdmin, user.ID, nil)
+	c.Params = append(c.Params, gin.Param{Key: "sessionId", Value: "7"})
+	controller.RevokeUserSession(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"errors"
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChangeRole changes the primary role of a user. The admin has to hold every
+// permission of the new role, and roles:admin to grant or revoke admin. Their
+// access tokens are expired so the next refresh carries the new permissions.
+func (ctrl *adminController) ChangeRole(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.AdminChangeRole)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+	user, ok := ctrl.targetUser(c, false)
+	if !ok || !otherUser(c, admin, user) {
+		return
+	}
+	if user.Role == body.Role {
+		c.JSON(http.StatusOK, gin.H{"user": user})
+		return
+	}
+
+	if err := ctrl.roleService.CheckRoleChange(c.Request.Context(), admin.ID, []string{body.Role}, []string{user.Role}); err != nil {
+		if errors.Is(err, services.ErrRoleEscalation) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	previous := user.Role
+	user.Role = body.Role
+	user, err := ctrl.userService.UpdateUser(c.Request.Context(), user)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	ctrl.roleService.ExpireAccessTokens(c.Request.Context(), user.ID)
+
+	ctrl.record(c, admin, user, models.AdminActionChangeRole, map[string]any{"from": previous, "to": user.Role})
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
@@ -0,0 +1,97 @@
+package admin
+
+import (
+	"errors"
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateUser creates an already verified account with a password chosen by
+// the admin, skipping the verification email. As with ChangeRole, the admin
+// has to hold every permission of the role given to the account.
+func (ctrl *adminController) CreateUser(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.AdminCreateUser)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	role := body.Role
+	if role == "" {
+		role = models.RoleUser
+	}
+	if err := ctrl.roleService.CheckRoleChange(c.Request.Context(), admin.ID, []string{role}, nil); err != nil {
+		if errors.Is(err, services.ErrRoleEscalation) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	hashedPassword, err := ctrl.passwordService.Hash(body.Password)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to hash password"})
+		return
+	}
+	jwtVersion, err := ctrl.utils.GenerateRandomBytes(8)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	user, err := ctrl.userService.Store(c.Request.Context(), repositories.CreateOneParams{
+		Name:       body.Name,
+		Username:   body.Username,
+		Email:      body.Email,
+		Password:   hashedPassword,
+		JWTVersion: jwtVersion,
+		IsVerified: true,
+		Role:       role,
+
+		PasswordResetRequired: body.RequirePasswordReset,
+		LinkCredentials:       true,
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrDuplicateUser) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	if body.RequirePasswordReset {
+		if err := ctrl.sendPasswordReset(user); err != nil {
+			log.Println(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "User created but the password reset email could not be sent"})
+			return
+		}
+	}
+
+	ctrl.record(c, admin, user, models.AdminActionCreateUser, map[string]any{
+		"role":                   user.Role,
+		"require_password_reset": body.RequirePasswordReset,
+	})
+	c.JSON(http.StatusCreated, gin.H{"user": user})
+}
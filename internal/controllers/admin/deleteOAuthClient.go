@@ -0,0 +1,32 @@
+package admin
+
+import (
+	"errors"
+	"log"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteOAuthClient removes a relying party. Its consents and pending codes
+// go with it and the sessions it holds end.
+func (ctrl *adminController) DeleteOAuthClient(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+	clientId := c.Param("clientId")
+	if err := ctrl.oauthServer.DeleteClient(c.Request.Context(), clientId); err != nil {
+		if errors.Is(err, services.ErrOAuthClientNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	ctrl.recordClientAction(c, admin, models.AdminActionDeleteOAuthClient, clientId, "")
+	c.JSON(http.StatusOK, gin.H{"message": "Client deleted"})
+}
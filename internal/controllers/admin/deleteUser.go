@@ -0,0 +1,36 @@
+package admin
+
+import (
+	"log"
+	"my-go-api/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteUser soft deletes a user. The account disappears from every lookup
+// but keeps its data, so it can be restored.
+func (ctrl *adminController) DeleteUser(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+	user, ok := ctrl.targetUser(c, false)
+	if !ok || !otherUser(c, admin, user) {
+		return
+	}
+
+	if err := ctrl.revokeAllTokens(c, user); err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	user, err := ctrl.userService.SetDeleted(c.Request.Context(), user.ID, true)
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	ctrl.record(c, admin, user, models.AdminActionDeleteUser, nil)
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"log"
+	"my-go-api/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ForcePasswordReset signs the user out everywhere and emails a reset link.
+// Password sign in stays blocked until the password has been reset.
+func (ctrl *adminController) ForcePasswordReset(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+	user, ok := ctrl.targetUser(c, false)
+	if !ok {
+		return
+	}
+
+	user.PasswordResetRequired = true
+	if err := ctrl.revokeAllTokens(c, user); err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	if err := ctrl.sendPasswordReset(user); err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Password reset required but the email could not be sent"})
+		return
+	}
+
+	ctrl.record(c, admin, user, models.AdminActionForcePasswordReset, nil)
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
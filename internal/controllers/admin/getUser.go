@@ -0,0 +1,30 @@
+package admin
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetUser returns a user, including a soft deleted one, together with the
+// actions admins have taken on the account and its sign in lockout.
+func (ctrl *adminController) GetUser(c *gin.Context) {
+	user, ok := ctrl.targetUser(c, true)
+	if !ok {
+		return
+	}
+	actions, err := ctrl.adminActionService.GetUserActions(c.Request.Context(), user.ID)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	loginLock, err := ctrl.loginProtection.GetLockState(c.Request.Context(), user.ID)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"user": user, "actions": actions, "login_lock": loginLock})
+}
@@ -0,0 +1,166 @@
+package admin
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// currentAdmin returns the admin performing the request, as set by the auth
+// middleware.
+func currentAdmin(c *gin.Context) (*models.User, bool) {
+	value, exist := c.Get(constants.CURRENT_USER)
+	if !exist {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+	admin, ok := value.(*models.User)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+	return admin, true
+}
+
+// targetUser loads the user named by the id path parameter. Soft deleted users
+// are only found with withDeleted.
+func (ctrl *adminController) targetUser(c *gin.Context, withDeleted bool) (*models.User, bool) {
+	userId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return nil, false
+	}
+	var user *models.User
+	if withDeleted {
+		user, err = ctrl.userService.GetUserByIdWithDeleted(c.Request.Context(), userId)
+	} else {
+		user, err = ctrl.userService.GetUserById(c.Request.Context(), userId)
+	}
+	if err != nil {
+		respondUserError(c, err)
+		return nil, false
+	}
+	return user, true
+}
+
+// otherUser stops admins from locking, deleting or demoting themselves, which
+// could leave nobody able to undo it.
+func otherUser(c *gin.Context, admin, user *models.User) bool {
+	if admin.ID == user.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed on your own account"})
+		return false
+	}
+	return true
+}
+
+// record stores the action in the admin action log and the audit log. The
+// action itself already happened, so a failure is logged rather than returned
+// to the admin.
+func (ctrl *adminController) record(c *gin.Context, admin, user *models.User, action string, details map[string]any) {
+	if _, err := ctrl.adminActionService.Record(c.Request.Context(), admin.ID, user.ID, action, details); err != nil {
+		log.Printf("failed to record admin action %s on %s by %s: %s", action, user.ID, admin.ID, err.Error())
+	}
+
+	eventType := models.AuditEventAdminAction
+	if action == models.AdminActionChangeRole {
+		eventType = models.AuditEventRoleChange
+	}
+	auditDetails := map[string]any{"action": action}
+	for key, value := range details {
+		auditDetails[key] = value
+	}
+	ctrl.auditService.Record(c.Request.Context(), services.AuditParams{
+		Type:     eventType,
+		ActorId:  admin.ID,
+		TargetId: user.ID,
+		Details:  auditDetails,
+	})
+}
+
+// revokeAllTokens bumps the jwt version of user, which invalidates every
+// access token already issued, and revokes all of their sessions.
+func (ctrl *adminController) revokeAllTokens(c *gin.Context, user *models.User) error {
+	jwtVersion, err := ctrl.utils.GenerateRandomBytes(8)
+	if err != nil {
+		return err
+	}
+	user.JwtVersion = jwtVersion
+	if _, err := ctrl.userService.UpdateUser(c.Request.Context(), user); err != nil {
+		return err
+	}
+	sessions, err := ctrl.sessionService.RevokeOtherSessions(c.Request.Context(), user.ID, uuid.Nil)
+	if err != nil {
+		return err
+	}
+	ctrl.authService.RevokeSessionTokens(sessions)
+	return nil
+}
+
+// sendPasswordReset emails user a password reset link, like ForgotPassword.
+func (ctrl *adminController) sendPasswordReset(user *models.User) error {
+	pairToken, err := ctrl.authService.GeneratePairToken()
+	if err != nil {
+		return err
+	}
+	if err := ctrl.redisService.SavePasswordResetToken(services.PasswordResetData{
+		HashedToken: pairToken.Hashed,
+		UserId:      user.ID.String(),
+	}); err != nil {
+		return err
+	}
+	return ctrl.emailService.SendPasswordResetRequest(services.SendPasswordResetParams{
+		Name:   user.Username,
+		Email:  user.Email,
+		Token:  pairToken.Raw,
+		Locale: user.Locale,
+	})
+}
+
+// recordKeyAction stores a change of the keyring in the audit log.
+func (ctrl *adminController) recordKeyAction(c *gin.Context, admin *models.User, action string, key *models.SigningKey) {
+	ctrl.auditService.Record(c.Request.Context(), services.AuditParams{
+		Type:    models.AuditEventAdminAction,
+		ActorId: admin.ID,
+		Details: map[string]any{"action": action, "kid": key.ID, "algorithm": key.Algorithm},
+	})
+}
+
+// recordClientAction stores a change of the client registry in the audit log.
+func (ctrl *adminController) recordClientAction(c *gin.Context, admin *models.User, action string, clientId, name string) {
+	ctrl.auditService.Record(c.Request.Context(), services.AuditParams{
+		Type:    models.AuditEventAdminAction,
+		ActorId: admin.ID,
+		Details: map[string]any{"action": action, "client_id": clientId, "name": name},
+	})
+}
+
+func respondSigningKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrSigningKeyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSigningKeyActive),
+		errors.Is(err, services.ErrSigningKeyRetired),
+		errors.Is(err, services.ErrSigningKeyUnpublished),
+		errors.Is(err, services.ErrSigningKeyDraining):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+	}
+}
+
+func respondUserError(c *gin.Context, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	log.Println(err.Error())
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+}
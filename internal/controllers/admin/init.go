@@ -0,0 +1,85 @@
+package admin
+
+import (
+	"my-go-api/internal/services"
+	"my-go-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IAdminController interface {
+	CreateUser(c *gin.Context)
+	GetUser(c *gin.Context)
+	LockUser(c *gin.Context)
+	UnlockUser(c *gin.Context)
+	ForcePasswordReset(c *gin.Context)
+	VerifyUser(c *gin.Context)
+	ChangeRole(c *gin.Context)
+	DeleteUser(c *gin.Context)
+	RestoreUser(c *gin.Context)
+	RevokeTokens(c *gin.Context)
+	ListUserSessions(c *gin.Context)
+	RevokeUserSession(c *gin.Context)
+	ListAuditEvents(c *gin.Context)
+	ExportAuditEvents(c *gin.Context)
+	ListOutboxEmails(c *gin.Context)
+	RetryOutboxEmail(c *gin.Context)
+	ListSigningKeys(c *gin.Context)
+	IntroduceSigningKey(c *gin.Context)
+	PromoteSigningKey(c *gin.Context)
+	RetireSigningKey(c *gin.Context)
+	ListOAuthClients(c *gin.Context)
+	RegisterOAuthClient(c *gin.Context)
+	DeleteOAuthClient(c *gin.Context)
+}
+
+type adminController struct {
+	userService        services.IUserService
+	passwordService    services.IPasswordService
+	authService        services.IAuthService
+	redisService       services.IRedisService
+	sessionService     services.ISessionService
+	emailService       services.IEmailService
+	roleService        services.IRoleService
+	adminActionService services.IAdminActionService
+	auditService       services.IAuditService
+	loginProtection    services.ILoginProtectionService
+	emailOutbox        services.IEmailOutboxService
+	signingKeys        services.ISigningKeyService
+	oauthServer        services.IOAuthServerService
+	utils              utils.IUtils
+}
+
+func NewAdminController(
+	userService services.IUserService,
+	passwordService services.IPasswordService,
+	authService services.IAuthService,
+	redisService services.IRedisService,
+	sessionService services.ISessionService,
+	emailService services.IEmailService,
+	roleService services.IRoleService,
+	adminActionService services.IAdminActionService,
+	auditService services.IAuditService,
+	loginProtection services.ILoginProtectionService,
+	emailOutbox services.IEmailOutboxService,
+	signingKeys services.ISigningKeyService,
+	oauthServer services.IOAuthServerService,
+	utils utils.IUtils,
+) IAdminController {
+	return &adminController{
+		userService:        userService,
+		passwordService:    passwordService,
+		authService:        authService,
+		redisService:       redisService,
+		sessionService:     sessionService,
+		emailService:       emailService,
+		roleService:        roleService,
+		adminActionService: adminActionService,
+		auditService:       auditService,
+		loginProtection:    loginProtection,
+		emailOutbox:        emailOutbox,
+		signingKeys:        signingKeys,
+		oauthServer:        oauthServer,
+		utils:              utils,
+	}
+}
@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IntroduceSigningKey adds a verify only key. It is published right away and
+// can be promoted once verifiers had the time to fetch it.
+func (ctrl *adminController) IntroduceSigningKey(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.IntroduceSigningKey)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	key, err := ctrl.signingKeys.Introduce(c.Request.Context(), body.Algorithm)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	ctrl.recordKeyAction(c, admin, models.AdminActionIntroduceSigningKey, key)
+	c.JSON(http.StatusCreated, gin.H{"key": key})
+}
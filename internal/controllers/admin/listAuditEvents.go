@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/repositories"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const defaultAuditPageSize = 50
+
+// ListAuditEvents returns one page of the audit log, newest first. Pass
+// next_before back as before, with the same filters, to get the following
+// page.
+func (ctrl *adminController) ListAuditEvents(c *gin.Context) {
+	query, ok := auditQuery(c)
+	if !ok {
+		return
+	}
+	params := auditEventParams(query)
+	if params.Limit == 0 {
+		params.Limit = defaultAuditPageSize
+	}
+	limit := params.Limit
+	// one extra event tells whether another page follows
+	params.Limit++
+
+	events, err := ctrl.auditService.List(c.Request.Context(), params)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	var nextBefore *int64
+	if len(events) > limit {
+		events = events[:limit]
+		nextBefore = &events[limit-1].ID
+	}
+	c.JSON(http.StatusOK, gin.H{"events": events, "next_before": nextBefore})
+}
+
+func auditQuery(c *gin.Context) (dto.ListAuditEvents, bool) {
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return dto.ListAuditEvents{}, false
+	}
+	query, ok := value.(dto.ListAuditEvents)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return dto.ListAuditEvents{}, false
+	}
+	return query, true
+}
+
+// auditEventParams turns the validated query into repository filters. The
+// ids were validated as uuids already.
+func auditEventParams(query dto.ListAuditEvents) repositories.ListAuditEventsParams {
+	params := repositories.ListAuditEventsParams{
+		Chain:     query.Chain,
+		Type:      query.Type,
+		Outcome:   query.Outcome,
+		IpAddress: query.IpAddress,
+		RequestId: query.RequestId,
+		From:      query.From,
+		To:        query.To,
+		BeforeId:  query.Before,
+		Limit:     query.Limit,
+	}
+	if id, err := uuid.Parse(query.ActorId); err == nil {
+		params.ActorId = &id
+	}
+	if id, err := uuid.Parse(query.TargetId); err == nil {
+		params.TargetId = &id
+	}
+	return params
+}
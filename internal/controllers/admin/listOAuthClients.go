@@ -0,0 +1,19 @@
+package admin
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListOAuthClients returns the registered relying parties, without secrets.
+func (ctrl *adminController) ListOAuthClients(c *gin.Context) {
+	clients, err := ctrl.oauthServer.ListClients(c.Request.Context())
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"clients": clients})
+}
@@ -0,0 +1,52 @@
+package admin
+
+import (
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/repositories"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultOutboxPageSize = 50
+
+// ListOutboxEmails returns one page of queued, sent and dead emails, newest
+// first. Pass next_before back as before, with the same filters, to get the
+// following page.
+func (ctrl *adminController) ListOutboxEmails(c *gin.Context) {
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	query, ok := value.(dto.ListOutboxEmails)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultOutboxPageSize
+	}
+
+	// one extra email tells whether another page follows
+	emails, err := ctrl.emailOutbox.List(c.Request.Context(), repositories.ListOutboxEmailsParams{
+		Status:    query.Status,
+		Recipient: query.Recipient,
+		BeforeId:  query.Before,
+		Limit:     limit + 1,
+	})
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	var nextBefore *int64
+	if len(emails) > limit {
+		emails = emails[:limit]
+		nextBefore = &emails[limit-1].ID
+	}
+	c.JSON(http.StatusOK, gin.H{"emails": emails, "next_before": nextBefore})
+}
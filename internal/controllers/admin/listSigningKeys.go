@@ -0,0 +1,20 @@
+package admin
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListSigningKeys returns every key of the keyring, retired ones included,
+// without their private keys.
+func (ctrl *adminController) ListSigningKeys(c *gin.Context) {
+	keys, err := ctrl.signingKeys.List(c.Request.Context())
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"keys": keys})
+}
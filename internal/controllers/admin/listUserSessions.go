@@ -0,0 +1,38 @@
+package admin
+
+import (
+	"log"
+	"my-go-api/internal/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListUserSessions returns the active sessions of a user, the way the user
+// sees them in GET /auth/sessions.
+func (ctrl *adminController) ListUserSessions(c *gin.Context) {
+	user, ok := ctrl.targetUser(c, false)
+	if !ok {
+		return
+	}
+	sessions, err := ctrl.sessionService.GetActiveSessions(c.Request.Context(), user.ID)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	result := make([]dto.Session, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, dto.Session{
+			ID:         session.ID,
+			DeviceId:   session.DeviceId.String(),
+			IpAddress:  session.IpAddress,
+			UserAgent:  session.UserAgent,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiredAt:  session.ExpiredAt,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"sessions": result})
+}
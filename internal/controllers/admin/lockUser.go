@@ -0,0 +1,48 @@
+package admin
+
+import (
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LockUser blocks every way of signing in and ends the sessions the user
+// already has.
+func (ctrl *adminController) LockUser(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.AdminLockUser)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+	user, ok := ctrl.targetUser(c, false)
+	if !ok || !otherUser(c, admin, user) {
+		return
+	}
+
+	user, err := ctrl.userService.SetLocked(c.Request.Context(), user.ID, true, body.Reason)
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+	if err := ctrl.revokeAllTokens(c, user); err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	ctrl.record(c, admin, user, models.AdminActionLockUser, map[string]any{"reason": body.Reason})
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
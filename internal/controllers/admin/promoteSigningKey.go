@@ -0,0 +1,25 @@
+package admin
+
+import (
+	"my-go-api/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromoteSigningKey makes a key the one new tokens are signed with. The key
+// it replaces keeps verifying until it is retired. Pass force=true to promote
+// a key before the publish delay passed.
+func (ctrl *adminController) PromoteSigningKey(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+	key, err := ctrl.signingKeys.Promote(c.Request.Context(), c.Param("kid"), c.Query("force") == "true")
+	if err != nil {
+		respondSigningKeyError(c, err)
+		return
+	}
+	ctrl.recordKeyAction(c, admin, models.AdminActionPromoteSigningKey, key)
+	c.JSON(http.StatusOK, gin.H{"key": key})
+}
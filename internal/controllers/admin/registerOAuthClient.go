@@ -0,0 +1,54 @@
+package admin
+
+import (
+	"errors"
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterOAuthClient registers a relying party. The secret of a confidential
+// client is only returned in this response.
+func (ctrl *adminController) RegisterOAuthClient(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.RegisterOAuthClient)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	client, secret, err := ctrl.oauthServer.RegisterClient(c.Request.Context(), services.RegisterOAuthClientParams{
+		Name:         body.Name,
+		RedirectUris: body.RedirectUris,
+		Confidential: body.Confidential,
+		CreatedBy:    admin.ID,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRedirectUri) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	ctrl.recordClientAction(c, admin, models.AdminActionRegisterOAuthClient, client.ID, client.Name)
+	response := gin.H{"client": client}
+	if secret != "" {
+		response["client_secret"] = secret
+	}
+	c.JSON(http.StatusCreated, response)
+}
@@ -0,0 +1,32 @@
+package admin
+
+import (
+	"my-go-api/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *adminController) RestoreUser(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+	user, ok := ctrl.targetUser(c, true)
+	if !ok {
+		return
+	}
+	if user.DeletedAt == nil {
+		c.JSON(http.StatusOK, gin.H{"user": user})
+		return
+	}
+
+	user, err := ctrl.userService.SetDeleted(c.Request.Context(), user.ID, false)
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	ctrl.record(c, admin, user, models.AdminActionRestoreUser, nil)
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
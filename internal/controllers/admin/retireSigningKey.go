@@ -0,0 +1,25 @@
+package admin
+
+import (
+	"my-go-api/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RetireSigningKey stops accepting tokens signed with a key that no longer
+// signs. Pass force=true to retire it before its tokens drained, which signs
+// out everyone holding one.
+func (ctrl *adminController) RetireSigningKey(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+	key, err := ctrl.signingKeys.Retire(c.Request.Context(), c.Param("kid"), c.Query("force") == "true")
+	if err != nil {
+		respondSigningKeyError(c, err)
+		return
+	}
+	ctrl.recordKeyAction(c, admin, models.AdminActionRetireSigningKey, key)
+	c.JSON(http.StatusOK, gin.H{"key": key})
+}
@@ -0,0 +1,46 @@
+package admin
+
+import (
+	"errors"
+	"log"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RetryOutboxEmail queues a dead email again with a fresh set of attempts.
+func (ctrl *adminController) RetryOutboxEmail(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email id"})
+		return
+	}
+
+	email, err := ctrl.emailOutbox.Retry(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOutboxEmailNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrOutboxEmailNotDead):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Println(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		}
+		return
+	}
+
+	ctrl.auditService.Record(c.Request.Context(), services.AuditParams{
+		Type:    models.AuditEventAdminAction,
+		ActorId: admin.ID,
+		Details: map[string]any{"action": models.AdminActionRetryEmail, "email_id": email.ID},
+	})
+	c.JSON(http.StatusOK, gin.H{"email": email})
+}
@@ -0,0 +1,31 @@
+package admin
+
+import (
+	"log"
+	"my-go-api/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RevokeTokens signs the user out of every device by bumping their jwt
+// version and revoking their sessions.
+func (ctrl *adminController) RevokeTokens(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+	user, ok := ctrl.targetUser(c, false)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.revokeAllTokens(c, user); err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	ctrl.record(c, admin, user, models.AdminActionRevokeTokens, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "All tokens revoked"})
+}
@@ -0,0 +1,44 @@
+package admin
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"my-go-api/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RevokeUserSession signs a user out of a single session.
+func (ctrl *adminController) RevokeUserSession(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+	user, ok := ctrl.targetUser(c, false)
+	if !ok {
+		return
+	}
+	sessionId, err := strconv.Atoi(c.Param("sessionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session id"})
+		return
+	}
+
+	session, err := ctrl.sessionService.RevokeSessionById(c.Request.Context(), user.ID, sessionId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	ctrl.authService.RevokeSessionTokens([]models.Token{*session})
+
+	ctrl.record(c, admin, user, models.AdminActionRevokeSession, map[string]any{"session_id": sessionId})
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
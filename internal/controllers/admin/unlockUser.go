@@ -0,0 +1,35 @@
+package admin
+
+import (
+	"log"
+	"my-go-api/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UnlockUser lifts an admin lock as well as a lockout after failed sign ins.
+func (ctrl *adminController) UnlockUser(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+	user, ok := ctrl.targetUser(c, false)
+	if !ok {
+		return
+	}
+
+	user, err := ctrl.userService.SetLocked(c.Request.Context(), user.ID, false, "")
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+	if err := ctrl.loginProtection.Unlock(c.Request.Context(), user.ID); err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	ctrl.record(c, admin, user, models.AdminActionUnlockUser, nil)
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
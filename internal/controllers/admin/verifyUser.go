@@ -0,0 +1,35 @@
+package admin
+
+import (
+	"log"
+	"my-go-api/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *adminController) VerifyUser(c *gin.Context) {
+	admin, ok := currentAdmin(c)
+	if !ok {
+		return
+	}
+	user, ok := ctrl.targetUser(c, false)
+	if !ok {
+		return
+	}
+	if user.IsVerified {
+		c.JSON(http.StatusOK, gin.H{"user": user})
+		return
+	}
+
+	user.IsVerified = true
+	user, err := ctrl.userService.UpdateUser(c.Request.Context(), user)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	ctrl.record(c, admin, user, models.AdminActionVerifyUser, nil)
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
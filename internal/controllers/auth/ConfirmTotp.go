@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfirmTotp enables two-factor authentication and returns the recovery
+// codes. They are not stored in plain text and cannot be shown again.
+func (ctrl *authController) ConfirmTotp(c *gin.Context) {
+	_, userId, ok := getTokenPayload(c)
+	if !ok {
+		return
+	}
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.MfaCode)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	recoveryCodes, err := ctrl.mfaService.ConfirmEnrollment(c.Request.Context(), userId, body.Code)
+	if err != nil {
+		respondMfaError(c, err)
+		return
+	}
+
+	ctrl.auditSelf(c, models.AuditEventMfaEnable, models.AuditOutcomeSuccess, userId, map[string]any{"method": "totp"})
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": recoveryCodes})
+}
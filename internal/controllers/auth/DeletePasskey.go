@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"my-go-api/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *authController) DeletePasskey(c *gin.Context) {
+	_, userId, ok := getTokenPayload(c)
+	if !ok {
+		return
+	}
+
+	passkeyId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid passkey id"})
+		return
+	}
+
+	if err := ctrl.webauthnService.DeleteCredential(c.Request.Context(), userId, passkeyId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Passkey not found"})
+			return
+		}
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	ctrl.auditSelf(c, models.AuditEventPasskeyRemove, models.AuditOutcomeSuccess, userId, map[string]any{"passkey_id": passkeyId})
+	c.JSON(http.StatusOK, gin.H{"message": "Passkey deleted"})
+}
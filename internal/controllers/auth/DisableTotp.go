@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"log"
+	"my-go-api/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *authController) DisableTotp(c *gin.Context) {
+	_, userId, ok := getTokenPayload(c)
+	if !ok {
+		return
+	}
+	if !ctrl.reauthenticateMfa(c, userId) {
+		return
+	}
+
+	if err := ctrl.mfaService.Disable(c.Request.Context(), userId); err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	ctrl.auditSelf(c, models.AuditEventMfaDisable, models.AuditOutcomeSuccess, userId, map[string]any{"method": "totp"})
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"encoding/base64"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnrollTotp starts TOTP enrollment. The secret only becomes active once a
+// code generated from it is sent to ConfirmTotp.
+func (ctrl *authController) EnrollTotp(c *gin.Context) {
+	_, userId, ok := getTokenPayload(c)
+	if !ok {
+		return
+	}
+
+	user, err := ctrl.userService.GetUserById(c.Request.Context(), userId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	enrollment, err := ctrl.mfaService.BeginEnrollment(c.Request.Context(), user)
+	if err != nil {
+		respondMfaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"secret":      enrollment.Secret,
+		"otpauth_uri": enrollment.URI,
+		"qr_code":     "data:image/png;base64," + base64.StdEncoding.EncodeToString(enrollment.QRCode),
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"my-go-api/internal/constants"
 	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
 	"my-go-api/internal/services"
 	"net/http"
 	"strings"
@@ -54,13 +55,18 @@ func (ctrl *authController) ForgotPassword(c *gin.Context) {
 	}
 
 	if err := ctrl.emailService.SendPasswordResetRequest(services.SendPasswordResetParams{
-		Name:  user.Username,
-		Email: user.Email,
-		Token: pairToken.Raw,
+		Name:   user.Username,
+		Email:  user.Email,
+		Token:  pairToken.Raw,
+		Locale: emailLocale(c, user),
 	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
 		return
 	}
+	ctrl.auditService.Record(c.Request.Context(), services.AuditParams{
+		Type:     models.AuditEventPasswordResetRequest,
+		TargetId: user.ID,
+	})
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": fmt.Sprintf("An email has been sent to %s. Please follow the instruction to reset your password", body.Email),
@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *authController) GetIdentities(c *gin.Context) {
+	_, userId, ok := getTokenPayload(c)
+	if !ok {
+		return
+	}
+
+	identities, err := ctrl.identityService.GetUserIdentities(c.Request.Context(), userId)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"identities": identities})
+}
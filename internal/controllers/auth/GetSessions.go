@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"log"
+	"my-go-api/internal/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *authController) GetSessions(c *gin.Context) {
+	tokenPayload, userId, ok := getTokenPayload(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := ctrl.sessionService.GetActiveSessions(c.Request.Context(), userId)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	result := make([]dto.Session, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, dto.Session{
+			ID:         session.ID,
+			DeviceId:   session.DeviceId.String(),
+			IpAddress:  session.IpAddress,
+			UserAgent:  session.UserAgent,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiredAt:  session.ExpiredAt,
+			Current:    session.Jti.String() == tokenPayload.Jti,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": result})
+}
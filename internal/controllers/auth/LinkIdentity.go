@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LinkIdentity returns the provider authorization URL for a signed in user.
+// The provider redirects back to the regular oauth callback, which links the
+// provider account instead of signing in. The response sets the binding
+// cookie the callback requires, so clients must call this with credentials
+// from the browser that is going to follow the URL.
+func (ctrl *authController) LinkIdentity(c *gin.Context) {
+	_, userId, ok := getTokenPayload(c)
+	if !ok {
+		return
+	}
+
+	flow, err := ctrl.oauthService.LinkCodeURL(c.Request.Context(), c.Param("provider"), userId)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownOAuthProvider) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	setOAuthBinding(c, flow.Binding)
+	c.JSON(http.StatusOK, gin.H{"url": flow.URL})
+}
@@ -1,16 +1,24 @@
 package auth
 
 import (
+	"database/sql"
+	"errors"
+	"log"
+	"math"
 	"my-go-api/internal/constants"
 	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
 	"my-go-api/internal/services"
 	"net/http"
-	"os"
-	"strings"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Login signs in with a password. Unknown identities and wrong passwords get
+// the same answer, and both count towards the back-off and lockout of the
+// identity, so responses do not reveal which accounts exist.
 func (ctrl *authController) Login(c *gin.Context) {
 	value, exist := c.Get(constants.VALIDATED_BODY)
 	if !exist {
@@ -24,32 +32,70 @@ func (ctrl *authController) Login(c *gin.Context) {
 	}
 	user, err := ctrl.userService.GetUserByIdentity(c.Request.Context(), body.Identity)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Println(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		user = nil
+	}
+
+	subject := services.LoginSubject(user, body.Identity)
+	wait, err := ctrl.loginProtection.Check(c.Request.Context(), subject, c.ClientIP())
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
 		return
 	}
-	if !user.IsVerified {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Please verify your account first"})
+	if wait > 0 {
+		ctrl.auditLoginFailure(c, user, body.Identity, "throttled")
+		tooManyLoginAttempts(c, wait)
 		return
 	}
-	if err := ctrl.passwordService.Verify(user.Password, body.Password); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "wrong password"})
+
+	hash := services.DummyPasswordHash
+	if user != nil && user.Password != "" {
+		hash = user.Password
+	}
+	if err := ctrl.passwordService.Verify(hash, body.Password); err != nil || hash == services.DummyPasswordHash {
+		ctrl.loginFailed(c, user, body.Identity, subject)
 		return
 	}
-	authToken, err := ctrl.authService.CreateAuthTokens(services.CreateAuthTokenParams{
-		UserId:     user.ID,
-		JwtVersion: user.JwtVersion,
-	})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := ctrl.loginProtection.RecordSuccess(c.Request.Context(), subject); err != nil {
+		log.Println(err.Error())
+	}
+
+	if !user.IsVerified {
+		ctrl.auditLoginFailure(c, user, body.Identity, "unverified")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Please verify your account first"})
 		return
 	}
-	c.SetCookie(constants.COOKIE_REFRESH_TOKEN, authToken.RefreshToken, 3600*24*365, "/", "", os.Getenv("GO_ENV") == "production", true)
-	c.JSON(http.StatusOK, gin.H{
-		"user":  user,
-		"token": authToken.AccessToken,
-	})
+	ctrl.signIn(c, user, "password")
+}
+
+func (ctrl *authController) loginFailed(c *gin.Context, user *models.User, identity, subject string) {
+	ctrl.auditLoginFailure(c, user, identity, "invalid_credentials")
+	if err := ctrl.loginProtection.RecordFailure(c.Request.Context(), user, subject, c.ClientIP(), c.Request.UserAgent()); err != nil {
+		log.Println(err.Error())
+	}
+	c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+}
+
+// auditLoginFailure records a refused password sign in. user is nil for
+// identities that do not exist, which are then only known by identity.
+func (ctrl *authController) auditLoginFailure(c *gin.Context, user *models.User, identity, reason string) {
+	params := services.AuditParams{
+		Type:    models.AuditEventLogin,
+		Outcome: models.AuditOutcomeFailure,
+		Details: map[string]any{"method": "password", "identity": identity, "reason": reason},
+	}
+	if user != nil {
+		params.ActorId, params.TargetId = user.ID, user.ID
+	}
+	ctrl.auditService.Record(c.Request.Context(), params)
+}
+
+func tooManyLoginAttempts(c *gin.Context, wait time.Duration) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many sign in attempts, please try again later"})
 }
@@ -3,10 +3,12 @@ package auth
 import (
 	"log"
 	"my-go-api/internal/constants"
+	"my-go-api/internal/models"
 	"my-go-api/internal/services"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func (ctrl *authController) Logout(c *gin.Context) {
@@ -41,6 +43,14 @@ func (ctrl *authController) Logout(c *gin.Context) {
 		return
 	}
 
+	if err := ctrl.sessionService.RevokeSession(c.Request.Context(), hashedToken); err != nil {
+		log.Println(err.Error() + " failed to revoke session")
+	}
+
+	if userId, err := uuid.Parse(tokenPayload.UserId); err == nil {
+		ctrl.auditSelf(c, models.AuditEventLogout, models.AuditOutcomeSuccess, userId, nil)
+	}
+
 	c.SetCookie(constants.COOKIE_REFRESH_TOKEN, "", -1, "/", "", false, false)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Logout"})
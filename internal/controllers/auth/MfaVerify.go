@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MfaVerify is the second step of signing in when two-factor authentication is
+// enabled. It trades the mfa token from the first step and a TOTP or recovery
+// code for real tokens.
+func (ctrl *authController) MfaVerify(c *gin.Context) {
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.MfaVerify)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	userId, err := ctrl.mfaService.VerifyChallenge(c.Request.Context(), body.MfaToken, body.Code)
+	if err != nil {
+		ctrl.auditService.Record(c.Request.Context(), services.AuditParams{
+			Type:    models.AuditEventMfaChallenge,
+			Outcome: models.AuditOutcomeFailure,
+			Details: map[string]any{"method": "code", "reason": err.Error()},
+		})
+		respondMfaError(c, err)
+		return
+	}
+	user, err := ctrl.userService.GetUserById(c.Request.Context(), userId)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+
+	ctrl.issueAuthTokens(c, user, "mfa")
+}
+
+func respondMfaError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidMfaChallenge), errors.Is(err, services.ErrInvalidMfaCode):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrMfaAlreadyEnabled), errors.Is(err, services.ErrMfaNotEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrMfaNotEnrolled):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+	}
+}
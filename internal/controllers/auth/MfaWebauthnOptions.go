@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MfaWebauthnOptions starts a passkey assertion for the user behind an mfa
+// token, as an alternative to a TOTP code.
+func (ctrl *authController) MfaWebauthnOptions(c *gin.Context) {
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.MfaToken)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	userId, err := ctrl.mfaService.UseChallenge(body.MfaToken)
+	if err != nil {
+		respondMfaError(c, err)
+		return
+	}
+	ceremony, err := ctrl.webauthnService.BeginSecondFactor(c.Request.Context(), userId)
+	if err != nil {
+		respondWebauthnError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ceremony)
+}
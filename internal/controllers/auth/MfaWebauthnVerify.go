@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *authController) MfaWebauthnVerify(c *gin.Context) {
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.MfaWebauthn)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	userId, err := ctrl.mfaService.UseChallenge(body.MfaToken)
+	if err != nil {
+		respondMfaError(c, err)
+		return
+	}
+	if err := ctrl.webauthnService.FinishSecondFactor(c.Request.Context(), userId, body.SessionId, body.Credential); err != nil {
+		ctrl.auditSelf(c, models.AuditEventMfaChallenge, models.AuditOutcomeFailure, userId, map[string]any{"method": "webauthn"})
+		respondWebauthnError(c, err)
+		return
+	}
+	if err := ctrl.mfaService.CompleteChallenge(body.MfaToken); err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	user, err := ctrl.userService.GetUserById(c.Request.Context(), userId)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+
+	ctrl.issueAuthTokens(c, user, "mfa_webauthn")
+}
@@ -0,0 +1,202 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	errOAuthEmailNotVerified   = errors.New("provider account email is not verified")
+	errIdentityLinkedElsewhere = errors.New("this provider account is linked to another user")
+	errProviderAlreadyLinked   = errors.New("a different account of this provider is already linked")
+	errAccountDisabled         = errors.New("this account is disabled")
+)
+
+func (ctrl *authController) OAuthCallback(c *gin.Context) {
+	if providerErr := c.Query("error"); providerErr != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": providerErr})
+		return
+	}
+	state, code := c.Query("state"), c.Query("code")
+	if state == "" || code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "state and code are required"})
+		return
+	}
+	result, err := ctrl.oauthService.Exchange(c.Request.Context(), c.Param("provider"), state, code, takeOAuthBinding(c))
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownOAuthProvider) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Println(err.Error())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "sign in failed"})
+		return
+	}
+
+	if result.LinkUserId != uuid.Nil {
+		identity, err := ctrl.linkOAuthIdentity(c, result.LinkUserId, result.Profile)
+		if err != nil {
+			respondIdentityError(c, err)
+			return
+		}
+		ctrl.auditSelf(c, models.AuditEventIdentityLink, models.AuditOutcomeSuccess, result.LinkUserId, map[string]any{"provider": identity.Provider})
+		c.JSON(http.StatusOK, gin.H{"identity": identity})
+		return
+	}
+
+	user, err := ctrl.resolveOAuthUser(c, result.Profile)
+	if err != nil {
+		respondIdentityError(c, err)
+		return
+	}
+
+	ctrl.signIn(c, user, "oauth:"+result.Profile.Provider)
+}
+
+// resolveOAuthUser finds the account a provider profile signs in to. A known
+// identity always wins. Otherwise the profile is linked to the account with
+// the same email, but only when the provider vouches for the email; when no
+// such account exists a new verified one is created. A deleted account is
+// neither signed in to nor replaced: its identities and email are kept, so
+// both paths end in errAccountDisabled.
+func (ctrl *authController) resolveOAuthUser(c *gin.Context, profile services.OAuthProfile) (*models.User, error) {
+	ctx := c.Request.Context()
+	identity, err := ctrl.identityService.GetIdentity(ctx, profile.Provider, profile.Subject)
+	if err == nil {
+		user, err := ctrl.userService.GetUserById(ctx, identity.UserId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errAccountDisabled
+		}
+		return user, err
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if profile.Email == "" || !profile.EmailVerified {
+		return nil, errOAuthEmailNotVerified
+	}
+
+	user, err := ctrl.userService.GetUserByEmail(ctx, profile.Email)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if user == nil {
+		jwtVersion, err := ctrl.utils.GenerateRandomBytes(8)
+		if err != nil {
+			return nil, err
+		}
+		username, err := ctrl.generateUsername(profile.Email)
+		if err != nil {
+			return nil, err
+		}
+		name := profile.Name
+		if name == "" {
+			name = username
+		}
+		user, err = ctrl.userService.Store(ctx, repositories.CreateOneParams{
+			Name:       name,
+			Username:   username,
+			Email:      profile.Email,
+			JWTVersion: jwtVersion,
+			Provider:   profile.Provider,
+			IsVerified: true,
+		})
+		// the username is random, so a conflict is the email of a deleted
+		// account
+		if errors.Is(err, repositories.ErrDuplicateUser) {
+			return nil, errAccountDisabled
+		}
+		if err != nil {
+			return nil, err
+		}
+	} else if !user.IsVerified {
+		// An unverified account with this email was never proven to belong to
+		// the provider account owner, so its password and sessions are dropped
+		// before the account is handed over.
+		jwtVersion, err := ctrl.utils.GenerateRandomBytes(8)
+		if err != nil {
+			return nil, err
+		}
+		if err := ctrl.identityService.RemoveCredentials(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		user.Password = ""
+		user.IsVerified = true
+		user.JwtVersion = jwtVersion
+		if user, err = ctrl.userService.UpdateUser(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := ctrl.linkOAuthIdentity(c, user.ID, profile); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// linkOAuthIdentity attaches the provider account to userId. Linking the same
+// account twice is a no-op.
+func (ctrl *authController) linkOAuthIdentity(c *gin.Context, userId uuid.UUID, profile services.OAuthProfile) (*models.UserIdentity, error) {
+	ctx := c.Request.Context()
+	identity, err := ctrl.identityService.GetIdentity(ctx, profile.Provider, profile.Subject)
+	if err == nil {
+		if identity.UserId != userId {
+			return nil, errIdentityLinkedElsewhere
+		}
+		return identity, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	identities, err := ctrl.identityService.GetUserIdentities(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, identity := range identities {
+		if identity.Provider == profile.Provider {
+			return nil, errProviderAlreadyLinked
+		}
+	}
+	return ctrl.identityService.LinkIdentity(ctx, repositories.CreateIdentityParams{
+		UserId:   userId,
+		Provider: profile.Provider,
+		Subject:  profile.Subject,
+		Email:    profile.Email,
+	})
+}
+
+func respondIdentityError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errOAuthEmailNotVerified), errors.Is(err, errAccountDisabled):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, errIdentityLinkedElsewhere), errors.Is(err, errProviderAlreadyLinked):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to sign in"})
+	}
+}
+
+var usernameDisallowed = regexp.MustCompile(`[^a-z0-9_]`)
+
+func (ctrl *authController) generateUsername(email string) (string, error) {
+	base := usernameDisallowed.ReplaceAllString(strings.ToLower(strings.Split(email, "@")[0]), "")
+	if len(base) > 40 {
+		base = base[:40]
+	}
+	suffix, err := ctrl.utils.GenerateRandomBytes(3)
+	if err != nil {
+		return "", err
+	}
+	return base + "_" + suffix, nil
+}
@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OAuthLogin redirects to the provider. The flow is bound to the browser by a
+// cookie, so nobody can sign a victim in to their own account by having it
+// open a callback URL.
+func (ctrl *authController) OAuthLogin(c *gin.Context) {
+	flow, err := ctrl.oauthService.AuthCodeURL(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownOAuthProvider) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start sign in"})
+		return
+	}
+	setOAuthBinding(c, flow.Binding)
+	c.Redirect(http.StatusFound, flow.URL)
+}
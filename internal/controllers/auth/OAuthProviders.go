@@ -0,0 +1,11 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *authController) OAuthProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": ctrl.oauthService.Providers()})
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PasskeyLogin signs a user in with a passkey alone. The assertion requires
+// user verification, so no further second factor is asked for.
+func (ctrl *authController) PasskeyLogin(c *gin.Context) {
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.PasskeyLogin)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	userId, err := ctrl.webauthnService.FinishLogin(c.Request.Context(), body.SessionId, body.Credential)
+	if err != nil {
+		ctrl.auditService.Record(c.Request.Context(), services.AuditParams{
+			Type:    models.AuditEventLogin,
+			Outcome: models.AuditOutcomeFailure,
+			Details: map[string]any{"method": "passkey", "reason": "invalid_credential"},
+		})
+		respondWebauthnError(c, err)
+		return
+	}
+	user, err := ctrl.userService.GetUserById(c.Request.Context(), userId)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+	if !user.IsVerified {
+		ctrl.auditSelf(c, models.AuditEventLogin, models.AuditOutcomeFailure, user.ID, map[string]any{"method": "passkey", "reason": "unverified"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Please verify your account first"})
+		return
+	}
+
+	ctrl.issueAuthTokens(c, user, "passkey")
+}
+
+func respondWebauthnError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidWebauthnSession):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrWebauthnVerification):
+		log.Println(err.Error())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": services.ErrWebauthnVerification.Error()})
+	case errors.Is(err, services.ErrNoPasskeys):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+	}
+}
@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PasskeyLoginOptions starts a passwordless sign in. The options are passed to
+// navigator.credentials.get and the session id is sent back to PasskeyLogin.
+func (ctrl *authController) PasskeyLoginOptions(c *gin.Context) {
+	ceremony, err := ctrl.webauthnService.BeginLogin(c.Request.Context())
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ceremony)
+}
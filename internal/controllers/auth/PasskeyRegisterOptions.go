@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PasskeyRegisterOptions starts adding a passkey to the signed in account. The
+// options are passed to navigator.credentials.create.
+func (ctrl *authController) PasskeyRegisterOptions(c *gin.Context) {
+	_, userId, ok := getTokenPayload(c)
+	if !ok {
+		return
+	}
+
+	user, err := ctrl.userService.GetUserById(c.Request.Context(), userId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	ceremony, err := ctrl.webauthnService.BeginRegistration(c.Request.Context(), user)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ceremony)
+}
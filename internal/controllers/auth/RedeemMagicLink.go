@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RedeemMagicLink signs in with the token from the emailed link. The link
+// points at the client, which posts the token here; a GET would let mail
+// scanners that prefetch links use it up.
+func (ctrl *authController) RedeemMagicLink(c *gin.Context) {
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.MagicLinkVerify)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	userId, err := ctrl.authService.RedeemMagicLink(body.Token)
+	if err != nil {
+		respondMagicLinkError(c, err)
+		return
+	}
+	ctrl.signInUserId(c, userId, "magic_link")
+}
+
+// signInUserId loads the user a passwordless first factor resolved to and
+// continues with signIn, so two-factor authentication still applies.
+func (ctrl *authController) signInUserId(c *gin.Context, userId uuid.UUID, method string) {
+	user, err := ctrl.userService.GetUserById(c.Request.Context(), userId)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+		return
+	}
+	ctrl.signIn(c, user, method)
+}
+
+func respondMagicLinkError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidMagicLink), errors.Is(err, services.ErrInvalidMagicLinkCode):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	default:
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+	}
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RedeemMagicLinkCode signs in with the token returned by RequestMagicLink and
+// the code from the email.
+func (ctrl *authController) RedeemMagicLinkCode(c *gin.Context) {
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.MagicLinkCode)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	userId, err := ctrl.authService.RedeemMagicLinkCode(body.Token, body.Code)
+	if err != nil {
+		respondMagicLinkError(c, err)
+		return
+	}
+	ctrl.signInUserId(c, userId, "magic_link_code")
+}
@@ -1,8 +1,10 @@
 package auth
 
 import (
+	"errors"
 	"log"
 	"my-go-api/internal/constants"
+	"my-go-api/internal/repositories"
 	"my-go-api/internal/services"
 	"net/http"
 	"os"
@@ -18,43 +20,119 @@ func (ctrl *authController) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	data, err := ctrl.redisService.GetRefreshToken(ctrl.utils.HashWithSHA256(cookieRefToken))
+	hashedToken := ctrl.utils.HashWithSHA256(cookieRefToken)
+	var deviceId uuid.UUID
+
+	claim, err := ctrl.authService.ClaimRefreshToken(c.Request.Context(), cookieRefToken, nil)
 	if err != nil {
 		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	if claim.Status == services.RefreshTokenMissing {
+		// fall back to the persisted session, e.g. after a redis flush
+		session, err := ctrl.sessionService.GetActiveSessionByHash(c.Request.Context(), hashedToken)
+		if err != nil {
+			if ctrl.authService.HandleRefreshTokenReuse(c.Request.Context(), hashedToken, c.ClientIP(), c.Request.UserAgent()) {
+				c.SetCookie(constants.COOKIE_REFRESH_TOKEN, "", -1, "/", "", false, false)
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token reuse detected"})
+				return
+			}
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		if session.ClientId != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		claim, err = ctrl.authService.ClaimRefreshToken(c.Request.Context(), cookieRefToken, &services.RefreshTokenData{
+			HashedToken: session.Hash,
+			UserId:      session.UserId.String(),
+			Jti:         session.Jti.String(),
+		})
+		if err != nil {
+			log.Println(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+			return
+		}
+		deviceId = session.DeviceId
+	}
+
+	switch claim.Status {
+	case services.RefreshTokenRotated:
+		// a concurrent request already rotated this token within the grace period
+		c.SetCookie(constants.COOKIE_REFRESH_TOKEN, claim.Rotated.RefreshToken, 3600*24*365, "/", "", os.Getenv("GO_ENV") == "production", true)
+		c.JSON(http.StatusOK, gin.H{"token": claim.Rotated.AccessToken})
+		return
+	case services.RefreshTokenRotationPending:
+		c.JSON(http.StatusConflict, gin.H{"error": "Refresh token rotation in progress"})
+		return
+	case services.RefreshTokenMissing:
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
+	data := claim.Data
+	// tokens of OAuth clients are refreshed at the token endpoint, here they
+	// would turn into first party tokens with the user's permissions
+	if data.ClientId != "" {
+		ctrl.authService.ReleaseRefreshTokenClaim(hashedToken)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if deviceId == uuid.Nil {
+		deviceId = getDeviceId(c)
+	}
+
 	userId, err := uuid.Parse(data.UserId)
 	if err != nil {
+		ctrl.authService.ReleaseRefreshTokenClaim(hashedToken)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	oldJti, err := uuid.Parse(data.Jti)
 	if err != nil {
+		ctrl.authService.ReleaseRefreshTokenClaim(hashedToken)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	user, err := ctrl.userService.GetUserById(c.Request.Context(), userId)
 	if err != nil {
+		ctrl.authService.ReleaseRefreshTokenClaim(hashedToken)
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
+	if accountLocked(c, user) {
+		ctrl.authService.ReleaseRefreshTokenClaim(hashedToken)
+		return
+	}
 
-	authToken, err := ctrl.authService.CreateAuthTokens(services.CreateAuthTokenParams{
+	authToken, err := ctrl.authService.CreateAuthTokens(c.Request.Context(), services.CreateAuthTokenParams{
 		UserId:      userId,
 		JwtVersion:  user.JwtVersion,
+		DeviceId:    deviceId,
+		UserAgent:   c.Request.UserAgent(),
+		IpAddress:   c.ClientIP(),
 		OldRefToken: &cookieRefToken,
 		OldTokenJti: &oldJti,
 	})
+	if errors.Is(err, repositories.ErrSessionNotActive) {
+		ctrl.authService.ReleaseRefreshTokenClaim(hashedToken)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
 	if err != nil {
 		log.Println(err.Error())
+		ctrl.authService.ReleaseRefreshTokenClaim(hashedToken)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	ctrl.authService.CompleteRefreshTokenRotation(cookieRefToken, authToken)
+
 	c.SetCookie(constants.COOKIE_REFRESH_TOKEN, authToken.RefreshToken, 3600*24*365, "/", "", os.Getenv("GO_ENV") == "production", true)
 
 	c.JSON(http.StatusOK, gin.H{"token": authToken.AccessToken})
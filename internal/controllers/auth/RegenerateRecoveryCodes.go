@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegenerateRecoveryCodes replaces every recovery code, used or not.
+func (ctrl *authController) RegenerateRecoveryCodes(c *gin.Context) {
+	_, userId, ok := getTokenPayload(c)
+	if !ok {
+		return
+	}
+	if !ctrl.reauthenticateMfa(c, userId) {
+		return
+	}
+
+	recoveryCodes, err := ctrl.mfaService.RegenerateRecoveryCodes(c.Request.Context(), userId)
+	if err != nil {
+		respondMfaError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": recoveryCodes})
+}
@@ -5,11 +5,13 @@ import (
 	"log"
 	"my-go-api/internal/constants"
 	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
 	"my-go-api/internal/repositories"
 	"my-go-api/internal/services"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func (ctrl *authController) Register(c *gin.Context) {
@@ -44,36 +46,44 @@ func (ctrl *authController) Register(c *gin.Context) {
 		return
 	}
 
-	user, err := ctrl.userService.Store(c.Request.Context(), repositories.CreateOneParams{
-		Name:       body.Name,
-		Username:   body.Username,
-		Email:      body.Email,
-		Password:   hashedPassword,
-		JWTVersion: jwtVersion,
-	})
+	// the verification email is queued with the user, so it goes out exactly
+	// when the account exists
+	userId := uuid.New()
+	data, err := ctrl.authService.CreateVerificationToken(userId)
 	if err != nil {
 		c.JSON(http.StatusConflict, gin.H{"errors": err.Error()})
 		return
 	}
-
-	data, err := ctrl.authService.CreateVerificationToken(user.ID)
+	verificationEmail, err := ctrl.emailService.VerificationEmail(services.SendEmailVerificationParams{
+		Name:   body.Username,
+		Email:  body.Email,
+		Code:   data.Code,
+		Locale: emailLocale(c, &models.User{Locale: body.Locale}),
+	})
 	if err != nil {
-		c.JSON(http.StatusConflict, gin.H{"errors": err.Error()})
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
 		return
 	}
 
-	if err := ctrl.emailService.SendVerificationEmail(
-		services.SendEmailVerificationParams{
-			Name:  user.Username,
-			Email: user.Email,
-			Code:  data.Code,
-		},
-	); err != nil {
-		log.Println(err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
+	user, err := ctrl.userService.Store(c.Request.Context(), repositories.CreateOneParams{
+		Id:              userId,
+		Name:            body.Name,
+		Username:        body.Username,
+		Email:           body.Email,
+		Password:        hashedPassword,
+		JWTVersion:      jwtVersion,
+		Locale:          body.Locale,
+		OutboxEmail:     &verificationEmail,
+		LinkCredentials: true,
+	})
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"errors": err.Error()})
 		return
 	}
 
+	ctrl.auditSelf(c, models.AuditEventRegister, models.AuditOutcomeSuccess, user.ID, nil)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"token":   data.RawToken,
 		"message": fmt.Sprintf("An email has been sent to %s. Please follow the instruction to verify your account.", user.Email)},
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *authController) RegisterPasskey(c *gin.Context) {
+	_, userId, ok := getTokenPayload(c)
+	if !ok {
+		return
+	}
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.RegisterPasskey)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	user, err := ctrl.userService.GetUserById(c.Request.Context(), userId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	passkey, err := ctrl.webauthnService.FinishRegistration(c.Request.Context(), user, body.SessionId, body.Name, body.Credential)
+	if err != nil {
+		respondWebauthnError(c, err)
+		return
+	}
+
+	ctrl.auditSelf(c, models.AuditEventPasskeyAdd, models.AuditOutcomeSuccess, userId, map[string]any{"passkey_id": passkey.ID})
+	c.JSON(http.StatusCreated, gin.H{"passkey": passkey})
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"fmt"
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestMagicLink emails a one-time code and a sign in link. Unknown and
+// unverified emails get a request too, one that can never sign in, so the
+// response and the work behind it do not tell which emails are registered;
+// the only difference is queueing the email in the outbox.
+func (ctrl *authController) RequestMagicLink(c *gin.Context) {
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.MagicLink)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+	message := fmt.Sprintf("If %s belongs to a verified account, an email with a sign in code and link has been sent.", body.Email)
+
+	user, err := ctrl.userService.GetUserByEmail(c.Request.Context(), body.Email)
+	known := err == nil && user.IsVerified
+	userId := uuid.Nil
+	if known {
+		userId = user.ID
+	}
+
+	data, err := ctrl.authService.CreateMagicLinkToken(userId)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	if known {
+		if err := ctrl.emailService.SendMagicLinkEmail(services.SendMagicLinkParams{
+			Name:   user.Username,
+			Email:  user.Email,
+			Code:   data.Code,
+			Token:  data.LinkToken,
+			Locale: emailLocale(c, user),
+		}); err != nil {
+			log.Println(err.Error())
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": data.RawToken, "message": message})
+}
@@ -39,9 +39,10 @@ func (ctrl *authController) ResendVerification(c *gin.Context) {
 	}
 	if err := ctrl.emailService.SendVerificationEmail(
 		services.SendEmailVerificationParams{
-			Name:  user.Username,
-			Email: user.Email,
-			Code:  data.Code,
+			Name:   user.Username,
+			Email:  user.Email,
+			Code:   data.Code,
+			Locale: emailLocale(c, user),
 		},
 	); err != nil {
 		log.Println(err.Error())
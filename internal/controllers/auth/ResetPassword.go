@@ -4,6 +4,8 @@ import (
 	"log"
 	"my-go-api/internal/constants"
 	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -24,6 +26,11 @@ func (ctrl *authController) ResetPassword(c *gin.Context) {
 
 	data, err := ctrl.redisService.GetPasswordResetToken(ctrl.utils.HashWithSHA256(body.Token))
 	if err != nil {
+		ctrl.auditService.Record(c.Request.Context(), services.AuditParams{
+			Type:    models.AuditEventPasswordReset,
+			Outcome: models.AuditOutcomeFailure,
+			Details: map[string]any{"reason": "invalid_token"},
+		})
 		log.Println("token not found in redis")
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
@@ -57,8 +64,10 @@ func (ctrl *authController) ResetPassword(c *gin.Context) {
 		return
 	}
 
+	hadPassword := user.Password != ""
 	user.JwtVersion = nv
 	user.Password = newPassword
+	user.PasswordResetRequired = false
 
 	if _, err := ctrl.userService.UpdateUser(c.Request.Context(), user); err != nil {
 		log.Println("failed to update user jwt_version")
@@ -66,12 +75,23 @@ func (ctrl *authController) ResetPassword(c *gin.Context) {
 		return
 	}
 
+	// The reset link proves ownership of the email, so an account created
+	// through a social login gains password sign in here as well.
+	if !hadPassword {
+		if _, err := ctrl.identityService.LinkCredentials(c.Request.Context(), user); err != nil {
+			log.Println("failed to link credentials identity")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	if err := ctrl.redisService.DeletePasswordResetToken(ctrl.utils.HashWithSHA256(body.Token)); err != nil {
 		log.Println("failed to delete pwd reset token from redis")
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
+	ctrl.auditSelf(c, models.AuditEventPasswordReset, models.AuditOutcomeSuccess, user.ID, nil)
 	c.JSON(http.StatusOK, gin.H{"message": "Reset password is successful"})
 
 }
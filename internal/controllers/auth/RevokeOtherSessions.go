@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"log"
+	"my-go-api/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (ctrl *authController) RevokeOtherSessions(c *gin.Context) {
+	tokenPayload, userId, ok := getTokenPayload(c)
+	if !ok {
+		return
+	}
+
+	currentJti, err := uuid.Parse(tokenPayload.Jti)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	sessions, err := ctrl.sessionService.RevokeOtherSessions(c.Request.Context(), userId, currentJti)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	ctrl.authService.RevokeSessionTokens(sessions)
+	ctrl.auditSelf(c, models.AuditEventSessionRevoke, models.AuditOutcomeSuccess, userId, map[string]any{"revoked": len(sessions), "keep_current": true})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Other sessions revoked",
+		"revoked": len(sessions),
+	})
+}
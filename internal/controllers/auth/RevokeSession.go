@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"my-go-api/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *authController) RevokeSession(c *gin.Context) {
+	_, userId, ok := getTokenPayload(c)
+	if !ok {
+		return
+	}
+
+	sessionId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session id"})
+		return
+	}
+
+	session, err := ctrl.sessionService.RevokeSessionById(c.Request.Context(), userId, sessionId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	ctrl.authService.RevokeSessionTokens([]models.Token{*session})
+	ctrl.auditSelf(c, models.AuditEventSessionRevoke, models.AuditOutcomeSuccess, userId, map[string]any{"session_id": sessionId})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
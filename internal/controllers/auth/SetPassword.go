@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetPassword lets an account created through a social login add password
+// sign in. Accounts that already have a password use the reset flow.
+func (ctrl *authController) SetPassword(c *gin.Context) {
+	_, userId, ok := getTokenPayload(c)
+	if !ok {
+		return
+	}
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.SetPassword)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	user, err := ctrl.userService.GetUserById(c.Request.Context(), userId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	if user.Password != "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "password is already set"})
+		return
+	}
+
+	hashedPassword, err := ctrl.passwordService.Hash(body.Password)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to hash password"})
+		return
+	}
+	user.Password = hashedPassword
+	if _, err := ctrl.userService.UpdateUser(c.Request.Context(), user); err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	identity, err := ctrl.identityService.LinkCredentials(c.Request.Context(), user)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	ctrl.auditSelf(c, models.AuditEventPasswordSet, models.AuditOutcomeSuccess, user.ID, nil)
+	c.JSON(http.StatusOK, gin.H{"identity": identity})
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *authController) UnlinkIdentity(c *gin.Context) {
+	_, userId, ok := getTokenPayload(c)
+	if !ok {
+		return
+	}
+
+	identityId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid identity id"})
+		return
+	}
+
+	identity, err := ctrl.identityService.UnlinkIdentity(c.Request.Context(), userId, identityId)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Identity not found"})
+		case errors.Is(err, repositories.ErrLastIdentity):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Println(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		}
+		return
+	}
+
+	ctrl.auditSelf(c, models.AuditEventIdentityUnlink, models.AuditOutcomeSuccess, userId, map[string]any{"provider": identity.Provider})
+	c.JSON(http.StatusOK, gin.H{"message": "Identity unlinked"})
+}
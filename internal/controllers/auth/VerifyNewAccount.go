@@ -4,6 +4,7 @@ import (
 	"log"
 	"my-go-api/internal/constants"
 	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
 	"my-go-api/internal/services"
 	"net/http"
 	"os"
@@ -34,6 +35,11 @@ func (ctrl *authController) VerifyNewAccount(c *gin.Context) {
 			Code:     body.Code,
 		})
 	if err != nil {
+		ctrl.auditService.Record(c.Request.Context(), services.AuditParams{
+			Type:    models.AuditEventVerifyAccount,
+			Outcome: models.AuditOutcomeFailure,
+			Details: map[string]any{"reason": err.Error()},
+		})
 		log.Println("Verify account token and code failure")
 		c.JSON(
 			http.StatusInternalServerError,
@@ -82,9 +88,14 @@ func (ctrl *authController) VerifyNewAccount(c *gin.Context) {
 		return
 	}
 
-	authToken, err := ctrl.authService.CreateAuthTokens(services.CreateAuthTokenParams{
+	ctrl.auditSelf(c, models.AuditEventVerifyAccount, models.AuditOutcomeSuccess, user.ID, nil)
+
+	authToken, err := ctrl.authService.CreateAuthTokens(c.Request.Context(), services.CreateAuthTokenParams{
 		UserId:     user.ID,
 		JwtVersion: user.JwtVersion,
+		DeviceId:   getDeviceId(c),
+		UserAgent:  c.Request.UserAgent(),
+		IpAddress:  c.ClientIP(),
 	})
 	if err != nil {
 		c.JSON(
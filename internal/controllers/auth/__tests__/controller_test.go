@@ -0,0 +1,73 @@
+package auth_test
+
+import (
+	"my-go-api/internal/controllers/auth"
+	mockutils "my-go-api/mocks"
+	mockservices "my-go-api/mocks/mock_services"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+)
+
+// authMocks holds every dependency of the auth controller.
+type authMocks struct {
+	passwordService *mockservices.MockIPasswordService
+	authService     *mockservices.MockIAuthService
+	userService     *mockservices.MockIUserService
+	emailService    *mockservices.MockIEmailService
+	redisService    *mockservices.MockIRedisService
+	sessionService  *mockservices.MockISessionService
+	oauthService    *mockservices.MockIOAuthService
+	identityService *mockservices.MockIIdentityService
+	mfaService      *mockservices.MockIMfaService
+	webauthnService *mockservices.MockIWebauthnService
+	loginProtection *mockservices.MockILoginProtectionService
+	auditService    *mockservices.MockIAuditService
+	utils           *mockutils.MockIUtils
+}
+
+// newAuthController builds the auth controller on fresh mocks. It accepts any
+// audit event; tests about the audit log use newAuditedAuthController and set
+// their own expectations instead.
+func newAuthController(t *testing.T) (auth.IAuthController, authMocks) {
+	controller, mocks := newAuditedAuthController(t)
+	mocks.auditService.EXPECT().Record(gomock.Any(), gomock.Any()).AnyTimes()
+	return controller, mocks
+}
+
+func newAuditedAuthController(t *testing.T) (auth.IAuthController, authMocks) {
+	ctrl := gomock.NewController(t)
+	mocks := authMocks{
+		passwordService: mockservices.NewMockIPasswordService(ctrl),
+		authService:     mockservices.NewMockIAuthService(ctrl),
+		userService:     mockservices.NewMockIUserService(ctrl),
+		emailService:    mockservices.NewMockIEmailService(ctrl),
+		redisService:    mockservices.NewMockIRedisService(ctrl),
+		sessionService:  mockservices.NewMockISessionService(ctrl),
+		oauthService:    mockservices.NewMockIOAuthService(ctrl),
+		identityService: mockservices.NewMockIIdentityService(ctrl),
+		mfaService:      mockservices.NewMockIMfaService(ctrl),
+		webauthnService: mockservices.NewMockIWebauthnService(ctrl),
+		loginProtection: mockservices.NewMockILoginProtectionService(ctrl),
+		auditService:    mockservices.NewMockIAuditService(ctrl),
+		utils:           mockutils.NewMockIUtils(ctrl),
+	}
+	controller := auth.NewAuthController(
+		mocks.passwordService,
+		mocks.authService,
+		mocks.userService,
+		mocks.emailService,
+		mocks.redisService,
+		mocks.sessionService,
+		mocks.oauthService,
+		mocks.identityService,
+		mocks.mfaService,
+		mocks.webauthnService,
+		mocks.loginProtection,
+		mocks.auditService,
+		mocks.utils,
+	)
+	gin.SetMode(gin.TestMode)
+	return controller, mocks
+}
@@ -0,0 +1,102 @@
+package auth_test
+
+import (
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func newIdentityContext(method, target string, userId uuid.UUID) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, target, nil)
+	c.Set(constants.ACCESS_TOKEN_PAYLOAD, services.JWTPayload{UserId: userId.String(), Jti: uuid.NewString()})
+	return c, w
+}
+
+func TestUnlinkIdentity_RefusesLastIdentity(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	userId := uuid.New()
+
+	mocks.identityService.EXPECT().UnlinkIdentity(gomock.Any(), userId, 4).Return(nil, repositories.ErrLastIdentity)
+
+	c, w := newIdentityContext(http.MethodDelete, "/auth/identities/4", userId)
+	c.Params = gin.Params{{Key: "id", Value: "4"}}
+	controller.UnlinkIdentity(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestUnlinkIdentity_Success(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	userId := uuid.New()
+
+	mocks.identityService.EXPECT().UnlinkIdentity(gomock.Any(), userId, 4).
+		Return(&models.UserIdentity{ID: 4, Provider: "github"}, nil)
+
+	c, w := newIdentityContext(http.MethodDelete, "/auth/identities/4", userId)
+	c.Params = gin.Params{{Key: "id", Value: "4"}}
+	controller.UnlinkIdentity(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestLinkIdentity_ReturnsProviderURL(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	userId := uuid.New()
+
+	mocks.oauthService.EXPECT().LinkCodeURL(gomock.Any(), "github", userId).
+		Return(services.OAuthFlow{URL: "https://github.test/authorize", Binding: "binding-1"}, nil)
+
+	c, w := newIdentityContext(http.MethodPost, "/auth/identities/github", userId)
+	c.Params = gin.Params{{Key: "provider", Value: "github"}}
+	controller.LinkIdentity(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"url":"https://github.test/authorize"}`, w.Body.String())
+	cookie := w.Result().Cookies()[0]
+	assert.Equal(t, constants.COOKIE_OAUTH_BINDING, cookie.Name)
+	assert.Equal(t, "binding-1", cookie.Value)
+}
+
+func TestSetPassword_AddsCredentialsIdentity(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	user := &models.User{ID: uuid.New(), Email: "ari@mail.com", Provider: "google"}
+
+	mocks.userService.EXPECT().GetUserById(gomock.Any(), user.ID).Return(user, nil)
+	mocks.passwordService.EXPECT().Hash("Secret123").Return("hashed", nil)
+	mocks.userService.EXPECT().UpdateUser(gomock.Any(), gomock.Cond(func(u *models.User) bool {
+		return u.Password == "hashed"
+	})).Return(user, nil)
+	mocks.identityService.EXPECT().LinkCredentials(gomock.Any(), user).
+		Return(&models.UserIdentity{Provider: models.IdentityProviderCredentials}, nil)
+
+	c, w := newIdentityContext(http.MethodPost, "/auth/identities/password", user.ID)
+	c.Set(constants.VALIDATED_BODY, dto.SetPassword{Password: "Secret123"})
+	controller.SetPassword(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestSetPassword_RefusesWhenPasswordExists(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	user := &models.User{ID: uuid.New(), Password: "existing"}
+
+	mocks.userService.EXPECT().GetUserById(gomock.Any(), user.ID).Return(user, nil)
+
+	c, w := newIdentityContext(http.MethodPost, "/auth/identities/password", user.ID)
+	c.Set(constants.VALIDATED_BODY, dto.SetPassword{Password: "Secret123"})
+	controller.SetPassword(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
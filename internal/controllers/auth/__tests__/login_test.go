@@ -1,13 +1,11 @@
 package auth_test
 
 import (
+	"database/sql"
 	"errors"
-	"my-go-api/internal/controllers/auth"
 	"my-go-api/internal/dto"
 	"my-go-api/internal/models"
 	"my-go-api/internal/services"
-	mockutils "my-go-api/mocks"
-	mockservices "my-go-api/mocks/mock_services"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -20,23 +18,7 @@ import (
 )
 
 func TestLogin_Success(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-	mockUserService := mockservices.NewMockIUserService(ctrl)
-	mockAuthService := mockservices.NewMockIAuthService(ctrl)
-	mockEmailService := mockservices.NewMockIEmailService(ctrl)
-	mockPasswordService := mockservices.NewMockIPasswordService(ctrl)
-	mockRedisService := mockservices.NewMockIRedisService(ctrl)
-	mockUtils := mockutils.NewMockIUtils(ctrl)
-	controller := auth.NewAuthController(
-		mockPasswordService,
-		mockAuthService,
-		mockUserService,
-		mockEmailService,
-		mockRedisService,
-		mockUtils,
-	)
-	gin.SetMode(gin.TestMode)
+	controller, mocks := newAuditedAuthController(t)
 	// Simulate validated body middleware
 	body := dto.Login{
 		Identity: "ari@mail.com",
@@ -60,12 +42,23 @@ func TestLogin_Success(t *testing.T) {
 		RefreshToken: "refresh-token",
 	}
 	// Set expectations
-	mockUserService.EXPECT().GetUserByIdentity(gomock.Any(), "ari@mail.com").Return(&user, nil)
-	mockPasswordService.EXPECT().Verify("hashed-password", "password123").Return(nil)
-	mockAuthService.EXPECT().CreateAuthTokens(services.CreateAuthTokenParams{
-		UserId:     user.ID,
-		JwtVersion: "v1",
-	}).Return(authTokens, nil)
+	mocks.userService.EXPECT().GetUserByIdentity(gomock.Any(), "ari@mail.com").Return(&user, nil)
+	mocks.loginProtection.EXPECT().Check(gomock.Any(), "user:"+user.ID.String(), gomock.Any()).Return(time.Duration(0), nil)
+	mocks.passwordService.EXPECT().Verify("hashed-password", "password123").Return(nil)
+	mocks.loginProtection.EXPECT().RecordSuccess(gomock.Any(), "user:"+user.ID.String()).Return(nil)
+	mocks.mfaService.EXPECT().IsEnabled(gomock.Any(), user.ID).Return(false, nil)
+	mocks.webauthnService.EXPECT().HasCredentials(gomock.Any(), user.ID).Return(false, nil)
+	mocks.authService.EXPECT().CreateAuthTokens(gomock.Any(), gomock.Cond(func(x any) bool {
+		params, ok := x.(services.CreateAuthTokenParams)
+		return ok && params.UserId == user.ID && params.JwtVersion == "v1" && params.DeviceId != uuid.Nil
+	})).Return(authTokens, nil)
+	mocks.auditService.EXPECT().Record(gomock.Any(), services.AuditParams{
+		Type:     models.AuditEventLogin,
+		Outcome:  models.AuditOutcomeSuccess,
+		ActorId:  user.ID,
+		TargetId: user.ID,
+		Details:  map[string]any{"method": "password"},
+	})
 	// Setup Gin context
 	req := httptest.NewRequest(http.MethodPost, "/login", nil)
 	w := httptest.NewRecorder()
@@ -81,33 +74,24 @@ func TestLogin_Success(t *testing.T) {
 }
 
 func TestLogin_UserNotFound(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockUserService := mockservices.NewMockIUserService(ctrl)
-	mockAuthService := mockservices.NewMockIAuthService(ctrl)
-	mockEmailService := mockservices.NewMockIEmailService(ctrl)
-	mockPasswordService := mockservices.NewMockIPasswordService(ctrl)
-	mockRedisService := mockservices.NewMockIRedisService(ctrl)
-	mockUtils := mockutils.NewMockIUtils(ctrl)
-
-	controller := auth.NewAuthController(
-		mockPasswordService,
-		mockAuthService,
-		mockUserService,
-		mockEmailService,
-		mockRedisService,
-		mockUtils,
-	)
-
-	gin.SetMode(gin.TestMode)
+	controller, mocks := newAuditedAuthController(t)
 
 	body := dto.Login{
 		Identity: "ari@mail.com",
 		Password: "password123",
 	}
 
-	mockUserService.EXPECT().GetUserByIdentity(gomock.Any(), "ari@mail.com").Return(models.User{}, errors.New("not found"))
+	// unknown identities are answered like wrong passwords
+	mocks.userService.EXPECT().GetUserByIdentity(gomock.Any(), "ari@mail.com").Return(nil, sql.ErrNoRows)
+	mocks.loginProtection.EXPECT().Check(gomock.Any(), "identity:ari@mail.com", gomock.Any()).Return(time.Duration(0), nil)
+	mocks.passwordService.EXPECT().Verify(services.DummyPasswordHash, "password123").Return(errors.New("mismatch"))
+	mocks.loginProtection.EXPECT().RecordFailure(gomock.Any(), nil, "identity:ari@mail.com", gomock.Any(), gomock.Any()).Return(nil)
+	// without an account the failure is only known by the identity tried
+	mocks.auditService.EXPECT().Record(gomock.Any(), services.AuditParams{
+		Type:    models.AuditEventLogin,
+		Outcome: models.AuditOutcomeFailure,
+		Details: map[string]any{"method": "password", "identity": "ari@mail.com", "reason": "invalid_credentials"},
+	})
 
 	req := httptest.NewRequest(http.MethodPost, "/login", nil)
 	w := httptest.NewRecorder()
@@ -117,6 +101,43 @@ func TestLogin_UserNotFound(t *testing.T) {
 
 	controller.Login(c)
 
-	assert.Equal(t, http.StatusNotFound, w.Code)
-	assert.Contains(t, w.Body.String(), "not found")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid credentials")
+}
+
+func TestLogin_WrongPasswordCountsFailure(t *testing.T) {
+	controller, mocks := newAuthController(t)
+
+	user := &models.User{ID: uuid.New(), Password: "hashed-password", IsVerified: true}
+	subject := "user:" + user.ID.String()
+	mocks.userService.EXPECT().GetUserByIdentity(gomock.Any(), "ari00").Return(user, nil)
+	mocks.loginProtection.EXPECT().Check(gomock.Any(), subject, gomock.Any()).Return(time.Duration(0), nil)
+	mocks.passwordService.EXPECT().Verify("hashed-password", "guess").Return(errors.New("mismatch"))
+	mocks.loginProtection.EXPECT().RecordFailure(gomock.Any(), user, subject, gomock.Any(), gomock.Any()).Return(nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/login", nil)
+	c.Set("validatedBody", dto.Login{Identity: "ari00", Password: "guess"})
+	controller.Login(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid credentials")
+}
+
+func TestLogin_ThrottledBeforePasswordCheck(t *testing.T) {
+	controller, mocks := newAuthController(t)
+
+	user := &models.User{ID: uuid.New(), Password: "hashed-password", IsVerified: true}
+	mocks.userService.EXPECT().GetUserByIdentity(gomock.Any(), "ari00").Return(user, nil)
+	mocks.loginProtection.EXPECT().Check(gomock.Any(), "user:"+user.ID.String(), gomock.Any()).Return(1500*time.Millisecond, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/login", nil)
+	c.Set("validatedBody", dto.Login{Identity: "ari00", Password: "password123"})
+	controller.Login(c)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
 }
@@ -0,0 +1,107 @@
+package auth_test
+
+import (
+	"database/sql"
+	"errors"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRequestMagicLink_SendsEmail(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	user := &models.User{ID: uuid.New(), Username: "ari00", Email: "ari@mail.com", IsVerified: true}
+
+	mocks.userService.EXPECT().GetUserByEmail(gomock.Any(), "ari@mail.com").Return(user, nil)
+	mocks.authService.EXPECT().CreateMagicLinkToken(user.ID).
+		Return(services.MagicLinkTokenData{RawToken: "raw", LinkToken: "emailed-link-token", Code: "a1b2c3d4"}, nil)
+	mocks.emailService.EXPECT().SendMagicLinkEmail(services.SendMagicLinkParams{
+		Name:  "ari00",
+		Email: "ari@mail.com",
+		Code:  "a1b2c3d4",
+		Token: "emailed-link-token",
+	}).Return(nil)
+
+	c, w := newBodyContext(dto.MagicLink{Email: "ari@mail.com"})
+	controller.RequestMagicLink(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"token":"raw"`)
+	assert.NotContains(t, w.Body.String(), "emailed-link-token")
+}
+
+func TestRequestMagicLink_UnknownEmailLooksTheSame(t *testing.T) {
+	controller, mocks := newAuthController(t)
+
+	mocks.userService.EXPECT().GetUserByEmail(gomock.Any(), "nobody@mail.com").Return(nil, sql.ErrNoRows)
+	mocks.authService.EXPECT().CreateMagicLinkToken(uuid.Nil).
+		Return(services.MagicLinkTokenData{RawToken: "decoy", LinkToken: "unused", Code: "a1b2c3d4"}, nil)
+
+	c, w := newBodyContext(dto.MagicLink{Email: "nobody@mail.com"})
+	controller.RequestMagicLink(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"token":"decoy"`)
+}
+
+func TestRequestMagicLink_EmailFailureLooksTheSame(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	user := &models.User{ID: uuid.New(), Username: "ari00", Email: "ari@mail.com", IsVerified: true}
+
+	mocks.userService.EXPECT().GetUserByEmail(gomock.Any(), "ari@mail.com").Return(user, nil)
+	mocks.authService.EXPECT().CreateMagicLinkToken(user.ID).
+		Return(services.MagicLinkTokenData{RawToken: "raw", LinkToken: "emailed-link-token", Code: "a1b2c3d4"}, nil)
+	mocks.emailService.EXPECT().SendMagicLinkEmail(gomock.Any()).Return(errors.New("outbox down"))
+
+	c, w := newBodyContext(dto.MagicLink{Email: "ari@mail.com"})
+	controller.RequestMagicLink(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"token":"raw"`)
+}
+
+func TestRedeemMagicLinkCode_SignsIn(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	user := &models.User{ID: uuid.New(), Email: "ari@mail.com", IsVerified: true}
+
+	mocks.authService.EXPECT().RedeemMagicLinkCode("raw", "a1b2c3d4").Return(user.ID, nil)
+	mocks.userService.EXPECT().GetUserById(gomock.Any(), user.ID).Return(user, nil)
+	expectTokens(mocks, user.ID)
+
+	c, w := newBodyContext(dto.MagicLinkCode{Token: "raw", Code: "a1b2c3d4"})
+	controller.RedeemMagicLinkCode(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"token":"access"`)
+}
+
+func TestRedeemMagicLinkCode_RequiresPasswordReset(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	user := &models.User{ID: uuid.New(), Email: "ari@mail.com", IsVerified: true, PasswordResetRequired: true}
+
+	mocks.authService.EXPECT().RedeemMagicLinkCode("raw", "a1b2c3d4").Return(user.ID, nil)
+	mocks.userService.EXPECT().GetUserById(gomock.Any(), user.ID).Return(user, nil)
+
+	c, w := newBodyContext(dto.MagicLinkCode{Token: "raw", Code: "a1b2c3d4"})
+	controller.RedeemMagicLinkCode(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Password reset required")
+}
+
+func TestRedeemMagicLink_Invalid(t *testing.T) {
+	controller, mocks := newAuthController(t)
+
+	mocks.authService.EXPECT().RedeemMagicLink("used").Return(uuid.Nil, services.ErrInvalidMagicLink)
+
+	c, w := newBodyContext(dto.MagicLinkVerify{Token: "used"})
+	controller.RedeemMagicLink(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
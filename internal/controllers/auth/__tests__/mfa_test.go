@@ -0,0 +1,134 @@
+package auth_test
+
+import (
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func newBodyContext(body any) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	c.Set(constants.VALIDATED_BODY, body)
+	return c, w
+}
+
+func TestLogin_MfaEnabledReturnsChallenge(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	user := &models.User{ID: uuid.New(), Email: "ari@mail.com", Password: "hashed", IsVerified: true}
+
+	mocks.userService.EXPECT().GetUserByIdentity(gomock.Any(), "ari@mail.com").Return(user, nil)
+	mocks.loginProtection.EXPECT().Check(gomock.Any(), gomock.Any(), gomock.Any()).Return(time.Duration(0), nil)
+	mocks.passwordService.EXPECT().Verify("hashed", "password123").Return(nil)
+	mocks.loginProtection.EXPECT().RecordSuccess(gomock.Any(), gomock.Any()).Return(nil)
+	mocks.mfaService.EXPECT().IsEnabled(gomock.Any(), user.ID).Return(true, nil)
+	mocks.webauthnService.EXPECT().HasCredentials(gomock.Any(), user.ID).Return(true, nil)
+	mocks.mfaService.EXPECT().CreateChallenge(user.ID).Return("mfa-token", nil)
+
+	c, w := newBodyContext(dto.Login{Identity: "ari@mail.com", Password: "password123"})
+	controller.Login(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"mfa_token":"mfa-token"`)
+	assert.Contains(t, w.Body.String(), `"mfa_methods":["totp","recovery_code","webauthn"]`)
+	assert.NotContains(t, w.Body.String(), `"token"`)
+}
+
+func TestPasskeyLogin_RequiresPasswordReset(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	user := &models.User{ID: uuid.New(), Email: "ari@mail.com", IsVerified: true, PasswordResetRequired: true}
+
+	mocks.webauthnService.EXPECT().FinishLogin(gomock.Any(), "session-1", gomock.Any()).Return(user.ID, nil)
+	mocks.userService.EXPECT().GetUserById(gomock.Any(), user.ID).Return(user, nil)
+
+	c, w := newBodyContext(dto.PasskeyLogin{SessionId: "session-1", Credential: []byte(`{}`)})
+	controller.PasskeyLogin(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Password reset required")
+}
+
+func TestMfaVerify_Success(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	user := &models.User{ID: uuid.New(), Email: "ari@mail.com", IsVerified: true}
+
+	mocks.mfaService.EXPECT().VerifyChallenge(gomock.Any(), "mfa-token", "123456").Return(user.ID, nil)
+	mocks.userService.EXPECT().GetUserById(gomock.Any(), user.ID).Return(user, nil)
+	mocks.authService.EXPECT().CreateAuthTokens(gomock.Any(), gomock.Any()).
+		Return(services.CreateAuthTokensResult{AccessToken: "access", RefreshToken: "refresh"}, nil)
+
+	c, w := newBodyContext(dto.MfaVerify{MfaToken: "mfa-token", Code: "123456"})
+	controller.MfaVerify(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"token":"access"`)
+}
+
+func TestMfaVerify_InvalidCode(t *testing.T) {
+	controller, mocks := newAuthController(t)
+
+	mocks.mfaService.EXPECT().VerifyChallenge(gomock.Any(), "mfa-token", "000000").Return(uuid.Nil, services.ErrInvalidMfaCode)
+
+	c, w := newBodyContext(dto.MfaVerify{MfaToken: "mfa-token", Code: "000000"})
+	controller.MfaVerify(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestDisableTotp_RequiresPassword(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	user := &models.User{ID: uuid.New(), Password: "hashed"}
+
+	mocks.userService.EXPECT().GetUserById(gomock.Any(), user.ID).Return(user, nil)
+	mocks.passwordService.EXPECT().Verify("hashed", "wrong").Return(assert.AnError)
+
+	c, w := newBodyContext(dto.MfaReauth{Password: "wrong", Code: "123456"})
+	c.Set(constants.ACCESS_TOKEN_PAYLOAD, services.JWTPayload{UserId: user.ID.String()})
+	controller.DisableTotp(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestMfaWebauthnVerify_Success(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	user := &models.User{ID: uuid.New(), Email: "ari@mail.com", IsVerified: true}
+	credential := []byte(`{"id":"credential"}`)
+
+	mocks.mfaService.EXPECT().UseChallenge("mfa-token").Return(user.ID, nil)
+	mocks.webauthnService.EXPECT().FinishSecondFactor(gomock.Any(), user.ID, "session-1", gomock.Any()).Return(nil)
+	mocks.mfaService.EXPECT().CompleteChallenge("mfa-token").Return(nil)
+	mocks.userService.EXPECT().GetUserById(gomock.Any(), user.ID).Return(user, nil)
+	mocks.authService.EXPECT().CreateAuthTokens(gomock.Any(), gomock.Any()).
+		Return(services.CreateAuthTokensResult{AccessToken: "access", RefreshToken: "refresh"}, nil)
+
+	c, w := newBodyContext(dto.MfaWebauthn{MfaToken: "mfa-token", SessionId: "session-1", Credential: credential})
+	controller.MfaWebauthnVerify(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"token":"access"`)
+}
+
+func TestMfaWebauthnVerify_FailedAssertionKeepsChallenge(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	userId := uuid.New()
+
+	mocks.mfaService.EXPECT().UseChallenge("mfa-token").Return(userId, nil)
+	mocks.webauthnService.EXPECT().FinishSecondFactor(gomock.Any(), userId, "session-1", gomock.Any()).
+		Return(services.ErrWebauthnVerification)
+
+	c, w := newBodyContext(dto.MfaWebauthn{MfaToken: "mfa-token", SessionId: "session-1", Credential: []byte(`{}`)})
+	controller.MfaWebauthnVerify(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,297 @@
+package auth_test
+
+import (
+	"database/sql"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func newCallbackContext() (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/auth/oauth/google/callback?state=state-1&code=code-1", nil)
+	c.Request.AddCookie(&http.Cookie{Name: constants.COOKIE_OAUTH_BINDING, Value: "binding-1"})
+	c.Params = gin.Params{{Key: "provider", Value: "google"}}
+	return c, w
+}
+
+var googleProfile = services.OAuthProfile{
+	Provider:      "google",
+	Subject:       "google-subject-1",
+	Email:         "ari@mail.com",
+	EmailVerified: true,
+	Name:          "Ari",
+}
+
+var googleIdentityParams = func(userId uuid.UUID) repositories.CreateIdentityParams {
+	return repositories.CreateIdentityParams{
+		UserId:   userId,
+		Provider: "google",
+		Subject:  "google-subject-1",
+		Email:    "ari@mail.com",
+	}
+}
+
+func expectTokens(mocks authMocks, userId uuid.UUID) {
+	mocks.mfaService.EXPECT().IsEnabled(gomock.Any(), userId).Return(false, nil)
+	mocks.webauthnService.EXPECT().HasCredentials(gomock.Any(), userId).Return(false, nil)
+	mocks.authService.EXPECT().CreateAuthTokens(gomock.Any(), gomock.Cond(func(p services.CreateAuthTokenParams) bool {
+		return p.UserId == userId
+	})).Return(services.CreateAuthTokensResult{AccessToken: "access", RefreshToken: "refresh"}, nil)
+}
+
+func TestOAuthCallback_KnownIdentitySignsIn(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	user := &models.User{ID: uuid.New(), Email: "other@mail.com", IsVerified: true}
+
+	mocks.oauthService.EXPECT().Exchange(gomock.Any(), "google", "state-1", "code-1", "binding-1").
+		Return(services.OAuthExchangeResult{Profile: googleProfile}, nil)
+	mocks.identityService.EXPECT().GetIdentity(gomock.Any(), "google", "google-subject-1").
+		Return(&models.UserIdentity{UserId: user.ID, Provider: "google"}, nil)
+	mocks.userService.EXPECT().GetUserById(gomock.Any(), user.ID).Return(user, nil)
+	expectTokens(mocks, user.ID)
+
+	c, w := newCallbackContext()
+	controller.OAuthCallback(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"token":"access"`)
+}
+
+func TestOAuthCallback_PasskeyIsSecondFactor(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	user := &models.User{ID: uuid.New(), Email: "ari@mail.com", IsVerified: true}
+
+	mocks.oauthService.EXPECT().Exchange(gomock.Any(), "google", "state-1", "code-1", "binding-1").
+		Return(services.OAuthExchangeResult{Profile: googleProfile}, nil)
+	mocks.identityService.EXPECT().GetIdentity(gomock.Any(), "google", "google-subject-1").
+		Return(&models.UserIdentity{UserId: user.ID, Provider: "google"}, nil)
+	mocks.userService.EXPECT().GetUserById(gomock.Any(), user.ID).Return(user, nil)
+	// a registered passkey is asked for even without TOTP
+	mocks.mfaService.EXPECT().IsEnabled(gomock.Any(), user.ID).Return(false, nil)
+	mocks.webauthnService.EXPECT().HasCredentials(gomock.Any(), user.ID).Return(true, nil)
+	mocks.mfaService.EXPECT().CreateChallenge(user.ID).Return("mfa-token", nil)
+
+	c, w := newCallbackContext()
+	controller.OAuthCallback(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"mfa_methods":["webauthn"]`)
+	assert.NotContains(t, w.Body.String(), `"token"`)
+}
+
+func TestOAuthCallback_RequiresPasswordReset(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	user := &models.User{ID: uuid.New(), Email: "ari@mail.com", IsVerified: true, PasswordResetRequired: true}
+
+	mocks.oauthService.EXPECT().Exchange(gomock.Any(), "google", "state-1", "code-1", "binding-1").
+		Return(services.OAuthExchangeResult{Profile: googleProfile}, nil)
+	mocks.identityService.EXPECT().GetIdentity(gomock.Any(), "google", "google-subject-1").
+		Return(&models.UserIdentity{UserId: user.ID, Provider: "google"}, nil)
+	mocks.userService.EXPECT().GetUserById(gomock.Any(), user.ID).Return(user, nil)
+
+	c, w := newCallbackContext()
+	controller.OAuthCallback(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Password reset required")
+}
+
+func TestOAuthCallback_CreatesUser(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	created := &models.User{ID: uuid.New(), Email: "ari@mail.com", Provider: "google", IsVerified: true, JwtVersion: "jwt-version"}
+
+	mocks.oauthService.EXPECT().Exchange(gomock.Any(), "google", "state-1", "code-1", "binding-1").
+		Return(services.OAuthExchangeResult{Profile: googleProfile}, nil)
+	mocks.identityService.EXPECT().GetIdentity(gomock.Any(), "google", "google-subject-1").Return(nil, sql.ErrNoRows).Times(2)
+	mocks.userService.EXPECT().GetUserByEmail(gomock.Any(), "ari@mail.com").Return(nil, sql.ErrNoRows)
+	mocks.utils.EXPECT().GenerateRandomBytes(8).Return("jwt-version", nil)
+	mocks.utils.EXPECT().GenerateRandomBytes(3).Return("a1b2c3", nil)
+	mocks.userService.EXPECT().Store(gomock.Any(), repositories.CreateOneParams{
+		Name:       "Ari",
+		Username:   "ari_a1b2c3",
+		Email:      "ari@mail.com",
+		JWTVersion: "jwt-version",
+		Provider:   "google",
+		IsVerified: true,
+	}).Return(created, nil)
+	mocks.identityService.EXPECT().GetUserIdentities(gomock.Any(), created.ID).Return([]models.UserIdentity{}, nil)
+	mocks.identityService.EXPECT().LinkIdentity(gomock.Any(), googleIdentityParams(created.ID)).Return(&models.UserIdentity{}, nil)
+	expectTokens(mocks, created.ID)
+
+	c, w := newCallbackContext()
+	controller.OAuthCallback(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestOAuthCallback_AutoLinksVerifiedAccount(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	existing := &models.User{ID: uuid.New(), Email: "ari@mail.com", Password: "hashed", IsVerified: true}
+
+	mocks.oauthService.EXPECT().Exchange(gomock.Any(), "google", "state-1", "code-1", "binding-1").
+		Return(services.OAuthExchangeResult{Profile: googleProfile}, nil)
+	mocks.identityService.EXPECT().GetIdentity(gomock.Any(), "google", "google-subject-1").Return(nil, sql.ErrNoRows).Times(2)
+	mocks.userService.EXPECT().GetUserByEmail(gomock.Any(), "ari@mail.com").Return(existing, nil)
+	mocks.identityService.EXPECT().GetUserIdentities(gomock.Any(), existing.ID).
+		Return([]models.UserIdentity{{Provider: models.IdentityProviderCredentials}}, nil)
+	mocks.identityService.EXPECT().LinkIdentity(gomock.Any(), googleIdentityParams(existing.ID)).Return(&models.UserIdentity{}, nil)
+	expectTokens(mocks, existing.ID)
+
+	c, w := newCallbackContext()
+	controller.OAuthCallback(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "hashed", existing.Password)
+}
+
+func TestOAuthCallback_TakesOverUnverifiedAccount(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	existing := &models.User{ID: uuid.New(), Email: "ari@mail.com", Password: "hashed", JwtVersion: "old-version"}
+
+	mocks.oauthService.EXPECT().Exchange(gomock.Any(), "google", "state-1", "code-1", "binding-1").
+		Return(services.OAuthExchangeResult{Profile: googleProfile}, nil)
+	mocks.identityService.EXPECT().GetIdentity(gomock.Any(), "google", "google-subject-1").Return(nil, sql.ErrNoRows).Times(2)
+	mocks.userService.EXPECT().GetUserByEmail(gomock.Any(), "ari@mail.com").Return(existing, nil)
+	mocks.utils.EXPECT().GenerateRandomBytes(8).Return("new-version", nil)
+	mocks.identityService.EXPECT().RemoveCredentials(gomock.Any(), existing.ID).Return(nil)
+	mocks.userService.EXPECT().UpdateUser(gomock.Any(), gomock.Cond(func(u *models.User) bool {
+		return u.Password == "" && u.IsVerified && u.JwtVersion == "new-version"
+	})).Return(existing, nil)
+	mocks.identityService.EXPECT().GetUserIdentities(gomock.Any(), existing.ID).Return([]models.UserIdentity{}, nil)
+	mocks.identityService.EXPECT().LinkIdentity(gomock.Any(), googleIdentityParams(existing.ID)).Return(&models.UserIdentity{}, nil)
+	expectTokens(mocks, existing.ID)
+
+	c, w := newCallbackContext()
+	controller.OAuthCallback(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestOAuthCallback_RejectsIdentityOfDeletedAccount(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	userId := uuid.New()
+
+	mocks.oauthService.EXPECT().Exchange(gomock.Any(), "google", "state-1", "code-1", "binding-1").
+		Return(services.OAuthExchangeResult{Profile: googleProfile}, nil)
+	mocks.identityService.EXPECT().GetIdentity(gomock.Any(), "google", "google-subject-1").
+		Return(&models.UserIdentity{UserId: userId, Provider: "google"}, nil)
+	mocks.userService.EXPECT().GetUserById(gomock.Any(), userId).Return(nil, sql.ErrNoRows)
+
+	c, w := newCallbackContext()
+	controller.OAuthCallback(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "this account is disabled")
+}
+
+func TestOAuthCallback_RejectsEmailOfDeletedAccount(t *testing.T) {
+	controller, mocks := newAuthController(t)
+
+	mocks.oauthService.EXPECT().Exchange(gomock.Any(), "google", "state-1", "code-1", "binding-1").
+		Return(services.OAuthExchangeResult{Profile: googleProfile}, nil)
+	mocks.identityService.EXPECT().GetIdentity(gomock.Any(), "google", "google-subject-1").Return(nil, sql.ErrNoRows)
+	mocks.userService.EXPECT().GetUserByEmail(gomock.Any(), "ari@mail.com").Return(nil, sql.ErrNoRows)
+	mocks.utils.EXPECT().GenerateRandomBytes(8).Return("jwt-version", nil)
+	mocks.utils.EXPECT().GenerateRandomBytes(3).Return("a1b2c3", nil)
+	mocks.userService.EXPECT().Store(gomock.Any(), gomock.Any()).Return(nil, repositories.ErrDuplicateUser)
+
+	c, w := newCallbackContext()
+	controller.OAuthCallback(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "this account is disabled")
+}
+
+func TestOAuthCallback_RejectsUnverifiedEmail(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	profile := googleProfile
+	profile.EmailVerified = false
+
+	mocks.oauthService.EXPECT().Exchange(gomock.Any(), "google", "state-1", "code-1", "binding-1").
+		Return(services.OAuthExchangeResult{Profile: profile}, nil)
+	mocks.identityService.EXPECT().GetIdentity(gomock.Any(), "google", "google-subject-1").Return(nil, sql.ErrNoRows)
+
+	c, w := newCallbackContext()
+	controller.OAuthCallback(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestOAuthCallback_LinkFlowRejectsIdentityOfAnotherUser(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	linkUserId := uuid.New()
+
+	mocks.oauthService.EXPECT().Exchange(gomock.Any(), "google", "state-1", "code-1", "binding-1").
+		Return(services.OAuthExchangeResult{Profile: googleProfile, LinkUserId: linkUserId}, nil)
+	mocks.identityService.EXPECT().GetIdentity(gomock.Any(), "google", "google-subject-1").
+		Return(&models.UserIdentity{UserId: uuid.New(), Provider: "google"}, nil)
+
+	c, w := newCallbackContext()
+	controller.OAuthCallback(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestOAuthCallback_LinkFlowLinksIdentity(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	linkUserId := uuid.New()
+
+	mocks.oauthService.EXPECT().Exchange(gomock.Any(), "google", "state-1", "code-1", "binding-1").
+		Return(services.OAuthExchangeResult{Profile: googleProfile, LinkUserId: linkUserId}, nil)
+	mocks.identityService.EXPECT().GetIdentity(gomock.Any(), "google", "google-subject-1").Return(nil, sql.ErrNoRows)
+	mocks.identityService.EXPECT().GetUserIdentities(gomock.Any(), linkUserId).
+		Return([]models.UserIdentity{{Provider: models.IdentityProviderCredentials}}, nil)
+	mocks.identityService.EXPECT().LinkIdentity(gomock.Any(), googleIdentityParams(linkUserId)).
+		Return(&models.UserIdentity{ID: 3, Provider: "google"}, nil)
+
+	c, w := newCallbackContext()
+	controller.OAuthCallback(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"provider":"google"`)
+}
+
+func TestOAuthLogin_UnknownProvider(t *testing.T) {
+	controller, mocks := newAuthController(t)
+
+	mocks.oauthService.EXPECT().AuthCodeURL(gomock.Any(), "myspace").Return(services.OAuthFlow{}, services.ErrUnknownOAuthProvider)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/auth/oauth/myspace", nil)
+	c.Params = gin.Params{{Key: "provider", Value: "myspace"}}
+	controller.OAuthLogin(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestOAuthLogin_SetsBindingCookie(t *testing.T) {
+	controller, mocks := newAuthController(t)
+
+	mocks.oauthService.EXPECT().AuthCodeURL(gomock.Any(), "google").
+		Return(services.OAuthFlow{URL: "https://accounts.example/authorize", Binding: "binding-1"}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/auth/oauth/google", nil)
+	c.Params = gin.Params{{Key: "provider", Value: "google"}}
+	controller.OAuthLogin(c)
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://accounts.example/authorize", w.Header().Get("Location"))
+	cookie := w.Result().Cookies()[0]
+	assert.Equal(t, constants.COOKIE_OAUTH_BINDING, cookie.Name)
+	assert.Equal(t, "binding-1", cookie.Value)
+	assert.True(t, cookie.HttpOnly)
+}
@@ -0,0 +1,96 @@
+package auth_test
+
+import (
+	"database/sql"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetSessions_FlagsCurrentSession(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	userId := uuid.New()
+	currentJti := uuid.New()
+
+	mocks.sessionService.EXPECT().GetActiveSessions(gomock.Any(), userId).Return([]models.Token{
+		{ID: 1, Jti: currentJti, UserAgent: "current-browser"},
+		{ID: 2, Jti: uuid.New(), UserAgent: "other-browser"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/auth/sessions", nil)
+	c.Set(constants.ACCESS_TOKEN_PAYLOAD, services.JWTPayload{UserId: userId.String(), Jti: currentJti.String()})
+
+	controller.GetSessions(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"sessions":[
+		{"id":1,"device_id":"00000000-0000-0000-0000-000000000000","ip_address":"","user_agent":"current-browser","created_at":"","last_used_at":"","expired_at":"","current":true},
+		{"id":2,"device_id":"00000000-0000-0000-0000-000000000000","ip_address":"","user_agent":"other-browser","created_at":"","last_used_at":"","expired_at":"","current":false}
+	]}`, w.Body.String())
+}
+
+func TestRevokeSession_Success(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	userId := uuid.New()
+	revoked := &models.Token{ID: 7, Hash: "hashed", Jti: uuid.New()}
+
+	mocks.sessionService.EXPECT().RevokeSessionById(gomock.Any(), userId, 7).Return(revoked, nil)
+	mocks.authService.EXPECT().RevokeSessionTokens([]models.Token{*revoked})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/auth/sessions/7", nil)
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set(constants.ACCESS_TOKEN_PAYLOAD, services.JWTPayload{UserId: userId.String(), Jti: uuid.NewString()})
+
+	controller.RevokeSession(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRevokeSession_NotFound(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	userId := uuid.New()
+
+	mocks.sessionService.EXPECT().RevokeSessionById(gomock.Any(), userId, 7).Return(nil, sql.ErrNoRows)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/auth/sessions/7", nil)
+	c.Params = gin.Params{{Key: "id", Value: "7"}}
+	c.Set(constants.ACCESS_TOKEN_PAYLOAD, services.JWTPayload{UserId: userId.String(), Jti: uuid.NewString()})
+
+	controller.RevokeSession(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRevokeOtherSessions_KeepsCurrent(t *testing.T) {
+	controller, mocks := newAuthController(t)
+	userId := uuid.New()
+	currentJti := uuid.New()
+	others := []models.Token{{ID: 2, Hash: "other", Jti: uuid.New()}}
+
+	mocks.sessionService.EXPECT().RevokeOtherSessions(gomock.Any(), userId, currentJti).Return(others, nil)
+	mocks.authService.EXPECT().RevokeSessionTokens(others)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/auth/sessions", nil)
+	c.Set(constants.ACCESS_TOKEN_PAYLOAD, services.JWTPayload{UserId: userId.String(), Jti: currentJti.String()})
+
+	controller.RevokeOtherSessions(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"revoked":1`)
+}
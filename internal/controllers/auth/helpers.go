@@ -0,0 +1,211 @@
+package auth
+
+import (
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// getDeviceId returns the device id stored in the device cookie, issuing a new
+// one when the cookie is missing or malformed.
+func getDeviceId(c *gin.Context) uuid.UUID {
+	if value, err := c.Cookie(constants.COOKIE_DEVICE_ID); err == nil {
+		if deviceId, err := uuid.Parse(value); err == nil {
+			return deviceId
+		}
+	}
+	deviceId := uuid.New()
+	c.SetCookie(constants.COOKIE_DEVICE_ID, deviceId.String(), 3600*24*365, "/", "", os.Getenv("GO_ENV") == "production", true)
+	return deviceId
+}
+
+// setOAuthBinding binds an OAuth flow to this browser. The cookie is Lax so it
+// survives the top level redirect back from the provider, and lives as long
+// as the flow's state.
+func setOAuthBinding(c *gin.Context, binding string) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(constants.COOKIE_OAUTH_BINDING, binding, int(services.OAuthStateTTL.Seconds()), "/", "", os.Getenv("GO_ENV") == "production", true)
+}
+
+// takeOAuthBinding reads the binding cookie of the flow and clears it.
+func takeOAuthBinding(c *gin.Context) string {
+	binding, _ := c.Cookie(constants.COOKIE_OAUTH_BINDING)
+	c.SetCookie(constants.COOKIE_OAUTH_BINDING, "", -1, "/", "", false, true)
+	return binding
+}
+
+// getTokenPayload reads the access token payload set by the auth middleware and
+// aborts the request with 401 when it is missing.
+func getTokenPayload(c *gin.Context) (services.JWTPayload, uuid.UUID, bool) {
+	value, exist := c.Get(constants.ACCESS_TOKEN_PAYLOAD)
+	if !exist {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return services.JWTPayload{}, uuid.Nil, false
+	}
+	tokenPayload, ok := value.(services.JWTPayload)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token payload"})
+		return services.JWTPayload{}, uuid.Nil, false
+	}
+	userId, err := uuid.Parse(tokenPayload.UserId)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return services.JWTPayload{}, uuid.Nil, false
+	}
+	return tokenPayload, userId, true
+}
+
+// emailLocale is the language emails to user are written in: their saved
+// locale, or the languages the browser asks for.
+func emailLocale(c *gin.Context, user *models.User) string {
+	if user.Locale != "" {
+		return user.Locale
+	}
+	return c.GetHeader("Accept-Language")
+}
+
+// auditSelf records an event where user acted on their own account.
+func (ctrl *authController) auditSelf(c *gin.Context, eventType, outcome string, userId uuid.UUID, details map[string]any) {
+	ctrl.auditService.Record(c.Request.Context(), services.AuditParams{
+		Type:     eventType,
+		Outcome:  outcome,
+		ActorId:  userId,
+		TargetId: userId,
+		Details:  details,
+	})
+}
+
+// accountLocked responds with 403 when an admin has locked the account of
+// user.
+func accountLocked(c *gin.Context, user *models.User) bool {
+	if user.LockedAt == nil {
+		return false
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": "Account is locked"})
+	return true
+}
+
+// signInRefused answers for accounts that may not sign in, locked ones and
+// ones that must reset their password first, and records the refused login.
+func (ctrl *authController) signInRefused(c *gin.Context, user *models.User, method string) bool {
+	reason := ""
+	switch {
+	case accountLocked(c, user):
+		reason = "account_locked"
+	case user.PasswordResetRequired:
+		reason = "password_reset_required"
+		c.JSON(http.StatusForbidden, gin.H{"error": "Password reset required, please follow the link sent to your email"})
+	default:
+		return false
+	}
+	ctrl.auditSelf(c, models.AuditEventLogin, models.AuditOutcomeFailure, user.ID, map[string]any{"method": method, "reason": reason})
+	return true
+}
+
+// signIn finishes a successful first factor. Users with two-factor
+// authentication enabled or a registered passkey get an mfa challenge token
+// to redeem at /auth/mfa/verify, or with a passkey at /auth/mfa/webauthn,
+// instead of real tokens. method names the first factor in the audit log.
+func (ctrl *authController) signIn(c *gin.Context, user *models.User, method string) {
+	if ctrl.signInRefused(c, user, method) {
+		return
+	}
+	mfaEnabled, err := ctrl.mfaService.IsEnabled(c.Request.Context(), user.ID)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	hasPasskeys, err := ctrl.webauthnService.HasCredentials(c.Request.Context(), user.ID)
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	if mfaEnabled || hasPasskeys {
+		mfaToken, err := ctrl.mfaService.CreateChallenge(user.ID)
+		if err != nil {
+			log.Println(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+			return
+		}
+		methods := []string{}
+		if mfaEnabled {
+			methods = append(methods, "totp", "recovery_code")
+		}
+		if hasPasskeys {
+			methods = append(methods, "webauthn")
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"mfa_required": true,
+			"mfa_token":    mfaToken,
+			"mfa_methods":  methods,
+		})
+		return
+	}
+	ctrl.issueAuthTokens(c, user, method)
+}
+
+// issueAuthTokens creates a new session for user, sets the refresh token
+// cookie and responds with the access token. The login is recorded in the
+// audit log under method.
+func (ctrl *authController) issueAuthTokens(c *gin.Context, user *models.User, method string) {
+	if ctrl.signInRefused(c, user, method) {
+		return
+	}
+	authToken, err := ctrl.authService.CreateAuthTokens(c.Request.Context(), services.CreateAuthTokenParams{
+		UserId:     user.ID,
+		JwtVersion: user.JwtVersion,
+		DeviceId:   getDeviceId(c),
+		UserAgent:  c.Request.UserAgent(),
+		IpAddress:  c.ClientIP(),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctrl.auditSelf(c, models.AuditEventLogin, models.AuditOutcomeSuccess, user.ID, map[string]any{"method": method})
+	c.SetCookie(constants.COOKIE_REFRESH_TOKEN, authToken.RefreshToken, 3600*24*365, "/", "", os.Getenv("GO_ENV") == "production", true)
+	c.JSON(http.StatusOK, gin.H{
+		"user":  user,
+		"token": authToken.AccessToken,
+	})
+}
+
+// reauthenticateMfa checks the password, when the account has one, and a
+// current second factor code before a two-factor setting is changed.
+func (ctrl *authController) reauthenticateMfa(c *gin.Context, userId uuid.UUID) bool {
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return false
+	}
+	body, ok := value.(dto.MfaReauth)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return false
+	}
+	user, err := ctrl.userService.GetUserById(c.Request.Context(), userId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return false
+	}
+	if user.Password != "" {
+		if err := ctrl.passwordService.Verify(user.Password, body.Password); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "wrong password"})
+			return false
+		}
+	}
+	if err := ctrl.mfaService.VerifyCode(c.Request.Context(), userId, body.Code); err != nil {
+		respondMfaError(c, err)
+		return false
+	}
+	return true
+}
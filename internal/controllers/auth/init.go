@@ -17,6 +17,33 @@ type IAuthController interface {
 	ForgotPassword(c *gin.Context)
 	ResetPassword(c *gin.Context)
 	ResendVerification(c *gin.Context)
+	GetSessions(c *gin.Context)
+	RevokeSession(c *gin.Context)
+	RevokeOtherSessions(c *gin.Context)
+	OAuthProviders(c *gin.Context)
+	OAuthLogin(c *gin.Context)
+	OAuthCallback(c *gin.Context)
+	GetIdentities(c *gin.Context)
+	LinkIdentity(c *gin.Context)
+	UnlinkIdentity(c *gin.Context)
+	SetPassword(c *gin.Context)
+	MfaVerify(c *gin.Context)
+	GetMfaStatus(c *gin.Context)
+	EnrollTotp(c *gin.Context)
+	ConfirmTotp(c *gin.Context)
+	DisableTotp(c *gin.Context)
+	RegenerateRecoveryCodes(c *gin.Context)
+	MfaWebauthnOptions(c *gin.Context)
+	MfaWebauthnVerify(c *gin.Context)
+	PasskeyLoginOptions(c *gin.Context)
+	PasskeyLogin(c *gin.Context)
+	GetPasskeys(c *gin.Context)
+	PasskeyRegisterOptions(c *gin.Context)
+	RegisterPasskey(c *gin.Context)
+	DeletePasskey(c *gin.Context)
+	RequestMagicLink(c *gin.Context)
+	RedeemMagicLinkCode(c *gin.Context)
+	RedeemMagicLink(c *gin.Context)
 }
 
 type authController struct {
@@ -25,6 +52,13 @@ type authController struct {
 	emailService    services.IEmailService
 	passwordService services.IPasswordService
 	redisService    services.IRedisService
+	sessionService  services.ISessionService
+	oauthService    services.IOAuthService
+	identityService services.IIdentityService
+	mfaService      services.IMfaService
+	webauthnService services.IWebauthnService
+	loginProtection services.ILoginProtectionService
+	auditService    services.IAuditService
 	utils           utils.IUtils
 }
 
@@ -34,6 +68,13 @@ func NewAuthController(
 	userService services.IUserService,
 	emailService services.IEmailService,
 	redisService services.IRedisService,
+	sessionService services.ISessionService,
+	oauthService services.IOAuthService,
+	identityService services.IIdentityService,
+	mfaService services.IMfaService,
+	webauthnService services.IWebauthnService,
+	loginProtection services.ILoginProtectionService,
+	auditService services.IAuditService,
 	utils utils.IUtils,
 ) IAuthController {
 	return &authController{
@@ -42,6 +83,13 @@ func NewAuthController(
 		passwordService: passwordService,
 		emailService:    emailService,
 		authService:     authService,
+		sessionService:  sessionService,
+		oauthService:    oauthService,
+		identityService: identityService,
+		mfaService:      mfaService,
+		webauthnService: webauthnService,
+		loginProtection: loginProtection,
+		auditService:    auditService,
 		utils:           utils,
 	}
 }
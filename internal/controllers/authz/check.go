@@ -0,0 +1,46 @@
+package authz
+
+import (
+	"errors"
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *authzController) Check(c *gin.Context) {
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.AuthzCheck)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	allowed, err := ctrl.authzService.Check(c.Request.Context(), body.Object, body.Relation, body.Subject)
+	if err != nil {
+		respondAuthzError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"allowed": allowed})
+}
+
+func respondAuthzError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidRelationTuple),
+		errors.Is(err, services.ErrUnknownNamespace),
+		errors.Is(err, services.ErrUnknownRelation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAuthzDepthExceeded):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+	}
+}
@@ -0,0 +1,28 @@
+package authz
+
+import (
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *authzController) DeleteTuples(c *gin.Context) {
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.RelationTuples)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	if err := ctrl.authzService.DeleteTuples(c.Request.Context(), body.Tuples); err != nil {
+		respondAuthzError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Tuples deleted"})
+}
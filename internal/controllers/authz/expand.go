@@ -0,0 +1,29 @@
+package authz
+
+import (
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *authzController) Expand(c *gin.Context) {
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.AuthzExpand)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	tree, err := ctrl.authzService.Expand(c.Request.Context(), body.Object, body.Relation)
+	if err != nil {
+		respondAuthzError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tree": tree})
+}
@@ -0,0 +1,22 @@
+package authz
+
+import (
+	"my-go-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IAuthzController interface {
+	Check(c *gin.Context)
+	Expand(c *gin.Context)
+	WriteTuples(c *gin.Context)
+	DeleteTuples(c *gin.Context)
+}
+
+type authzController struct {
+	authzService services.IAuthzService
+}
+
+func NewAuthzController(authzService services.IAuthzService) IAuthzController {
+	return &authzController{authzService: authzService}
+}
@@ -0,0 +1,22 @@
+package dev
+
+import (
+	"my-go-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IDevController serves development helpers. Its routes are only registered
+// when previews are enabled, never by default in production.
+type IDevController interface {
+	ListEmailTemplates(c *gin.Context)
+	PreviewEmail(c *gin.Context)
+}
+
+type devController struct {
+	emailService services.IEmailService
+}
+
+func NewDevController(emailService services.IEmailService) IDevController {
+	return &devController{emailService: emailService}
+}
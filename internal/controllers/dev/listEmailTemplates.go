@@ -0,0 +1,12 @@
+package dev
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *devController) ListEmailTemplates(c *gin.Context) {
+	names, locales := ctrl.emailService.Templates()
+	c.JSON(http.StatusOK, gin.H{"templates": names, "locales": locales})
+}
@@ -0,0 +1,47 @@
+package dev
+
+import (
+	"errors"
+	"log"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PreviewEmail renders a template with sample data. The locale query value is
+// matched like a user's locale, falling back to the Accept-Language header;
+// format is html (default), text or json for every part at once.
+func (ctrl *devController) PreviewEmail(c *gin.Context) {
+	locale := c.Query("locale")
+	if locale == "" {
+		locale = c.GetHeader("Accept-Language")
+	}
+	rendered, err := ctrl.emailService.Preview(c.Param("template"), locale)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEmailTemplate) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Unknown email template"})
+			return
+		}
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Language", rendered.Locale)
+	switch c.DefaultQuery("format", "html") {
+	case "html":
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(rendered.HTML))
+	case "text":
+		c.String(http.StatusOK, "Subject: %s\n\n%s", rendered.Subject, rendered.Text)
+	case "json":
+		c.JSON(http.StatusOK, gin.H{
+			"locale":  rendered.Locale,
+			"subject": rendered.Subject,
+			"text":    rendered.Text,
+			"html":    rendered.HTML,
+		})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"errors": gin.H{"format": "Must be one of: html text json"}})
+	}
+}
@@ -0,0 +1,554 @@
+package oauth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"my-go-api/internal/config"
+	"my-go-api/internal/controllers/oauth"
+	"my-go-api/internal/controllers/wellknown"
+	"my-go-api/internal/middleware"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	"my-go-api/internal/utils"
+	mockservices "my-go-api/mocks/mock_services"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const relyingPartyCallback = "http://127.0.0.1/callback"
+
+// provider runs the OpenID Connect provider on an in-process server, backed
+// by miniredis and in-memory repositories.
+type provider struct {
+	server      *httptest.Server
+	oauthServer services.IOAuthServerService
+	authService services.IAuthService
+	user        *models.User
+}
+
+func newProvider(t *testing.T) *provider {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+
+	var engine *gin.Engine
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		engine.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	user := &models.User{
+		ID:         uuid.New(),
+		Username:   "ada",
+		Name:       "Ada Lovelace",
+		Email:      "ada@example.com",
+		IsVerified: true,
+		JwtVersion: "v1",
+	}
+	userService := mockservices.NewMockIUserService(ctrl)
+	userService.EXPECT().GetUserById(gomock.Any(), user.ID).Return(user, nil).AnyTimes()
+	roleService := mockservices.NewMockIRoleService(ctrl)
+	roleService.EXPECT().GetUserPermissions(gomock.Any(), user.ID).Return([]string{}, nil).AnyTimes()
+
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	redisService := services.NewRedisService(repositories.NewRedisRepository(rdb))
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	keyring, err := services.NewStaticJwtKeyring(config.JwtConfig{Algorithm: config.JwtAlgorithmES256, SigningKey: key})
+	require.NoError(t, err)
+	jwtService := services.NewJwtService(keyring, redisService)
+	util := utils.NewUtilities("secret", server.URL)
+	sessionService := newMemorySessionService()
+	authService := services.NewAuthService(redisService, util, jwtService, sessionService, roleService, services.NewSecurityEventService(), 10*time.Second)
+
+	oauthServer := services.NewOAuthServerService(
+		config.OidcConfig{
+			Issuer:           server.URL,
+			ConsentUri:       server.URL + "/consent",
+			AuthorizationTTL: 10 * time.Minute,
+			CodeTTL:          time.Minute,
+			IdTokenTTL:       time.Hour,
+		},
+		newMemoryClientRepository(),
+		newMemoryAuthorizationRepository(),
+		authService,
+		sessionService,
+		userService,
+		jwtService,
+		util,
+	)
+
+	controller := oauth.NewOAuthController(oauthServer)
+	wellKnownController := wellknown.NewWellKnownController(jwtService, oauthServer)
+	authMiddleware := middleware.NewAuthMiddleware(jwtService, userService)
+	validationMiddleware := middleware.NewValidationMiddleware(validator.New())
+
+	engine = gin.New()
+	engine.GET("/.well-known/jwks.json", wellKnownController.JWKS)
+	engine.GET("/.well-known/openid-configuration", wellKnownController.OpenIDConfiguration)
+	routes := engine.Group("/api/v1/oauth")
+	routes.GET("/authorize", controller.Authorize)
+	routes.POST("/token", controller.Token)
+	routes.GET("/userinfo", authMiddleware.RequireScope(services.OAuthScopeOpenId), controller.UserInfo)
+	routes.GET("/requests/:id", authMiddleware.Handler, controller.GetAuthorization)
+	routes.POST("/requests/:id", authMiddleware.Handler, validationMiddleware.DecideOAuthAuthorization, controller.DecideAuthorization)
+
+	return &provider{server: server, oauthServer: oauthServer, authService: authService, user: user}
+}
+
+func (p *provider) registerClient(t *testing.T, confidential bool) (*models.OAuthClient, string) {
+	client, secret, err := p.oauthServer.RegisterClient(context.Background(), services.RegisterOAuthClientParams{
+		Name:         "Wiki",
+		RedirectUris: []string{relyingPartyCallback},
+		Confidential: confidential,
+	})
+	require.NoError(t, err)
+	return client, secret
+}
+
+// firstPartyToken signs the user in to the API itself, as the consent page
+// would be.
+func (p *provider) firstPartyToken(t *testing.T) string {
+	tokens, err := p.authService.CreateAuthTokens(context.Background(), services.CreateAuthTokenParams{
+		UserId:     p.user.ID,
+		JwtVersion: p.user.JwtVersion,
+		DeviceId:   uuid.New(),
+	})
+	require.NoError(t, err)
+	return tokens.AccessToken
+}
+
+// consent follows authorizeURL to the consent page and approves the request
+// as the signed in user, returning the redirect back to the client.
+func (p *provider) consent(t *testing.T, authorizeURL string, approve bool) *url.URL {
+	noRedirects := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	res, err := noRedirects.Get(authorizeURL)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusFound, res.StatusCode)
+	consentPage, err := url.Parse(res.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, "/consent", consentPage.Path)
+	request := p.server.URL + "/api/v1/oauth/requests/" + consentPage.Query().Get("request")
+
+	bearer := "Bearer " + p.firstPartyToken(t)
+	var prompt struct {
+		Client          models.OAuthClient `json:"client"`
+		Scopes          []string           `json:"scopes"`
+		ConsentRequired bool               `json:"consent_required"`
+	}
+	doJSON(t, http.MethodGet, request, bearer, "", http.StatusOK, &prompt)
+	require.True(t, prompt.ConsentRequired)
+	require.Equal(t, "Wiki", prompt.Client.Name)
+
+	var decision struct {
+		RedirectTo string `json:"redirect_to"`
+	}
+	doJSON(t, http.MethodPost, request, bearer, fmt.Sprintf(`{"approve":%t}`, approve), http.StatusOK, &decision)
+	redirect, err := url.Parse(decision.RedirectTo)
+	require.NoError(t, err)
+	return redirect
+}
+
+func doJSON(t *testing.T, method, target, authorization, body string, status int, out any) {
+	req, err := http.NewRequest(method, target, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, status, res.StatusCode)
+	if out != nil {
+		require.NoError(t, json.NewDecoder(res.Body).Decode(out))
+	}
+}
+
+func postToken(t *testing.T, target string, form url.Values, status int) map[string]any {
+	res, err := http.PostForm(target, form)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, status, res.StatusCode)
+	body := map[string]any{}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	return body
+}
+
+func TestRelyingParty_SignsInThroughTheOIDCClient(t *testing.T) {
+	p := newProvider(t)
+	client, secret := p.registerClient(t, true)
+	rp := services.NewOIDCProvider(config.OAuthProviderConfig{
+		Name:         "self",
+		Type:         config.OAuthProviderTypeOIDC,
+		Issuer:       p.server.URL,
+		ClientId:     client.ID,
+		ClientSecret: secret,
+		RedirectUri:  relyingPartyCallback,
+		Scopes:       []string{"openid", "email", "profile"},
+	}, p.server.Client())
+
+	verifier := "verifier-" + strings.Repeat("x", 43)
+	authorizeURL, err := rp.AuthCodeURL(context.Background(), "state-1", "nonce-1", verifier)
+	require.NoError(t, err)
+	callback := p.consent(t, authorizeURL, true)
+
+	require.Equal(t, "state-1", callback.Query().Get("state"))
+	require.Equal(t, p.server.URL, callback.Query().Get("iss"))
+	profile, err := rp.Exchange(context.Background(), callback.Query().Get("code"), "nonce-1", verifier)
+	require.NoError(t, err)
+	require.Equal(t, p.user.ID.String(), profile.Subject)
+	require.Equal(t, "ada@example.com", profile.Email)
+	require.True(t, profile.EmailVerified)
+
+	// the code was consumed by the exchange
+	_, err = rp.Exchange(context.Background(), callback.Query().Get("code"), "nonce-1", verifier)
+	require.Error(t, err)
+}
+
+func TestRelyingParty_DeniedConsent(t *testing.T) {
+	p := newProvider(t)
+	client, _ := p.registerClient(t, false)
+
+	challenge := sha256.Sum256([]byte(strings.Repeat("v", 43)))
+	authorizeURL := p.server.URL + "/api/v1/oauth/authorize?" + url.Values{
+		"response_type":         {"code"},
+		"client_id":             {client.ID},
+		"redirect_uri":          {relyingPartyCallback},
+		"scope":                 {"openid"},
+		"state":                 {"state-2"},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}.Encode()
+	callback := p.consent(t, authorizeURL, false)
+
+	require.Equal(t, services.OAuthErrorAccessDenied, callback.Query().Get("error"))
+	require.Equal(t, "state-2", callback.Query().Get("state"))
+	require.Empty(t, callback.Query().Get("code"))
+}
+
+func TestRelyingParty_PublicClientTokensAreScoped(t *testing.T) {
+	p := newProvider(t)
+	client, _ := p.registerClient(t, false)
+	tokenURL := p.server.URL + services.OAuthTokenPath
+	userInfoURL := p.server.URL + services.OAuthUserInfoPath
+
+	verifier := strings.Repeat("v", 43)
+	challenge := sha256.Sum256([]byte(verifier))
+	authorizeURL := p.server.URL + "/api/v1/oauth/authorize?" + url.Values{
+		"response_type":         {"code"},
+		"client_id":             {client.ID},
+		"redirect_uri":          {relyingPartyCallback},
+		"scope":                 {"openid profile"},
+		"state":                 {"state-3"},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}.Encode()
+	callback := p.consent(t, authorizeURL, true)
+
+	tokens := postToken(t, tokenURL, url.Values{
+		"grant_type":    {"authorization_code"},
+		"client_id":     {client.ID},
+		"code":          {callback.Query().Get("code")},
+		"redirect_uri":  {relyingPartyCallback},
+		"code_verifier": {verifier},
+	}, http.StatusOK)
+	require.Equal(t, "openid profile", tokens["scope"])
+	require.NotEmpty(t, tokens["id_token"])
+
+	var claims map[string]any
+	doJSON(t, http.MethodGet, userInfoURL, "Bearer "+tokens["access_token"].(string), "", http.StatusOK, &claims)
+	require.Equal(t, p.user.ID.String(), claims["sub"])
+	require.Equal(t, "Ada Lovelace", claims["name"])
+	require.NotContains(t, claims, "email")
+
+	// client tokens are no good for the API itself, first party tokens no
+	// good for userinfo
+	doJSON(t, http.MethodGet, p.server.URL+"/api/v1/oauth/requests/any", "Bearer "+tokens["access_token"].(string), "", http.StatusForbidden, nil)
+	doJSON(t, http.MethodGet, userInfoURL, "Bearer "+p.firstPartyToken(t), "", http.StatusForbidden, nil)
+
+	refreshed := postToken(t, tokenURL, url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {client.ID},
+		"refresh_token": {tokens["refresh_token"].(string)},
+	}, http.StatusOK)
+	require.NotEqual(t, tokens["refresh_token"], refreshed["refresh_token"])
+	doJSON(t, http.MethodGet, userInfoURL, "Bearer "+refreshed["access_token"].(string), "", http.StatusOK, &claims)
+
+	other, _ := p.registerClient(t, false)
+	rejected := postToken(t, tokenURL, url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {other.ID},
+		"refresh_token": {refreshed["refresh_token"].(string)},
+	}, http.StatusBadRequest)
+	require.Equal(t, services.OAuthErrorInvalidGrant, rejected["error"])
+}
+
+type memoryClientRepository struct {
+	mu       sync.Mutex
+	clients  map[string]models.OAuthClient
+	consents map[string][]string
+}
+
+func newMemoryClientRepository() *memoryClientRepository {
+	return &memoryClientRepository{clients: map[string]models.OAuthClient{}, consents: map[string][]string{}}
+}
+
+func (r *memoryClientRepository) List(ctx context.Context) ([]models.OAuthClient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clients := []models.OAuthClient{}
+	for _, client := range r.clients {
+		clients = append(clients, client)
+	}
+	return clients, nil
+}
+
+func (r *memoryClientRepository) GetOne(ctx context.Context, id string) (*models.OAuthClient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	client, ok := r.clients[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &client, nil
+}
+
+func (r *memoryClientRepository) Create(ctx context.Context, params repositories.CreateOAuthClientParams) (*models.OAuthClient, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	client := models.OAuthClient{ID: params.Id, Name: params.Name, RedirectUris: params.RedirectUris}
+	if params.SecretHash != "" {
+		client.SecretHash = &params.SecretHash
+		client.Confidential = true
+	}
+	r.clients[params.Id] = client
+	return &client, nil
+}
+
+func (r *memoryClientRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.clients[id]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.clients, id)
+	return nil
+}
+
+func (r *memoryClientRepository) GetConsent(ctx context.Context, userId uuid.UUID, clientId string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	scopes, ok := r.consents[userId.String()+clientId]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return scopes, nil
+}
+
+func (r *memoryClientRepository) SaveConsent(ctx context.Context, userId uuid.UUID, clientId string, scopes []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.consents[userId.String()+clientId] = scopes
+	return nil
+}
+
+type memoryAuthorization struct {
+	models.OAuthAuthorization
+	codeHash  string
+	expiresAt time.Time
+}
+
+type memoryAuthorizationRepository struct {
+	mu             sync.Mutex
+	authorizations map[string]*memoryAuthorization
+}
+
+func newMemoryAuthorizationRepository() *memoryAuthorizationRepository {
+	return &memoryAuthorizationRepository{authorizations: map[string]*memoryAuthorization{}}
+}
+
+func (r *memoryAuthorizationRepository) Create(ctx context.Context, params repositories.CreateOAuthAuthorizationParams) (*models.OAuthAuthorization, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	authorization := &memoryAuthorization{
+		OAuthAuthorization: models.OAuthAuthorization{
+			ID:            params.Id,
+			ClientId:      params.ClientId,
+			RedirectUri:   params.RedirectUri,
+			Scope:         params.Scope,
+			State:         params.State,
+			Nonce:         params.Nonce,
+			CodeChallenge: params.CodeChallenge,
+			Prompt:        params.Prompt,
+		},
+		expiresAt: params.ExpiresAt,
+	}
+	r.authorizations[params.Id] = authorization
+	return &authorization.OAuthAuthorization, nil
+}
+
+func (r *memoryAuthorizationRepository) pending(id string) (*memoryAuthorization, error) {
+	authorization, ok := r.authorizations[id]
+	if !ok || authorization.UserId != nil || time.Now().After(authorization.expiresAt) {
+		return nil, sql.ErrNoRows
+	}
+	return authorization, nil
+}
+
+func (r *memoryAuthorizationRepository) GetPending(ctx context.Context, id string) (*models.OAuthAuthorization, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	authorization, err := r.pending(id)
+	if err != nil {
+		return nil, err
+	}
+	copied := authorization.OAuthAuthorization
+	return &copied, nil
+}
+
+func (r *memoryAuthorizationRepository) Approve(ctx context.Context, id string, userId uuid.UUID, codeHash string, expiresAt time.Time) (*models.OAuthAuthorization, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	authorization, err := r.pending(id)
+	if err != nil {
+		return nil, err
+	}
+	authorization.UserId = &userId
+	authorization.codeHash = codeHash
+	authorization.expiresAt = expiresAt
+	copied := authorization.OAuthAuthorization
+	return &copied, nil
+}
+
+func (r *memoryAuthorizationRepository) Deny(ctx context.Context, id string) (*models.OAuthAuthorization, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	authorization, err := r.pending(id)
+	if err != nil {
+		return nil, err
+	}
+	delete(r.authorizations, id)
+	return &authorization.OAuthAuthorization, nil
+}
+
+func (r *memoryAuthorizationRepository) Redeem(ctx context.Context, codeHash string) (*models.OAuthAuthorization, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, authorization := range r.authorizations {
+		if authorization.codeHash == codeHash {
+			delete(r.authorizations, id)
+			if time.Now().After(authorization.expiresAt) {
+				return nil, sql.ErrNoRows
+			}
+			return &authorization.OAuthAuthorization, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *memoryAuthorizationRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+// memorySessionService keeps the sessions the flows create; the methods the
+// provider does not use panic through the nil embedded interface.
+type memorySessionService struct {
+	services.ISessionService
+	mu       sync.Mutex
+	nextId   int
+	sessions map[string]*models.Token
+}
+
+func newMemorySessionService() *memorySessionService {
+	return &memorySessionService{sessions: map[string]*models.Token{}}
+}
+
+func (s *memorySessionService) CreateSession(ctx context.Context, params repositories.CreateSessionParams) (*models.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextId++
+	session := &models.Token{
+		ID:        s.nextId,
+		Hash:      params.Hash,
+		Jti:       params.Jti,
+		FamilyId:  params.FamilyId,
+		DeviceId:  params.DeviceId,
+		UserId:    params.UserId,
+		ExpiredAt: params.ExpiredAt.Format(time.RFC3339Nano),
+		Scope:     params.Scope,
+	}
+	if params.ClientId != "" {
+		session.ClientId = &params.ClientId
+	}
+	s.sessions[params.Hash] = session
+	return session, nil
+}
+
+func (s *memorySessionService) RotateSession(ctx context.Context, oldHash string, params repositories.CreateSessionParams) (*models.Token, error) {
+	s.mu.Lock()
+	old, ok := s.sessions[oldHash]
+	if !ok || old.IsRevoked {
+		s.mu.Unlock()
+		return nil, sql.ErrNoRows
+	}
+	old.IsRevoked = true
+	params.FamilyId = old.FamilyId
+	s.mu.Unlock()
+	return s.CreateSession(ctx, params)
+}
+
+func (s *memorySessionService) GetSessionByHash(ctx context.Context, hash string) (*models.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[hash]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (s *memorySessionService) GetActiveSessionByHash(ctx context.Context, hash string) (*models.Token, error) {
+	session, err := s.GetSessionByHash(ctx, hash)
+	if err != nil || session.IsRevoked {
+		return nil, sql.ErrNoRows
+	}
+	return session, nil
+}
+
+func (s *memorySessionService) RevokeDeviceSessions(ctx context.Context, userId, deviceId uuid.UUID) ([]models.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	revoked := []models.Token{}
+	for _, session := range s.sessions {
+		if session.UserId == userId && session.DeviceId == deviceId && !session.IsRevoked {
+			session.IsRevoked = true
+			revoked = append(revoked, *session)
+		}
+	}
+	return revoked, nil
+}
@@ -0,0 +1,42 @@
+package oauth
+
+import (
+	"errors"
+	"log"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Authorize starts the authorization code flow. A valid request is handed to
+// the consent page, a faulty one is sent back to the client. Requests naming
+// an unknown client or redirect URI are answered here, never redirected.
+func (ctrl *oauthController) Authorize(c *gin.Context) {
+	if err := c.Request.ParseForm(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": err.Error()})
+		return
+	}
+	form := c.Request.Form
+	target, err := ctrl.oauthServer.Authorize(c.Request.Context(), services.AuthorizeParams{
+		ResponseType:        form.Get("response_type"),
+		ClientId:            form.Get("client_id"),
+		RedirectUri:         form.Get("redirect_uri"),
+		Scope:               form.Get("scope"),
+		State:               form.Get("state"),
+		Nonce:               form.Get("nonce"),
+		CodeChallenge:       form.Get("code_challenge"),
+		CodeChallengeMethod: form.Get("code_challenge_method"),
+		Prompt:              form.Get("prompt"),
+	})
+	switch {
+	case errors.Is(err, services.ErrOAuthClientNotFound), errors.Is(err, services.ErrRedirectUriMismatch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": err.Error()})
+		return
+	case err != nil:
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return
+	}
+	c.Redirect(http.StatusFound, target)
+}
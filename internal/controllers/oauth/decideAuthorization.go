@@ -0,0 +1,43 @@
+package oauth
+
+import (
+	"errors"
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DecideAuthorization approves or denies a request for the signed in user and
+// returns the URI the consent page sends the browser back to the client with.
+func (ctrl *oauthController) DecideAuthorization(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.DecideOAuthAuthorization)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	target, err := ctrl.oauthServer.Decide(c.Request.Context(), c.Param("id"), user.ID, *body.Approve)
+	if err != nil {
+		if errors.Is(err, services.ErrAuthorizationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"redirect_to": target})
+}
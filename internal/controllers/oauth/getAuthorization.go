@@ -0,0 +1,30 @@
+package oauth
+
+import (
+	"errors"
+	"log"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAuthorization tells the consent page which client asks for which scopes
+// and whether the signed in user has to be asked at all.
+func (ctrl *oauthController) GetAuthorization(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	prompt, err := ctrl.oauthServer.GetAuthorization(c.Request.Context(), c.Param("id"), user.ID)
+	if err != nil {
+		if errors.Is(err, services.ErrAuthorizationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	c.JSON(http.StatusOK, prompt)
+}
@@ -0,0 +1,44 @@
+package oauth
+
+import (
+	"errors"
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func currentUser(c *gin.Context) (*models.User, bool) {
+	value, exist := c.Get(constants.CURRENT_USER)
+	if !exist {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+	user, ok := value.(*models.User)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+	return user, true
+}
+
+// respondOAuthError answers in the format of RFC 6749, section 5.2.
+func respondOAuthError(c *gin.Context, err error) {
+	var oauthErr *services.OAuthError
+	if !errors.As(err, &oauthErr) {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return
+	}
+	status := http.StatusBadRequest
+	if oauthErr.Code == services.OAuthErrorInvalidClient {
+		status = http.StatusUnauthorized
+		if _, _, basic := c.Request.BasicAuth(); basic {
+			c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+		}
+	}
+	c.JSON(status, gin.H{"error": oauthErr.Code, "error_description": oauthErr.Description})
+}
@@ -0,0 +1,25 @@
+package oauth
+
+import (
+	"my-go-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IOAuthController serves the endpoints of the OpenID Connect provider, and
+// the consent API the front end shows authorization requests with.
+type IOAuthController interface {
+	Authorize(c *gin.Context)
+	GetAuthorization(c *gin.Context)
+	DecideAuthorization(c *gin.Context)
+	Token(c *gin.Context)
+	UserInfo(c *gin.Context)
+}
+
+type oauthController struct {
+	oauthServer services.IOAuthServerService
+}
+
+func NewOAuthController(oauthServer services.IOAuthServerService) IOAuthController {
+	return &oauthController{oauthServer: oauthServer}
+}
@@ -0,0 +1,50 @@
+package oauth
+
+import (
+	"my-go-api/internal/services"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Token exchanges an authorization code or a refresh token. Confidential
+// clients authenticate with HTTP Basic or client_secret in the form, public
+// clients only send their client_id.
+func (ctrl *oauthController) Token(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	clientId, clientSecret, basic := c.Request.BasicAuth()
+	if basic {
+		// RFC 6749 form encodes the credentials before they are put in the
+		// header
+		var err error
+		if clientId, err = url.QueryUnescape(clientId); err == nil {
+			clientSecret, err = url.QueryUnescape(clientSecret)
+		}
+		if err != nil {
+			respondOAuthError(c, &services.OAuthError{Code: services.OAuthErrorInvalidClient, Description: "malformed client credentials"})
+			return
+		}
+	} else {
+		clientId, clientSecret = c.PostForm("client_id"), c.PostForm("client_secret")
+	}
+
+	response, err := ctrl.oauthServer.Exchange(c.Request.Context(), services.OAuthTokenParams{
+		GrantType:    c.PostForm("grant_type"),
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		Code:         c.PostForm("code"),
+		RedirectUri:  c.PostForm("redirect_uri"),
+		CodeVerifier: c.PostForm("code_verifier"),
+		RefreshToken: c.PostForm("refresh_token"),
+		UserAgent:    c.Request.UserAgent(),
+		IpAddress:    c.ClientIP(),
+	})
+	if err != nil {
+		respondOAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
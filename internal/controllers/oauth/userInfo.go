@@ -0,0 +1,21 @@
+package oauth
+
+import (
+	"my-go-api/internal/constants"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserInfo returns the claims about the user the access token was granted.
+func (ctrl *oauthController) UserInfo(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	value, _ := c.Get(constants.ACCESS_TOKEN_PAYLOAD)
+	payload, _ := value.(services.JWTPayload)
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, ctrl.oauthServer.UserInfo(user, payload.Scope))
+}
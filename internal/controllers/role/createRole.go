@@ -0,0 +1,38 @@
+package role
+
+import (
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/repositories"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *roleController) CreateRole(c *gin.Context) {
+	actor, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.CreateRole)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	role, err := ctrl.roleService.CreateRole(c.Request.Context(), actor.ID, repositories.CreateRoleParams{
+		Name:        body.Name,
+		Description: body.Description,
+		Permissions: body.Permissions,
+	})
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"role": role})
+}
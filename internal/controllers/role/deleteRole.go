@@ -0,0 +1,47 @@
+package role
+
+import (
+	"errors"
+	"log"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *roleController) DeleteRole(c *gin.Context) {
+	actor, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role id"})
+		return
+	}
+
+	if err := ctrl.roleService.DeleteRole(c.Request.Context(), id); err != nil {
+		respondRoleError(c, err)
+		return
+	}
+	ctrl.recordRoleAction(c, actor, models.AdminActionDeleteRole, map[string]any{"role_id": id})
+	c.JSON(http.StatusOK, gin.H{"message": "Role deleted"})
+}
+
+func respondRoleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrRoleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrRoleExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSystemRole), errors.Is(err, services.ErrRoleEscalation):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUnknownRole), errors.Is(err, services.ErrUnknownPermission):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+	}
+}
@@ -0,0 +1,18 @@
+package role
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *roleController) GetPermissions(c *gin.Context) {
+	permissions, err := ctrl.roleService.GetPermissions(c.Request.Context())
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"permissions": permissions})
+}
@@ -0,0 +1,18 @@
+package role
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *roleController) GetRoles(c *gin.Context) {
+	roles, err := ctrl.roleService.GetRoles(c.Request.Context())
+	if err != nil {
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"roles": roles})
+}
@@ -0,0 +1,40 @@
+package role
+
+import (
+	"my-go-api/internal/constants"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentUser returns the user performing the request, whose permissions
+// bound what it can grant.
+func currentUser(c *gin.Context) (*models.User, bool) {
+	value, exist := c.Get(constants.CURRENT_USER)
+	if !exist {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+	user, ok := value.(*models.User)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+	return user, true
+}
+
+// recordRoleAction stores a change of a role in the audit log. The change
+// already happened, so it is recorded like the other admin actions.
+func (ctrl *roleController) recordRoleAction(c *gin.Context, actor *models.User, action string, details map[string]any) {
+	auditDetails := map[string]any{"action": action}
+	for key, value := range details {
+		auditDetails[key] = value
+	}
+	ctrl.auditService.Record(c.Request.Context(), services.AuditParams{
+		Type:    models.AuditEventAdminAction,
+		ActorId: actor.ID,
+		Details: auditDetails,
+	})
+}
@@ -0,0 +1,27 @@
+package role
+
+import (
+	"my-go-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IRoleController interface {
+	GetRoles(c *gin.Context)
+	GetPermissions(c *gin.Context)
+	CreateRole(c *gin.Context)
+	SetRolePermissions(c *gin.Context)
+	DeleteRole(c *gin.Context)
+	GetUserRoles(c *gin.Context)
+	SetUserRoles(c *gin.Context)
+}
+
+type roleController struct {
+	roleService  services.IRoleService
+	userService  services.IUserService
+	auditService services.IAuditService
+}
+
+func NewRoleController(roleService services.IRoleService, userService services.IUserService, auditService services.IAuditService) IRoleController {
+	return &roleController{roleService: roleService, userService: userService, auditService: auditService}
+}
@@ -0,0 +1,45 @@
+package role
+
+import (
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (ctrl *roleController) SetRolePermissions(c *gin.Context) {
+	actor, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role id"})
+		return
+	}
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.RolePermissions)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	role, err := ctrl.roleService.SetRolePermissions(c.Request.Context(), actor.ID, id, body.Permissions)
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+	ctrl.recordRoleAction(c, actor, models.AdminActionSetRolePermissions, map[string]any{
+		"role_id":     role.ID,
+		"role":        role.Name,
+		"permissions": body.Permissions,
+	})
+	c.JSON(http.StatusOK, gin.H{"role": role})
+}
@@ -0,0 +1,62 @@
+package role
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SetUserRoles replaces the roles assigned to a user. The primary role is
+// still changed through the user itself.
+func (ctrl *roleController) SetUserRoles(c *gin.Context) {
+	actor, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	userId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	body, ok := value.(dto.UserRoles)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+	if _, err := ctrl.userService.GetUserById(c.Request.Context(), userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		log.Println(err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+
+	roles, err := ctrl.roleService.SetUserRoles(c.Request.Context(), actor.ID, userId, body.Roles)
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	ctrl.auditService.Record(c.Request.Context(), services.AuditParams{
+		Type:     models.AuditEventRoleChange,
+		ActorId:  actor.ID,
+		TargetId: userId,
+		Details:  map[string]any{"roles": body.Roles},
+	})
+	c.JSON(http.StatusOK, gin.H{"roles": roles})
+}
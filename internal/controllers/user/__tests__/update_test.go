@@ -0,0 +1,100 @@
+package user_test
+
+import (
+	"errors"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/controllers/user"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	mockservices "my-go-api/mocks/mock_services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type userMocks struct {
+	userService  *mockservices.MockIUserService
+	auditService *mockservices.MockIAuditService
+	authService  *mockservices.MockIAuthService
+	emailService *mockservices.MockIEmailService
+}
+
+func newUserController(t *testing.T) (user.IUserController, userMocks) {
+	ctrl := gomock.NewController(t)
+	mocks := userMocks{
+		userService:  mockservices.NewMockIUserService(ctrl),
+		auditService: mockservices.NewMockIAuditService(ctrl),
+		authService:  mockservices.NewMockIAuthService(ctrl),
+		emailService: mockservices.NewMockIEmailService(ctrl),
+	}
+	controller := user.NewUserController(
+		mocks.userService,
+		mockservices.NewMockIRoleService(ctrl),
+		mocks.auditService,
+		mocks.authService,
+		mocks.emailService,
+	)
+	gin.SetMode(gin.TestMode)
+	return controller, mocks
+}
+
+func newUpdateContext(current *models.User, body map[string]any) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/v1/users/"+current.ID.String(), nil)
+	c.Params = gin.Params{{Key: "id", Value: current.ID.String()}}
+	c.Set(constants.CURRENT_USER, current)
+	c.Set(constants.VALIDATED_BODY, body)
+	return c, w
+}
+
+func TestUpdate_FailureIsReportedAndAudited(t *testing.T) {
+	controller, mocks := newUserController(t)
+	current := &models.User{ID: uuid.New(), Name: "Ari Test", Email: "ari@mail.com", IsVerified: true}
+
+	mocks.userService.EXPECT().GetUserById(gomock.Any(), current.ID).Return(&models.User{ID: current.ID, Name: "Ari Test"}, nil)
+	mocks.userService.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+	mocks.auditService.EXPECT().Record(gomock.Any(), services.AuditParams{
+		Type:     models.AuditEventUserUpdate,
+		Outcome:  models.AuditOutcomeFailure,
+		ActorId:  current.ID,
+		TargetId: current.ID,
+		Details:  map[string]any{"fields": []string{"name"}},
+	})
+
+	c, w := newUpdateContext(current, map[string]any{"name": "Ari Renamed"})
+	controller.Update(c)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestUpdate_NewEmailNeedsVerification(t *testing.T) {
+	controller, mocks := newUserController(t)
+	current := &models.User{ID: uuid.New(), Username: "ari08", Email: "ari@mail.com", IsVerified: true}
+
+	mocks.userService.EXPECT().GetUserById(gomock.Any(), current.ID).
+		Return(&models.User{ID: current.ID, Username: "ari08", Email: "ari@mail.com", IsVerified: true}, nil)
+	mocks.userService.EXPECT().UpdateUser(gomock.Any(), gomock.Cond(func(u *models.User) bool {
+		return u.Email == "new@mail.com" && !u.IsVerified
+	})).DoAndReturn(func(_ any, u *models.User) (*models.User, error) { return u, nil })
+	mocks.auditService.EXPECT().Record(gomock.Any(), gomock.Any())
+	mocks.authService.EXPECT().CreateVerificationToken(current.ID).
+		Return(services.VerificationTokenData{RawToken: "raw", Code: "a1b2c3d4"}, nil)
+	mocks.emailService.EXPECT().SendVerificationEmail(services.SendEmailVerificationParams{
+		Name:  "ari08",
+		Email: "new@mail.com",
+		Code:  "a1b2c3d4",
+	}).Return(nil)
+
+	c, w := newUpdateContext(current, map[string]any{"email": "new@mail.com"})
+	controller.Update(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"token":"raw"`)
+	assert.Contains(t, w.Body.String(), `"is_verified":false`)
+}
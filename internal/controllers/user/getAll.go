@@ -1,18 +1,52 @@
 package user
 
 import (
+	"errors"
 	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/dto"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+// GetAll returns one page of users. Pass next_cursor back as cursor, with the
+// same filters and sort, to get the following page.
 func (ctrl *userController) GetAll(c *gin.Context) {
-	users, err := ctrl.userService.GetAllUsers(c.Request.Context())
+	value, exist := c.Get(constants.VALIDATED_BODY)
+	if !exist {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validated body not exists"})
+		return
+	}
+	query, ok := value.(dto.ListUsers)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid type for validated body"})
+		return
+	}
+
+	params := repositories.ListUsersParams{
+		Search:        strings.TrimSpace(query.Search),
+		Role:          query.Role,
+		Provider:      query.Provider,
+		IsVerified:    query.IsVerified,
+		CreatedAfter:  query.CreatedAfter,
+		CreatedBefore: query.CreatedBefore,
+		SortBy:        strings.TrimPrefix(query.Sort, "-"),
+		Desc:          strings.HasPrefix(query.Sort, "-"),
+		Limit:         query.Limit,
+	}
+	page, err := ctrl.userService.ListUsers(c.Request.Context(), params, query.Cursor)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		log.Println(err.Error())
 		c.JSON(http.StatusInternalServerError, gin.H{"errors": "Something went wrong"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"users": users})
+	c.JSON(http.StatusOK, page)
 }
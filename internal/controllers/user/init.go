@@ -13,9 +13,25 @@ type IUserController interface {
 }
 
 type userController struct {
-	userService services.IUserService
+	userService  services.IUserService
+	roleService  services.IRoleService
+	auditService services.IAuditService
+	authService  services.IAuthService
+	emailService services.IEmailService
 }
 
-func NewUserController(userService services.IUserService) IUserController {
-	return &userController{userService: userService}
+func NewUserController(
+	userService services.IUserService,
+	roleService services.IRoleService,
+	auditService services.IAuditService,
+	authService services.IAuthService,
+	emailService services.IEmailService,
+) IUserController {
+	return &userController{
+		userService:  userService,
+		roleService:  roleService,
+		auditService: auditService,
+		authService:  authService,
+		emailService: emailService,
+	}
 }
@@ -3,7 +3,13 @@ package user
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"log"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
 	"net/http"
+	"slices"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -31,25 +37,124 @@ func (ctrl *userController) Update(c *gin.Context) {
 		}
 	}
 
+	roleChanged := false
+	emailChanged := false
+	previousRole := existingUser.Role
+	fields := []string{}
 	if v, ok := value.(map[string]any); ok {
+		for _, field := range []string{"username", "name", "email", "locale", "role"} {
+			if _, exists := v[field].(string); exists {
+				fields = append(fields, field)
+			}
+		}
 		if username, exists := v["username"].(string); exists {
 			existingUser.Username = username
 		}
 		if name, exists := v["name"].(string); exists {
 			existingUser.Name = name
 		}
-		if email, exists := v["email"].(string); exists {
+		if email, exists := v["email"].(string); exists && email != existingUser.Email {
+			// a new address has to be verified before it can be trusted
 			existingUser.Email = email
+			existingUser.IsVerified = false
+			emailChanged = true
 		}
-		if password, exists := v["password"].(string); exists {
-			existingUser.Password = password
+		if locale, exists := v["locale"].(string); exists {
+			existingUser.Locale = locale
 		}
-		if role, exists := v["role"].(string); exists {
+		if role, exists := v["role"].(string); exists && role != existingUser.Role {
+			// changing a role, including one's own, needs roles:assign
+			// and only to roles whose permissions the actor holds
+			value, _ := c.Get(constants.ACCESS_TOKEN_PAYLOAD)
+			if payload, ok := value.(services.JWTPayload); !ok || !slices.Contains(payload.Permissions, models.PermissionRolesAssign) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to change roles"})
+				return
+			}
+			actor, ok := c.Value(constants.CURRENT_USER).(*models.User)
+			if !ok {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+				return
+			}
+			if err := ctrl.roleService.CheckRoleChange(c.Request.Context(), actor.ID, []string{role}, []string{existingUser.Role}); err != nil {
+				if errors.Is(err, services.ErrRoleEscalation) {
+					c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+					return
+				}
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+				return
+			}
 			existingUser.Role = role
+			roleChanged = true
+		}
+	}
+
+	actorId := uuid.Nil
+	if actor, ok := c.Value(constants.CURRENT_USER).(*models.User); ok {
+		actorId = actor.ID
+	}
+	updatedUser, err := ctrl.userService.UpdateUser(c.Request.Context(), existingUser)
+	if err != nil {
+		log.Println(err.Error())
+		ctrl.auditService.Record(c.Request.Context(), services.AuditParams{
+			Type:     models.AuditEventUserUpdate,
+			Outcome:  models.AuditOutcomeFailure,
+			ActorId:  actorId,
+			TargetId: existingUser.ID,
+			Details:  map[string]any{"fields": fields},
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong"})
+		return
+	}
+	if roleChanged {
+		ctrl.roleService.ExpireAccessTokens(c.Request.Context(), updatedUser.ID)
+	}
+
+	ctrl.auditService.Record(c.Request.Context(), services.AuditParams{
+		Type:     models.AuditEventUserUpdate,
+		ActorId:  actorId,
+		TargetId: updatedUser.ID,
+		Details:  map[string]any{"fields": fields},
+	})
+	if roleChanged {
+		ctrl.auditService.Record(c.Request.Context(), services.AuditParams{
+			Type:     models.AuditEventRoleChange,
+			ActorId:  actorId,
+			TargetId: updatedUser.ID,
+			Details:  map[string]any{"from": previousRole, "to": updatedUser.Role},
+		})
+	}
+
+	if emailChanged {
+		data, err := ctrl.sendVerification(updatedUser)
+		if err != nil {
+			log.Println(err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "User updated but the verification email could not be sent"})
+			return
 		}
+		c.JSON(http.StatusOK, gin.H{
+			"user":    updatedUser,
+			"token":   data.RawToken,
+			"message": fmt.Sprintf("An email has been sent to %s. Please follow the instruction to verify the new address.", updatedUser.Email),
+		})
+		return
 	}
 
-	ctrl.userService.UpdateUser(c.Request.Context(), existingUser)
+	c.JSON(http.StatusOK, gin.H{"user": updatedUser})
+}
 
-	c.JSON(http.StatusOK, gin.H{"user": existingUser})
+// sendVerification emails a verification code to the new address of user.
+func (ctrl *userController) sendVerification(user *models.User) (services.VerificationTokenData, error) {
+	data, err := ctrl.authService.CreateVerificationToken(user.ID)
+	if err != nil {
+		return services.VerificationTokenData{}, err
+	}
+	if err := ctrl.emailService.SendVerificationEmail(services.SendEmailVerificationParams{
+		Name:   user.Username,
+		Email:  user.Email,
+		Code:   data.Code,
+		Locale: user.Locale,
+	}); err != nil {
+		return services.VerificationTokenData{}, err
+	}
+	return data, nil
 }
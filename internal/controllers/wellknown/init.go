@@ -0,0 +1,26 @@
+package wellknown
+
+import (
+	"my-go-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IWellKnownController serves the public documents other services discover
+// under /.well-known.
+type IWellKnownController interface {
+	JWKS(c *gin.Context)
+	OpenIDConfiguration(c *gin.Context)
+}
+
+type wellKnownController struct {
+	jwtService  services.IJwtService
+	oauthServer services.IOAuthServerService
+}
+
+func NewWellKnownController(jwtService services.IJwtService, oauthServer services.IOAuthServerService) IWellKnownController {
+	return &wellKnownController{
+		jwtService:  jwtService,
+		oauthServer: oauthServer,
+	}
+}
@@ -0,0 +1,14 @@
+package wellknown
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKS publishes the public keys access tokens are verified with. Verifiers
+// may cache them for a while and fetch them again on an unknown kid.
+func (ctrl *wellKnownController) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, ctrl.jwtService.JWKS())
+}
@@ -0,0 +1,14 @@
+package wellknown
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpenIDConfiguration publishes the discovery document relying parties
+// configure themselves from.
+func (ctrl *wellKnownController) OpenIDConfiguration(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, ctrl.oauthServer.Configuration())
+}
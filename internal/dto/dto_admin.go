@@ -0,0 +1,62 @@
+package dto
+
+import "time"
+
+type AdminCreateUser struct {
+	Name     string `json:"name" validate:"required,min=5"`
+	Email    string `json:"email" validate:"required,email"`
+	Username string `json:"username" validate:"required,min=5"`
+	Password string `json:"password" validate:"required,strongPassword"`
+	Role     string `json:"role" validate:"omitempty,oneof=user admin"`
+	// RequirePasswordReset emails a reset link and blocks password sign in
+	// until the user has chosen their own password.
+	RequirePasswordReset bool `json:"require_password_reset"`
+}
+
+type AdminLockUser struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+type AdminChangeRole struct {
+	Role string `json:"role" validate:"required,oneof=user admin"`
+}
+
+// ListAuditEvents is read from the query string. Before takes the id of the
+// last event of the previous page; Format only applies to the export.
+type ListAuditEvents struct {
+	Chain     string     `form:"chain" validate:"max=64"`
+	ActorId   string     `form:"actor_id" validate:"omitempty,uuid"`
+	TargetId  string     `form:"target_id" validate:"omitempty,uuid"`
+	Type      string     `form:"type" validate:"max=50"`
+	Outcome   string     `form:"outcome" validate:"omitempty,oneof=success failure"`
+	IpAddress string     `form:"ip" validate:"omitempty,ip"`
+	RequestId string     `form:"request_id" validate:"max=64"`
+	From      *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Before    int64      `form:"before" validate:"min=0"`
+	Limit     int        `form:"limit" validate:"omitempty,min=1,max=500"`
+	Format    string     `form:"format" validate:"omitempty,oneof=csv jsonl"`
+}
+
+// ListOutboxEmails is read from the query string. Before takes the id of the
+// last email of the previous page.
+type ListOutboxEmails struct {
+	Status    string `form:"status" validate:"omitempty,oneof=pending sent dead"`
+	Recipient string `form:"recipient" validate:"omitempty,email"`
+	Before    int64  `form:"before" validate:"min=0"`
+	Limit     int    `form:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+// IntroduceSigningKey picks the algorithm of the new key, the configured one
+// when empty.
+type IntroduceSigningKey struct {
+	Algorithm string `json:"algorithm" validate:"omitempty,oneof=HS256 RS256 ES256 EdDSA"`
+}
+
+// RegisterOAuthClient registers a relying party. Confidential clients get a
+// secret, public ones authenticate with PKCE only.
+type RegisterOAuthClient struct {
+	Name         string   `json:"name" validate:"required,max=100"`
+	RedirectUris []string `json:"redirect_uris" validate:"required,min=1,max=20,dive,required,max=2000"`
+	Confidential bool     `json:"confidential"`
+}
@@ -1,10 +1,14 @@
 package dto
 
+import "encoding/json"
+
 type Register struct {
 	Name     string `json:"name" validate:"required,min=5"`
 	Email    string `json:"email" validate:"required,email"`
 	Username string `json:"username" validate:"required,min=5"`
 	Password string `json:"password" validate:"required,strongPassword"`
+	// Locale is the preferred language as a BCP 47 tag, e.g. fr-CA.
+	Locale string `json:"locale" validate:"omitempty,max=35,bcp47_language_tag"`
 }
 
 type Login struct {
@@ -30,3 +34,61 @@ type ResetPassword struct {
 type ResendVerification struct {
 	Email string `json:"email" validate:"required,email"`
 }
+
+type MagicLink struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type MagicLinkCode struct {
+	Token string `json:"token" validate:"required"`
+	Code  string `json:"code" validate:"required,min=8,max=8"`
+}
+
+type MagicLinkVerify struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type SetPassword struct {
+	Password string `json:"password" validate:"required,strongPassword"`
+	// Locale is the preferred language as a BCP 47 tag, e.g. fr-CA.
+	Locale string `json:"locale" validate:"omitempty,max=35,bcp47_language_tag"`
+}
+
+type MfaVerify struct {
+	MfaToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type MfaCode struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// MfaReauth confirms a sensitive two-factor change. Password is required for
+// accounts that have one.
+type MfaReauth struct {
+	Password string `json:"password"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type MfaToken struct {
+	MfaToken string `json:"mfa_token" validate:"required"`
+}
+
+// Credential fields hold the PublicKeyCredential returned by
+// navigator.credentials, serialized with toJSON().
+type MfaWebauthn struct {
+	MfaToken   string          `json:"mfa_token" validate:"required"`
+	SessionId  string          `json:"session_id" validate:"required"`
+	Credential json.RawMessage `json:"credential" validate:"required"`
+}
+
+type PasskeyLogin struct {
+	SessionId  string          `json:"session_id" validate:"required"`
+	Credential json.RawMessage `json:"credential" validate:"required"`
+}
+
+type RegisterPasskey struct {
+	SessionId  string          `json:"session_id" validate:"required"`
+	Name       string          `json:"name" validate:"max=100"`
+	Credential json.RawMessage `json:"credential" validate:"required"`
+}
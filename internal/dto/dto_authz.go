@@ -0,0 +1,16 @@
+package dto
+
+type AuthzCheck struct {
+	Object   string `json:"object" validate:"required"`
+	Relation string `json:"relation" validate:"required"`
+	Subject  string `json:"subject" validate:"required"`
+}
+
+type AuthzExpand struct {
+	Object   string `json:"object" validate:"required"`
+	Relation string `json:"relation" validate:"required"`
+}
+
+type RelationTuples struct {
+	Tuples []string `json:"tuples" validate:"required,min=1,max=100,dive,required"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+// DecideOAuthAuthorization is the answer of the user on the consent page.
+type DecideOAuthAuthorization struct {
+	Approve *bool `json:"approve" validate:"required"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+type CreateRole struct {
+	Name        string   `json:"name" validate:"required,min=3,max=50"`
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions" validate:"dive,required"`
+}
+
+type RolePermissions struct {
+	Permissions []string `json:"permissions" validate:"required,dive,required"`
+}
+
+type UserRoles struct {
+	Roles []string `json:"roles" validate:"required,dive,required"`
+}
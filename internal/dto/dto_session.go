@@ -0,0 +1,12 @@
+package dto
+
+type Session struct {
+	ID         int    `json:"id"`
+	DeviceId   string `json:"device_id"`
+	IpAddress  string `json:"ip_address"`
+	UserAgent  string `json:"user_agent"`
+	CreatedAt  string `json:"created_at"`
+	LastUsedAt string `json:"last_used_at"`
+	ExpiredAt  string `json:"expired_at"`
+	Current    bool   `json:"current"`
+}
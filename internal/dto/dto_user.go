@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+// ListUsers is read from the query string. Sort takes a column name, prefixed
+// with - for descending order.
+type ListUsers struct {
+	Limit         int        `form:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor        string     `form:"cursor" validate:"max=512"`
+	Search        string     `form:"search" validate:"max=100"`
+	Role          string     `form:"role" validate:"omitempty,oneof=user admin"`
+	Provider      string     `form:"provider" validate:"max=50"`
+	IsVerified    *bool      `form:"is_verified"`
+	CreatedAfter  *time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore *time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	Sort          string     `form:"sort" validate:"omitempty,oneof=created_at -created_at username -username name -name email -email"`
+}
@@ -0,0 +1,89 @@
+package middleware_test
+
+import (
+	"my-go-api/internal/constants"
+	"my-go-api/internal/middleware"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRouter serves handler after storing value in the context under key,
+// the way IAuthMiddleware.Handler does. A nil value stores nothing.
+func newRouter(key string, value any, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if value != nil {
+			c.Set(key, value)
+		}
+		c.Next()
+	})
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/users", handler, ok)
+	router.GET("/users/:id", handler, ok)
+	return router
+}
+
+func serve(router *gin.Engine, path string) int {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w.Code
+}
+
+func TestRequireRoles(t *testing.T) {
+	authorization := middleware.NewAuthorizationMiddleware()
+	handler := authorization.RequireRoles(models.RoleAdmin)
+
+	admin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	user := &models.User{ID: uuid.New(), Role: models.RoleUser}
+
+	assert.Equal(t, http.StatusOK, serve(newRouter(constants.CURRENT_USER, admin, handler), "/users"))
+	assert.Equal(t, http.StatusForbidden, serve(newRouter(constants.CURRENT_USER, user, handler), "/users"))
+	assert.Equal(t, http.StatusUnauthorized, serve(newRouter(constants.CURRENT_USER, nil, handler), "/users"))
+}
+
+func TestRequireSelfOrRoles(t *testing.T) {
+	authorization := middleware.NewAuthorizationMiddleware()
+	handler := authorization.RequireSelfOrRoles("id", models.RoleAdmin)
+
+	admin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	user := &models.User{ID: uuid.New(), Role: models.RoleUser}
+	other := uuid.New()
+
+	assert.Equal(t, http.StatusOK, serve(newRouter(constants.CURRENT_USER, user, handler), "/users/"+user.ID.String()))
+	assert.Equal(t, http.StatusForbidden, serve(newRouter(constants.CURRENT_USER, user, handler), "/users/"+other.String()))
+	assert.Equal(t, http.StatusForbidden, serve(newRouter(constants.CURRENT_USER, user, handler), "/users/not-a-uuid"))
+	assert.Equal(t, http.StatusOK, serve(newRouter(constants.CURRENT_USER, admin, handler), "/users/"+other.String()))
+}
+
+func TestRequirePermissions(t *testing.T) {
+	authorization := middleware.NewAuthorizationMiddleware()
+	handler := authorization.RequirePermissions(models.PermissionRolesRead, models.PermissionRolesWrite)
+
+	editor := services.JWTPayload{UserId: uuid.NewString(), Permissions: []string{models.PermissionRolesRead, models.PermissionRolesWrite}}
+	reader := services.JWTPayload{UserId: uuid.NewString(), Permissions: []string{models.PermissionRolesRead}}
+
+	assert.Equal(t, http.StatusOK, serve(newRouter(constants.ACCESS_TOKEN_PAYLOAD, editor, handler), "/users"))
+	assert.Equal(t, http.StatusForbidden, serve(newRouter(constants.ACCESS_TOKEN_PAYLOAD, reader, handler), "/users"))
+	assert.Equal(t, http.StatusUnauthorized, serve(newRouter(constants.ACCESS_TOKEN_PAYLOAD, nil, handler), "/users"))
+}
+
+func TestRequireSelfOrPermissions(t *testing.T) {
+	authorization := middleware.NewAuthorizationMiddleware()
+	handler := authorization.RequireSelfOrPermissions("id", models.PermissionUsersRead)
+
+	self := uuid.New()
+	user := services.JWTPayload{UserId: self.String()}
+	support := services.JWTPayload{UserId: uuid.NewString(), Permissions: []string{models.PermissionUsersRead}}
+
+	assert.Equal(t, http.StatusOK, serve(newRouter(constants.ACCESS_TOKEN_PAYLOAD, user, handler), "/users/"+self.String()))
+	assert.Equal(t, http.StatusForbidden, serve(newRouter(constants.ACCESS_TOKEN_PAYLOAD, user, handler), "/users/"+uuid.NewString()))
+	assert.Equal(t, http.StatusOK, serve(newRouter(constants.ACCESS_TOKEN_PAYLOAD, support, handler), "/users/"+self.String()))
+}
@@ -0,0 +1,98 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"my-go-api/internal/config"
+	"my-go-api/internal/middleware"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitMiddleware(t *testing.T, limits config.RateLimitConfig) middleware.IRateLimitMiddleware {
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	return middleware.NewRateLimitMiddleware(limits, services.NewRedisService(repositories.NewRedisRepository(rdb)))
+}
+
+// newRateLimitedRouter echoes the email of the JSON body, so tests can tell
+// the body is still readable after the limiter.
+func newRateLimitedRouter(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/auth/forgot-password", handler, func(c *gin.Context) {
+		var body struct {
+			Email string `json:"email"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"email": body.Email})
+	})
+	return router
+}
+
+func post(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/auth/forgot-password", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, r)
+	return w
+}
+
+func TestRateLimitByIP(t *testing.T) {
+	rateLimit := newRateLimitMiddleware(t, config.RateLimitConfig{"auth": {Limit: 2, Window: time.Minute}})
+	router := newRateLimitedRouter(rateLimit.ByIP("auth"))
+
+	first := post(router, `{"email":"a@example.com"}`)
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "2", first.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2;w=60", first.Header().Get("RateLimit-Policy"))
+
+	assert.Equal(t, http.StatusOK, post(router, `{"email":"a@example.com"}`).Code)
+
+	limited := post(router, `{"email":"a@example.com"}`)
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Equal(t, "0", limited.Header().Get("RateLimit-Remaining"))
+	assert.NotEmpty(t, limited.Header().Get("Retry-After"))
+	assert.Equal(t, limited.Header().Get("RateLimit-Reset"), limited.Header().Get("Retry-After"))
+}
+
+func TestRateLimitByEmail(t *testing.T) {
+	rateLimit := newRateLimitMiddleware(t, config.RateLimitConfig{"email": {Limit: 1, Window: time.Hour}})
+	router := newRateLimitedRouter(rateLimit.ByEmail("email"))
+
+	w := post(router, `{"email":"A@example.com"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "A@example.com", body["email"])
+
+	assert.Equal(t, http.StatusTooManyRequests, post(router, `{"email":"a@example.com "}`).Code)
+	assert.Equal(t, http.StatusOK, post(router, `{"email":"b@example.com"}`).Code)
+	// without an email the validation middleware answers, not the limiter
+	assert.Equal(t, http.StatusOK, post(router, `{}`).Code)
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	rateLimit := newRateLimitMiddleware(t, config.RateLimitConfig{"auth": {Limit: 0, Window: time.Minute}})
+	router := newRateLimitedRouter(rateLimit.ByIP("auth"))
+
+	for range 3 {
+		w := post(router, `{"email":"a@example.com"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	}
+	assert.Equal(t, http.StatusOK, post(newRateLimitedRouter(rateLimit.ByIP("unknown")), `{}`).Code)
+}
@@ -0,0 +1,53 @@
+package middleware_test
+
+import (
+	"my-go-api/internal/constants"
+	"my-go-api/internal/middleware"
+	"my-go-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveRequestId(header string) (*httptest.ResponseRecorder, string, services.RequestInfo) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var requestId string
+	var info services.RequestInfo
+	router.GET("/", middleware.NewRequestIdMiddleware().Handler, func(c *gin.Context) {
+		requestId = c.GetString(constants.REQUEST_ID)
+		info = services.RequestInfoFrom(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("User-Agent", "curl/8.0")
+	if header != "" {
+		r.Header.Set("X-Request-ID", header)
+	}
+	router.ServeHTTP(w, r)
+	return w, requestId, info
+}
+
+func TestRequestIdKeepsClientId(t *testing.T) {
+	w, requestId, info := serveRequestId("edge-42.a")
+
+	assert.Equal(t, "edge-42.a", requestId)
+	assert.Equal(t, "edge-42.a", w.Header().Get("X-Request-ID"))
+	assert.Equal(t, services.RequestInfo{RequestId: "edge-42.a", IpAddress: "192.0.2.1", UserAgent: "curl/8.0"}, info)
+}
+
+func TestRequestIdReplacesMissingOrUnsafeId(t *testing.T) {
+	for _, header := range []string{"", "a b", "id\nforged: entry", string(make([]byte, 65))} {
+		w, requestId, info := serveRequestId(header)
+
+		_, err := uuid.Parse(requestId)
+		assert.NoError(t, err, "header %q", header)
+		assert.Equal(t, requestId, w.Header().Get("X-Request-ID"))
+		assert.Equal(t, requestId, info.RequestId)
+	}
+}
@@ -1,9 +1,11 @@
 package middleware
 
 import (
+	"fmt"
 	"my-go-api/internal/constants"
 	"my-go-api/internal/services"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -16,7 +18,12 @@ type authMiddleware struct {
 }
 
 type IAuthMiddleware interface {
+	// Handler authenticates first party access tokens. Tokens issued to OAuth
+	// clients are refused, they are only good for the routes of RequireScope.
 	Handler(c *gin.Context)
+	// RequireScope authenticates access tokens issued to OAuth clients that
+	// were granted scope.
+	RequireScope(scope string) gin.HandlerFunc
 }
 
 func NewAuthMiddleware(jwtService services.IJwtService, userService services.IUserService) IAuthMiddleware {
@@ -27,18 +34,49 @@ func NewAuthMiddleware(jwtService services.IJwtService, userService services.IUs
 }
 
 func (m *authMiddleware) Handler(c *gin.Context) {
+	payload, ok := m.authenticate(c)
+	if !ok {
+		return
+	}
+	if payload.ClientId != "" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Token was issued to an OAuth client"})
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+func (m *authMiddleware) RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload, ok := m.authenticate(c)
+		if !ok {
+			return
+		}
+		if payload.ClientId == "" || !slices.Contains(strings.Fields(payload.Scope), scope) {
+			c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_scope", scope=%q`, scope))
+			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient_scope"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// authenticate verifies the bearer token and loads its user into the
+// context, or aborts the request.
+func (m *authMiddleware) authenticate(c *gin.Context) (services.JWTPayload, bool) {
 	authorization := c.GetHeader("Authorization")
 	if authorization == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		c.Abort()
-		return
+		return services.JWTPayload{}, false
 	}
 
 	const bearerPrefix = "Bearer "
 	if !strings.HasPrefix(authorization, bearerPrefix) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
 		c.Abort()
-		return
+		return services.JWTPayload{}, false
 	}
 
 	tokenStr := strings.TrimSpace(strings.TrimPrefix(authorization, bearerPrefix))
@@ -47,30 +85,36 @@ func (m *authMiddleware) Handler(c *gin.Context) {
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		c.Abort()
-		return
+		return services.JWTPayload{}, false
 	}
 
 	userId, err := uuid.Parse(payload.UserId)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		c.Abort()
-		return
+		return services.JWTPayload{}, false
 	}
 
 	user, err := m.userService.GetUserById(c.Request.Context(), userId)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		c.Abort()
-		return
+		return services.JWTPayload{}, false
 	}
 
 	if payload.JwtVersion != user.JwtVersion {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid jwt version"})
 		c.Abort()
-		return
+		return services.JWTPayload{}, false
 	}
 
-	c.Set(constants.ACCESS_TOKEN_PAYLOAD, payload)
+	if user.LockedAt != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account is locked"})
+		c.Abort()
+		return services.JWTPayload{}, false
+	}
 
-	c.Next()
+	c.Set(constants.ACCESS_TOKEN_PAYLOAD, payload)
+	c.Set(constants.CURRENT_USER, user)
+	return payload, true
 }
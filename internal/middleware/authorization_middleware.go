@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"my-go-api/internal/constants"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type authorizationMiddleware struct{}
+
+// IAuthorizationMiddleware builds per route guards. They must run after
+// IAuthMiddleware.Handler, which loads the current user.
+type IAuthorizationMiddleware interface {
+	RequireRoles(roles ...string) gin.HandlerFunc
+	RequireSelfOrRoles(param string, roles ...string) gin.HandlerFunc
+	RequirePermissions(permissions ...string) gin.HandlerFunc
+	RequireSelfOrPermissions(param string, permissions ...string) gin.HandlerFunc
+}
+
+func NewAuthorizationMiddleware() IAuthorizationMiddleware {
+	return &authorizationMiddleware{}
+}
+
+// RequireRoles lets the request through when the current user has one of
+// roles.
+func (m *authorizationMiddleware) RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		if !slices.Contains(roles, user.Role) {
+			forbidden(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSelfOrRoles lets the request through when the path parameter param
+// is the current user's own id, or when the user has one of roles.
+func (m *authorizationMiddleware) RequireSelfOrRoles(param string, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := currentUser(c)
+		if !ok {
+			return
+		}
+		if !isSelf(c, param, user.ID.String()) && !slices.Contains(roles, user.Role) {
+			forbidden(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequirePermissions lets the request through when the access token grants
+// every one of permissions.
+func (m *authorizationMiddleware) RequirePermissions(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload, ok := tokenPayload(c)
+		if !ok {
+			return
+		}
+		if !hasPermissions(payload, permissions) {
+			forbidden(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSelfOrPermissions lets the request through when the path parameter
+// param is the current user's own id, or when the access token grants every
+// one of permissions.
+func (m *authorizationMiddleware) RequireSelfOrPermissions(param string, permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload, ok := tokenPayload(c)
+		if !ok {
+			return
+		}
+		if !isSelf(c, param, payload.UserId) && !hasPermissions(payload, permissions) {
+			forbidden(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+func isSelf(c *gin.Context, param, userId string) bool {
+	id, err := uuid.Parse(c.Param(param))
+	return err == nil && id.String() == userId
+}
+
+func hasPermissions(payload services.JWTPayload, permissions []string) bool {
+	for _, permission := range permissions {
+		if !slices.Contains(payload.Permissions, permission) {
+			return false
+		}
+	}
+	return true
+}
+
+func tokenPayload(c *gin.Context) (services.JWTPayload, bool) {
+	value, exist := c.Get(constants.ACCESS_TOKEN_PAYLOAD)
+	if !exist {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		c.Abort()
+		return services.JWTPayload{}, false
+	}
+	payload, ok := value.(services.JWTPayload)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		c.Abort()
+		return services.JWTPayload{}, false
+	}
+	return payload, true
+}
+
+func currentUser(c *gin.Context) (*models.User, bool) {
+	value, exist := c.Get(constants.CURRENT_USER)
+	if !exist {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		c.Abort()
+		return nil, false
+	}
+	user, ok := value.(*models.User)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		c.Abort()
+		return nil, false
+	}
+	return user, true
+}
+
+func forbidden(c *gin.Context) {
+	c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+	c.Abort()
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"my-go-api/internal/config"
+	"my-go-api/internal/constants"
+	"my-go-api/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type rateLimitMiddleware struct {
+	limits       config.RateLimitConfig
+	redisService services.IRedisService
+}
+
+// IRateLimitMiddleware builds limiters for the limits named in
+// config.RateLimitConfig. Requests over the limit get 429 with Retry-After;
+// every response carries the RateLimit-* headers of the limiter. Unknown or
+// disabled limits let every request through.
+type IRateLimitMiddleware interface {
+	// ByIP counts requests per client IP.
+	ByIP(name string) gin.HandlerFunc
+	// ByUser counts requests per signed in user and must run after
+	// IAuthMiddleware.Handler. Anonymous requests are counted per IP.
+	ByUser(name string) gin.HandlerFunc
+	// ByEmail counts requests per email address in the JSON body, so it can
+	// run before the validation middleware. Requests without one pass.
+	ByEmail(name string) gin.HandlerFunc
+}
+
+func NewRateLimitMiddleware(limits config.RateLimitConfig, redisService services.IRedisService) IRateLimitMiddleware {
+	return &rateLimitMiddleware{limits: limits, redisService: redisService}
+}
+
+func (m *rateLimitMiddleware) ByIP(name string) gin.HandlerFunc {
+	return m.limiter(name, func(c *gin.Context) string {
+		return "ip:" + c.ClientIP()
+	})
+}
+
+func (m *rateLimitMiddleware) ByUser(name string) gin.HandlerFunc {
+	return m.limiter(name, func(c *gin.Context) string {
+		if value, exist := c.Get(constants.ACCESS_TOKEN_PAYLOAD); exist {
+			if payload, ok := value.(services.JWTPayload); ok && payload.UserId != "" {
+				return "user:" + payload.UserId
+			}
+		}
+		return "ip:" + c.ClientIP()
+	})
+}
+
+func (m *rateLimitMiddleware) ByEmail(name string) gin.HandlerFunc {
+	return m.limiter(name, func(c *gin.Context) string {
+		if email := bodyEmail(c); email != "" {
+			return "email:" + email
+		}
+		return ""
+	})
+}
+
+// limiter counts the request against the key returned by keyOf. An empty key
+// skips the limiter. Redis failures let the request through rather than take
+// the endpoints down with it.
+func (m *rateLimitMiddleware) limiter(name string, keyOf func(c *gin.Context) string) gin.HandlerFunc {
+	limit, ok := m.limits[name]
+	if !ok || limit.Limit <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		key := keyOf(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+		result, err := m.redisService.TakeRateLimit(name, key, limit.Limit, limit.Window)
+		if err != nil {
+			log.Printf("rate limit %s: %s", name, err.Error())
+			c.Next()
+			return
+		}
+
+		reset := strconv.Itoa(ceilSeconds(result.Reset))
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Limit, ceilSeconds(limit.Window)))
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", reset)
+		if !result.Allowed {
+			c.Header("Retry-After", reset)
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests, please try again later"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// bodyEmail reads the email field of a JSON body and puts the body back for
+// the handlers that follow.
+func bodyEmail(c *gin.Context) string {
+	if c.Request.Body == nil {
+		return ""
+	}
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return ""
+	}
+	var body struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(body.Email))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
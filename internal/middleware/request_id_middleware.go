@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"my-go-api/internal/constants"
+	"my-go-api/internal/services"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const requestIdHeader = "X-Request-ID"
+
+// requestIdPattern limits ids taken from clients to what fits the audit log
+// and cannot inject anything into logs or exports.
+var requestIdPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+type requestIdMiddleware struct{}
+
+// IRequestIdMiddleware tags every request with an id, taken from the
+// X-Request-ID header when the client sends a usable one. The id is echoed
+// in the response, stored under constants.REQUEST_ID and, with the client IP
+// and user agent, in the request context for the audit log.
+type IRequestIdMiddleware interface {
+	Handler(c *gin.Context)
+}
+
+func NewRequestIdMiddleware() IRequestIdMiddleware {
+	return &requestIdMiddleware{}
+}
+
+func (m *requestIdMiddleware) Handler(c *gin.Context) {
+	requestId := c.GetHeader(requestIdHeader)
+	if !requestIdPattern.MatchString(requestId) {
+		requestId = uuid.NewString()
+	}
+	c.Set(constants.REQUEST_ID, requestId)
+	c.Header(requestIdHeader, requestId)
+	c.Request = c.Request.WithContext(services.WithRequestInfo(c.Request.Context(), services.RequestInfo{
+		RequestId: requestId,
+		IpAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}))
+	c.Next()
+}
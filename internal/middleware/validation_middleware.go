@@ -26,6 +26,32 @@ type IValidationMiddleware interface {
 	ForgotPassword(c *gin.Context)
 	ResetPassword(c *gin.Context)
 	ResendVerification(c *gin.Context)
+	SetPassword(c *gin.Context)
+	MfaVerify(c *gin.Context)
+	MfaCode(c *gin.Context)
+	MfaReauth(c *gin.Context)
+	MfaToken(c *gin.Context)
+	MfaWebauthn(c *gin.Context)
+	PasskeyLogin(c *gin.Context)
+	RegisterPasskey(c *gin.Context)
+	MagicLink(c *gin.Context)
+	MagicLinkCode(c *gin.Context)
+	MagicLinkVerify(c *gin.Context)
+	CreateRole(c *gin.Context)
+	RolePermissions(c *gin.Context)
+	UserRoles(c *gin.Context)
+	AuthzCheck(c *gin.Context)
+	AuthzExpand(c *gin.Context)
+	RelationTuples(c *gin.Context)
+	AdminCreateUser(c *gin.Context)
+	AdminLockUser(c *gin.Context)
+	AdminChangeRole(c *gin.Context)
+	ListUsers(c *gin.Context)
+	ListAuditEvents(c *gin.Context)
+	ListOutboxEmails(c *gin.Context)
+	IntroduceSigningKey(c *gin.Context)
+	RegisterOAuthClient(c *gin.Context)
+	DecideOAuthAuthorization(c *gin.Context)
 }
 
 func NewValidationMiddleware(validate *validator.Validate) IValidationMiddleware {
@@ -53,6 +79,20 @@ func (m *validationMiddleware) runValidation(c *gin.Context, input any) {
 		}
 	}
 
+	m.validateStruct(c, input)
+}
+
+// runQueryValidation is runValidation for input read from the query string.
+func (m *validationMiddleware) runQueryValidation(c *gin.Context, input any) {
+	if err := c.ShouldBindQuery(input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": "Invalid query params"})
+		c.Abort()
+		return
+	}
+	m.validateStruct(c, input)
+}
+
+func (m *validationMiddleware) validateStruct(c *gin.Context, input any) {
 	if err := m.validate.Struct(input); err != nil {
 		var validationErrors validator.ValidationErrors
 		var msgErrors = make(map[string]string)
@@ -93,6 +133,188 @@ func (m *validationMiddleware) ResetPassword(c *gin.Context) {
 	c.Next()
 }
 
+func (m *validationMiddleware) SetPassword(c *gin.Context) {
+	var input dto.SetPassword
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) MfaVerify(c *gin.Context) {
+	var input dto.MfaVerify
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) MfaCode(c *gin.Context) {
+	var input dto.MfaCode
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) MfaReauth(c *gin.Context) {
+	var input dto.MfaReauth
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) MfaToken(c *gin.Context) {
+	var input dto.MfaToken
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) MfaWebauthn(c *gin.Context) {
+	var input dto.MfaWebauthn
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) PasskeyLogin(c *gin.Context) {
+	var input dto.PasskeyLogin
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) RegisterPasskey(c *gin.Context) {
+	var input dto.RegisterPasskey
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) MagicLink(c *gin.Context) {
+	var input dto.MagicLink
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) MagicLinkCode(c *gin.Context) {
+	var input dto.MagicLinkCode
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) MagicLinkVerify(c *gin.Context) {
+	var input dto.MagicLinkVerify
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) CreateRole(c *gin.Context) {
+	var input dto.CreateRole
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) RolePermissions(c *gin.Context) {
+	var input dto.RolePermissions
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) UserRoles(c *gin.Context) {
+	var input dto.UserRoles
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) AuthzCheck(c *gin.Context) {
+	var input dto.AuthzCheck
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) AuthzExpand(c *gin.Context) {
+	var input dto.AuthzExpand
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) RelationTuples(c *gin.Context) {
+	var input dto.RelationTuples
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) AdminCreateUser(c *gin.Context) {
+	var input dto.AdminCreateUser
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) AdminLockUser(c *gin.Context) {
+	var input dto.AdminLockUser
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) AdminChangeRole(c *gin.Context) {
+	var input dto.AdminChangeRole
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) ListUsers(c *gin.Context) {
+	var input dto.ListUsers
+	m.runQueryValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) ListAuditEvents(c *gin.Context) {
+	var input dto.ListAuditEvents
+	m.runQueryValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) ListOutboxEmails(c *gin.Context) {
+	var input dto.ListOutboxEmails
+	m.runQueryValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) IntroduceSigningKey(c *gin.Context) {
+	var input dto.IntroduceSigningKey
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) RegisterOAuthClient(c *gin.Context) {
+	var input dto.RegisterOAuthClient
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
+func (m *validationMiddleware) DecideOAuthAuthorization(c *gin.Context) {
+	var input dto.DecideOAuthAuthorization
+	m.runValidation(c, &input)
+	c.Set(constants.VALIDATED_BODY, input)
+	c.Next()
+}
+
 func (m *validationMiddleware) VerifyNewAccount(c *gin.Context) {
 	var input dto.VerifyNewAccount
 	m.runValidation(c, &input)
@@ -141,9 +363,13 @@ func (m *validationMiddleware) UpdateUser(c *gin.Context) {
 		}
 	}
 
-	if password, exists := input["password"].(string); exists {
-		if err := m.validate.Var(password, "required,strongPassword"); err != nil {
-			valErrors["password"] = "a minimum of 5 characters including an uppercase letter, a lowercase letter, and a number is required"
+	if _, exists := input["password"]; exists {
+		valErrors["password"] = "cannot be changed here, use /auth/forgot-password to choose a new password"
+	}
+
+	if locale, exists := input["locale"].(string); exists {
+		if err := m.validate.Var(locale, "omitempty,max=35,bcp47_language_tag"); err != nil {
+			valErrors["locale"] = "must be a language tag such as en or fr-CA"
 		}
 	}
 
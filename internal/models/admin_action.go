@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// Actions recorded in admin_actions.action.
+const (
+	AdminActionCreateUser         = "create_user"
+	AdminActionLockUser           = "lock_user"
+	AdminActionUnlockUser         = "unlock_user"
+	AdminActionForcePasswordReset = "force_password_reset"
+	AdminActionVerifyUser         = "verify_user"
+	AdminActionChangeRole         = "change_role"
+	AdminActionDeleteUser         = "delete_user"
+	AdminActionRestoreUser        = "restore_user"
+	AdminActionRevokeTokens       = "revoke_tokens"
+	AdminActionRevokeSession      = "revoke_session"
+)
+
+// Actions that do not concern a user, they are only recorded in the audit log.
+const (
+	AdminActionRetryEmail          = "retry_email"
+	AdminActionIntroduceSigningKey = "introduce_signing_key"
+	AdminActionPromoteSigningKey   = "promote_signing_key"
+	AdminActionRetireSigningKey    = "retire_signing_key"
+	AdminActionRegisterOAuthClient = "register_oauth_client"
+	AdminActionDeleteOAuthClient   = "delete_oauth_client"
+	AdminActionSetRolePermissions  = "set_role_permissions"
+	AdminActionDeleteRole          = "delete_role"
+)
+
+// AdminAction records an admin acting on a user account. AdminId is nil once
+// the admin has been removed.
+type AdminAction struct {
+	ID           int             `json:"id"`
+	AdminId      *uuid.UUID      `json:"admin_id"`
+	TargetUserId *uuid.UUID      `json:"target_user_id"`
+	Action       string          `json:"action"`
+	Details      json.RawMessage `json:"details"`
+	CreatedAt    string          `json:"created_at"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// Types recorded in audit_events.event_type.
+const (
+	AuditEventLogin                = "login"
+	AuditEventLogout               = "logout"
+	AuditEventRegister             = "register"
+	AuditEventVerifyAccount        = "verify_account"
+	AuditEventPasswordResetRequest = "password_reset_request"
+	AuditEventPasswordReset        = "password_reset"
+	AuditEventPasswordSet          = "password_set"
+	AuditEventMfaEnable            = "mfa_enable"
+	AuditEventMfaDisable           = "mfa_disable"
+	AuditEventMfaChallenge         = "mfa_challenge"
+	AuditEventSessionRevoke        = "session_revoke"
+	AuditEventIdentityLink         = "identity_link"
+	AuditEventIdentityUnlink       = "identity_unlink"
+	AuditEventPasskeyAdd           = "passkey_add"
+	AuditEventPasskeyRemove        = "passkey_remove"
+	AuditEventUserUpdate           = "user_update"
+	AuditEventRoleChange           = "role_change"
+	AuditEventAdminAction          = "admin_action"
+)
+
+// AuditChainDefault is the chain events are recorded on unless another one is
+// given. The API has no tenants, so it is the only chain written today.
+const AuditChainDefault = "default"
+
+// Outcomes recorded in audit_events.outcome.
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEvent is one entry of the security audit log. ActorId is the user who
+// acted and TargetId the account acted on; either is nil when unknown, such
+// as a failed login for an identity that does not exist. Hash chains the event
+// to PrevHash, the hash of the event before it on the same Chain.
+type AuditEvent struct {
+	ID        int64           `json:"id"`
+	Chain     string          `json:"chain"`
+	Type      string          `json:"type"`
+	Outcome   string          `json:"outcome"`
+	ActorId   *uuid.UUID      `json:"actor_id"`
+	TargetId  *uuid.UUID      `json:"target_id"`
+	IpAddress string          `json:"ip_address"`
+	UserAgent string          `json:"user_agent"`
+	RequestId string          `json:"request_id"`
+	Details   json.RawMessage `json:"details"`
+	CreatedAt string          `json:"created_at"`
+	PrevHash  string          `json:"prev_hash"`
+	Hash      string          `json:"hash"`
+}
+
+// AuditCheckpoint is a signed statement that the audit chain Chain ended with
+// the event LastEventId, whose hash was LastHash.
+type AuditCheckpoint struct {
+	ID          int64  `json:"id"`
+	Chain       string `json:"chain"`
+	LastEventId int64  `json:"last_event_id"`
+	LastHash    string `json:"last_hash"`
+	Signature   string `json:"signature"`
+	CreatedAt   string `json:"created_at"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "github.com/google/uuid"
+
+// OAuthClient is a relying party signing users in through the OpenID Connect
+// provider. Confidential clients authenticate with a secret, public clients
+// such as single page and native apps only with PKCE.
+type OAuthClient struct {
+	ID           string     `json:"client_id"`
+	Name         string     `json:"name"`
+	SecretHash   *string    `json:"-"`
+	Confidential bool       `json:"confidential"`
+	RedirectUris []string   `json:"redirect_uris"`
+	CreatedBy    *uuid.UUID `json:"created_by"`
+	CreatedAt    string     `json:"created_at"`
+}
+
+// OAuthAuthorization is an authorization request of a client. UserId is set
+// once the user approved it, together with the authorization code.
+type OAuthAuthorization struct {
+	ID            string
+	ClientId      string
+	RedirectUri   string
+	Scope         string
+	State         string
+	Nonce         string
+	CodeChallenge string
+	Prompt        string
+	UserId        *uuid.UUID
+	CreatedAt     string
+	ExpiresAt     string
+}
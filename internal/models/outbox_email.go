@@ -0,0 +1,28 @@
+package models
+
+import "encoding/json"
+
+// Statuses of email_outbox rows. Pending emails are retried until they are
+// sent or run out of attempts, which leaves them dead until an admin retries
+// them.
+const (
+	OutboxEmailPending = "pending"
+	OutboxEmailSent    = "sent"
+	OutboxEmailDead    = "dead"
+)
+
+type OutboxEmail struct {
+	ID            int64  `json:"id"`
+	Template      string `json:"template"`
+	Recipient     string `json:"recipient"`
+	RecipientName string `json:"recipient_name"`
+	Locale        string `json:"locale"`
+	// Data holds codes and links, it is never returned by the API.
+	Data          json.RawMessage `json:"-"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error"`
+	NextAttemptAt string          `json:"next_attempt_at"`
+	CreatedAt     string          `json:"created_at"`
+	SentAt        *string         `json:"sent_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// RelationTuple states that a subject has a relation to an object, written
+// namespace:object_id#relation@subject. The subject is a user when SubjectId
+// is set, otherwise an object or, with SubjectRelation, a userset such as
+// group:eng#member.
+type RelationTuple struct {
+	Namespace        string
+	ObjectId         string
+	Relation         string
+	SubjectId        string
+	SubjectNamespace string
+	SubjectObjectId  string
+	SubjectRelation  string
+}
@@ -0,0 +1,36 @@
+package models
+
+const (
+	PermissionUsersRead      = "users:read"
+	PermissionUsersWrite     = "users:write"
+	PermissionUsersManage    = "users:manage"
+	PermissionRolesRead      = "roles:read"
+	PermissionRolesWrite     = "roles:write"
+	PermissionRolesAssign    = "roles:assign"
+	PermissionRolesAdmin     = "roles:admin"
+	PermissionSessionsRead   = "sessions:read"
+	PermissionSessionsRevoke = "sessions:revoke"
+	PermissionAuthzCheck     = "authz:check"
+	PermissionAuthzWrite     = "authz:write"
+	PermissionAuditRead      = "audit:read"
+	PermissionOutboxRead     = "outbox:read"
+	PermissionOutboxRetry    = "outbox:retry"
+	PermissionKeysManage     = "keys:manage"
+	PermissionClientsManage  = "clients:manage"
+)
+
+type Role struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// IsSystem marks the built in roles, which cannot be changed or deleted.
+	IsSystem    bool     `json:"is_system"`
+	Permissions []string `json:"permissions"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+type Permission struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
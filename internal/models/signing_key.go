@@ -0,0 +1,23 @@
+package models
+
+// Statuses of signing_keys rows. Exactly one key is active and signs new
+// tokens. Keys that verify only are published so tokens they signed keep
+// working, or so verifiers learn a key before it is promoted. Retired keys are
+// neither published nor accepted.
+const (
+	SigningKeyActive  = "active"
+	SigningKeyVerify  = "verify"
+	SigningKeyRetired = "retired"
+)
+
+type SigningKey struct {
+	ID        string `json:"kid"`
+	Algorithm string `json:"algorithm"`
+	Status    string `json:"status"`
+	// PrivateKey is sealed, it is never returned by the API.
+	PrivateKey    string  `json:"-"`
+	CreatedAt     string  `json:"created_at"`
+	ActivatedAt   *string `json:"activated_at"`
+	DeactivatedAt *string `json:"deactivated_at"`
+	RetiredAt     *string `json:"retired_at"`
+}
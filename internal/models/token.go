@@ -2,11 +2,30 @@ package models
 
 import "github.com/google/uuid"
 
+// Reasons recorded in tokens.revoke_reason.
+const (
+	RevokeReasonRotated       = "rotated"
+	RevokeReasonRevoked       = "revoked"
+	RevokeReasonReuseDetected = "reuse_detected"
+)
+
 type Token struct {
-	ID        int       `json:"id"`
-	Hash      string    `json:"hash"`
-	IsRevoked bool      `json:"is_revoked"`
-	DeviceId  uuid.UUID `json:"device_id"`
-	UserId    uuid.UUID `json:"user_id"`
-	ExpiredAt string    `json:"expired_at"`
+	ID           int       `json:"id"`
+	Hash         string    `json:"-"`
+	Jti          uuid.UUID `json:"-"`
+	FamilyId     uuid.UUID `json:"family_id"`
+	IsRevoked    bool      `json:"is_revoked"`
+	RevokeReason *string   `json:"revoke_reason,omitempty"`
+	DeviceId     uuid.UUID `json:"device_id"`
+	UserId       uuid.UUID `json:"user_id"`
+	UserAgent    string    `json:"user_agent"`
+	IpAddress    string    `json:"ip_address"`
+	CreatedAt    string    `json:"created_at"`
+	LastUsedAt   string    `json:"last_used_at"`
+	RevokedAt    *string   `json:"revoked_at,omitempty"`
+	ExpiredAt    string    `json:"expired_at"`
+	// ClientId is the OAuth client the session was issued to, nil for first
+	// party sessions. Scope holds the scopes the user granted it.
+	ClientId *string `json:"client_id,omitempty"`
+	Scope    string  `json:"scope,omitempty"`
 }
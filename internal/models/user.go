@@ -4,6 +4,11 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	ID         uuid.UUID `json:"id"`
 	Username   string    `json:"username"`
@@ -16,4 +21,13 @@ type User struct {
 	UpdatedAt  string    `json:"updated_at,omitempty"`
 	JwtVersion string    `json:"-"`
 	IsVerified bool      `json:"is_verified"`
+	// Locale is the preferred language as a BCP 47 tag, empty when unknown.
+	Locale string `json:"locale,omitempty"`
+	// LockedAt is set while an admin has locked the account.
+	LockedAt   *string `json:"locked_at,omitempty"`
+	LockReason string  `json:"lock_reason,omitempty"`
+	// PasswordResetRequired blocks password sign in until the password is
+	// reset through the emailed link.
+	PasswordResetRequired bool    `json:"password_reset_required"`
+	DeletedAt             *string `json:"deleted_at,omitempty"`
 }
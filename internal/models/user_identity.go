@@ -0,0 +1,16 @@
+package models
+
+import "github.com/google/uuid"
+
+// IdentityProviderCredentials is the identity backing username/email and
+// password sign in. Its subject is the user id.
+const IdentityProviderCredentials = "credentials"
+
+type UserIdentity struct {
+	ID        int       `json:"id"`
+	UserId    uuid.UUID `json:"-"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"-"`
+	Email     string    `json:"email"`
+	CreatedAt string    `json:"created_at"`
+}
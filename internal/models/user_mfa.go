@@ -0,0 +1,13 @@
+package models
+
+import "github.com/google/uuid"
+
+type UserMfa struct {
+	UserId uuid.UUID `json:"-"`
+	// TotpSecret is stored encrypted, see services.IMfaService.
+	TotpSecret   string  `json:"-"`
+	Enabled      bool    `json:"enabled"`
+	LastUsedStep int64   `json:"-"`
+	CreatedAt    string  `json:"created_at"`
+	EnabledAt    *string `json:"enabled_at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "github.com/google/uuid"
+
+// WebauthnCredential is a registered passkey or security key.
+type WebauthnCredential struct {
+	ID              int       `json:"id"`
+	UserId          uuid.UUID `json:"-"`
+	CredentialId    []byte    `json:"-"`
+	PublicKey       []byte    `json:"-"`
+	AttestationType string    `json:"-"`
+	// Transports is a comma separated list of authenticator transports.
+	Transports     string  `json:"-"`
+	AAGUID         []byte  `json:"-"`
+	SignCount      uint32  `json:"-"`
+	BackupEligible bool    `json:"-"`
+	BackupState    bool    `json:"synced"`
+	Name           string  `json:"name"`
+	CreatedAt      string  `json:"created_at"`
+	LastUsedAt     *string `json:"last_used_at"`
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"my-go-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type CreateAdminActionParams struct {
+	AdminId      uuid.UUID
+	TargetUserId uuid.UUID
+	Action       string
+	Details      []byte
+}
+
+type IAdminActionRepository interface {
+	CreateOne(ctx context.Context, params CreateAdminActionParams) (*models.AdminAction, error)
+	GetByTargetUserId(ctx context.Context, userId uuid.UUID) ([]models.AdminAction, error)
+}
+
+type adminActionRepository struct {
+	db *sql.DB
+}
+
+func NewAdminActionRepository(db *sql.DB) IAdminActionRepository {
+	return &adminActionRepository{db: db}
+}
+
+func (s *adminActionRepository) CreateOne(ctx context.Context, params CreateAdminActionParams) (*models.AdminAction, error) {
+	action := &models.AdminAction{}
+	query := fmt.Sprintf(`
+		INSERT INTO admin_actions (admin_id, target_user_id, action, details)
+		VALUES ($1, $2, $3, $4)
+		RETURNING %s`, adminActionSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query,
+		params.AdminId,
+		params.TargetUserId,
+		params.Action,
+		params.Details,
+	).Scan(scanAdminAction(action)...); err != nil {
+		return nil, err
+	}
+	return action, nil
+}
+
+// GetByTargetUserId returns the actions taken on a user, newest first.
+func (s *adminActionRepository) GetByTargetUserId(ctx context.Context, userId uuid.UUID) ([]models.AdminAction, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM admin_actions
+		WHERE target_user_id = $1
+		ORDER BY created_at DESC, id DESC`, adminActionSelectedFields)
+	rows, err := s.db.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	actions := []models.AdminAction{}
+	for rows.Next() {
+		action := models.AdminAction{}
+		if err := rows.Scan(scanAdminAction(&action)...); err != nil {
+			return nil, err
+		}
+		actions = append(actions, action)
+	}
+	return actions, rows.Err()
+}
+
+func scanAdminAction(action *models.AdminAction) []any {
+	return []any{&action.ID, &action.AdminId, &action.TargetUserId, &action.Action, &action.Details, &action.CreatedAt}
+}
+
+const adminActionSelectedFields = `id, admin_id, target_user_id, action, details, created_at `
@@ -0,0 +1,318 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"my-go-api/internal/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateAuditEventParams leaves actor_id and target_id NULL for uuid.Nil. The
+// event is appended to Chain.
+type CreateAuditEventParams struct {
+	Chain     string
+	Type      string
+	Outcome   string
+	ActorId   uuid.UUID
+	TargetId  uuid.UUID
+	IpAddress string
+	UserAgent string
+	RequestId string
+	Details   []byte
+}
+
+// ListAuditEventsParams filters the audit log. Events are returned newest
+// first; BeforeId continues behind the event with that id and a zero Limit
+// returns every match.
+type ListAuditEventsParams struct {
+	Chain     string
+	ActorId   *uuid.UUID
+	TargetId  *uuid.UUID
+	Type      string
+	Outcome   string
+	IpAddress string
+	RequestId string
+	From      *time.Time
+	To        *time.Time
+	BeforeId  int64
+	Limit     int
+}
+
+type CreateAuditCheckpointParams struct {
+	Chain       string
+	LastEventId int64
+	LastHash    string
+	Signature   string
+}
+
+type IAuditEventRepository interface {
+	CreateOne(ctx context.Context, params CreateAuditEventParams, seal func(event *models.AuditEvent) error) (*models.AuditEvent, error)
+	List(ctx context.Context, params ListAuditEventsParams) ([]models.AuditEvent, error)
+	Each(ctx context.Context, params ListAuditEventsParams, fn func(event models.AuditEvent) error) error
+	Walk(ctx context.Context, chain string, fn func(event models.AuditEvent) error) error
+	GetHead(ctx context.Context, chain string) (*models.AuditEvent, error)
+	GetChains(ctx context.Context) ([]string, error)
+	CreateCheckpoint(ctx context.Context, params CreateAuditCheckpointParams) (*models.AuditCheckpoint, error)
+	GetCheckpoints(ctx context.Context, chain string) ([]models.AuditCheckpoint, error)
+}
+
+// auditChainLockId names the advisory locks that let one writer at a time
+// append to a chain. The second key of the lock is the hash of the chain, so
+// writers of different chains rarely wait for each other.
+const auditChainLockId = 0x61756469
+
+type auditEventRepository struct {
+	db *sql.DB
+}
+
+func NewAuditEventRepository(db *sql.DB) IAuditEventRepository {
+	return &auditEventRepository{db: db}
+}
+
+// CreateOne appends an event to its chain. seal is called with the id,
+// creation time and PrevHash filled in and must set Hash; the event is only
+// stored when it succeeds.
+func (s *auditEventRepository) CreateOne(ctx context.Context, params CreateAuditEventParams, seal func(event *models.AuditEvent) error) (*models.AuditEvent, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// held until the transaction ends, so no other event can slip in between
+	// reading the head and storing the new one
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1, hashtext($2))`, auditChainLockId, params.Chain); err != nil {
+		return nil, err
+	}
+	event := &models.AuditEvent{
+		Chain:     params.Chain,
+		Type:      params.Type,
+		Outcome:   params.Outcome,
+		IpAddress: params.IpAddress,
+		UserAgent: params.UserAgent,
+		RequestId: params.RequestId,
+		Details:   params.Details,
+		// whole seconds, as stored by the column
+		CreatedAt: time.Now().UTC().Truncate(time.Second).Format(time.RFC3339),
+	}
+	if params.ActorId != uuid.Nil {
+		event.ActorId = &params.ActorId
+	}
+	if params.TargetId != uuid.Nil {
+		event.TargetId = &params.TargetId
+	}
+	if err := tx.QueryRowContext(ctx, `SELECT hash FROM audit_events WHERE chain = $1 ORDER BY id DESC LIMIT 1`, event.Chain).Scan(&event.PrevHash); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err := tx.QueryRowContext(ctx, `SELECT nextval(pg_get_serial_sequence('audit_events', 'id'))`).Scan(&event.ID); err != nil {
+		return nil, err
+	}
+	if err := seal(event); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO audit_events
+		(id, chain, event_type, outcome, actor_id, target_id, ip_address, user_agent, request_id, details, created_at, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		event.ID,
+		event.Chain,
+		event.Type,
+		event.Outcome,
+		uuid.NullUUID{UUID: params.ActorId, Valid: params.ActorId != uuid.Nil},
+		uuid.NullUUID{UUID: params.TargetId, Valid: params.TargetId != uuid.Nil},
+		event.IpAddress,
+		event.UserAgent,
+		event.RequestId,
+		event.Details,
+		event.CreatedAt,
+		event.PrevHash,
+		event.Hash,
+	); err != nil {
+		return nil, err
+	}
+	return event, tx.Commit()
+}
+
+func (s *auditEventRepository) List(ctx context.Context, params ListAuditEventsParams) ([]models.AuditEvent, error) {
+	events := []models.AuditEvent{}
+	err := s.Each(ctx, params, func(event models.AuditEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Each calls fn for every matching event while the rows are read, so exports
+// do not hold the whole log in memory. An error from fn stops the iteration.
+func (s *auditEventRepository) Each(ctx context.Context, params ListAuditEventsParams, fn func(event models.AuditEvent) error) error {
+	conditions, args := auditEventFilters(params)
+	query := fmt.Sprintf(`SELECT %s FROM audit_events`, auditEventSelectedFields)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id DESC"
+	if params.Limit > 0 {
+		args = append(args, params.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var event models.AuditEvent
+		if err := rows.Scan(scanAuditEvent(&event)...); err != nil {
+			return err
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Walk calls fn for every event of chain in chain order, oldest first.
+func (s *auditEventRepository) Walk(ctx context.Context, chain string, fn func(event models.AuditEvent) error) error {
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`SELECT %s FROM audit_events WHERE chain = $1 ORDER BY id ASC`, auditEventSelectedFields), chain)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var event models.AuditEvent
+		if err := rows.Scan(scanAuditEvent(&event)...); err != nil {
+			return err
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// GetHead returns the newest event of chain, sql.ErrNoRows when there is none.
+func (s *auditEventRepository) GetHead(ctx context.Context, chain string) (*models.AuditEvent, error) {
+	event := &models.AuditEvent{}
+	query := fmt.Sprintf(`SELECT %s FROM audit_events WHERE chain = $1 ORDER BY id DESC LIMIT 1`, auditEventSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, chain).Scan(scanAuditEvent(event)...); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// GetChains returns every chain that has events or checkpoints, so a chain
+// whose events were all removed is still found through its checkpoints.
+func (s *auditEventRepository) GetChains(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT chain FROM audit_events
+		UNION
+		SELECT chain FROM audit_checkpoints
+		ORDER BY chain`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	chains := []string{}
+	for rows.Next() {
+		var chain string
+		if err := rows.Scan(&chain); err != nil {
+			return nil, err
+		}
+		chains = append(chains, chain)
+	}
+	return chains, rows.Err()
+}
+
+func (s *auditEventRepository) CreateCheckpoint(ctx context.Context, params CreateAuditCheckpointParams) (*models.AuditCheckpoint, error) {
+	checkpoint := &models.AuditCheckpoint{}
+	query := fmt.Sprintf(`
+		INSERT INTO audit_checkpoints (chain, last_event_id, last_hash, signature)
+		VALUES ($1, $2, $3, $4)
+		RETURNING %s`, auditCheckpointSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, params.Chain, params.LastEventId, params.LastHash, params.Signature).Scan(scanAuditCheckpoint(checkpoint)...); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// GetCheckpoints returns every checkpoint of chain in the order they were
+// written.
+func (s *auditEventRepository) GetCheckpoints(ctx context.Context, chain string) ([]models.AuditCheckpoint, error) {
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`SELECT %s FROM audit_checkpoints WHERE chain = $1 ORDER BY id ASC`, auditCheckpointSelectedFields), chain)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	checkpoints := []models.AuditCheckpoint{}
+	for rows.Next() {
+		var checkpoint models.AuditCheckpoint
+		if err := rows.Scan(scanAuditCheckpoint(&checkpoint)...); err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, rows.Err()
+}
+
+func auditEventFilters(params ListAuditEventsParams) ([]string, []any) {
+	conditions := []string{}
+	args := []any{}
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if params.Chain != "" {
+		add("chain = $%d", params.Chain)
+	}
+	if params.ActorId != nil {
+		add("actor_id = $%d", *params.ActorId)
+	}
+	if params.TargetId != nil {
+		add("target_id = $%d", *params.TargetId)
+	}
+	if params.Type != "" {
+		add("event_type = $%d", params.Type)
+	}
+	if params.Outcome != "" {
+		add("outcome = $%d", params.Outcome)
+	}
+	if params.IpAddress != "" {
+		add("ip_address = $%d", params.IpAddress)
+	}
+	if params.RequestId != "" {
+		add("request_id = $%d", params.RequestId)
+	}
+	if params.From != nil {
+		add("created_at >= $%d", *params.From)
+	}
+	if params.To != nil {
+		add("created_at < $%d", *params.To)
+	}
+	if params.BeforeId > 0 {
+		add("id < $%d", params.BeforeId)
+	}
+	return conditions, args
+}
+
+func scanAuditEvent(event *models.AuditEvent) []any {
+	return []any{&event.ID, &event.Chain, &event.Type, &event.Outcome, &event.ActorId, &event.TargetId, &event.IpAddress, &event.UserAgent, &event.RequestId, &event.Details, &event.CreatedAt, &event.PrevHash, &event.Hash}
+}
+
+func scanAuditCheckpoint(checkpoint *models.AuditCheckpoint) []any {
+	return []any{&checkpoint.ID, &checkpoint.Chain, &checkpoint.LastEventId, &checkpoint.LastHash, &checkpoint.Signature, &checkpoint.CreatedAt}
+}
+
+const auditEventSelectedFields = `id, chain, event_type, outcome, actor_id, target_id, ip_address, user_agent, request_id, details, created_at, prev_hash, hash `
+
+const auditCheckpointSelectedFields = `id, chain, last_event_id, last_hash, signature, created_at `
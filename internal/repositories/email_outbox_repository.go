@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"my-go-api/internal/models"
+	"strings"
+	"time"
+)
+
+// EnqueueEmailParams describes an email to render from Template. Data is the
+// JSON encoded template data.
+type EnqueueEmailParams struct {
+	Template      string
+	Recipient     string
+	RecipientName string
+	Locale        string
+	Data          []byte
+}
+
+// ListOutboxEmailsParams filters the outbox, newest first. BeforeId continues
+// behind the email with that id.
+type ListOutboxEmailsParams struct {
+	Status    string
+	Recipient string
+	BeforeId  int64
+	Limit     int
+}
+
+type IEmailOutboxRepository interface {
+	Enqueue(ctx context.Context, params EnqueueEmailParams) (*models.OutboxEmail, error)
+	// Claim leases up to limit due emails to the caller for lease and counts
+	// the attempt. Emails leased by another worker are skipped.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEmail, error)
+	MarkSent(ctx context.Context, id int64) error
+	// MarkFailed records a failed attempt. The email is tried again at
+	// retryAt, or is dead when retryAt is nil.
+	MarkFailed(ctx context.Context, id int64, lastError string, retryAt *time.Time) error
+	// Retry queues a dead email again with a fresh set of attempts. It returns
+	// sql.ErrNoRows when there is no dead email with id.
+	Retry(ctx context.Context, id int64) (*models.OutboxEmail, error)
+	GetOne(ctx context.Context, id int64) (*models.OutboxEmail, error)
+	List(ctx context.Context, params ListOutboxEmailsParams) ([]models.OutboxEmail, error)
+}
+
+type emailOutboxRepository struct {
+	db *sql.DB
+}
+
+func NewEmailOutboxRepository(db *sql.DB) IEmailOutboxRepository {
+	return &emailOutboxRepository{db: db}
+}
+
+// queryRower is implemented by *sql.DB and *sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// enqueueEmail inserts an outbox email through db, which lets other
+// repositories queue an email in the transaction of the change it is about.
+func enqueueEmail(ctx context.Context, db queryRower, params EnqueueEmailParams) (*models.OutboxEmail, error) {
+	email := &models.OutboxEmail{}
+	data := params.Data
+	if len(data) == 0 {
+		data = []byte("{}")
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO email_outbox (template, recipient, recipient_name, locale, data)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING %s`, outboxEmailSelectedFields)
+	if err := db.QueryRowContext(ctx, query, params.Template, params.Recipient, params.RecipientName, params.Locale, data).
+		Scan(scanOutboxEmail(email)...); err != nil {
+		return nil, err
+	}
+	return email, nil
+}
+
+func (s *emailOutboxRepository) Enqueue(ctx context.Context, params EnqueueEmailParams) (*models.OutboxEmail, error) {
+	return enqueueEmail(ctx, s.db, params)
+}
+
+func (s *emailOutboxRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEmail, error) {
+	query := fmt.Sprintf(`
+		UPDATE email_outbox
+		SET locked_until = NOW() + make_interval(secs => $2), attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE status = 'pending' AND next_attempt_at <= NOW() AND (locked_until IS NULL OR locked_until < NOW())
+			ORDER BY next_attempt_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %s`, outboxEmailSelectedFields)
+	rows, err := s.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	emails := []models.OutboxEmail{}
+	for rows.Next() {
+		var email models.OutboxEmail
+		if err := rows.Scan(scanOutboxEmail(&email)...); err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+	return emails, rows.Err()
+}
+
+func (s *emailOutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE email_outbox
+		SET status = 'sent', sent_at = NOW(), locked_until = NULL, last_error = '', data = '{}'
+		WHERE id = $1`, id)
+	return err
+}
+
+func (s *emailOutboxRepository) MarkFailed(ctx context.Context, id int64, lastError string, retryAt *time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE email_outbox
+		SET status = CASE WHEN $3::timestamptz IS NULL THEN 'dead' ELSE 'pending' END,
+			next_attempt_at = COALESCE($3::timestamptz, next_attempt_at),
+			last_error = $2,
+			locked_until = NULL
+		WHERE id = $1`, id, lastError, retryAt)
+	return err
+}
+
+func (s *emailOutboxRepository) Retry(ctx context.Context, id int64) (*models.OutboxEmail, error) {
+	email := &models.OutboxEmail{}
+	query := fmt.Sprintf(`
+		UPDATE email_outbox
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW(), locked_until = NULL
+		WHERE id = $1 AND status = 'dead'
+		RETURNING %s`, outboxEmailSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, id).Scan(scanOutboxEmail(email)...); err != nil {
+		return nil, err
+	}
+	return email, nil
+}
+
+func (s *emailOutboxRepository) GetOne(ctx context.Context, id int64) (*models.OutboxEmail, error) {
+	email := &models.OutboxEmail{}
+	query := fmt.Sprintf(`SELECT %s FROM email_outbox WHERE id = $1`, outboxEmailSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, id).Scan(scanOutboxEmail(email)...); err != nil {
+		return nil, err
+	}
+	return email, nil
+}
+
+func (s *emailOutboxRepository) List(ctx context.Context, params ListOutboxEmailsParams) ([]models.OutboxEmail, error) {
+	conditions := []string{}
+	args := []any{}
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if params.Status != "" {
+		add("status = $%d", params.Status)
+	}
+	if params.Recipient != "" {
+		add("recipient = $%d", params.Recipient)
+	}
+	if params.BeforeId > 0 {
+		add("id < $%d", params.BeforeId)
+	}
+	query := fmt.Sprintf(`SELECT %s FROM email_outbox`, outboxEmailSelectedFields)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, params.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	emails := []models.OutboxEmail{}
+	for rows.Next() {
+		var email models.OutboxEmail
+		if err := rows.Scan(scanOutboxEmail(&email)...); err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+	return emails, rows.Err()
+}
+
+func scanOutboxEmail(email *models.OutboxEmail) []any {
+	return []any{&email.ID, &email.Template, &email.Recipient, &email.RecipientName, &email.Locale, &email.Data, &email.Status, &email.Attempts, &email.LastError, &email.NextAttemptAt, &email.CreatedAt, &email.SentAt}
+}
+
+const outboxEmailSelectedFields = `id, template, recipient, recipient_name, locale, data, status, attempts, last_error, next_attempt_at, created_at, sent_at `
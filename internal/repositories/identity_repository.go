@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"my-go-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var ErrLastIdentity = errors.New("cannot unlink the last login method")
+
+type CreateIdentityParams struct {
+	UserId   uuid.UUID
+	Provider string
+	Subject  string
+	Email    string
+}
+
+type IIdentityRepository interface {
+	CreateOne(ctx context.Context, params CreateIdentityParams) (*models.UserIdentity, error)
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error)
+	GetByUserId(ctx context.Context, userId uuid.UUID) ([]models.UserIdentity, error)
+	DeleteById(ctx context.Context, userId uuid.UUID, id int) (*models.UserIdentity, error)
+	DeleteByUserProvider(ctx context.Context, userId uuid.UUID, provider string) error
+}
+
+type identityRepository struct {
+	db *sql.DB
+}
+
+func NewIdentityRepository(db *sql.DB) IIdentityRepository {
+	return &identityRepository{db: db}
+}
+
+func (s *identityRepository) CreateOne(ctx context.Context, params CreateIdentityParams) (*models.UserIdentity, error) {
+	return createIdentity(ctx, s.db, params)
+}
+
+// createIdentity inserts an identity through db, so a user can be created
+// together with its identity in one transaction.
+func createIdentity(ctx context.Context, db queryRower, params CreateIdentityParams) (*models.UserIdentity, error) {
+	identity := &models.UserIdentity{}
+	query := fmt.Sprintf(`INSERT INTO user_identities (user_id, provider, subject, email)
+		VALUES ($1, $2, $3, $4)
+		RETURNING %s`, identitySelectedFields)
+	if err := db.QueryRowContext(ctx, query,
+		params.UserId,
+		params.Provider,
+		params.Subject,
+		params.Email,
+	).Scan(scanIdentity(identity)...); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (s *identityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	identity := &models.UserIdentity{}
+	query := fmt.Sprintf(`SELECT %s FROM user_identities WHERE provider = $1 AND subject = $2`, identitySelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, provider, subject).Scan(scanIdentity(identity)...); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (s *identityRepository) GetByUserId(ctx context.Context, userId uuid.UUID) ([]models.UserIdentity, error) {
+	query := fmt.Sprintf(`SELECT %s FROM user_identities WHERE user_id = $1 ORDER BY created_at`, identitySelectedFields)
+	rows, err := s.db.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	identities := []models.UserIdentity{}
+	for rows.Next() {
+		var identity models.UserIdentity
+		if err := rows.Scan(scanIdentity(&identity)...); err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+	return identities, rows.Err()
+}
+
+// DeleteById unlinks one identity of the user. The user row is locked so two
+// concurrent unlinks cannot both pass the last-identity check. Unlinking the
+// credentials identity also removes the password.
+func (s *identityRepository) DeleteById(ctx context.Context, userId uuid.UUID, id int) (*models.UserIdentity, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, userId); err != nil {
+		return nil, err
+	}
+	var count int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM user_identities WHERE user_id = $1`, userId).Scan(&count); err != nil {
+		return nil, err
+	}
+
+	identity := &models.UserIdentity{}
+	query := fmt.Sprintf(`SELECT %s FROM user_identities WHERE id = $1 AND user_id = $2`, identitySelectedFields)
+	if err := tx.QueryRowContext(ctx, query, id, userId).Scan(scanIdentity(identity)...); err != nil {
+		return nil, err
+	}
+	if count <= 1 {
+		return nil, ErrLastIdentity
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_identities WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+	if identity.Provider == models.IdentityProviderCredentials {
+		if _, err := tx.ExecContext(ctx, `UPDATE users SET password = NULL, updated_at = NOW() WHERE id = $1`, userId); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (s *identityRepository) DeleteByUserProvider(ctx context.Context, userId uuid.UUID, provider string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM user_identities WHERE user_id = $1 AND provider = $2`, userId, provider)
+	return err
+}
+
+func scanIdentity(identity *models.UserIdentity) []any {
+	return []any{&identity.ID, &identity.UserId, &identity.Provider, &identity.Subject, &identity.Email, &identity.CreatedAt}
+}
+
+const identitySelectedFields = `id, user_id, provider, subject, email, created_at`
@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"my-go-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type IMfaRepository interface {
+	GetByUserId(ctx context.Context, userId uuid.UUID) (*models.UserMfa, error)
+	SavePendingSecret(ctx context.Context, userId uuid.UUID, secret string) error
+	Enable(ctx context.Context, userId uuid.UUID, step int64, recoveryCodeHashes []string) error
+	Delete(ctx context.Context, userId uuid.UUID) error
+	UseStep(ctx context.Context, userId uuid.UUID, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userId uuid.UUID, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userId uuid.UUID, codeHash string) (bool, error)
+	CountRecoveryCodes(ctx context.Context, userId uuid.UUID) (int, error)
+}
+
+type mfaRepository struct {
+	db *sql.DB
+}
+
+func NewMfaRepository(db *sql.DB) IMfaRepository {
+	return &mfaRepository{db: db}
+}
+
+func (s *mfaRepository) GetByUserId(ctx context.Context, userId uuid.UUID) (*models.UserMfa, error) {
+	mfa := &models.UserMfa{}
+	query := fmt.Sprintf(`SELECT %s FROM user_mfa WHERE user_id = $1`, mfaSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, userId).Scan(scanMfa(mfa)...); err != nil {
+		return nil, err
+	}
+	return mfa, nil
+}
+
+// SavePendingSecret stores a new secret awaiting confirmation. An already
+// enabled secret is never replaced.
+func (s *mfaRepository) SavePendingSecret(ctx context.Context, userId uuid.UUID, secret string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO user_mfa (user_id, totp_secret) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET totp_secret = EXCLUDED.totp_secret, last_used_step = 0, created_at = NOW()
+		WHERE user_mfa.enabled = false`, userId, secret)
+	return err
+}
+
+// Enable turns on the confirmed secret and stores the first recovery codes in
+// one transaction.
+func (s *mfaRepository) Enable(ctx context.Context, userId uuid.UUID, step int64, recoveryCodeHashes []string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE user_mfa SET enabled = true, enabled_at = NOW(), last_used_step = $2
+		WHERE user_id = $1 AND enabled = false`, userId, step)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	if err := replaceRecoveryCodes(ctx, tx, userId, recoveryCodeHashes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *mfaRepository) Delete(ctx context.Context, userId uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userId); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_mfa WHERE user_id = $1`, userId); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UseStep records step as the last accepted TOTP time step. It reports false
+// when the step, or a later one, was already used, which stops a code from
+// being replayed within its validity window.
+func (s *mfaRepository) UseStep(ctx context.Context, userId uuid.UUID, step int64) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE user_mfa SET last_used_step = $2
+		WHERE user_id = $1 AND last_used_step < $2`, userId, step)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+func (s *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userId uuid.UUID, codeHashes []string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(ctx, tx, userId, codeHashes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *mfaRepository) UseRecoveryCode(ctx context.Context, userId uuid.UUID, codeHash string) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE mfa_recovery_codes SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`, userId, codeHash)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+func (s *mfaRepository) CountRecoveryCodes(ctx context.Context, userId uuid.UUID) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM mfa_recovery_codes
+		WHERE user_id = $1 AND used_at IS NULL`, userId).Scan(&count)
+	return count, err
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userId uuid.UUID, codeHashes []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userId); err != nil {
+		return err
+	}
+	for _, codeHash := range codeHashes {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO mfa_recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userId, codeHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanMfa(mfa *models.UserMfa) []any {
+	return []any{&mfa.UserId, &mfa.TotpSecret, &mfa.Enabled, &mfa.LastUsedStep, &mfa.CreatedAt, &mfa.EnabledAt}
+}
+
+const mfaSelectedFields = `user_id, totp_secret, enabled, last_used_step, created_at, enabled_at`
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"my-go-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateOAuthAuthorizationParams struct {
+	// Id is the hashed request id.
+	Id            string
+	ClientId      string
+	RedirectUri   string
+	Scope         string
+	State         string
+	Nonce         string
+	CodeChallenge string
+	Prompt        string
+	ExpiresAt     time.Time
+}
+
+type IOAuthAuthorizationRepository interface {
+	Create(ctx context.Context, params CreateOAuthAuthorizationParams) (*models.OAuthAuthorization, error)
+	// GetPending returns the request id while it waits for a decision.
+	GetPending(ctx context.Context, id string) (*models.OAuthAuthorization, error)
+	// Approve attaches the user and the hashed authorization code to the
+	// pending request id and moves its expiry to expiresAt. It returns
+	// sql.ErrNoRows when id is not pending, so a request is decided once.
+	Approve(ctx context.Context, id string, userId uuid.UUID, codeHash string, expiresAt time.Time) (*models.OAuthAuthorization, error)
+	// Deny deletes the pending request id.
+	Deny(ctx context.Context, id string) (*models.OAuthAuthorization, error)
+	// Redeem deletes and returns the approved request of an unexpired code,
+	// so a code can be exchanged once.
+	Redeem(ctx context.Context, codeHash string) (*models.OAuthAuthorization, error)
+	// DeleteExpired removes requests and codes that were never used.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type oauthAuthorizationRepository struct {
+	db *sql.DB
+}
+
+func NewOAuthAuthorizationRepository(db *sql.DB) IOAuthAuthorizationRepository {
+	return &oauthAuthorizationRepository{db: db}
+}
+
+func (s *oauthAuthorizationRepository) Create(ctx context.Context, params CreateOAuthAuthorizationParams) (*models.OAuthAuthorization, error) {
+	authorization := &models.OAuthAuthorization{}
+	query := fmt.Sprintf(`
+		INSERT INTO oauth_authorizations (id, client_id, redirect_uri, scope, state, nonce, code_challenge, prompt, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING %s`, oauthAuthorizationSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query,
+		params.Id,
+		params.ClientId,
+		params.RedirectUri,
+		params.Scope,
+		params.State,
+		params.Nonce,
+		params.CodeChallenge,
+		params.Prompt,
+		params.ExpiresAt,
+	).Scan(scanOAuthAuthorization(authorization)...); err != nil {
+		return nil, err
+	}
+	return authorization, nil
+}
+
+func (s *oauthAuthorizationRepository) GetPending(ctx context.Context, id string) (*models.OAuthAuthorization, error) {
+	authorization := &models.OAuthAuthorization{}
+	query := fmt.Sprintf(`
+		SELECT %s FROM oauth_authorizations
+		WHERE id = $1 AND code_hash IS NULL AND expires_at > NOW()`, oauthAuthorizationSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, id).Scan(scanOAuthAuthorization(authorization)...); err != nil {
+		return nil, err
+	}
+	return authorization, nil
+}
+
+func (s *oauthAuthorizationRepository) Approve(ctx context.Context, id string, userId uuid.UUID, codeHash string, expiresAt time.Time) (*models.OAuthAuthorization, error) {
+	authorization := &models.OAuthAuthorization{}
+	query := fmt.Sprintf(`
+		UPDATE oauth_authorizations SET user_id = $2, code_hash = $3, expires_at = $4
+		WHERE id = $1 AND code_hash IS NULL AND expires_at > NOW()
+		RETURNING %s`, oauthAuthorizationSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, id, userId, codeHash, expiresAt).
+		Scan(scanOAuthAuthorization(authorization)...); err != nil {
+		return nil, err
+	}
+	return authorization, nil
+}
+
+func (s *oauthAuthorizationRepository) Deny(ctx context.Context, id string) (*models.OAuthAuthorization, error) {
+	authorization := &models.OAuthAuthorization{}
+	query := fmt.Sprintf(`
+		DELETE FROM oauth_authorizations
+		WHERE id = $1 AND code_hash IS NULL AND expires_at > NOW()
+		RETURNING %s`, oauthAuthorizationSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, id).Scan(scanOAuthAuthorization(authorization)...); err != nil {
+		return nil, err
+	}
+	return authorization, nil
+}
+
+func (s *oauthAuthorizationRepository) Redeem(ctx context.Context, codeHash string) (*models.OAuthAuthorization, error) {
+	authorization := &models.OAuthAuthorization{}
+	var live bool
+	query := fmt.Sprintf(`
+		DELETE FROM oauth_authorizations
+		WHERE code_hash = $1
+		RETURNING %s, expires_at > NOW()`, oauthAuthorizationSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, codeHash).Scan(append(scanOAuthAuthorization(authorization), &live)...); err != nil {
+		return nil, err
+	}
+	// an expired code is deleted all the same, it cannot be tried again
+	if !live {
+		return nil, sql.ErrNoRows
+	}
+	return authorization, nil
+}
+
+func (s *oauthAuthorizationRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM oauth_authorizations WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func scanOAuthAuthorization(authorization *models.OAuthAuthorization) []any {
+	return []any{&authorization.ID, &authorization.ClientId, &authorization.RedirectUri, &authorization.Scope, &authorization.State, &authorization.Nonce, &authorization.CodeChallenge, &authorization.Prompt, &authorization.UserId, &authorization.CreatedAt, &authorization.ExpiresAt}
+}
+
+const oauthAuthorizationSelectedFields = `id, client_id, redirect_uri, scope, state, nonce, code_challenge, prompt, user_id, created_at, expires_at `
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"my-go-api/internal/models"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type CreateOAuthClientParams struct {
+	Id   string
+	Name string
+	// SecretHash is empty for public clients.
+	SecretHash   string
+	RedirectUris []string
+	CreatedBy    uuid.UUID
+}
+
+type IOAuthClientRepository interface {
+	List(ctx context.Context) ([]models.OAuthClient, error)
+	GetOne(ctx context.Context, id string) (*models.OAuthClient, error)
+	Create(ctx context.Context, params CreateOAuthClientParams) (*models.OAuthClient, error)
+	// Delete removes the client along with its consents, authorizations and
+	// sessions. It returns sql.ErrNoRows when there is no such client.
+	Delete(ctx context.Context, id string) error
+	// GetConsent returns the scopes userId granted the client, sql.ErrNoRows
+	// when it never did.
+	GetConsent(ctx context.Context, userId uuid.UUID, clientId string) ([]string, error)
+	// SaveConsent replaces the scopes userId granted the client.
+	SaveConsent(ctx context.Context, userId uuid.UUID, clientId string, scopes []string) error
+}
+
+type oauthClientRepository struct {
+	db *sql.DB
+}
+
+func NewOAuthClientRepository(db *sql.DB) IOAuthClientRepository {
+	return &oauthClientRepository{db: db}
+}
+
+func (s *oauthClientRepository) List(ctx context.Context) ([]models.OAuthClient, error) {
+	query := fmt.Sprintf(`SELECT %s FROM oauth_clients ORDER BY created_at, id`, oauthClientSelectedFields)
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	clients := []models.OAuthClient{}
+	for rows.Next() {
+		var client models.OAuthClient
+		var redirectUris string
+		if err := rows.Scan(scanOAuthClient(&client, &redirectUris)...); err != nil {
+			return nil, err
+		}
+		clients = append(clients, finishOAuthClient(client, redirectUris))
+	}
+	return clients, rows.Err()
+}
+
+func (s *oauthClientRepository) GetOne(ctx context.Context, id string) (*models.OAuthClient, error) {
+	var client models.OAuthClient
+	var redirectUris string
+	query := fmt.Sprintf(`SELECT %s FROM oauth_clients WHERE id = $1`, oauthClientSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, id).Scan(scanOAuthClient(&client, &redirectUris)...); err != nil {
+		return nil, err
+	}
+	client = finishOAuthClient(client, redirectUris)
+	return &client, nil
+}
+
+func (s *oauthClientRepository) Create(ctx context.Context, params CreateOAuthClientParams) (*models.OAuthClient, error) {
+	var client models.OAuthClient
+	var redirectUris string
+	query := fmt.Sprintf(`
+		INSERT INTO oauth_clients (id, name, secret_hash, redirect_uris, created_by)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5)
+		RETURNING %s`, oauthClientSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query,
+		params.Id,
+		params.Name,
+		params.SecretHash,
+		strings.Join(params.RedirectUris, " "),
+		params.CreatedBy,
+	).Scan(scanOAuthClient(&client, &redirectUris)...); err != nil {
+		return nil, err
+	}
+	client = finishOAuthClient(client, redirectUris)
+	return &client, nil
+}
+
+func (s *oauthClientRepository) Delete(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM oauth_clients WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *oauthClientRepository) GetConsent(ctx context.Context, userId uuid.UUID, clientId string) ([]string, error) {
+	var scope string
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT scope FROM oauth_consents
+		WHERE user_id = $1 AND client_id = $2`, userId, clientId).Scan(&scope); err != nil {
+		return nil, err
+	}
+	return strings.Fields(scope), nil
+}
+
+func (s *oauthClientRepository) SaveConsent(ctx context.Context, userId uuid.UUID, clientId string, scopes []string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO oauth_consents (user_id, client_id, scope)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, client_id) DO UPDATE SET scope = EXCLUDED.scope, updated_at = NOW()`,
+		userId, clientId, strings.Join(scopes, " "))
+	return err
+}
+
+// scanOAuthClient scans the space separated redirect URIs into redirectUris,
+// finishOAuthClient splits them.
+func scanOAuthClient(client *models.OAuthClient, redirectUris *string) []any {
+	return []any{&client.ID, &client.Name, &client.SecretHash, redirectUris, &client.CreatedBy, &client.CreatedAt}
+}
+
+func finishOAuthClient(client models.OAuthClient, redirectUris string) models.OAuthClient {
+	client.RedirectUris = strings.Fields(redirectUris)
+	client.Confidential = client.SecretHash != nil
+	return client
+}
+
+const oauthClientSelectedFields = `id, name, secret_hash, redirect_uris, created_by, created_at `
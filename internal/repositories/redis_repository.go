@@ -13,6 +13,7 @@ type IRedisRepository interface {
 	HGet(key string, field string) (string, error)
 	Delete(key string) error
 	HGetAll(key string) (map[string]string, error)
+	EvalScript(script *redis.Script, keys []string, args ...any) (any, error)
 }
 
 type redisRepository struct {
@@ -70,3 +71,12 @@ func (s *redisRepository) HGetAll(key string) (map[string]string, error) {
 	}
 	return result, nil
 }
+
+func (s *redisRepository) EvalScript(script *redis.Script, keys []string, args ...any) (any, error) {
+	ctx := context.Background()
+	result, err := script.Run(ctx, s.rdb, keys, args...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis script failed: %w", err)
+	}
+	return result, nil
+}
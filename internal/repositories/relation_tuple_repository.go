@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"my-go-api/internal/models"
+)
+
+type IRelationTupleRepository interface {
+	Write(ctx context.Context, tuples []models.RelationTuple) error
+	Delete(ctx context.Context, tuples []models.RelationTuple) error
+	GetByObjectRelation(ctx context.Context, namespace, objectId, relation string) ([]models.RelationTuple, error)
+}
+
+type relationTupleRepository struct {
+	db *sql.DB
+}
+
+func NewRelationTupleRepository(db *sql.DB) IRelationTupleRepository {
+	return &relationTupleRepository{db: db}
+}
+
+// Write stores tuples in one transaction. Tuples that already exist are left
+// as they are.
+func (s *relationTupleRepository) Write(ctx context.Context, tuples []models.RelationTuple) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, tuple := range tuples {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO relation_tuples
+			(namespace, object_id, relation, subject_id, subject_namespace, subject_object_id, subject_relation)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT DO NOTHING`, tupleArgs(tuple)...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *relationTupleRepository) Delete(ctx context.Context, tuples []models.RelationTuple) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, tuple := range tuples {
+		if _, err := tx.ExecContext(ctx, `
+			DELETE FROM relation_tuples
+			WHERE namespace = $1 AND object_id = $2 AND relation = $3 AND subject_id = $4
+			AND subject_namespace = $5 AND subject_object_id = $6 AND subject_relation = $7`, tupleArgs(tuple)...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *relationTupleRepository) GetByObjectRelation(ctx context.Context, namespace, objectId, relation string) ([]models.RelationTuple, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM relation_tuples
+		WHERE namespace = $1 AND object_id = $2 AND relation = $3`, relationTupleSelectedFields)
+	rows, err := s.db.QueryContext(ctx, query, namespace, objectId, relation)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tuples := []models.RelationTuple{}
+	for rows.Next() {
+		tuple := models.RelationTuple{}
+		if err := rows.Scan(scanRelationTuple(&tuple)...); err != nil {
+			return nil, err
+		}
+		tuples = append(tuples, tuple)
+	}
+	return tuples, rows.Err()
+}
+
+func tupleArgs(tuple models.RelationTuple) []any {
+	return []any{tuple.Namespace, tuple.ObjectId, tuple.Relation, tuple.SubjectId, tuple.SubjectNamespace, tuple.SubjectObjectId, tuple.SubjectRelation}
+}
+
+func scanRelationTuple(tuple *models.RelationTuple) []any {
+	return []any{&tuple.Namespace, &tuple.ObjectId, &tuple.Relation, &tuple.SubjectId, &tuple.SubjectNamespace, &tuple.SubjectObjectId, &tuple.SubjectRelation}
+}
+
+const relationTupleSelectedFields = `namespace, object_id, relation, subject_id, subject_namespace, subject_object_id, subject_relation `
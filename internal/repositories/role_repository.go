@@ -0,0 +1,263 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"my-go-api/internal/models"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type CreateRoleParams struct {
+	Name        string
+	Description string
+	Permissions []string
+}
+
+type IRoleRepository interface {
+	GetAll(ctx context.Context) ([]models.Role, error)
+	GetOne(ctx context.Context, id int) (*models.Role, error)
+	CreateOne(ctx context.Context, params CreateRoleParams) (*models.Role, error)
+	SetPermissions(ctx context.Context, id int, permissions []string) error
+	DeleteOne(ctx context.Context, id int) error
+	GetPermissions(ctx context.Context) ([]models.Permission, error)
+	GetUserRoles(ctx context.Context, userId uuid.UUID) ([]models.Role, error)
+	SetUserRoles(ctx context.Context, userId uuid.UUID, roles []string) error
+	GetUserPermissions(ctx context.Context, userId uuid.UUID) ([]string, error)
+	// GetRoleUserIds returns the users holding the role, as their primary
+	// role or an assigned one.
+	GetRoleUserIds(ctx context.Context, id int) ([]uuid.UUID, error)
+}
+
+type roleRepository struct {
+	db *sql.DB
+}
+
+func NewRoleRepository(db *sql.DB) IRoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (s *roleRepository) GetAll(ctx context.Context) ([]models.Role, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s GROUP BY roles.id ORDER BY roles.id`, roleSelectedFields, roleJoins)
+	return s.queryRoles(ctx, query)
+}
+
+func (s *roleRepository) GetOne(ctx context.Context, id int) (*models.Role, error) {
+	role := &models.Role{}
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE roles.id = $1 GROUP BY roles.id`, roleSelectedFields, roleJoins)
+	if err := s.db.QueryRowContext(ctx, query, id).Scan(scanRole(role)...); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// CreateOne stores a custom role together with its permissions in one
+// transaction.
+func (s *roleRepository) CreateOne(ctx context.Context, params CreateRoleParams) (*models.Role, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO roles (name, description) VALUES ($1, $2) RETURNING id`,
+		params.Name, params.Description).Scan(&id); err != nil {
+		return nil, err
+	}
+	if err := replaceRolePermissions(ctx, tx, id, params.Permissions); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.GetOne(ctx, id)
+}
+
+func (s *roleRepository) SetPermissions(ctx context.Context, id int, permissions []string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := replaceRolePermissions(ctx, tx, id, permissions); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteOne removes a custom role. It returns sql.ErrNoRows when there is no
+// such custom role.
+func (s *roleRepository) DeleteOne(ctx context.Context, id int) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM roles WHERE id = $1 AND is_system = false`, id)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *roleRepository) GetPermissions(ctx context.Context) ([]models.Permission, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name, description FROM permissions ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := []models.Permission{}
+	for rows.Next() {
+		permission := models.Permission{}
+		if err := rows.Scan(&permission.ID, &permission.Name, &permission.Description); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions, rows.Err()
+}
+
+// GetUserRoles returns the roles assigned to the user on top of the primary
+// role stored on the user itself.
+func (s *roleRepository) GetUserRoles(ctx context.Context, userId uuid.UUID) ([]models.Role, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE roles.id IN (SELECT role_id FROM user_role_assignments WHERE user_id = $1)
+		GROUP BY roles.id ORDER BY roles.id`, roleSelectedFields, roleJoins)
+	return s.queryRoles(ctx, query, userId)
+}
+
+// SetUserRoles replaces the roles assigned to the user with the roles named.
+func (s *roleRepository) SetUserRoles(ctx context.Context, userId uuid.UUID, roles []string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_role_assignments WHERE user_id = $1`, userId); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO user_role_assignments (user_id, role_id)
+		SELECT $1, id FROM roles WHERE name = ANY($2)`, userId, roles); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetUserPermissions resolves the effective permissions of the user: those of
+// its primary role and of every assigned role.
+func (s *roleRepository) GetUserPermissions(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT permissions.name FROM permissions
+		JOIN role_permissions ON role_permissions.permission_id = permissions.id
+		JOIN roles ON roles.id = role_permissions.role_id
+		WHERE roles.name = (SELECT role::text FROM users WHERE id = $1)
+		OR roles.id IN (SELECT role_id FROM user_role_assignments WHERE user_id = $1)
+		ORDER BY permissions.name`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := []string{}
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions, rows.Err()
+}
+
+func (s *roleRepository) GetRoleUserIds(ctx context.Context, id int) ([]uuid.UUID, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id FROM user_role_assignments WHERE role_id = $1
+		UNION
+		SELECT users.id FROM users JOIN roles ON roles.name = users.role::text
+		WHERE roles.id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	userIds := []uuid.UUID{}
+	for rows.Next() {
+		var userId uuid.UUID
+		if err := rows.Scan(&userId); err != nil {
+			return nil, err
+		}
+		userIds = append(userIds, userId)
+	}
+	return userIds, rows.Err()
+}
+
+func (s *roleRepository) queryRoles(ctx context.Context, query string, args ...any) ([]models.Role, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []models.Role{}
+	for rows.Next() {
+		role := models.Role{}
+		if err := rows.Scan(scanRole(&role)...); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func replaceRolePermissions(ctx context.Context, tx *sql.Tx, roleId int, permissions []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM role_permissions WHERE role_id = $1`, roleId); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO role_permissions (role_id, permission_id)
+		SELECT $1, id FROM permissions WHERE name = ANY($2)`, roleId, permissions)
+	return err
+}
+
+func scanRole(role *models.Role) []any {
+	return []any{&role.ID, &role.Name, &role.Description, &role.IsSystem, commaSeparated{&role.Permissions}, &role.CreatedAt}
+}
+
+const roleSelectedFields = `roles.id, roles.name, roles.description, roles.is_system,
+	COALESCE(string_agg(permissions.name, ',' ORDER BY permissions.name), ''), roles.created_at `
+
+const roleJoins = `roles
+	LEFT JOIN role_permissions ON role_permissions.role_id = roles.id
+	LEFT JOIN permissions ON permissions.id = role_permissions.permission_id`
+
+// commaSeparated scans a comma separated column into a string slice.
+type commaSeparated struct {
+	dest *[]string
+}
+
+func (l commaSeparated) Scan(src any) error {
+	var value string
+	switch v := src.(type) {
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	case nil:
+	default:
+		return fmt.Errorf("cannot scan %T into a string list", src)
+	}
+	*l.dest = []string{}
+	if value != "" {
+		*l.dest = strings.Split(value, ",")
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"my-go-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrSessionNotActive is returned by Rotate when the token being rotated was
+// already revoked, e.g. by a family revocation racing the refresh.
+var ErrSessionNotActive = errors.New("session is not active")
+
+type CreateSessionParams struct {
+	Hash      string
+	Jti       uuid.UUID
+	FamilyId  uuid.UUID
+	DeviceId  uuid.UUID
+	UserId    uuid.UUID
+	UserAgent string
+	IpAddress string
+	ExpiredAt time.Time
+	// ClientId and Scope are set for sessions of OAuth clients.
+	ClientId string
+	Scope    string
+}
+
+type ISessionRepository interface {
+	CreateOne(ctx context.Context, params CreateSessionParams) (*models.Token, error)
+	Rotate(ctx context.Context, oldHash string, params CreateSessionParams) (*models.Token, error)
+	GetByHash(ctx context.Context, hash string) (*models.Token, error)
+	GetActiveByHash(ctx context.Context, hash string) (*models.Token, error)
+	GetActiveByUserId(ctx context.Context, userId uuid.UUID) ([]models.Token, error)
+	RevokeByHash(ctx context.Context, hash string) error
+	RevokeById(ctx context.Context, userId uuid.UUID, id int) (*models.Token, error)
+	RevokeByUserDevice(ctx context.Context, userId, deviceId uuid.UUID) ([]models.Token, error)
+	RevokeAllExcept(ctx context.Context, userId, jti uuid.UUID) ([]models.Token, error)
+	RevokeFamily(ctx context.Context, familyId uuid.UUID) ([]models.Token, error)
+	RevokeByClient(ctx context.Context, clientId string) ([]models.Token, error)
+}
+
+type sessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) ISessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (s *sessionRepository) CreateOne(ctx context.Context, params CreateSessionParams) (*models.Token, error) {
+	token := &models.Token{}
+	query := fmt.Sprintf(`INSERT INTO tokens (hash, jti, family_id, device_id, user_id, user_agent, ip_address, expired_at, client_id, scope)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10)
+		RETURNING %s`, tokenSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query,
+		params.Hash,
+		params.Jti,
+		params.FamilyId,
+		params.DeviceId,
+		params.UserId,
+		params.UserAgent,
+		params.IpAddress,
+		params.ExpiredAt,
+		params.ClientId,
+		params.Scope,
+	).Scan(scanToken(token)...); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// Rotate revokes the token identified by oldHash and stores its successor in a
+// single transaction. The successor joins the token family of its predecessor
+// and keeps the original created_at so a session reports when the device first
+// signed in rather than when it last refreshed; last_used_at of both records
+// is the time of the refresh. A token issued before sessions were persisted
+// has no record and its successor starts a new one; a token whose record is
+// revoked is not rotated and ErrSessionNotActive is returned.
+func (s *sessionRepository) Rotate(ctx context.Context, oldHash string, params CreateSessionParams) (*models.Token, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var createdAt time.Time
+	familyId := params.FamilyId
+	err = tx.QueryRowContext(ctx, `
+		UPDATE tokens
+		SET is_revoked=true, revoked_at=NOW(), revoke_reason=$2, last_used_at=NOW()
+		WHERE hash=$1 AND is_revoked=false
+		RETURNING created_at, family_id`, oldHash, models.RevokeReasonRotated).Scan(&createdAt, &familyId)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		var exists bool
+		if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tokens WHERE hash=$1)`, oldHash).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, ErrSessionNotActive
+		}
+		createdAt = time.Now()
+	}
+
+	token := &models.Token{}
+	query := fmt.Sprintf(`INSERT INTO tokens (hash, jti, family_id, device_id, user_id, user_agent, ip_address, expired_at, created_at, last_used_at, client_id, scope)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NULLIF($10, ''), $11)
+		RETURNING %s`, tokenSelectedFields)
+	if err := tx.QueryRowContext(ctx, query,
+		params.Hash,
+		params.Jti,
+		familyId,
+		params.DeviceId,
+		params.UserId,
+		params.UserAgent,
+		params.IpAddress,
+		params.ExpiredAt,
+		createdAt,
+		params.ClientId,
+		params.Scope,
+	).Scan(scanToken(token)...); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (s *sessionRepository) GetByHash(ctx context.Context, hash string) (*models.Token, error) {
+	token := &models.Token{}
+	query := fmt.Sprintf(`SELECT %s FROM tokens WHERE hash = $1`, tokenSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, hash).Scan(scanToken(token)...); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (s *sessionRepository) GetActiveByHash(ctx context.Context, hash string) (*models.Token, error) {
+	token := &models.Token{}
+	query := fmt.Sprintf(`
+		SELECT %s FROM tokens
+		WHERE hash = $1 AND is_revoked = false AND expired_at > NOW()`, tokenSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, hash).Scan(scanToken(token)...); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (s *sessionRepository) GetActiveByUserId(ctx context.Context, userId uuid.UUID) ([]models.Token, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM tokens
+		WHERE user_id = $1 AND is_revoked = false AND expired_at > NOW()
+		ORDER BY last_used_at DESC`, tokenSelectedFields)
+	return s.queryTokens(ctx, query, userId)
+}
+
+func (s *sessionRepository) RevokeByHash(ctx context.Context, hash string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE tokens
+		SET is_revoked=true, revoked_at=NOW(), revoke_reason=$2, last_used_at=NOW()
+		WHERE hash=$1 AND is_revoked=false`, hash, models.RevokeReasonRevoked)
+	return err
+}
+
+func (s *sessionRepository) RevokeById(ctx context.Context, userId uuid.UUID, id int) (*models.Token, error) {
+	token := &models.Token{}
+	query := fmt.Sprintf(`
+		UPDATE tokens
+		SET is_revoked=true, revoked_at=NOW(), revoke_reason=$3
+		WHERE id=$1 AND user_id=$2 AND is_revoked=false
+		RETURNING %s`, tokenSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, id, userId, models.RevokeReasonRevoked).Scan(scanToken(token)...); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (s *sessionRepository) RevokeByUserDevice(ctx context.Context, userId, deviceId uuid.UUID) ([]models.Token, error) {
+	query := fmt.Sprintf(`
+		UPDATE tokens
+		SET is_revoked=true, revoked_at=NOW(), revoke_reason=$3
+		WHERE user_id=$1 AND device_id=$2 AND is_revoked=false
+		RETURNING %s`, tokenSelectedFields)
+	return s.queryTokens(ctx, query, userId, deviceId, models.RevokeReasonRevoked)
+}
+
+func (s *sessionRepository) RevokeAllExcept(ctx context.Context, userId, jti uuid.UUID) ([]models.Token, error) {
+	query := fmt.Sprintf(`
+		UPDATE tokens
+		SET is_revoked=true, revoked_at=NOW(), revoke_reason=$3
+		WHERE user_id=$1 AND jti IS DISTINCT FROM $2 AND is_revoked=false
+		RETURNING %s`, tokenSelectedFields)
+	return s.queryTokens(ctx, query, userId, jti, models.RevokeReasonRevoked)
+}
+
+func (s *sessionRepository) RevokeFamily(ctx context.Context, familyId uuid.UUID) ([]models.Token, error) {
+	query := fmt.Sprintf(`
+		UPDATE tokens
+		SET is_revoked=true, revoked_at=NOW(), revoke_reason=$2
+		WHERE family_id=$1 AND is_revoked=false
+		RETURNING %s`, tokenSelectedFields)
+	return s.queryTokens(ctx, query, familyId, models.RevokeReasonReuseDetected)
+}
+
+func (s *sessionRepository) RevokeByClient(ctx context.Context, clientId string) ([]models.Token, error) {
+	query := fmt.Sprintf(`
+		UPDATE tokens
+		SET is_revoked=true, revoked_at=NOW(), revoke_reason=$2
+		WHERE client_id=$1 AND is_revoked=false
+		RETURNING %s`, tokenSelectedFields)
+	return s.queryTokens(ctx, query, clientId, models.RevokeReasonRevoked)
+}
+
+func (s *sessionRepository) queryTokens(ctx context.Context, query string, args ...any) ([]models.Token, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tokens := []models.Token{}
+	for rows.Next() {
+		var token models.Token
+		if err := rows.Scan(scanToken(&token)...); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+func scanToken(token *models.Token) []any {
+	return []any{&token.ID, &token.Hash, &token.Jti, &token.FamilyId, &token.IsRevoked, &token.RevokeReason, &token.DeviceId, &token.UserId, &token.UserAgent, &token.IpAddress, &token.CreatedAt, &token.LastUsedAt, &token.RevokedAt, &token.ExpiredAt, &token.ClientId, &token.Scope}
+}
+
+const tokenSelectedFields = `id, hash, jti, family_id, is_revoked, revoke_reason, device_id, user_id, user_agent, ip_address, created_at, last_used_at, revoked_at, expired_at, client_id, scope `
@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"my-go-api/internal/models"
+)
+
+type CreateSigningKeyParams struct {
+	Id         string
+	Algorithm  string
+	PrivateKey string
+}
+
+type ISigningKeyRepository interface {
+	// List returns every key that is not retired, oldest first.
+	List(ctx context.Context) ([]models.SigningKey, error)
+	ListAll(ctx context.Context) ([]models.SigningKey, error)
+	GetOne(ctx context.Context, id string) (*models.SigningKey, error)
+	// Create adds a key that verifies only.
+	Create(ctx context.Context, params CreateSigningKeyParams) (*models.SigningKey, error)
+	// Bootstrap adds params as the active key when the keyring is empty and
+	// does nothing otherwise.
+	Bootstrap(ctx context.Context, params CreateSigningKeyParams) error
+	// Promote makes the verify only key id the active key and demotes the
+	// previous active key to verify only, in one transaction. It returns
+	// sql.ErrNoRows when id is not a verify only key.
+	Promote(ctx context.Context, id string) (*models.SigningKey, error)
+	// Retire retires the verify only key id. It returns sql.ErrNoRows when id
+	// is not a verify only key.
+	Retire(ctx context.Context, id string) (*models.SigningKey, error)
+}
+
+type signingKeyRepository struct {
+	db *sql.DB
+}
+
+func NewSigningKeyRepository(db *sql.DB) ISigningKeyRepository {
+	return &signingKeyRepository{db: db}
+}
+
+func (s *signingKeyRepository) List(ctx context.Context) ([]models.SigningKey, error) {
+	return s.list(ctx, `WHERE status <> 'retired'`)
+}
+
+func (s *signingKeyRepository) ListAll(ctx context.Context) ([]models.SigningKey, error) {
+	return s.list(ctx, "")
+}
+
+func (s *signingKeyRepository) list(ctx context.Context, where string) ([]models.SigningKey, error) {
+	query := fmt.Sprintf(`SELECT %s FROM signing_keys %s ORDER BY created_at, id`, signingKeySelectedFields, where)
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	keys := []models.SigningKey{}
+	for rows.Next() {
+		var key models.SigningKey
+		if err := rows.Scan(scanSigningKey(&key)...); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (s *signingKeyRepository) GetOne(ctx context.Context, id string) (*models.SigningKey, error) {
+	key := &models.SigningKey{}
+	query := fmt.Sprintf(`SELECT %s FROM signing_keys WHERE id = $1`, signingKeySelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, id).Scan(scanSigningKey(key)...); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *signingKeyRepository) Create(ctx context.Context, params CreateSigningKeyParams) (*models.SigningKey, error) {
+	key := &models.SigningKey{}
+	query := fmt.Sprintf(`
+		INSERT INTO signing_keys (id, algorithm, private_key)
+		VALUES ($1, $2, $3)
+		RETURNING %s`, signingKeySelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, params.Id, params.Algorithm, params.PrivateKey).
+		Scan(scanSigningKey(key)...); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *signingKeyRepository) Bootstrap(ctx context.Context, params CreateSigningKeyParams) error {
+	// instances starting together insert the same key, the conflict lets one
+	// of them win
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO signing_keys (id, algorithm, private_key, status, activated_at)
+		SELECT $1, $2, $3, 'active', NOW()
+		WHERE NOT EXISTS (SELECT 1 FROM signing_keys)
+		ON CONFLICT DO NOTHING`, params.Id, params.Algorithm, params.PrivateKey)
+	return err
+}
+
+func (s *signingKeyRepository) Promote(ctx context.Context, id string) (*models.SigningKey, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	if err := tx.QueryRowContext(ctx, `SELECT status FROM signing_keys WHERE id = $1 FOR UPDATE`, id).Scan(&status); err != nil {
+		return nil, err
+	}
+	if status != models.SigningKeyVerify {
+		return nil, sql.ErrNoRows
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE signing_keys SET status = 'verify', deactivated_at = NOW()
+		WHERE status = 'active'`); err != nil {
+		return nil, err
+	}
+	key := &models.SigningKey{}
+	query := fmt.Sprintf(`
+		UPDATE signing_keys SET status = 'active', activated_at = NOW(), deactivated_at = NULL
+		WHERE id = $1
+		RETURNING %s`, signingKeySelectedFields)
+	if err := tx.QueryRowContext(ctx, query, id).Scan(scanSigningKey(key)...); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *signingKeyRepository) Retire(ctx context.Context, id string) (*models.SigningKey, error) {
+	key := &models.SigningKey{}
+	query := fmt.Sprintf(`
+		UPDATE signing_keys SET status = 'retired', retired_at = NOW()
+		WHERE id = $1 AND status = 'verify'
+		RETURNING %s`, signingKeySelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, id).Scan(scanSigningKey(key)...); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func scanSigningKey(key *models.SigningKey) []any {
+	return []any{&key.ID, &key.Algorithm, &key.Status, &key.PrivateKey, &key.CreatedAt, &key.ActivatedAt, &key.DeactivatedAt, &key.RetiredAt}
+}
+
+const signingKeySelectedFields = `id, algorithm, status, private_key, created_at, activated_at, deactivated_at, retired_at `
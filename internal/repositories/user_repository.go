@@ -3,36 +3,95 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"my-go-api/internal/models"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// ErrDuplicateUser is returned by CreateOne when the username or email is
+// already taken.
+var ErrDuplicateUser = errors.New("username or email is already taken")
+
+// pgUniqueViolation is the SQLSTATE of a unique constraint violation.
+const pgUniqueViolation = "23505"
+
 type GetOneParams struct {
 	Username *string
 	Email    *string
 	Id       *uuid.UUID
+	// WithDeleted also finds soft deleted users.
+	WithDeleted bool
 }
 
 type CreateOneParams struct {
+	// Id is generated by the database when it is uuid.Nil.
+	Id         uuid.UUID
 	Name       string
 	Username   string
 	Email      string
 	Password   string
 	JWTVersion string
+	Provider   string
+	IsVerified bool
+	Role       string
+	Locale     string
+	// PasswordResetRequired blocks password sign in until the user resets it.
+	PasswordResetRequired bool
+	// OutboxEmail is queued in the same transaction as the user when it is set.
+	OutboxEmail *EnqueueEmailParams
+	// LinkCredentials adds the credentials identity in the same transaction.
+	LinkCredentials bool
+}
+
+// ListUsersParams filters and orders a page of users. Empty filters match
+// every user.
+type ListUsersParams struct {
+	Search        string
+	Role          string
+	Provider      string
+	IsVerified    *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// SortBy is a key of UserSortColumns, Desc reverses the order.
+	SortBy string
+	Desc   bool
+	// After continues the listing behind the user with this sort value and id.
+	After *UserCursor
+	Limit int
+}
+
+type UserCursor struct {
+	Value string
+	Id    uuid.UUID
+}
+
+// UserSortColumns are the columns users can be listed by. Ties are broken by
+// id so that cursors stay stable.
+var UserSortColumns = map[string]string{
+	"created_at": "created_at",
+	"username":   "username",
+	"name":       "name",
+	"email":      "email",
 }
 
 type IUserRepository interface {
-	GetAll(ctx context.Context) ([]models.User, error)
+	List(ctx context.Context, params ListUsersParams) ([]models.User, error)
+	EstimateCount(ctx context.Context, params ListUsersParams) (int64, error)
 	CreateOne(ctx context.Context, params CreateOneParams) (*models.User, error)
 	GetById(ctx context.Context, userId uuid.UUID) (*models.User, error)
 	GetByUsername(ctx context.Context, username string) (*models.User, error)
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
 	UpdateOne(ctx context.Context, user *models.User) (*models.User, error)
 	GetOne(ctx context.Context, params GetOneParams) (*models.User, error)
+	SetLocked(ctx context.Context, userId uuid.UUID, locked bool, reason string) (*models.User, error)
+	SetDeleted(ctx context.Context, userId uuid.UUID, deleted bool) (*models.User, error)
 }
 
 type userRepository struct {
@@ -60,6 +119,9 @@ func (s *userRepository) GetOne(ctx context.Context, params GetOneParams) (*mode
 	default:
 		return nil, errors.New("no valid query field provided")
 	}
+	if !params.WithDeleted {
+		whereClause += " AND deleted_at IS NULL"
+	}
 	sqlQuery := fmt.Sprintf(`SELECT %s FROM users WHERE %s`, userSelectedFields, whereClause)
 	if err := s.db.QueryRowContext(ctx, sqlQuery, value).Scan(scanUser(user)...); err != nil {
 		return nil, err
@@ -67,9 +129,27 @@ func (s *userRepository) GetOne(ctx context.Context, params GetOneParams) (*mode
 	return user, nil
 }
 
-func (s *userRepository) GetAll(ctx context.Context) ([]models.User, error) {
-	query := fmt.Sprintf(`SELECT %s FROM users`, userSelectedFields)
-	rows, err := s.db.QueryContext(ctx, query)
+// List returns one page of users using keyset pagination on the sort column
+// and id.
+func (s *userRepository) List(ctx context.Context, params ListUsersParams) ([]models.User, error) {
+	column, ok := UserSortColumns[params.SortBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown sort column %q", params.SortBy)
+	}
+	conditions, args := userListFilters(params)
+	order, comparison := "ASC", ">"
+	if params.Desc {
+		order, comparison = "DESC", "<"
+	}
+	if params.After != nil {
+		args = append(args, params.After.Value, params.After.Id)
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s ($%d, $%d)", column, comparison, len(args)-1, len(args)))
+	}
+	args = append(args, params.Limit)
+	query := fmt.Sprintf(`SELECT %s FROM users WHERE %s ORDER BY %s %s, id %s LIMIT $%d`,
+		userSelectedFields, strings.Join(conditions, " AND "), column, order, order, len(args))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -77,29 +157,134 @@ func (s *userRepository) GetAll(ctx context.Context) ([]models.User, error) {
 	users := []models.User{}
 	for rows.Next() {
 		var user models.User
-		err := rows.Scan(scanUser(&user)...)
-		if err != nil {
-			log.Printf("Failed to scan user: %v", err)
+		if err := rows.Scan(scanUser(&user)...); err != nil {
 			return nil, err
 		}
 		users = append(users, user)
 	}
-	return users, nil
+	return users, rows.Err()
+}
+
+// EstimateCount returns the planner's row estimate for the filters of params,
+// which avoids counting every matching row on large tables.
+func (s *userRepository) EstimateCount(ctx context.Context, params ListUsersParams) (int64, error) {
+	conditions, args := userListFilters(params)
+	query := fmt.Sprintf(`EXPLAIN (FORMAT JSON) SELECT 1 FROM users WHERE %s`, strings.Join(conditions, " AND "))
+	var plan []byte
+	if err := s.db.QueryRowContext(ctx, query, args...).Scan(&plan); err != nil {
+		return 0, err
+	}
+	var explained []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(plan, &explained); err != nil {
+		return 0, err
+	}
+	if len(explained) == 0 {
+		return 0, errors.New("empty query plan")
+	}
+	return int64(explained[0].Plan.Rows), nil
+}
+
+// userListFilters builds the WHERE conditions shared by List and
+// EstimateCount.
+func userListFilters(params ListUsersParams) ([]string, []any) {
+	conditions := []string{"deleted_at IS NULL"}
+	args := []any{}
+	if params.Search != "" {
+		// matched with ILIKE, which the trigram indexes on these columns serve
+		args = append(args, "%"+likeEscaper.Replace(params.Search)+"%")
+		n := len(args)
+		conditions = append(conditions, fmt.Sprintf("(username ILIKE $%d OR name ILIKE $%d OR email ILIKE $%d)", n, n, n))
+	}
+	if params.Role != "" {
+		args = append(args, params.Role)
+		conditions = append(conditions, fmt.Sprintf("role = $%d", len(args)))
+	}
+	if params.Provider != "" {
+		args = append(args, params.Provider)
+		conditions = append(conditions, fmt.Sprintf("provider = $%d", len(args)))
+	}
+	if params.IsVerified != nil {
+		args = append(args, *params.IsVerified)
+		conditions = append(conditions, fmt.Sprintf("is_verified = $%d", len(args)))
+	}
+	if params.CreatedAfter != nil {
+		args = append(args, *params.CreatedAfter)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if params.CreatedBefore != nil {
+		args = append(args, *params.CreatedBefore)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+	return conditions, args
 }
 
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// CreateOne inserts the user, its credentials identity and params.OutboxEmail in
+// one transaction, so the email is sent exactly when the user exists and no
+// password account is left without its identity.
 func (s *userRepository) CreateOne(ctx context.Context, params CreateOneParams) (*models.User, error) {
 	user := &models.User{}
-	query := fmt.Sprintf(`INSERT INTO users (name, username, email, password, jwt_version)
-		VALUES ($1, $2, $3, $4, $5)
+	if params.Provider == "" {
+		params.Provider = "credentials"
+	}
+	if params.Role == "" {
+		params.Role = models.RoleUser
+	}
+	var id *uuid.UUID
+	if params.Id != uuid.Nil {
+		id = &params.Id
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`INSERT INTO users (id, name, username, email, password, jwt_version, provider, is_verified, role, locale, password_reset_required)
+		VALUES (COALESCE($1, uuid_generate_v4()), $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10, $11)
 		RETURNING %s`, userSelectedFields)
-	if err := s.db.QueryRowContext(ctx, query,
+	if err := tx.QueryRowContext(ctx, query,
+		id,
 		params.Name,
 		params.Username,
 		params.Email,
 		params.Password,
 		params.JWTVersion,
+		params.Provider,
+		params.IsVerified,
+		params.Role,
+		params.Locale,
+		params.PasswordResetRequired,
 	).
 		Scan(scanUser(user)...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return nil, ErrDuplicateUser
+		}
+		return nil, err
+	}
+	if params.LinkCredentials {
+		if _, err := createIdentity(ctx, tx, CreateIdentityParams{
+			UserId:   user.ID,
+			Provider: models.IdentityProviderCredentials,
+			Subject:  user.ID.String(),
+			Email:    user.Email,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if params.OutboxEmail != nil {
+		if _, err := enqueueEmail(ctx, tx, *params.OutboxEmail); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 	return user, nil
@@ -107,7 +292,7 @@ func (s *userRepository) CreateOne(ctx context.Context, params CreateOneParams)
 
 func (s *userRepository) GetById(ctx context.Context, userId uuid.UUID) (*models.User, error) {
 	user := &models.User{}
-	query := fmt.Sprintf(`SELECT %s FROM users WHERE id = $1`, userSelectedFields)
+	query := fmt.Sprintf(`SELECT %s FROM users WHERE id = $1 AND deleted_at IS NULL`, userSelectedFields)
 	if err := s.db.QueryRowContext(ctx, query, userId).
 		Scan(scanUser(user)...); err != nil {
 		return nil, err
@@ -117,7 +302,7 @@ func (s *userRepository) GetById(ctx context.Context, userId uuid.UUID) (*models
 
 func (s *userRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
 	user := &models.User{}
-	query := fmt.Sprintf(`SELECT %s FROM users WHERE username = $1`,
+	query := fmt.Sprintf(`SELECT %s FROM users WHERE username = $1 AND deleted_at IS NULL`,
 		userSelectedFields,
 	)
 	if err := s.db.QueryRowContext(ctx, query, username).
@@ -129,7 +314,7 @@ func (s *userRepository) GetByUsername(ctx context.Context, username string) (*m
 
 func (s *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	user := &models.User{}
-	query := fmt.Sprintf(`SELECT %s FROM users WHERE email = $1`, userSelectedFields)
+	query := fmt.Sprintf(`SELECT %s FROM users WHERE email = $1 AND deleted_at IS NULL`, userSelectedFields)
 	if err := s.db.QueryRowContext(ctx, query, email).
 		Scan(scanUser(user)...); err != nil {
 		return nil, err
@@ -141,17 +326,47 @@ func (s *userRepository) UpdateOne(ctx context.Context, user *models.User) (*mod
 	log.Println(user)
 	query := fmt.Sprintf(`
 		UPDATE users
-		SET username=$1, email=$2, name=$3, password=$4, role=$5, jwt_version=$6, is_verified=$7, updated_at=NOW()
-		WHERE id=$8 
+		SET username=$1, email=$2, name=$3, password=NULLIF($4, ''), role=$5, jwt_version=$6, is_verified=$7, password_reset_required=$8, locale=$9, updated_at=NOW()
+		WHERE id=$10 
+		RETURNING %s`, userSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, user.Username, user.Email, user.Name, user.Password, user.Role, user.JwtVersion, user.IsVerified, user.PasswordResetRequired, user.Locale, user.ID).Scan(scanUser(user)...); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SetLocked locks the user with reason, or unlocks it.
+func (s *userRepository) SetLocked(ctx context.Context, userId uuid.UUID, locked bool, reason string) (*models.User, error) {
+	user := &models.User{}
+	query := fmt.Sprintf(`
+		UPDATE users
+		SET locked_at = CASE WHEN $2 THEN COALESCE(locked_at, NOW()) END, lock_reason = $3, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING %s`, userSelectedFields)
+	if err := s.db.QueryRowContext(ctx, query, userId, locked, reason).Scan(scanUser(user)...); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SetDeleted soft deletes the user, or restores a soft deleted one.
+func (s *userRepository) SetDeleted(ctx context.Context, userId uuid.UUID, deleted bool) (*models.User, error) {
+	user := &models.User{}
+	query := fmt.Sprintf(`
+		UPDATE users
+		SET deleted_at = CASE WHEN $2 THEN COALESCE(deleted_at, NOW()) END, updated_at = NOW()
+		WHERE id = $1
 		RETURNING %s`, userSelectedFields)
-	if err := s.db.QueryRowContext(ctx, query, user.Username, user.Email, user.Name, user.Password, user.Role, user.JwtVersion, user.IsVerified, user.ID).Scan(scanUser(user)...); err != nil {
+	if err := s.db.QueryRowContext(ctx, query, userId, deleted).Scan(scanUser(user)...); err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
 func scanUser(user *models.User) []any {
-	return []any{&user.ID, &user.Name, &user.Email, &user.Username, &user.Password, &user.JwtVersion, &user.Provider, &user.IsVerified, &user.Role, &user.CreatedAt, &user.UpdatedAt}
+	return []any{&user.ID, &user.Name, &user.Email, &user.Username, &user.Password, &user.JwtVersion, &user.Provider, &user.IsVerified, &user.Role, &user.CreatedAt, &user.UpdatedAt,
+		&user.LockedAt, &user.LockReason, &user.PasswordResetRequired, &user.DeletedAt, &user.Locale}
 }
 
-const userSelectedFields = `id, name, email, username, password, jwt_version, provider, is_verified, role, created_at, updated_at `
+const userSelectedFields = `id, name, email, username, COALESCE(password, ''), jwt_version, provider, is_verified, role, created_at, updated_at,
+	locked_at, lock_reason, password_reset_required, deleted_at, locale `
@@ -28,7 +28,6 @@ func (suite *UserRepositoryTestSuite) SetupSuite() {
 	suite.db = db
 	suite.repo = NewUserRepository(db)
 	suite.db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`)
-	suite.db.Exec(`CREATE TYPE providers AS ENUM ('credentials', 'google')`)
 	suite.db.Exec(`CREATE TYPE user_roles AS ENUM ('user', 'admin')`)
 	// Create the users table
 	_, err = suite.db.Exec(`
@@ -39,10 +38,15 @@ func (suite *UserRepositoryTestSuite) SetupSuite() {
 				name VARCHAR(100) NOT NULL,
 				email VARCHAR(100) UNIQUE NOT NULL,
 				password TEXT,
-				provider providers DEFAULT 'credentials',
+				provider VARCHAR(50) DEFAULT 'credentials',
 				role user_roles DEFAULT 'user',
 				jwt_version VARCHAR(20) NOT NULL,
 				is_verified BOOLEAN NOT NULL DEFAULT false,
+				locked_at TIMESTAMP(0) WITH TIME ZONE,
+				lock_reason VARCHAR(255) NOT NULL DEFAULT '',
+				password_reset_required BOOLEAN NOT NULL DEFAULT false,
+				deleted_at TIMESTAMP(0) WITH TIME ZONE,
+				locale VARCHAR(35) NOT NULL DEFAULT '',
 				created_at TIMESTAMP(0)
 				WITH
 					TIME ZONE NOT NULL DEFAULT NOW (),
@@ -66,10 +70,6 @@ func (suite *UserRepositoryTestSuite) TearDownSuite() {
 	if err != nil {
 		suite.T().Fatal(err)
 	}
-	_, err = suite.db.Exec("DROP TYPE IF EXISTS providers")
-	if err != nil {
-		suite.T().Fatal(err)
-	}
 	_, err = suite.db.Exec("DROP TYPE IF EXISTS user_roles")
 	if err != nil {
 		suite.T().Fatal(err)
@@ -115,17 +115,39 @@ func (suite *UserRepositoryTestSuite) SetupTest() {
 	}
 }
 
-func (suite *UserRepositoryTestSuite) TestGetAll() {
-	suite.Run("It should get all users", func() {
-		users, err := suite.repo.GetAll(context.Background())
+func (suite *UserRepositoryTestSuite) TestList() {
+	suite.Run("It should page through users by username", func() {
+		params := ListUsersParams{SortBy: "username", Limit: 2}
+		users, err := suite.repo.List(context.Background(), params)
+		assert.NoError(suite.T(), err)
+		assert.Len(suite.T(), users, 2)
+		assert.Equal(suite.T(), "dummy00", users[0].Username)
+		assert.Equal(suite.T(), "jane00", users[1].Username)
+
+		params.After = &UserCursor{Value: users[1].Username, Id: users[1].ID}
+		users, err = suite.repo.List(context.Background(), params)
+		assert.NoError(suite.T(), err)
+		assert.Len(suite.T(), users, 1)
+		assert.Equal(suite.T(), "john00", users[0].Username)
+	})
+	suite.Run("It should search case-insensitively", func() {
+		users, err := suite.repo.List(context.Background(), ListUsersParams{SortBy: "created_at", Search: "JOHN", Limit: 10})
+		assert.NoError(suite.T(), err)
+		assert.Len(suite.T(), users, 1)
+		assert.Equal(suite.T(), "john@mail.com", users[0].Email)
+	})
+	suite.Run("It should leave out soft deleted users", func() {
+		_, err := suite.repo.SetDeleted(context.Background(), suite.ids[0], true)
 		assert.NoError(suite.T(), err)
-		assert.NotEmpty(suite.T(), users)
+		users, err := suite.repo.List(context.Background(), ListUsersParams{SortBy: "created_at", Limit: 10})
+		assert.NoError(suite.T(), err)
+		assert.Len(suite.T(), users, 2)
 	})
 	suite.Run("It should return empty slice if no users exist", func() {
 		if _, err := suite.db.Exec("TRUNCATE TABLE users RESTART IDENTITY CASCADE"); err != nil {
 			suite.T().Fatal(err)
 		}
-		users, err := suite.repo.GetAll(context.Background())
+		users, err := suite.repo.List(context.Background(), ListUsersParams{SortBy: "created_at", Limit: 10})
 		assert.NoError(suite.T(), err)
 		assert.Empty(suite.T(), users)
 	})
@@ -174,8 +196,7 @@ func (suite *UserRepositoryTestSuite) TestCreateOne() {
 			Password:   "xcvxvx",
 			JWTVersion: "xcvx",
 		})
-		assert.Error(suite.T(), err)
-		assert.Contains(suite.T(), err.Error(), "duplicate")
+		assert.ErrorIs(suite.T(), err, ErrDuplicateUser)
 	})
 	suite.Run("it should fail, because duplicate username", func() {
 		_, err := suite.repo.CreateOne(context.Background(), CreateOneParams{
@@ -185,8 +206,7 @@ func (suite *UserRepositoryTestSuite) TestCreateOne() {
 			Password:   "xcvxvx",
 			JWTVersion: "xcvx",
 		})
-		assert.Error(suite.T(), err)
-		assert.Contains(suite.T(), err.Error(), "duplicate")
+		assert.ErrorIs(suite.T(), err, ErrDuplicateUser)
 	})
 }
 
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"my-go-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type IWebauthnRepository interface {
+	CreateOne(ctx context.Context, credential *models.WebauthnCredential) (*models.WebauthnCredential, error)
+	GetByUserId(ctx context.Context, userId uuid.UUID) ([]models.WebauthnCredential, error)
+	CountByUserId(ctx context.Context, userId uuid.UUID) (int, error)
+	UpdateUsage(ctx context.Context, credentialId []byte, signCount uint32, backupState bool) (bool, error)
+	DeleteOne(ctx context.Context, userId uuid.UUID, id int) error
+}
+
+type webauthnRepository struct {
+	db *sql.DB
+}
+
+func NewWebauthnRepository(db *sql.DB) IWebauthnRepository {
+	return &webauthnRepository{db: db}
+}
+
+func (s *webauthnRepository) CreateOne(ctx context.Context, credential *models.WebauthnCredential) (*models.WebauthnCredential, error) {
+	created := &models.WebauthnCredential{}
+	query := fmt.Sprintf(`
+		INSERT INTO webauthn_credentials
+		(user_id, credential_id, public_key, attestation_type, transports, aaguid, sign_count, backup_eligible, backup_state, name)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING %s`, webauthnSelectedFields)
+	err := s.db.QueryRowContext(ctx, query,
+		credential.UserId,
+		credential.CredentialId,
+		credential.PublicKey,
+		credential.AttestationType,
+		credential.Transports,
+		credential.AAGUID,
+		int64(credential.SignCount),
+		credential.BackupEligible,
+		credential.BackupState,
+		credential.Name,
+	).Scan(scanWebauthnCredential(created)...)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *webauthnRepository) GetByUserId(ctx context.Context, userId uuid.UUID) ([]models.WebauthnCredential, error) {
+	query := fmt.Sprintf(`SELECT %s FROM webauthn_credentials WHERE user_id = $1 ORDER BY created_at`, webauthnSelectedFields)
+	rows, err := s.db.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	credentials := []models.WebauthnCredential{}
+	for rows.Next() {
+		credential := models.WebauthnCredential{}
+		if err := rows.Scan(scanWebauthnCredential(&credential)...); err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, credential)
+	}
+	return credentials, rows.Err()
+}
+
+func (s *webauthnRepository) CountByUserId(ctx context.Context, userId uuid.UUID) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webauthn_credentials WHERE user_id = $1`, userId).Scan(&count)
+	return count, err
+}
+
+// UpdateUsage stores the sign counter of a successful assertion. It reports
+// false when the stored counter has already moved past signCount, so two
+// assertions carrying the same counter cannot both succeed. Authenticators
+// that do not implement a counter always report zero.
+func (s *webauthnRepository) UpdateUsage(ctx context.Context, credentialId []byte, signCount uint32, backupState bool) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE webauthn_credentials
+		SET sign_count = $2, backup_state = $3, last_used_at = NOW()
+		WHERE credential_id = $1 AND (sign_count < $2 OR $2 = 0)`, credentialId, int64(signCount), backupState)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+func (s *webauthnRepository) DeleteOne(ctx context.Context, userId uuid.UUID, id int) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM webauthn_credentials WHERE id = $1 AND user_id = $2`, id, userId)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanWebauthnCredential(credential *models.WebauthnCredential) []any {
+	return []any{
+		&credential.ID,
+		&credential.UserId,
+		&credential.CredentialId,
+		&credential.PublicKey,
+		&credential.AttestationType,
+		&credential.Transports,
+		&credential.AAGUID,
+		&credential.SignCount,
+		&credential.BackupEligible,
+		&credential.BackupState,
+		&credential.Name,
+		&credential.CreatedAt,
+		&credential.LastUsedAt,
+	}
+}
+
+const webauthnSelectedFields = `id, user_id, credential_id, public_key, attestation_type, transports, aaguid, sign_count, backup_eligible, backup_state, name, created_at, last_used_at`
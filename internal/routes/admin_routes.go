@@ -0,0 +1,75 @@
+package routes
+
+import (
+	"my-go-api/internal/controllers/admin"
+	"my-go-api/internal/middleware"
+	"my-go-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminRoutes struct {
+	route                   *gin.RouterGroup
+	adminController         admin.IAdminController
+	validationMiddleware    middleware.IValidationMiddleware
+	authMiddleware          middleware.IAuthMiddleware
+	authorizationMiddleware middleware.IAuthorizationMiddleware
+	rateLimitMiddleware     middleware.IRateLimitMiddleware
+}
+
+func SetAdminRoutes(params AdminRoutes) {
+	authorize := params.authorizationMiddleware
+
+	v1AdminUsers := params.route.Group("/admin/users")
+	v1AdminUsers.Use(params.authMiddleware.Handler, params.rateLimitMiddleware.ByUser("api"), authorize.RequirePermissions(models.PermissionUsersManage))
+	{
+		v1AdminUsers.POST("", params.validationMiddleware.AdminCreateUser, params.adminController.CreateUser)
+		v1AdminUsers.GET("/:id", params.adminController.GetUser)
+		v1AdminUsers.DELETE("/:id", params.adminController.DeleteUser)
+		v1AdminUsers.POST("/:id/restore", params.adminController.RestoreUser)
+		v1AdminUsers.POST("/:id/lock", params.validationMiddleware.AdminLockUser, params.adminController.LockUser)
+		v1AdminUsers.POST("/:id/unlock", params.adminController.UnlockUser)
+		v1AdminUsers.POST("/:id/verify", params.adminController.VerifyUser)
+		v1AdminUsers.POST("/:id/password-reset", params.adminController.ForcePasswordReset)
+		v1AdminUsers.POST("/:id/revoke-tokens", params.adminController.RevokeTokens)
+		v1AdminUsers.PUT("/:id/role", authorize.RequirePermissions(models.PermissionRolesAssign), params.validationMiddleware.AdminChangeRole, params.adminController.ChangeRole)
+	}
+
+	v1AdminSessions := params.route.Group("/admin/users/:id/sessions")
+	v1AdminSessions.Use(params.authMiddleware.Handler, params.rateLimitMiddleware.ByUser("api"))
+	{
+		v1AdminSessions.GET("", authorize.RequirePermissions(models.PermissionSessionsRead), params.adminController.ListUserSessions)
+		v1AdminSessions.DELETE("/:sessionId", authorize.RequirePermissions(models.PermissionSessionsRevoke), params.adminController.RevokeUserSession)
+	}
+
+	v1AdminAudit := params.route.Group("/admin/audit-events")
+	v1AdminAudit.Use(params.authMiddleware.Handler, params.rateLimitMiddleware.ByUser("api"), authorize.RequirePermissions(models.PermissionAuditRead))
+	{
+		v1AdminAudit.GET("", params.validationMiddleware.ListAuditEvents, params.adminController.ListAuditEvents)
+		v1AdminAudit.GET("/export", params.validationMiddleware.ListAuditEvents, params.adminController.ExportAuditEvents)
+	}
+
+	v1AdminOutbox := params.route.Group("/admin/email-outbox")
+	v1AdminOutbox.Use(params.authMiddleware.Handler, params.rateLimitMiddleware.ByUser("api"), authorize.RequirePermissions(models.PermissionOutboxRead))
+	{
+		v1AdminOutbox.GET("", params.validationMiddleware.ListOutboxEmails, params.adminController.ListOutboxEmails)
+		v1AdminOutbox.POST("/:id/retry", authorize.RequirePermissions(models.PermissionOutboxRetry), params.adminController.RetryOutboxEmail)
+	}
+
+	v1AdminKeys := params.route.Group("/admin/signing-keys")
+	v1AdminKeys.Use(params.authMiddleware.Handler, params.rateLimitMiddleware.ByUser("api"), authorize.RequirePermissions(models.PermissionKeysManage))
+	{
+		v1AdminKeys.GET("", params.adminController.ListSigningKeys)
+		v1AdminKeys.POST("", params.validationMiddleware.IntroduceSigningKey, params.adminController.IntroduceSigningKey)
+		v1AdminKeys.POST("/:kid/promote", params.adminController.PromoteSigningKey)
+		v1AdminKeys.POST("/:kid/retire", params.adminController.RetireSigningKey)
+	}
+
+	v1AdminClients := params.route.Group("/admin/oauth-clients")
+	v1AdminClients.Use(params.authMiddleware.Handler, params.rateLimitMiddleware.ByUser("api"), authorize.RequirePermissions(models.PermissionClientsManage))
+	{
+		v1AdminClients.GET("", params.adminController.ListOAuthClients)
+		v1AdminClients.POST("", params.validationMiddleware.RegisterOAuthClient, params.adminController.RegisterOAuthClient)
+		v1AdminClients.DELETE("/:clientId", params.adminController.DeleteOAuthClient)
+	}
+}
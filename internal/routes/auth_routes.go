@@ -12,20 +12,67 @@ type AuthRoutesParams struct {
 	authController       auth.IAuthController
 	validationMiddleware middleware.IValidationMiddleware
 	authMiddleware       middleware.IAuthMiddleware
+	rateLimitMiddleware  middleware.IRateLimitMiddleware
 }
 
 func SetAuthRoutes(params AuthRoutesParams) {
+	rateLimit := params.rateLimitMiddleware
+	// endpoints sending an email are limited per address as well as per IP
+	emailLimits := []gin.HandlerFunc{rateLimit.ByIP("email_ip"), rateLimit.ByEmail("email")}
 
-	authRoutes := params.route.Group("/auth")
+	authRoutes := params.route.Group("/auth", rateLimit.ByIP("auth"))
 	{
 		authRoutes.GET("", params.authMiddleware.Handler, params.authController.GetAuth)
 		authRoutes.POST("", params.validationMiddleware.Login, params.authController.Login)
 		authRoutes.POST("/refresh-token", params.authController.RefreshToken)
-		authRoutes.POST("/reset-password", params.validationMiddleware.ResetPassword, params.authController.ResetPassword)
-		authRoutes.POST("/forgot-password", params.validationMiddleware.ForgotPassword, params.authController.ForgotPassword)
+		authRoutes.POST("/reset-password", rateLimit.ByIP("verify"), params.validationMiddleware.ResetPassword, params.authController.ResetPassword)
+		authRoutes.POST("/forgot-password", append(emailLimits, params.validationMiddleware.ForgotPassword, params.authController.ForgotPassword)...)
 		authRoutes.POST("/logout", params.authMiddleware.Handler, params.authController.Logout)
-		authRoutes.POST("/register", params.validationMiddleware.Register, params.authController.Register)
-		authRoutes.POST("/resend-verification", params.validationMiddleware.ResendVerification, params.authController.ResendVerification)
-		authRoutes.POST("/verify", params.validationMiddleware.VerifyNewAccount, params.authController.VerifyNewAccount)
+		authRoutes.POST("/register", append(emailLimits, rateLimit.ByIP("register"), params.validationMiddleware.Register, params.authController.Register)...)
+		authRoutes.POST("/resend-verification", append(emailLimits, params.validationMiddleware.ResendVerification, params.authController.ResendVerification)...)
+		authRoutes.POST("/verify", rateLimit.ByIP("verify"), params.validationMiddleware.VerifyNewAccount, params.authController.VerifyNewAccount)
+		authRoutes.GET("/oauth", params.authController.OAuthProviders)
+		authRoutes.GET("/oauth/:provider", params.authController.OAuthLogin)
+		authRoutes.GET("/oauth/:provider/callback", params.authController.OAuthCallback)
+		authRoutes.POST("/mfa/verify", rateLimit.ByIP("verify"), params.validationMiddleware.MfaVerify, params.authController.MfaVerify)
+		authRoutes.POST("/mfa/webauthn/options", params.validationMiddleware.MfaToken, params.authController.MfaWebauthnOptions)
+		authRoutes.POST("/mfa/webauthn", params.validationMiddleware.MfaWebauthn, params.authController.MfaWebauthnVerify)
+		authRoutes.POST("/magic-link", append(emailLimits, params.validationMiddleware.MagicLink, params.authController.RequestMagicLink)...)
+		authRoutes.POST("/magic-link/code", rateLimit.ByIP("verify"), params.validationMiddleware.MagicLinkCode, params.authController.RedeemMagicLinkCode)
+		authRoutes.POST("/magic-link/verify", rateLimit.ByIP("verify"), params.validationMiddleware.MagicLinkVerify, params.authController.RedeemMagicLink)
+		authRoutes.POST("/passkeys/login/options", params.authController.PasskeyLoginOptions)
+		authRoutes.POST("/passkeys/login", params.validationMiddleware.PasskeyLogin, params.authController.PasskeyLogin)
+	}
+
+	sessionRoutes := authRoutes.Group("/sessions", params.authMiddleware.Handler)
+	{
+		sessionRoutes.GET("", params.authController.GetSessions)
+		sessionRoutes.DELETE("", params.authController.RevokeOtherSessions)
+		sessionRoutes.DELETE("/:id", params.authController.RevokeSession)
+	}
+
+	identityRoutes := authRoutes.Group("/identities", params.authMiddleware.Handler)
+	{
+		identityRoutes.GET("", params.authController.GetIdentities)
+		identityRoutes.POST("/password", params.validationMiddleware.SetPassword, params.authController.SetPassword)
+		identityRoutes.POST("/:provider", params.authController.LinkIdentity)
+		identityRoutes.DELETE("/:id", params.authController.UnlinkIdentity)
+	}
+
+	mfaRoutes := authRoutes.Group("/mfa", params.authMiddleware.Handler)
+	{
+		mfaRoutes.GET("", params.authController.GetMfaStatus)
+		mfaRoutes.POST("/totp", params.authController.EnrollTotp)
+		mfaRoutes.POST("/totp/confirm", params.validationMiddleware.MfaCode, params.authController.ConfirmTotp)
+		mfaRoutes.DELETE("/totp", params.validationMiddleware.MfaReauth, params.authController.DisableTotp)
+		mfaRoutes.POST("/recovery-codes", params.validationMiddleware.MfaReauth, params.authController.RegenerateRecoveryCodes)
+	}
+
+	passkeyRoutes := authRoutes.Group("/passkeys", params.authMiddleware.Handler)
+	{
+		passkeyRoutes.GET("", params.authController.GetPasskeys)
+		passkeyRoutes.POST("/register/options", params.authController.PasskeyRegisterOptions)
+		passkeyRoutes.POST("/register", params.validationMiddleware.RegisterPasskey, params.authController.RegisterPasskey)
+		passkeyRoutes.DELETE("/:id", params.authController.DeletePasskey)
 	}
 }
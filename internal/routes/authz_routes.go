@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"my-go-api/internal/controllers/authz"
+	"my-go-api/internal/middleware"
+	"my-go-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthzRoutes struct {
+	route                   *gin.RouterGroup
+	authzController         authz.IAuthzController
+	validationMiddleware    middleware.IValidationMiddleware
+	authMiddleware          middleware.IAuthMiddleware
+	authorizationMiddleware middleware.IAuthorizationMiddleware
+	rateLimitMiddleware     middleware.IRateLimitMiddleware
+}
+
+func SetAuthzRoutes(params AuthzRoutes) {
+	authorize := params.authorizationMiddleware
+
+	v1Authz := params.route.Group("/authz")
+	v1Authz.Use(params.authMiddleware.Handler, params.rateLimitMiddleware.ByUser("api"))
+	{
+		v1Authz.POST("/check", authorize.RequirePermissions(models.PermissionAuthzCheck), params.validationMiddleware.AuthzCheck, params.authzController.Check)
+		v1Authz.POST("/expand", authorize.RequirePermissions(models.PermissionAuthzCheck), params.validationMiddleware.AuthzExpand, params.authzController.Expand)
+		v1Authz.POST("/tuples", authorize.RequirePermissions(models.PermissionAuthzWrite), params.validationMiddleware.RelationTuples, params.authzController.WriteTuples)
+		v1Authz.DELETE("/tuples", authorize.RequirePermissions(models.PermissionAuthzWrite), params.validationMiddleware.RelationTuples, params.authzController.DeleteTuples)
+	}
+}
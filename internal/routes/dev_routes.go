@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"my-go-api/internal/controllers/dev"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DevRoutes struct {
+	route         *gin.RouterGroup
+	devController dev.IDevController
+}
+
+func SetDevRoutes(params DevRoutes) {
+	v1Dev := params.route.Group("/dev")
+	{
+		v1Dev.GET("/emails", params.devController.ListEmailTemplates)
+		v1Dev.GET("/emails/:template", params.devController.PreviewEmail)
+	}
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"my-go-api/internal/controllers/oauth"
+	"my-go-api/internal/middleware"
+	"my-go-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OAuthRoutes struct {
+	route                *gin.RouterGroup
+	oauthController      oauth.IOAuthController
+	validationMiddleware middleware.IValidationMiddleware
+	authMiddleware       middleware.IAuthMiddleware
+	rateLimitMiddleware  middleware.IRateLimitMiddleware
+}
+
+// SetOAuthRoutes registers the OpenID Connect provider. The requests routes
+// are the consent API of the front end and take first party tokens, userinfo
+// takes the access tokens issued to clients.
+func SetOAuthRoutes(params OAuthRoutes) {
+	oauthRoutes := params.route.Group("/oauth", params.rateLimitMiddleware.ByIP("auth"))
+	{
+		oauthRoutes.GET("/authorize", params.oauthController.Authorize)
+		oauthRoutes.POST("/authorize", params.oauthController.Authorize)
+		oauthRoutes.POST("/token", params.oauthController.Token)
+		oauthRoutes.GET("/userinfo", params.authMiddleware.RequireScope(services.OAuthScopeOpenId), params.oauthController.UserInfo)
+		oauthRoutes.POST("/userinfo", params.authMiddleware.RequireScope(services.OAuthScopeOpenId), params.oauthController.UserInfo)
+		oauthRoutes.GET("/requests/:id", params.authMiddleware.Handler, params.oauthController.GetAuthorization)
+		oauthRoutes.POST("/requests/:id", params.authMiddleware.Handler, params.validationMiddleware.DecideOAuthAuthorization, params.oauthController.DecideAuthorization)
+	}
+}
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"my-go-api/internal/controllers/role"
+	"my-go-api/internal/middleware"
+	"my-go-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleRoutes struct {
+	route                   *gin.RouterGroup
+	roleController          role.IRoleController
+	validationMiddleware    middleware.IValidationMiddleware
+	authMiddleware          middleware.IAuthMiddleware
+	authorizationMiddleware middleware.IAuthorizationMiddleware
+	rateLimitMiddleware     middleware.IRateLimitMiddleware
+}
+
+func SetRoleRoutes(params RoleRoutes) {
+	authorize := params.authorizationMiddleware
+
+	v1Roles := params.route.Group("/roles")
+	v1Roles.Use(params.authMiddleware.Handler, params.rateLimitMiddleware.ByUser("api"))
+	{
+		v1Roles.GET("", authorize.RequirePermissions(models.PermissionRolesRead), params.roleController.GetRoles)
+		v1Roles.POST("", authorize.RequirePermissions(models.PermissionRolesWrite), params.validationMiddleware.CreateRole, params.roleController.CreateRole)
+		v1Roles.PUT("/:id/permissions", authorize.RequirePermissions(models.PermissionRolesWrite), params.validationMiddleware.RolePermissions, params.roleController.SetRolePermissions)
+		v1Roles.DELETE("/:id", authorize.RequirePermissions(models.PermissionRolesWrite), params.roleController.DeleteRole)
+	}
+
+	params.route.GET("/permissions", params.authMiddleware.Handler, params.rateLimitMiddleware.ByUser("api"), authorize.RequirePermissions(models.PermissionRolesRead), params.roleController.GetPermissions)
+
+	v1UserRoles := params.route.Group("/users/:id/roles")
+	v1UserRoles.Use(params.authMiddleware.Handler, params.rateLimitMiddleware.ByUser("api"))
+	{
+		v1UserRoles.GET("", authorize.RequireSelfOrPermissions("id", models.PermissionRolesRead), params.roleController.GetUserRoles)
+		v1UserRoles.PUT("", authorize.RequirePermissions(models.PermissionRolesAssign), params.validationMiddleware.UserRoles, params.roleController.SetUserRoles)
+	}
+}
@@ -1,10 +1,18 @@
 package routes
 
 import (
+	"context"
 	"database/sql"
+	"log"
 	"my-go-api/internal/config"
+	"my-go-api/internal/controllers/admin"
 	"my-go-api/internal/controllers/auth"
+	"my-go-api/internal/controllers/authz"
+	"my-go-api/internal/controllers/dev"
+	"my-go-api/internal/controllers/oauth"
+	"my-go-api/internal/controllers/role"
 	"my-go-api/internal/controllers/user"
+	"my-go-api/internal/controllers/wellknown"
 	"my-go-api/internal/middleware"
 	"my-go-api/internal/utils"
 
@@ -26,33 +34,140 @@ func RegisterRoutes(
 
 	router := gin.Default()
 
-	utilities := utils.NewUtilities(config.JWtSecretKey, config.AppUri, config.GoogleOAuth2)
+	utilities := utils.NewUtilities(config.JWtSecretKey, config.AppUri)
 
 	userRepo := repositories.NewUserRepository(db)
 	redisRepo := repositories.NewRedisRepository(rdb)
+	sessionRepo := repositories.NewSessionRepository(db)
+	identityRepo := repositories.NewIdentityRepository(db)
+	mfaRepo := repositories.NewMfaRepository(db)
+	webauthnRepo := repositories.NewWebauthnRepository(db)
+	roleRepo := repositories.NewRoleRepository(db)
+	relationTupleRepo := repositories.NewRelationTupleRepository(db)
+	adminActionRepo := repositories.NewAdminActionRepository(db)
+	auditEventRepo := repositories.NewAuditEventRepository(db)
+	emailOutboxRepo := repositories.NewEmailOutboxRepository(db)
+	signingKeyRepo := repositories.NewSigningKeyRepository(db)
+	oauthClientRepo := repositories.NewOAuthClientRepository(db)
+	oauthAuthorizationRepo := repositories.NewOAuthAuthorizationRepository(db)
 
 	// services
 	redisService := services.NewRedisService(redisRepo)
-	jwtService := services.NewJwtService(config.JWtSecretKey, redisService)
-	authService := services.NewAuthService(redisService, utilities, jwtService)
+	sessionService := services.NewSessionService(sessionRepo)
+	identityService := services.NewIdentityService(identityRepo)
+	roleService := services.NewRoleService(roleRepo, sessionService, redisService)
+	securityEventService := services.NewSecurityEventService()
+	signingKeyService, err := services.NewSigningKeyService(context.Background(), config.Jwt, signingKeyRepo)
+	if err != nil {
+		log.Fatalf("Could not load the signing keys: %v", err)
+	}
+	signingKeyService.Start(context.Background())
+	jwtService := services.NewJwtService(signingKeyService, redisService)
+	authService := services.NewAuthService(
+		redisService,
+		utilities,
+		jwtService,
+		sessionService,
+		roleService,
+		securityEventService,
+		config.RefreshTokenGracePeriod,
+	)
 	userService := services.NewUserService(userRepo)
-	emailService := services.NewEmailService(config.AppUri, utilities)
+	emailSender, err := services.NewEmailSender(config.Email, config.GoogleOAuth2)
+	if err != nil {
+		log.Fatalf("Could not configure the email transport: %v", err)
+	}
+	emailTemplates, err := services.NewEmailTemplates(config.Email.TemplateDir)
+	if err != nil {
+		log.Fatalf("Could not load email templates: %v", err)
+	}
+	emailService := services.NewEmailService(config.AppUri, emailSender, emailTemplates, emailOutboxRepo)
+	emailOutboxService := services.NewEmailOutboxService(config.Email.Outbox, emailOutboxRepo, emailService)
+	emailOutboxService.Start(context.Background())
+	loginProtectionService := services.NewLoginProtectionService(config.LoginProtection, redisService, emailService, securityEventService)
 	passwordService := services.NewPasswordService()
+	oauthProviders, err := services.NewOAuthProviders(config.OAuthProviders)
+	if err != nil {
+		log.Fatalf("Could not configure oauth providers: %v", err)
+	}
+	oauthService := services.NewOAuthService(oauthProviders, redisService, utilities)
+	mfaService := services.NewMfaService(mfaRepo, redisService, utilities, config.Mfa.Issuer, config.Mfa.EncryptionKey)
+	webauthnService, err := services.NewWebauthnService(config.Webauthn, webauthnRepo, redisService, utilities)
+	if err != nil {
+		log.Fatalf("Could not configure webauthn: %v", err)
+	}
+	adminActionService := services.NewAdminActionService(adminActionRepo)
+	auditService := services.NewAuditService(config.Audit, auditEventRepo)
+	oauthServerService := services.NewOAuthServerService(
+		config.Oidc,
+		oauthClientRepo,
+		oauthAuthorizationRepo,
+		authService,
+		sessionService,
+		userService,
+		jwtService,
+		utilities,
+	)
+	if config.Oidc.Enabled() {
+		oauthServerService.Start(context.Background())
+	}
+	authzService, err := services.NewAuthzService(config.AuthzSchema, relationTupleRepo, redisService)
+	if err != nil {
+		log.Fatalf("Could not load authz schema: %v", err)
+	}
 
-	userController := user.NewUserController(userService)
+	userController := user.NewUserController(userService, roleService, auditService, authService, emailService)
+	roleController := role.NewRoleController(roleService, userService, auditService)
+	authzController := authz.NewAuthzController(authzService)
+	devController := dev.NewDevController(emailService)
+	wellKnownController := wellknown.NewWellKnownController(jwtService, oauthServerService)
+	oauthController := oauth.NewOAuthController(oauthServerService)
+	adminController := admin.NewAdminController(
+		userService,
+		passwordService,
+		authService,
+		redisService,
+		sessionService,
+		emailService,
+		roleService,
+		adminActionService,
+		auditService,
+		loginProtectionService,
+		emailOutboxService,
+		signingKeyService,
+		oauthServerService,
+		utilities,
+	)
 	authController := auth.NewAuthController(
 		passwordService,
 		authService,
 		userService,
 		emailService,
 		redisService,
+		sessionService,
+		oauthService,
+		identityService,
+		mfaService,
+		webauthnService,
+		loginProtectionService,
+		auditService,
 		utilities,
 	)
 
 	validationMiddleware := middleware.NewValidationMiddleware(validate)
 	authMiddleware := middleware.NewAuthMiddleware(jwtService, userService)
+	authorizationMiddleware := middleware.NewAuthorizationMiddleware()
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(config.RateLimits, redisService)
+	requestIdMiddleware := middleware.NewRequestIdMiddleware()
 
 	router.SetTrustedProxies([]string{"127.0.0.1"})
+	router.Use(requestIdMiddleware.Handler)
+
+	SetWellKnownRoutes(WellKnownRoutes{
+		router:              router,
+		wellKnownController: wellKnownController,
+		oidc:                config.Oidc.Enabled(),
+	})
 
 	v1 := router.Group("/api/v1")
 	{
@@ -61,9 +176,39 @@ func RegisterRoutes(
 		})
 
 		SetUserRoutes(UserRoutes{
-			route:                v1,
-			userController:       userController,
-			validationMiddleware: validationMiddleware,
+			route:                   v1,
+			userController:          userController,
+			validationMiddleware:    validationMiddleware,
+			authMiddleware:          authMiddleware,
+			authorizationMiddleware: authorizationMiddleware,
+			rateLimitMiddleware:     rateLimitMiddleware,
+		})
+
+		SetRoleRoutes(RoleRoutes{
+			route:                   v1,
+			roleController:          roleController,
+			validationMiddleware:    validationMiddleware,
+			authMiddleware:          authMiddleware,
+			authorizationMiddleware: authorizationMiddleware,
+			rateLimitMiddleware:     rateLimitMiddleware,
+		})
+
+		SetAuthzRoutes(AuthzRoutes{
+			route:                   v1,
+			authzController:         authzController,
+			validationMiddleware:    validationMiddleware,
+			authMiddleware:          authMiddleware,
+			authorizationMiddleware: authorizationMiddleware,
+			rateLimitMiddleware:     rateLimitMiddleware,
+		})
+
+		SetAdminRoutes(AdminRoutes{
+			route:                   v1,
+			adminController:         adminController,
+			validationMiddleware:    validationMiddleware,
+			authMiddleware:          authMiddleware,
+			authorizationMiddleware: authorizationMiddleware,
+			rateLimitMiddleware:     rateLimitMiddleware,
 		})
 
 		SetAuthRoutes(AuthRoutesParams{
@@ -71,7 +216,25 @@ func RegisterRoutes(
 			authController:       authController,
 			authMiddleware:       authMiddleware,
 			validationMiddleware: validationMiddleware,
+			rateLimitMiddleware:  rateLimitMiddleware,
 		})
+
+		if config.Oidc.Enabled() {
+			SetOAuthRoutes(OAuthRoutes{
+				route:                v1,
+				oauthController:      oauthController,
+				validationMiddleware: validationMiddleware,
+				authMiddleware:       authMiddleware,
+				rateLimitMiddleware:  rateLimitMiddleware,
+			})
+		}
+
+		if config.Email.Preview {
+			SetDevRoutes(DevRoutes{
+				route:         v1,
+				devController: devController,
+			})
+		}
 	}
 
 	return router
@@ -3,21 +3,26 @@ package routes
 import (
 	"my-go-api/internal/controllers/user"
 	"my-go-api/internal/middleware"
+	"my-go-api/internal/models"
 
 	"github.com/gin-gonic/gin"
 )
 
 type UserRoutes struct {
-	route                *gin.RouterGroup
-	userController       user.IUserController
-	validationMiddleware middleware.IValidationMiddleware
+	route                   *gin.RouterGroup
+	userController          user.IUserController
+	validationMiddleware    middleware.IValidationMiddleware
+	authMiddleware          middleware.IAuthMiddleware
+	authorizationMiddleware middleware.IAuthorizationMiddleware
+	rateLimitMiddleware     middleware.IRateLimitMiddleware
 }
 
 func SetUserRoutes(params UserRoutes) {
 	v1Users := params.route.Group("/users")
+	v1Users.Use(params.authMiddleware.Handler, params.rateLimitMiddleware.ByUser("api"))
 	{
-		v1Users.GET("", params.userController.GetAll)
-		v1Users.GET("/:id", params.userController.GetUserById)
-		v1Users.PUT("/:id", params.validationMiddleware.UpdateUser, params.userController.Update)
+		v1Users.GET("", params.authorizationMiddleware.RequirePermissions(models.PermissionUsersRead), params.validationMiddleware.ListUsers, params.userController.GetAll)
+		v1Users.GET("/:id", params.authorizationMiddleware.RequireSelfOrPermissions("id", models.PermissionUsersRead), params.userController.GetUserById)
+		v1Users.PUT("/:id", params.authorizationMiddleware.RequireSelfOrPermissions("id", models.PermissionUsersWrite), params.validationMiddleware.UpdateUser, params.userController.Update)
 	}
 }
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"my-go-api/internal/controllers/wellknown"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WellKnownRoutes struct {
+	router              *gin.Engine
+	wellKnownController wellknown.IWellKnownController
+	// oidc serves the discovery document, only when the provider is enabled
+	oidc bool
+}
+
+// SetWellKnownRoutes registers the /.well-known documents at the root of the
+// server, where verifiers look for them.
+func SetWellKnownRoutes(params WellKnownRoutes) {
+	wellKnown := params.router.Group("/.well-known")
+	{
+		wellKnown.GET("/jwks.json", params.wellKnownController.JWKS)
+		if params.oidc {
+			wellKnown.GET("/openid-configuration", params.wellKnownController.OpenIDConfiguration)
+		}
+	}
+}
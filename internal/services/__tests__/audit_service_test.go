@@ -0,0 +1,345 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"my-go-api/internal/config"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+// memoryAuditEventRepository chains events the way the database repository
+// does, and hands details back reformatted like jsonb would.
+type memoryAuditEventRepository struct {
+	events      []models.AuditEvent
+	checkpoints []models.AuditCheckpoint
+	createErr   error
+	createCtx   context.Context
+}
+
+func (r *memoryAuditEventRepository) CreateOne(ctx context.Context, params repositories.CreateAuditEventParams, seal func(event *models.AuditEvent) error) (*models.AuditEvent, error) {
+	r.createCtx = ctx
+	if r.createErr != nil {
+		return nil, r.createErr
+	}
+	event := models.AuditEvent{
+		ID:        int64(len(r.events) + 1),
+		Chain:     params.Chain,
+		Type:      params.Type,
+		Outcome:   params.Outcome,
+		IpAddress: params.IpAddress,
+		UserAgent: params.UserAgent,
+		RequestId: params.RequestId,
+		Details:   params.Details,
+		CreatedAt: time.Now().UTC().Truncate(time.Second).Format(time.RFC3339),
+	}
+	if params.ActorId != uuid.Nil {
+		event.ActorId = &params.ActorId
+	}
+	if params.TargetId != uuid.Nil {
+		event.TargetId = &params.TargetId
+	}
+	if head, err := r.GetHead(ctx, params.Chain); err == nil {
+		event.PrevHash = head.Hash
+	}
+	if err := seal(&event); err != nil {
+		return nil, err
+	}
+	var stored bytes.Buffer
+	if err := json.Indent(&stored, event.Details, "", "  "); err != nil {
+		return nil, err
+	}
+	event.Details = stored.Bytes()
+	r.events = append(r.events, event)
+	return &event, nil
+}
+
+func (r *memoryAuditEventRepository) List(ctx context.Context, params repositories.ListAuditEventsParams) ([]models.AuditEvent, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *memoryAuditEventRepository) Each(ctx context.Context, params repositories.ListAuditEventsParams, fn func(event models.AuditEvent) error) error {
+	return errors.New("not implemented")
+}
+
+func (r *memoryAuditEventRepository) Walk(_ context.Context, chain string, fn func(event models.AuditEvent) error) error {
+	for _, event := range r.events {
+		if event.Chain != chain {
+			continue
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memoryAuditEventRepository) GetHead(_ context.Context, chain string) (*models.AuditEvent, error) {
+	for i := len(r.events) - 1; i >= 0; i-- {
+		if r.events[i].Chain == chain {
+			head := r.events[i]
+			return &head, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *memoryAuditEventRepository) GetChains(_ context.Context) ([]string, error) {
+	chains := []string{}
+	for _, event := range r.events {
+		if !slices.Contains(chains, event.Chain) {
+			chains = append(chains, event.Chain)
+		}
+	}
+	for _, checkpoint := range r.checkpoints {
+		if !slices.Contains(chains, checkpoint.Chain) {
+			chains = append(chains, checkpoint.Chain)
+		}
+	}
+	slices.Sort(chains)
+	return chains, nil
+}
+
+func (r *memoryAuditEventRepository) CreateCheckpoint(_ context.Context, params repositories.CreateAuditCheckpointParams) (*models.AuditCheckpoint, error) {
+	checkpoint := models.AuditCheckpoint{
+		ID:          int64(len(r.checkpoints) + 1),
+		Chain:       params.Chain,
+		LastEventId: params.LastEventId,
+		LastHash:    params.LastHash,
+		Signature:   params.Signature,
+	}
+	r.checkpoints = append(r.checkpoints, checkpoint)
+	return &checkpoint, nil
+}
+
+func (r *memoryAuditEventRepository) GetCheckpoints(_ context.Context, chain string) ([]models.AuditCheckpoint, error) {
+	checkpoints := []models.AuditCheckpoint{}
+	for _, checkpoint := range r.checkpoints {
+		if checkpoint.Chain == chain {
+			checkpoints = append(checkpoints, checkpoint)
+		}
+	}
+	return checkpoints, nil
+}
+
+var _ repositories.IAuditEventRepository = &memoryAuditEventRepository{}
+
+type AuditServiceTestSuite struct {
+	suite.Suite
+	signingKey ed25519.PrivateKey
+	repo       *memoryAuditEventRepository
+	service    services.IAuditService
+}
+
+func (suite *AuditServiceTestSuite) SetupTest() {
+	suite.signingKey = ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, ed25519.SeedSize))
+	suite.repo = &memoryAuditEventRepository{}
+	suite.service = services.NewAuditService(config.AuditConfig{SigningKey: suite.signingKey, CheckpointInterval: 2}, suite.repo)
+}
+
+func (suite *AuditServiceTestSuite) record(count int) {
+	suite.recordOn("", count)
+}
+
+func (suite *AuditServiceTestSuite) recordOn(chain string, count int) {
+	before := len(suite.repo.events)
+	for i := range count {
+		suite.service.Record(context.Background(), services.AuditParams{
+			Chain:    chain,
+			Type:     models.AuditEventLogin,
+			ActorId:  uuid.New(),
+			TargetId: uuid.New(),
+			Details:  map[string]any{"method": "password", "attempt": i},
+		})
+	}
+	suite.Require().Len(suite.repo.events, before+count)
+}
+
+func (suite *AuditServiceTestSuite) verify() *services.AuditChainReport {
+	return suite.verifyChain(models.AuditChainDefault)
+}
+
+func (suite *AuditServiceTestSuite) verifyChain(chain string) *services.AuditChainReport {
+	report, err := suite.service.VerifyChain(context.Background(), chain, nil)
+	suite.Require().NoError(err)
+	return report
+}
+
+func (suite *AuditServiceTestSuite) TestRecordTakesRequestInfoFromContext() {
+	userId := uuid.New()
+	ctx := services.WithRequestInfo(context.Background(), services.RequestInfo{
+		RequestId: "req-1",
+		IpAddress: "203.0.113.9",
+		UserAgent: "curl/8.0",
+	})
+	suite.service.Record(ctx, services.AuditParams{
+		Type:     models.AuditEventLogin,
+		ActorId:  userId,
+		TargetId: userId,
+		Details:  map[string]any{"method": "password"},
+	})
+
+	suite.Require().Len(suite.repo.events, 1)
+	event := suite.repo.events[0]
+	suite.Equal(models.AuditOutcomeSuccess, event.Outcome)
+	suite.Equal(userId, *event.ActorId)
+	suite.Equal("203.0.113.9", event.IpAddress)
+	suite.Equal("curl/8.0", event.UserAgent)
+	suite.Equal("req-1", event.RequestId)
+	suite.JSONEq(`{"method":"password"}`, string(event.Details))
+	suite.Equal(models.AuditChainDefault, event.Chain)
+	suite.Empty(event.PrevHash)
+	suite.Len(event.Hash, 64)
+}
+
+func (suite *AuditServiceTestSuite) TestRecordOutlivesCanceledRequest() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	suite.service.Record(ctx, services.AuditParams{Type: models.AuditEventLogout})
+
+	suite.NoError(suite.repo.createCtx.Err())
+	suite.Require().Len(suite.repo.events, 1)
+	suite.JSONEq(`{}`, string(suite.repo.events[0].Details))
+}
+
+func (suite *AuditServiceTestSuite) TestRecordSwallowsStoreErrors() {
+	suite.repo.createErr = errors.New("connection refused")
+
+	suite.NotPanics(func() {
+		suite.service.Record(context.Background(), services.AuditParams{Type: models.AuditEventLogout})
+	})
+}
+
+func (suite *AuditServiceTestSuite) TestVerifyChainAcceptsUntouchedLog() {
+	suite.record(5)
+
+	report := suite.verify()
+	suite.Nil(report.Break)
+	suite.Equal(int64(5), report.Events)
+	suite.Equal(int64(5), report.HeadId)
+	suite.Equal(2, report.Checkpoints)
+	suite.True(report.SignaturesChecked)
+}
+
+func (suite *AuditServiceTestSuite) TestVerifyChainCountsEventsFromBeforeTheChain() {
+	suite.repo.events = []models.AuditEvent{{ID: 1, Chain: models.AuditChainDefault}, {ID: 2, Chain: models.AuditChainDefault}}
+	suite.record(3)
+
+	report := suite.verify()
+	suite.Nil(report.Break)
+	suite.Equal(int64(2), report.Unchained)
+	suite.Equal(int64(3), report.Events)
+}
+
+func (suite *AuditServiceTestSuite) TestVerifyChainFindsEditedEvent() {
+	suite.record(5)
+	suite.repo.events[2].Details = []byte(`{"method":"passkey"}`)
+
+	report := suite.verify()
+	suite.Require().NotNil(report.Break)
+	suite.Equal(int64(3), report.Break.EventId)
+	suite.Equal("hash does not match the event", report.Break.Reason)
+}
+
+func (suite *AuditServiceTestSuite) TestVerifyChainFindsRehashedEvent() {
+	suite.record(5)
+	// rewriting an event together with its own hash still breaks the link
+	// from the event after it
+	suite.repo.events[2].Outcome = models.AuditOutcomeFailure
+	suite.repo.events[2].Hash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+	report := suite.verify()
+	suite.Require().NotNil(report.Break)
+	suite.Equal(int64(3), report.Break.EventId)
+}
+
+func (suite *AuditServiceTestSuite) TestVerifyChainFindsRemovedEvent() {
+	suite.record(5)
+	suite.repo.events = slices.Delete(suite.repo.events, 2, 3)
+
+	report := suite.verify()
+	suite.Require().NotNil(report.Break)
+	suite.Equal(int64(4), report.Break.EventId)
+	suite.Equal("prev_hash does not match the previous event", report.Break.Reason)
+}
+
+func (suite *AuditServiceTestSuite) TestCheckpointsCatchRemovedTail() {
+	suite.record(4)
+	suite.Require().Len(suite.repo.checkpoints, 2)
+	suite.repo.events = suite.repo.events[:3]
+
+	report := suite.verify()
+	suite.Require().NotNil(report.Break)
+	suite.Equal(int64(4), report.Break.EventId)
+	suite.Equal(int64(2), report.Break.CheckpointId)
+	suite.Equal("checkpointed event is missing", report.Break.Reason)
+}
+
+func (suite *AuditServiceTestSuite) TestChainsAreLinkedAndCheckpointedApart() {
+	suite.recordOn("tenant-a", 3)
+	suite.recordOn("tenant-b", 2)
+	suite.recordOn("tenant-a", 1)
+
+	chains, err := suite.service.Chains(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal([]string{"tenant-a", "tenant-b"}, chains)
+	// the first event of tenant-b starts its own chain
+	suite.Empty(suite.repo.events[3].PrevHash)
+	suite.Equal(suite.repo.events[2].Hash, suite.repo.events[5].PrevHash)
+
+	reportA := suite.verifyChain("tenant-a")
+	suite.Nil(reportA.Break)
+	suite.Equal(int64(4), reportA.Events)
+	suite.Equal(int64(6), reportA.HeadId)
+	suite.Equal(2, reportA.Checkpoints)
+	reportB := suite.verifyChain("tenant-b")
+	suite.Nil(reportB.Break)
+	suite.Equal(int64(2), reportB.Events)
+	suite.Equal(1, reportB.Checkpoints)
+}
+
+func (suite *AuditServiceTestSuite) TestVerifyChainFindsEventMovedToAnotherChain() {
+	suite.recordOn("tenant-a", 3)
+	suite.recordOn("tenant-b", 1)
+	suite.repo.events[3].Chain = "tenant-a"
+	suite.repo.events[3].PrevHash = suite.repo.events[2].Hash
+
+	report := suite.verifyChain("tenant-a")
+	suite.Require().NotNil(report.Break)
+	suite.Equal(int64(4), report.Break.EventId)
+	suite.Equal("hash does not match the event", report.Break.Reason)
+}
+
+func (suite *AuditServiceTestSuite) TestVerifyChainRejectsCheckpointsOfAnotherKey() {
+	suite.record(2)
+
+	otherKey := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{8}, ed25519.SeedSize))
+	report, err := suite.service.VerifyChain(context.Background(), models.AuditChainDefault, otherKey.Public().(ed25519.PublicKey))
+	suite.Require().NoError(err)
+	suite.Require().NotNil(report.Break)
+	suite.Equal("checkpoint signature does not verify", report.Break.Reason)
+}
+
+func (suite *AuditServiceTestSuite) TestCheckpointNeedsSigningKey() {
+	service := services.NewAuditService(config.AuditConfig{CheckpointInterval: 1}, suite.repo)
+	service.Record(context.Background(), services.AuditParams{Type: models.AuditEventLogout})
+
+	suite.Empty(suite.repo.checkpoints)
+	_, err := service.Checkpoint(context.Background(), models.AuditChainDefault)
+	suite.ErrorIs(err, services.ErrAuditSigningDisabled)
+}
+
+func TestAuditServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditServiceTestSuite))
+}
@@ -1,11 +1,15 @@
 package services_test
 
 import (
+	"context"
 	"errors"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
 	"my-go-api/internal/services"
 	mockutils "my-go-api/mocks"
 	mockservices "my-go-api/mocks/mock_services"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -19,6 +23,9 @@ type AuthServiceTestSuite struct {
 	mockRedis *mockservices.MockIRedisService
 	mockUtils *mockutils.MockIUtils
 	mockJwt   *mockservices.MockIJwtService
+	mockSess  *mockservices.MockISessionService
+	mockRole  *mockservices.MockIRoleService
+	mockEvent *mockservices.MockISecurityEventService
 	services  services.IAuthService
 }
 
@@ -27,13 +34,20 @@ func (suite *AuthServiceTestSuite) SetupTest() {
 	suite.mockRedis = mockservices.NewMockIRedisService(suite.ctrl)
 	suite.mockUtils = mockutils.NewMockIUtils(suite.ctrl)
 	suite.mockJwt = mockservices.NewMockIJwtService(suite.ctrl)
-	suite.services = services.NewAuthService(suite.mockRedis, suite.mockUtils, suite.mockJwt)
+	suite.mockSess = mockservices.NewMockISessionService(suite.ctrl)
+	suite.mockRole = mockservices.NewMockIRoleService(suite.ctrl)
+	suite.mockEvent = mockservices.NewMockISecurityEventService(suite.ctrl)
+	suite.services = services.NewAuthService(suite.mockRedis, suite.mockUtils, suite.mockJwt, suite.mockSess, suite.mockRole, suite.mockEvent, 10*time.Second)
 }
 
 func (suite *AuthServiceTestSuite) TearDownTest() {
 	suite.ctrl.Finish()
 }
 
+func (suite *AuthServiceTestSuite) expectPermissions() {
+	suite.mockRole.EXPECT().GetUserPermissions(gomock.Any(), gomock.Any()).Return([]string{}, nil)
+}
+
 func (suite *AuthServiceTestSuite) TestCreateAuthTokens() {
 
 	suite.Run("It should work for login flow", func() {
@@ -43,13 +57,19 @@ func (suite *AuthServiceTestSuite) TestCreateAuthTokens() {
 		accessToken := "access_token"
 		jwtVersion := "v1"
 
+		suite.mockRole.EXPECT().GetUserPermissions(gomock.Any(), userId).Return([]string{models.PermissionUsersRead}, nil)
 		suite.mockUtils.EXPECT().GenerateRandomBytes(32).Return(rawToken, nil)
 		suite.mockUtils.EXPECT().HashWithSHA256(rawToken).Return(hashedToken)
-		suite.mockJwt.EXPECT().Create(gomock.Any()).Return(accessToken, nil)
+		suite.mockJwt.EXPECT().Create(gomock.Cond(func(x any) bool {
+			payload, ok := x.(services.JWTPayload)
+			return ok && payload.UserId == userId.String() && len(payload.Permissions) == 1 && payload.Permissions[0] == models.PermissionUsersRead
+		})).Return(accessToken, nil)
 		suite.mockRedis.EXPECT().SaveRefreshToken(gomock.Any()).Return(nil)
+		suite.mockSess.EXPECT().RevokeDeviceSessions(gomock.Any(), userId, gomock.Any()).Return([]models.Token{}, nil)
+		suite.mockSess.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(&models.Token{}, nil)
 		suite.mockRedis.EXPECT().SaveAccessToken(gomock.Any()).Return(nil)
 
-		result, err := suite.services.CreateAuthTokens(services.CreateAuthTokenParams{
+		result, err := suite.services.CreateAuthTokens(context.Background(), services.CreateAuthTokenParams{
 			UserId:     userId,
 			JwtVersion: jwtVersion,
 		})
@@ -60,6 +80,7 @@ func (suite *AuthServiceTestSuite) TestCreateAuthTokens() {
 	})
 
 	suite.Run("It should work for refresh token flow", func() {
+		suite.expectPermissions()
 		userId := uuid.New()
 		rawToken := "raw_refresh_token"
 		hashedToken := "hashed_refresh_token"
@@ -75,10 +96,11 @@ func (suite *AuthServiceTestSuite) TestCreateAuthTokens() {
 		suite.mockUtils.EXPECT().GenerateRandomBytes(32).Return(rawToken, nil)
 		suite.mockUtils.EXPECT().HashWithSHA256(rawToken).Return(hashedToken)
 		suite.mockRedis.EXPECT().SaveRefreshToken(gomock.Any()).Return(nil)
+		suite.mockSess.EXPECT().RotateSession(gomock.Any(), oldHashedToken, gomock.Any()).Return(&models.Token{}, nil)
 		suite.mockJwt.EXPECT().Create(gomock.Any()).Return(accessToken, nil)
 		suite.mockRedis.EXPECT().SaveAccessToken(gomock.Any()).Return(nil)
 
-		result, err := suite.services.CreateAuthTokens(services.CreateAuthTokenParams{
+		result, err := suite.services.CreateAuthTokens(context.Background(), services.CreateAuthTokenParams{
 			UserId:      userId,
 			JwtVersion:  jwtVersion,
 			OldRefToken: &oldRefToken,
@@ -92,6 +114,131 @@ func (suite *AuthServiceTestSuite) TestCreateAuthTokens() {
 
 }
 
+func (suite *AuthServiceTestSuite) TestCreateAuthTokensSessions() {
+	suite.Run("It should record the session against the device", func() {
+		suite.expectPermissions()
+		userId := uuid.New()
+		deviceId := uuid.New()
+		rawToken := "raw_refresh_token"
+		hashedToken := "hashed_refresh_token"
+
+		suite.mockUtils.EXPECT().GenerateRandomBytes(32).Return(rawToken, nil)
+		suite.mockUtils.EXPECT().HashWithSHA256(rawToken).Return(hashedToken)
+		suite.mockRedis.EXPECT().SaveRefreshToken(gomock.Any()).Return(nil)
+		suite.mockSess.EXPECT().RevokeDeviceSessions(gomock.Any(), userId, deviceId).Return([]models.Token{}, nil)
+		suite.mockSess.EXPECT().CreateSession(gomock.Any(), gomock.Cond(func(x any) bool {
+			params, ok := x.(repositories.CreateSessionParams)
+			return ok &&
+				params.Hash == hashedToken &&
+				params.DeviceId == deviceId &&
+				params.UserAgent == "Mozilla/5.0" &&
+				params.IpAddress == "10.0.0.1"
+		})).Return(&models.Token{}, nil)
+		suite.mockJwt.EXPECT().Create(gomock.Any()).Return("access_token", nil)
+		suite.mockRedis.EXPECT().SaveAccessToken(gomock.Any()).Return(nil)
+
+		_, err := suite.services.CreateAuthTokens(context.Background(), services.CreateAuthTokenParams{
+			UserId:     userId,
+			JwtVersion: "v1",
+			DeviceId:   deviceId,
+			UserAgent:  "Mozilla/5.0",
+			IpAddress:  "10.0.0.1",
+		})
+
+		assert.NoError(suite.T(), err)
+	})
+
+	suite.Run("It should drop tokens of the session previously open on the device", func() {
+		suite.expectPermissions()
+		userId := uuid.New()
+		deviceId := uuid.New()
+		previousJti := uuid.New()
+		rawToken := "raw_refresh_token"
+		hashedToken := "hashed_refresh_token"
+
+		suite.mockUtils.EXPECT().GenerateRandomBytes(32).Return(rawToken, nil)
+		suite.mockUtils.EXPECT().HashWithSHA256(rawToken).Return(hashedToken)
+		suite.mockRedis.EXPECT().SaveRefreshToken(gomock.Any()).Return(nil)
+		suite.mockSess.EXPECT().RevokeDeviceSessions(gomock.Any(), userId, deviceId).Return([]models.Token{
+			{Hash: "previous_hash", Jti: previousJti},
+		}, nil)
+		suite.mockRedis.EXPECT().DeleteRefreshToken("previous_hash").Return(nil)
+		suite.mockRedis.EXPECT().DeleteAccessToken(previousJti.String()).Return(nil)
+		suite.mockSess.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(&models.Token{}, nil)
+		suite.mockJwt.EXPECT().Create(gomock.Any()).Return("access_token", nil)
+		suite.mockRedis.EXPECT().SaveAccessToken(gomock.Any()).Return(nil)
+
+		_, err := suite.services.CreateAuthTokens(context.Background(), services.CreateAuthTokenParams{
+			UserId:     userId,
+			JwtVersion: "v1",
+			DeviceId:   deviceId,
+		})
+
+		assert.NoError(suite.T(), err)
+	})
+
+	suite.Run("Fail when unable to store the session", func() {
+		suite.expectPermissions()
+		userId := uuid.New()
+		rawToken := "raw_refresh_token"
+		hashedToken := "hashed_refresh_token"
+
+		suite.mockUtils.EXPECT().GenerateRandomBytes(32).Return(rawToken, nil)
+		suite.mockUtils.EXPECT().HashWithSHA256(rawToken).Return(hashedToken)
+		suite.mockRedis.EXPECT().SaveRefreshToken(gomock.Any()).Return(nil)
+		suite.mockSess.EXPECT().RevokeDeviceSessions(gomock.Any(), userId, gomock.Any()).Return([]models.Token{}, nil)
+		suite.mockSess.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+		suite.mockRedis.EXPECT().DeleteRefreshToken(hashedToken).Return(nil)
+
+		_, err := suite.services.CreateAuthTokens(context.Background(), services.CreateAuthTokenParams{
+			UserId:     userId,
+			JwtVersion: "v1",
+		})
+
+		assert.Error(suite.T(), err)
+	})
+}
+
+func (suite *AuthServiceTestSuite) TestHandleRefreshTokenReuse() {
+	suite.Run("It should revoke the whole family of a rotated token", func() {
+		rotated := models.RevokeReasonRotated
+		familyId := uuid.New()
+		descendant := models.Token{Hash: "descendant_hash", Jti: uuid.New()}
+
+		suite.mockSess.EXPECT().GetSessionByHash(gomock.Any(), "replayed_hash").Return(&models.Token{
+			IsRevoked:    true,
+			RevokeReason: &rotated,
+			FamilyId:     familyId,
+		}, nil)
+		suite.mockSess.EXPECT().RevokeTokenFamily(gomock.Any(), familyId).Return([]models.Token{descendant}, nil)
+		suite.mockRedis.EXPECT().DeleteRefreshToken("descendant_hash").Return(nil)
+		suite.mockRedis.EXPECT().DeleteAccessToken(descendant.Jti.String()).Return(nil)
+		suite.mockEvent.EXPECT().Emit(gomock.Any(), gomock.Cond(func(x any) bool {
+			event, ok := x.(services.SecurityEvent)
+			return ok && event.Type == services.SecurityEventRefreshTokenReuse && event.Details["family_id"] == familyId.String()
+		}))
+
+		assert.True(suite.T(), suite.services.HandleRefreshTokenReuse(context.Background(), "replayed_hash", "10.0.0.1", "curl"))
+	})
+
+	suite.Run("It should ignore tokens revoked for other reasons", func() {
+		revoked := models.RevokeReasonRevoked
+
+		suite.mockSess.EXPECT().GetSessionByHash(gomock.Any(), "logged_out_hash").Return(&models.Token{
+			IsRevoked:    true,
+			RevokeReason: &revoked,
+		}, nil)
+
+		assert.False(suite.T(), suite.services.HandleRefreshTokenReuse(context.Background(), "logged_out_hash", "10.0.0.1", "curl"))
+	})
+
+	suite.Run("It should ignore unknown tokens", func() {
+		suite.mockSess.EXPECT().GetSessionByHash(gomock.Any(), "unknown_hash").Return(nil, errors.New("no rows"))
+
+		assert.False(suite.T(), suite.services.HandleRefreshTokenReuse(context.Background(), "unknown_hash", "10.0.0.1", "curl"))
+	})
+}
+
 func (suite *AuthServiceTestSuite) TestVerificationTokenFlow() {
 	suite.Run("Successfully create verification token", func() {
 		userId := uuid.New()
@@ -188,12 +335,13 @@ func (suite *AuthServiceTestSuite) TestVerifyVerificationToken() {
 
 func (suite *AuthServiceTestSuite) TestCreateAuthTokensErrorCases() {
 	suite.Run("Fail when unable to generate random bytes", func() {
+		suite.expectPermissions()
 		userId := uuid.New()
 		jwtVersion := "v1"
 
 		suite.mockUtils.EXPECT().GenerateRandomBytes(32).Return("", errors.New("random error"))
 
-		_, err := suite.services.CreateAuthTokens(services.CreateAuthTokenParams{
+		_, err := suite.services.CreateAuthTokens(context.Background(), services.CreateAuthTokenParams{
 			UserId:     userId,
 			JwtVersion: jwtVersion,
 		})
@@ -202,6 +350,7 @@ func (suite *AuthServiceTestSuite) TestCreateAuthTokensErrorCases() {
 	})
 
 	suite.Run("Fail when unable to save refresh token", func() {
+		suite.expectPermissions()
 		userId := uuid.New()
 		rawToken := "raw_refresh_token"
 		hashedToken := "hashed_refresh_token"
@@ -211,7 +360,7 @@ func (suite *AuthServiceTestSuite) TestCreateAuthTokensErrorCases() {
 		suite.mockUtils.EXPECT().HashWithSHA256(rawToken).Return(hashedToken)
 		suite.mockRedis.EXPECT().SaveRefreshToken(gomock.Any()).Return(errors.New("redis error"))
 
-		_, err := suite.services.CreateAuthTokens(services.CreateAuthTokenParams{
+		_, err := suite.services.CreateAuthTokens(context.Background(), services.CreateAuthTokenParams{
 			UserId:     userId,
 			JwtVersion: jwtVersion,
 		})
@@ -220,6 +369,7 @@ func (suite *AuthServiceTestSuite) TestCreateAuthTokensErrorCases() {
 	})
 
 	suite.Run("Fail when unable to create JWT", func() {
+		suite.expectPermissions()
 		userId := uuid.New()
 		rawToken := "raw_refresh_token"
 		hashedToken := "hashed_refresh_token"
@@ -228,19 +378,45 @@ func (suite *AuthServiceTestSuite) TestCreateAuthTokensErrorCases() {
 		suite.mockUtils.EXPECT().GenerateRandomBytes(32).Return(rawToken, nil)
 		suite.mockUtils.EXPECT().HashWithSHA256(rawToken).Return(hashedToken)
 		suite.mockRedis.EXPECT().SaveRefreshToken(gomock.Any()).Return(nil)
+		suite.mockSess.EXPECT().RevokeDeviceSessions(gomock.Any(), userId, gomock.Any()).Return([]models.Token{}, nil)
+		suite.mockSess.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(&models.Token{}, nil)
 		suite.mockJwt.EXPECT().Create(gomock.Any()).Return("", errors.New("jwt error"))
 
-		_, err := suite.services.CreateAuthTokens(services.CreateAuthTokenParams{
+		_, err := suite.services.CreateAuthTokens(context.Background(), services.CreateAuthTokenParams{
 			UserId:     userId,
 			JwtVersion: jwtVersion,
 		})
 
 		assert.Error(suite.T(), err)
 	})
+	suite.Run("Fail when the rotated session is no longer active", func() {
+		suite.expectPermissions()
+		oldRefToken := "old ref token"
+		oldHashedToken := "old_hashed_refresh_token"
+		rawToken := "raw_refresh_token"
+		hashedToken := "hashed_refresh_token"
+
+		suite.mockUtils.EXPECT().HashWithSHA256(oldRefToken).Return(oldHashedToken)
+		suite.mockRedis.EXPECT().DeleteRefreshToken(oldHashedToken).Return(nil)
+		suite.mockUtils.EXPECT().GenerateRandomBytes(32).Return(rawToken, nil)
+		suite.mockUtils.EXPECT().HashWithSHA256(rawToken).Return(hashedToken)
+		suite.mockRedis.EXPECT().SaveRefreshToken(gomock.Any()).Return(nil)
+		suite.mockSess.EXPECT().RotateSession(gomock.Any(), oldHashedToken, gomock.Any()).Return(nil, repositories.ErrSessionNotActive)
+		suite.mockRedis.EXPECT().DeleteRefreshToken(hashedToken).Return(nil)
+
+		_, err := suite.services.CreateAuthTokens(context.Background(), services.CreateAuthTokenParams{
+			UserId:      uuid.New(),
+			JwtVersion:  "v1",
+			OldRefToken: &oldRefToken,
+		})
+
+		assert.ErrorIs(suite.T(), err, repositories.ErrSessionNotActive)
+	})
 }
 
 func (suite *AuthServiceTestSuite) TestRefreshTokenEdgeCases() {
 	suite.Run("Fail when unable to delete old refresh token", func() {
+		suite.expectPermissions()
 		userId := uuid.New()
 		jwtVersion := "v1"
 		oldRefToken := "old_ref_token"
@@ -250,7 +426,7 @@ func (suite *AuthServiceTestSuite) TestRefreshTokenEdgeCases() {
 		suite.mockUtils.EXPECT().HashWithSHA256(oldRefToken).Return(oldHashedToken)
 		suite.mockRedis.EXPECT().DeleteRefreshToken(oldHashedToken).Return(errors.New("delete error"))
 
-		_, err := suite.services.CreateAuthTokens(services.CreateAuthTokenParams{
+		_, err := suite.services.CreateAuthTokens(context.Background(), services.CreateAuthTokenParams{
 			UserId:      userId,
 			JwtVersion:  jwtVersion,
 			OldRefToken: &oldRefToken,
@@ -261,6 +437,7 @@ func (suite *AuthServiceTestSuite) TestRefreshTokenEdgeCases() {
 	})
 
 	suite.Run("Fail when unable to delete old access token", func() {
+		suite.expectPermissions()
 		userId := uuid.New()
 		jwtVersion := "v1"
 		oldRefToken := "old_ref_token"
@@ -271,7 +448,7 @@ func (suite *AuthServiceTestSuite) TestRefreshTokenEdgeCases() {
 		suite.mockRedis.EXPECT().DeleteRefreshToken(oldHashedToken).Return(nil)
 		suite.mockRedis.EXPECT().DeleteAccessToken(oldJti.String()).Return(errors.New("delete error"))
 
-		_, err := suite.services.CreateAuthTokens(services.CreateAuthTokenParams{
+		_, err := suite.services.CreateAuthTokens(context.Background(), services.CreateAuthTokenParams{
 			UserId:      userId,
 			JwtVersion:  jwtVersion,
 			OldRefToken: &oldRefToken,
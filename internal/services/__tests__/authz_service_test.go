@@ -0,0 +1,184 @@
+package services_test
+
+import (
+	"context"
+	"my-go-api/internal/config"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+// memoryRelationTupleRepository keeps tuples in memory and counts reads, so
+// tests can tell cached checks from evaluated ones.
+type memoryRelationTupleRepository struct {
+	mu     sync.Mutex
+	tuples []models.RelationTuple
+	reads  int
+}
+
+func (r *memoryRelationTupleRepository) Write(_ context.Context, tuples []models.RelationTuple) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, tuple := range tuples {
+		if !slices.Contains(r.tuples, tuple) {
+			r.tuples = append(r.tuples, tuple)
+		}
+	}
+	return nil
+}
+
+func (r *memoryRelationTupleRepository) Delete(_ context.Context, tuples []models.RelationTuple) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tuples = slices.DeleteFunc(r.tuples, func(tuple models.RelationTuple) bool {
+		return slices.Contains(tuples, tuple)
+	})
+	return nil
+}
+
+func (r *memoryRelationTupleRepository) GetByObjectRelation(_ context.Context, namespace, objectId, relation string) ([]models.RelationTuple, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reads++
+	tuples := []models.RelationTuple{}
+	for _, tuple := range r.tuples {
+		if tuple.Namespace == namespace && tuple.ObjectId == objectId && tuple.Relation == relation {
+			tuples = append(tuples, tuple)
+		}
+	}
+	return tuples, nil
+}
+
+var _ repositories.IRelationTupleRepository = &memoryRelationTupleRepository{}
+
+var testAuthzSchema = config.AuthzSchema{
+	"group": {Relations: map[string]config.AuthzRelation{
+		"member": {},
+	}},
+	"folder": {Relations: map[string]config.AuthzRelation{
+		"viewer": {},
+	}},
+	"document": {Relations: map[string]config.AuthzRelation{
+		"owner":  {},
+		"parent": {},
+		"viewer": {Union: []config.AuthzUserset{
+			{This: true},
+			{ComputedUserset: "owner"},
+			{TupleToUserset: &config.AuthzTupleToUserset{Tupleset: "parent", ComputedUserset: "viewer"}},
+		}},
+	}},
+}
+
+type AuthzServiceTestSuite struct {
+	suite.Suite
+	repo    *memoryRelationTupleRepository
+	service services.IAuthzService
+}
+
+func (suite *AuthzServiceTestSuite) SetupTest() {
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(suite.T()).Addr()})
+	suite.repo = &memoryRelationTupleRepository{}
+	service, err := services.NewAuthzService(testAuthzSchema, suite.repo, services.NewRedisService(repositories.NewRedisRepository(rdb)))
+	suite.Require().NoError(err)
+	suite.service = service
+}
+
+func (suite *AuthzServiceTestSuite) write(tuples ...string) {
+	suite.Require().NoError(suite.service.WriteTuples(context.Background(), tuples))
+}
+
+func (suite *AuthzServiceTestSuite) check(object, relation, subject string) bool {
+	allowed, err := suite.service.Check(context.Background(), object, relation, subject)
+	suite.Require().NoError(err)
+	return allowed
+}
+
+func (suite *AuthzServiceTestSuite) TestDirectAndComputedUsersets() {
+	suite.write("document:readme#viewer@alice", "document:readme#owner@bob")
+
+	suite.True(suite.check("document:readme", "viewer", "alice"))
+	suite.True(suite.check("document:readme", "viewer", "bob"))
+	suite.False(suite.check("document:readme", "owner", "alice"))
+	suite.False(suite.check("document:readme", "viewer", "carol"))
+}
+
+func (suite *AuthzServiceTestSuite) TestGroupUsersetsAndParents() {
+	suite.write(
+		"group:eng#member@carol",
+		"folder:docs#viewer@group:eng#member",
+		"document:readme#parent@folder:docs",
+	)
+
+	suite.True(suite.check("folder:docs", "viewer", "carol"))
+	suite.True(suite.check("document:readme", "viewer", "carol"))
+	suite.False(suite.check("document:readme", "viewer", "dave"))
+}
+
+func (suite *AuthzServiceTestSuite) TestCyclesEndTheBranch() {
+	suite.write("group:a#member@group:b#member", "group:b#member@group:a#member")
+
+	suite.False(suite.check("group:a", "member", "alice"))
+}
+
+func (suite *AuthzServiceTestSuite) TestWritesInvalidateCachedChecks() {
+	suite.write("document:readme#viewer@alice")
+	suite.True(suite.check("document:readme", "viewer", "alice"))
+
+	reads := suite.repo.reads
+	suite.True(suite.check("document:readme", "viewer", "alice"))
+	suite.Equal(reads, suite.repo.reads, "second check should be served from the cache")
+
+	suite.Require().NoError(suite.service.DeleteTuples(context.Background(), []string{"document:readme#viewer@alice"}))
+	suite.False(suite.check("document:readme", "viewer", "alice"))
+}
+
+func (suite *AuthzServiceTestSuite) TestExpand() {
+	suite.write(
+		"document:readme#viewer@alice",
+		"document:readme#owner@bob",
+		"document:readme#viewer@group:eng#member",
+		"group:eng#member@carol",
+	)
+
+	tree, err := suite.service.Expand(context.Background(), "document:readme", "viewer")
+	suite.Require().NoError(err)
+	suite.Equal(services.AuthzTree{
+		Userset:  "document:readme#viewer",
+		Subjects: []string{"alice"},
+		Children: []services.AuthzTree{
+			{Userset: "group:eng#member", Subjects: []string{"carol"}},
+			{Userset: "document:readme#owner", Subjects: []string{"bob"}},
+		},
+	}, tree)
+}
+
+func (suite *AuthzServiceTestSuite) TestRejectsTuplesOutsideTheSchema() {
+	ctx := context.Background()
+	suite.ErrorIs(suite.service.WriteTuples(ctx, []string{"document:readme#viewer"}), services.ErrInvalidRelationTuple)
+	suite.ErrorIs(suite.service.WriteTuples(ctx, []string{"document:readme#editor@alice"}), services.ErrUnknownRelation)
+	suite.ErrorIs(suite.service.WriteTuples(ctx, []string{"team:eng#member@alice"}), services.ErrUnknownNamespace)
+	suite.Empty(suite.repo.tuples)
+
+	_, err := suite.service.Check(ctx, "document:readme", "editor", "alice")
+	suite.ErrorIs(err, services.ErrUnknownRelation)
+}
+
+func (suite *AuthzServiceTestSuite) TestRejectsBrokenSchema() {
+	_, err := services.NewAuthzService(config.AuthzSchema{
+		"document": {Relations: map[string]config.AuthzRelation{
+			"viewer": {Union: []config.AuthzUserset{{ComputedUserset: "editor"}}},
+		}},
+	}, suite.repo, nil)
+	suite.ErrorIs(err, services.ErrUnknownRelation)
+}
+
+func TestAuthzServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthzServiceTestSuite))
+}
@@ -0,0 +1,157 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"my-go-api/internal/config"
+	"my-go-api/internal/models"
+	"my-go-api/internal/services"
+	mockrepositories "my-go-api/mocks/mock_repositories"
+	mockservices "my-go-api/mocks/mock_services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type EmailOutboxServiceTestSuite struct {
+	suite.Suite
+	ctrl             *gomock.Controller
+	mockOutboxRepo   *mockrepositories.MockIEmailOutboxRepository
+	mockEmailService *mockservices.MockIEmailService
+	service          services.IEmailOutboxService
+}
+
+var outboxConfig = config.EmailOutboxConfig{
+	Workers:      1,
+	PollInterval: 10 * time.Millisecond,
+	BatchSize:    10,
+	MaxAttempts:  3,
+	BackoffBase:  time.Minute,
+	BackoffMax:   3 * time.Minute,
+	Lease:        time.Minute,
+}
+
+func (suite *EmailOutboxServiceTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockOutboxRepo = mockrepositories.NewMockIEmailOutboxRepository(suite.ctrl)
+	suite.mockEmailService = mockservices.NewMockIEmailService(suite.ctrl)
+	suite.service = services.NewEmailOutboxService(outboxConfig, suite.mockOutboxRepo, suite.mockEmailService)
+}
+
+func (suite *EmailOutboxServiceTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *EmailOutboxServiceTestSuite) claim(emails ...models.OutboxEmail) {
+	suite.mockOutboxRepo.EXPECT().Claim(gomock.Any(), outboxConfig.BatchSize, outboxConfig.Lease).Return(emails, nil)
+}
+
+// retryAfter matches a retry time about wait from now.
+func retryAfter(wait time.Duration) gomock.Matcher {
+	return gomock.Cond(func(x any) bool {
+		retryAt, ok := x.(*time.Time)
+		if !ok || retryAt == nil {
+			return false
+		}
+		delay := time.Until(*retryAt)
+		return delay > wait-time.Second && delay <= wait
+	})
+}
+
+func (suite *EmailOutboxServiceTestSuite) TestDeliverPendingMarksSent() {
+	email := models.OutboxEmail{ID: 1, Attempts: 1}
+	suite.claim(email)
+	suite.mockEmailService.EXPECT().Deliver(gomock.Any(), email).Return(nil)
+	suite.mockOutboxRepo.EXPECT().MarkSent(gomock.Any(), int64(1)).Return(nil)
+
+	count, err := suite.service.DeliverPending(context.Background())
+	suite.NoError(err)
+	suite.Equal(1, count)
+}
+
+func (suite *EmailOutboxServiceTestSuite) TestDeliverPendingBacksOff() {
+	for attempts, wait := range map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute} {
+		email := models.OutboxEmail{ID: int64(attempts), Attempts: attempts}
+		suite.claim(email)
+		suite.mockEmailService.EXPECT().Deliver(gomock.Any(), email).Return(errors.New("connection refused"))
+		suite.mockOutboxRepo.EXPECT().MarkFailed(gomock.Any(), email.ID, "connection refused", retryAfter(wait)).Return(nil)
+
+		_, err := suite.service.DeliverPending(context.Background())
+		suite.NoError(err)
+	}
+}
+
+func (suite *EmailOutboxServiceTestSuite) TestDeliverPendingDeadLetters() {
+	suite.Run("after the last attempt", func() {
+		email := models.OutboxEmail{ID: 1, Attempts: outboxConfig.MaxAttempts}
+		suite.claim(email)
+		suite.mockEmailService.EXPECT().Deliver(gomock.Any(), email).Return(errors.New("connection refused"))
+		suite.mockOutboxRepo.EXPECT().MarkFailed(gomock.Any(), int64(1), "connection refused", (*time.Time)(nil)).Return(nil)
+
+		_, err := suite.service.DeliverPending(context.Background())
+		suite.NoError(err)
+	})
+
+	suite.Run("right away when the email can never be sent", func() {
+		email := models.OutboxEmail{ID: 2, Attempts: 1}
+		cause := fmt.Errorf("%w: bad recipient", services.ErrInvalidEmailMessage)
+		suite.claim(email)
+		suite.mockEmailService.EXPECT().Deliver(gomock.Any(), email).Return(cause)
+		suite.mockOutboxRepo.EXPECT().MarkFailed(gomock.Any(), int64(2), cause.Error(), (*time.Time)(nil)).Return(nil)
+
+		_, err := suite.service.DeliverPending(context.Background())
+		suite.NoError(err)
+	})
+}
+
+func (suite *EmailOutboxServiceTestSuite) TestStartDeliversInTheBackground() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	delivered := make(chan struct{})
+	email := models.OutboxEmail{ID: 1, Attempts: 1}
+	suite.mockOutboxRepo.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.OutboxEmail{email}, nil)
+	suite.mockOutboxRepo.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.OutboxEmail{}, nil).AnyTimes()
+	suite.mockEmailService.EXPECT().Deliver(gomock.Any(), email).Return(nil)
+	suite.mockOutboxRepo.EXPECT().MarkSent(gomock.Any(), int64(1)).DoAndReturn(func(context.Context, int64) error {
+		close(delivered)
+		return nil
+	})
+
+	suite.service.Start(ctx)
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		suite.Fail("the email was not delivered")
+	}
+}
+
+func (suite *EmailOutboxServiceTestSuite) TestRetry() {
+	suite.Run("queues a dead email", func() {
+		suite.mockOutboxRepo.EXPECT().Retry(gomock.Any(), int64(1)).Return(&models.OutboxEmail{ID: 1, Status: models.OutboxEmailPending}, nil)
+		email, err := suite.service.Retry(context.Background(), 1)
+		suite.NoError(err)
+		suite.Equal(models.OutboxEmailPending, email.Status)
+	})
+
+	suite.Run("refuses emails that are not dead", func() {
+		suite.mockOutboxRepo.EXPECT().Retry(gomock.Any(), int64(2)).Return(nil, sql.ErrNoRows)
+		suite.mockOutboxRepo.EXPECT().GetOne(gomock.Any(), int64(2)).Return(&models.OutboxEmail{ID: 2, Status: models.OutboxEmailSent}, nil)
+		_, err := suite.service.Retry(context.Background(), 2)
+		suite.ErrorIs(err, services.ErrOutboxEmailNotDead)
+	})
+
+	suite.Run("reports unknown emails", func() {
+		suite.mockOutboxRepo.EXPECT().Retry(gomock.Any(), int64(3)).Return(nil, sql.ErrNoRows)
+		suite.mockOutboxRepo.EXPECT().GetOne(gomock.Any(), int64(3)).Return(nil, sql.ErrNoRows)
+		_, err := suite.service.Retry(context.Background(), 3)
+		suite.ErrorIs(err, services.ErrOutboxEmailNotFound)
+	})
+}
+
+func TestEmailOutboxServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(EmailOutboxServiceTestSuite))
+}
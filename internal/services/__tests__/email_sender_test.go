@@ -0,0 +1,265 @@
+package services_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"my-go-api/internal/config"
+	"my-go-api/internal/services"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeSmtpServer accepts one SMTP session and records what it was sent.
+type fakeSmtpServer struct {
+	listener net.Listener
+	extras   []string
+	auth     string
+	from     string
+	to       string
+	data     string
+	done     chan struct{}
+}
+
+func startFakeSmtpServer(extras ...string) (*fakeSmtpServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	server := &fakeSmtpServer{listener: listener, extras: extras, done: make(chan struct{})}
+	go server.serve()
+	return server, nil
+}
+
+func (s *fakeSmtpServer) port() string {
+	return strconv.Itoa(s.listener.Addr().(*net.TCPAddr).Port)
+}
+
+func (s *fakeSmtpServer) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ready")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			for _, extra := range s.extras {
+				text.PrintfLine("250-%s", extra)
+			}
+			text.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			s.auth = arg
+			text.PrintfLine("235 accepted")
+		case "MAIL":
+			s.from = arg
+			text.PrintfLine("250 ok")
+		case "RCPT":
+			s.to = arg
+			text.PrintfLine("250 ok")
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.data = string(data)
+			text.PrintfLine("250 queued")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+type EmailSenderTestSuite struct {
+	suite.Suite
+	message services.EmailMessage
+}
+
+func (suite *EmailSenderTestSuite) SetupTest() {
+	suite.message = services.EmailMessage{
+		To:      "ari@mail.com",
+		Subject: "Réinitialiser",
+		Body:    "Hello ari.\nFollow this link\nhttp://localhost:5000/reset-password/abc",
+	}
+}
+
+// readEmail parses raw as an email and returns it with its decoded body.
+func (suite *EmailSenderTestSuite) readEmail(raw string) (*mail.Message, string) {
+	parsed, err := mail.ReadMessage(strings.NewReader(raw))
+	suite.Require().NoError(err)
+	body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	suite.Require().NoError(err)
+	return parsed, string(body)
+}
+
+func (suite *EmailSenderTestSuite) TestSmtpSendsMessage() {
+	server, err := startFakeSmtpServer()
+	suite.Require().NoError(err)
+	sender := services.NewSmtpEmailSender(config.SmtpConfig{
+		Host:     "127.0.0.1",
+		Port:     server.port(),
+		Username: "mailer",
+		Password: "secret",
+		TLS:      config.SmtpTLSNone,
+	}, "Go Auth API <no-reply@example.com>")
+
+	suite.Require().NoError(sender.Send(context.Background(), suite.message))
+	<-server.done
+
+	credentials, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(server.auth, "PLAIN "))
+	suite.Require().NoError(err)
+	suite.Equal("\x00mailer\x00secret", string(credentials))
+	suite.Equal("FROM:<no-reply@example.com>", server.from)
+	suite.Equal("TO:<ari@mail.com>", server.to)
+
+	parsed, body := suite.readEmail(server.data)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	suite.Require().NoError(err)
+	suite.Equal("Réinitialiser", subject)
+	suite.Equal("Go Auth API <no-reply@example.com>", parsed.Header.Get("From"))
+	suite.Contains(parsed.Header.Get("Message-ID"), "@example.com>")
+	suite.Equal(suite.message.Body, strings.TrimSuffix(body, "\n"))
+}
+
+func (suite *EmailSenderTestSuite) TestSmtpRequiresOfferedStartTLS() {
+	server, err := startFakeSmtpServer()
+	suite.Require().NoError(err)
+	sender := services.NewSmtpEmailSender(config.SmtpConfig{
+		Host: "127.0.0.1",
+		Port: server.port(),
+		TLS:  config.SmtpTLSStartTLS,
+	}, "no-reply@example.com")
+
+	err = sender.Send(context.Background(), suite.message)
+	suite.ErrorContains(err, "does not offer STARTTLS")
+	server.listener.Close()
+}
+
+func (suite *EmailSenderTestSuite) TestSmtpReturnsConnectionErrors() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+	port := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	listener.Close()
+
+	sender := services.NewSmtpEmailSender(config.SmtpConfig{
+		Host: "127.0.0.1",
+		Port: port,
+		TLS:  config.SmtpTLSNone,
+	}, "no-reply@example.com")
+	suite.ErrorContains(sender.Send(context.Background(), suite.message), "smtp connect failed")
+}
+
+func (suite *EmailSenderTestSuite) TestFileWritesToMaildir() {
+	dir := suite.T().TempDir()
+	sender, err := services.NewFileEmailSender(dir, "")
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(sender.Send(context.Background(), suite.message))
+
+	entries, err := os.ReadDir(filepath.Join(dir, "new"))
+	suite.Require().NoError(err)
+	suite.Require().Len(entries, 1)
+	tmpEntries, err := os.ReadDir(filepath.Join(dir, "tmp"))
+	suite.Require().NoError(err)
+	suite.Empty(tmpEntries)
+
+	raw, err := os.ReadFile(filepath.Join(dir, "new", entries[0].Name()))
+	suite.Require().NoError(err)
+	parsed, body := suite.readEmail(string(raw))
+	suite.Equal("ari@mail.com", parsed.Header.Get("To"))
+	suite.Contains(body, "reset-password/abc")
+}
+
+func (suite *EmailSenderTestSuite) TestComposesMultipartWithEncodedHeaders() {
+	dir := suite.T().TempDir()
+	sender, err := services.NewFileEmailSender(dir, "")
+	suite.Require().NoError(err)
+	suite.message.ToName = "Zoé Ça"
+	suite.message.HTML = "<p>Hello ari.</p>"
+	suite.Require().NoError(sender.Send(context.Background(), suite.message))
+
+	entries, err := os.ReadDir(filepath.Join(dir, "new"))
+	suite.Require().NoError(err)
+	raw, err := os.ReadFile(filepath.Join(dir, "new", entries[0].Name()))
+	suite.Require().NoError(err)
+	parsed, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	suite.Require().NoError(err)
+
+	suite.NotContains(parsed.Header.Get("To"), "é")
+	to, err := parsed.Header.AddressList("To")
+	suite.Require().NoError(err)
+	suite.Equal("Zoé Ça", to[0].Name)
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	suite.Require().NoError(err)
+	suite.Equal("multipart/alternative", mediaType)
+	parts := multipart.NewReader(parsed.Body, params["boundary"])
+	for _, expected := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", suite.message.Body},
+		{"text/html; charset=utf-8", suite.message.HTML},
+	} {
+		part, err := parts.NextRawPart()
+		suite.Require().NoError(err)
+		suite.Equal(expected.contentType, part.Header.Get("Content-Type"))
+		content, err := io.ReadAll(quotedprintable.NewReader(part))
+		suite.Require().NoError(err)
+		suite.Equal(expected.content, strings.ReplaceAll(string(content), "\r\n", "\n"))
+	}
+	_, err = parts.NextPart()
+	suite.ErrorIs(err, io.EOF)
+}
+
+func (suite *EmailSenderTestSuite) TestMemoryCapturesMessages() {
+	sender := services.NewMemoryEmailSender()
+	suite.Require().NoError(sender.Send(context.Background(), suite.message))
+	suite.Equal([]services.EmailMessage{suite.message}, sender.Messages())
+
+	sender.Reset()
+	suite.Empty(sender.Messages())
+}
+
+func (suite *EmailSenderTestSuite) TestRejectsHeaderInjection() {
+	sender := services.NewMemoryEmailSender()
+
+	err := sender.Send(context.Background(), services.EmailMessage{
+		To:      "ari@mail.com",
+		Subject: "Hello\r\nBcc: everyone@mail.com",
+	})
+	suite.True(errors.Is(err, services.ErrInvalidEmailMessage))
+	err = sender.Send(context.Background(), services.EmailMessage{To: "not an address"})
+	suite.True(errors.Is(err, services.ErrInvalidEmailMessage))
+	suite.Empty(sender.Messages())
+}
+
+func (suite *EmailSenderTestSuite) TestNewEmailSenderRejectsUnknownTransport() {
+	_, err := services.NewEmailSender(config.EmailConfig{Transport: "pigeon"}, config.GoogleOAuth2Config{})
+	suite.Error(err)
+}
+
+func TestEmailSenderTestSuite(t *testing.T) {
+	suite.Run(t, new(EmailSenderTestSuite))
+}
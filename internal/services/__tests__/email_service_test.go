@@ -1,9 +1,14 @@
 package services_test
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
 	"my-go-api/internal/services"
-	mockutils "my-go-api/mocks"
+	mockrepositories "my-go-api/mocks/mock_repositories"
+	mockservices "my-go-api/mocks/mock_services"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,25 +18,49 @@ import (
 
 type EmailServiceTestSuite struct {
 	suite.Suite
-	ctrl      *gomock.Controller
-	mockUtils *mockutils.MockIUtils
-	services  services.IEmailService
+	ctrl           *gomock.Controller
+	mockSender     *mockservices.MockIEmailSender
+	mockOutboxRepo *mockrepositories.MockIEmailOutboxRepository
+	services       services.IEmailService
 }
 
 func (suite *EmailServiceTestSuite) SetupTest() {
 	appUri := "http://localhost:5000"
 	suite.ctrl = gomock.NewController(suite.T())
-	suite.mockUtils = mockutils.NewMockIUtils(suite.ctrl)
-	suite.services = services.NewEmailService(appUri, suite.mockUtils)
+	suite.mockSender = mockservices.NewMockIEmailSender(suite.ctrl)
+	suite.mockOutboxRepo = mockrepositories.NewMockIEmailOutboxRepository(suite.ctrl)
+	templates, err := services.NewEmailTemplates("")
+	suite.Require().NoError(err)
+	suite.services = services.NewEmailService(appUri, suite.mockSender, templates, suite.mockOutboxRepo)
 }
 
 func (suite *EmailServiceTestSuite) TearDownTest() {
 	suite.ctrl.Finish()
 }
 
+// queued turns enqueued params into the row the outbox hands to Deliver.
+func queued(params repositories.EnqueueEmailParams) models.OutboxEmail {
+	return models.OutboxEmail{
+		ID:            1,
+		Template:      params.Template,
+		Recipient:     params.Recipient,
+		RecipientName: params.RecipientName,
+		Locale:        params.Locale,
+		Data:          params.Data,
+		Status:        models.OutboxEmailPending,
+		Attempts:      1,
+	}
+}
+
 func (suite *EmailServiceTestSuite) TestSendVerificationEmail() {
-	suite.Run("It should send the email", func() {
-		suite.mockUtils.EXPECT().SendEmailWithGmail(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	suite.Run("It should queue the email", func() {
+		suite.mockOutboxRepo.EXPECT().Enqueue(gomock.Any(), gomock.Cond(func(x any) bool {
+			params := x.(repositories.EnqueueEmailParams)
+			var data map[string]any
+			return params.Template == services.EmailTemplateVerifyAccount &&
+				params.Recipient == "ari@mail.com" &&
+				json.Unmarshal(params.Data, &data) == nil && data["Code"] == "8888"
+		})).Return(&models.OutboxEmail{ID: 1}, nil)
 		err := suite.services.SendVerificationEmail(services.SendEmailVerificationParams{
 			Name:  "ari",
 			Email: "ari@mail.com",
@@ -40,8 +69,8 @@ func (suite *EmailServiceTestSuite) TestSendVerificationEmail() {
 		assert.NoError(suite.T(), err)
 	})
 
-	suite.Run("It should fail to send the email", func() {
-		suite.mockUtils.EXPECT().SendEmailWithGmail(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("error"))
+	suite.Run("It should fail to queue the email", func() {
+		suite.mockOutboxRepo.EXPECT().Enqueue(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
 		err := suite.services.SendVerificationEmail(services.SendEmailVerificationParams{
 			Name:  "ari",
 			Email: "ari@mail.com",
@@ -51,6 +80,80 @@ func (suite *EmailServiceTestSuite) TestSendVerificationEmail() {
 	})
 }
 
+func (suite *EmailServiceTestSuite) TestDeliver() {
+	params, err := suite.services.VerificationEmail(services.SendEmailVerificationParams{
+		Name:  "ari",
+		Email: "ari@mail.com",
+		Code:  "8888",
+	})
+	suite.Require().NoError(err)
+
+	suite.Run("It should render and send the email", func() {
+		suite.mockSender.EXPECT().Send(gomock.Any(), gomock.Cond(func(x any) bool {
+			message := x.(services.EmailMessage)
+			return message.To == "ari@mail.com" && message.Subject == "Email verification"
+		})).Return(nil)
+		suite.NoError(suite.services.Deliver(context.Background(), queued(params)))
+	})
+
+	suite.Run("It should return the transport error", func() {
+		suite.mockSender.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+		suite.Error(suite.services.Deliver(context.Background(), queued(params)))
+	})
+
+	suite.Run("It should reject unknown templates", func() {
+		email := queued(params)
+		email.Template = "welcome"
+		suite.ErrorIs(suite.services.Deliver(context.Background(), email), services.ErrUnknownEmailTemplate)
+	})
+}
+
+func (suite *EmailServiceTestSuite) TestSendPasswordResetRequest() {
+	sender := services.NewMemoryEmailSender()
+	templates, err := services.NewEmailTemplates("")
+	suite.Require().NoError(err)
+	emailService := services.NewEmailService("http://localhost:5000", sender, templates, suite.mockOutboxRepo)
+
+	var emails []models.OutboxEmail
+	suite.mockOutboxRepo.EXPECT().Enqueue(gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(_ context.Context, params repositories.EnqueueEmailParams) (*models.OutboxEmail, error) {
+			email := queued(params)
+			emails = append(emails, email)
+			return &email, nil
+		})
+
+	err = emailService.SendPasswordResetRequest(services.SendPasswordResetParams{
+		Name:  "ari",
+		Email: "ari@mail.com",
+		Token: "reset-token",
+	})
+	suite.Require().NoError(err)
+	err = emailService.SendPasswordResetRequest(services.SendPasswordResetParams{
+		Name:   "Zoé",
+		Email:  "zoe@mail.com",
+		Token:  "reset-token",
+		Locale: "fr-CA",
+	})
+	suite.Require().NoError(err)
+	suite.Empty(sender.Messages(), "nothing is sent before the outbox delivers it")
+	for _, email := range emails {
+		suite.Require().NoError(emailService.Deliver(context.Background(), email))
+	}
+
+	suite.Require().Len(sender.Messages(), 2)
+	message := sender.Messages()[0]
+	suite.Equal("ari@mail.com", message.To)
+	suite.Equal("ari", message.ToName)
+	suite.Equal("Reset Password", message.Subject)
+	suite.Contains(message.Body, "http://localhost:5000/reset-password/reset-token")
+	suite.Contains(message.HTML, `href="http://localhost:5000/reset-password/reset-token"`)
+
+	message = sender.Messages()[1]
+	suite.Equal("Réinitialisation du mot de passe", message.Subject)
+	suite.Contains(message.Body, "Bonjour Zoé.")
+	suite.Contains(message.HTML, `<html lang="fr">`)
+}
+
 func TestEmailServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(EmailServiceTestSuite))
 }
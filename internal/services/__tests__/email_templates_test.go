@@ -0,0 +1,108 @@
+package services_test
+
+import (
+	"my-go-api/internal/services"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EmailTemplatesTestSuite struct {
+	suite.Suite
+	templates services.IEmailTemplates
+}
+
+func (suite *EmailTemplatesTestSuite) SetupTest() {
+	templates, err := services.NewEmailTemplates("")
+	suite.Require().NoError(err)
+	suite.templates = templates
+}
+
+func (suite *EmailTemplatesTestSuite) render(locale string) services.RenderedEmail {
+	rendered, err := suite.templates.Render(services.EmailTemplateVerifyAccount, locale, map[string]any{"Name": "ari", "Code": "123456"})
+	suite.Require().NoError(err)
+	return rendered
+}
+
+func (suite *EmailTemplatesTestSuite) TestListsEmbeddedTemplates() {
+	suite.Equal([]string{"account_locked", "magic_link", "password_reset", "verify_account"}, suite.templates.Names())
+	suite.Equal([]string{"en", "fr"}, suite.templates.Locales())
+}
+
+func (suite *EmailTemplatesTestSuite) TestMatchesLocale() {
+	for locale, expected := range map[string]string{
+		"":                   "en",
+		"fr":                 "fr",
+		"fr-CA":              "fr",
+		"de-DE, fr;q=0.8":    "fr",
+		"ja":                 "en",
+		"not a language tag": "en",
+	} {
+		suite.Equal(expected, suite.render(locale).Locale, locale)
+	}
+}
+
+func (suite *EmailTemplatesTestSuite) TestRendersEveryPart() {
+	rendered := suite.render("en")
+	suite.Equal("Email verification", rendered.Subject)
+	suite.Equal("Hello ari.\n\nThis is your verification code: 123456\n", rendered.Text)
+	suite.Contains(rendered.HTML, `<html lang="en">`)
+	suite.Contains(rendered.HTML, "<title>Email verification</title>")
+	suite.Contains(rendered.HTML, "123456")
+}
+
+func (suite *EmailTemplatesTestSuite) TestEscapesHTML() {
+	rendered, err := suite.templates.Render(services.EmailTemplateVerifyAccount, "en", map[string]any{"Name": "<script>x</script>", "Code": "1"})
+	suite.Require().NoError(err)
+	suite.NotContains(rendered.HTML, "<script>")
+	suite.Contains(rendered.HTML, "&lt;script&gt;")
+	suite.Contains(rendered.Text, "<script>")
+}
+
+func (suite *EmailTemplatesTestSuite) TestMissingDataFails() {
+	_, err := suite.templates.Render(services.EmailTemplateVerifyAccount, "en", map[string]any{"Name": "ari"})
+	suite.Error(err)
+}
+
+func (suite *EmailTemplatesTestSuite) TestUnknownTemplate() {
+	_, err := suite.templates.Render("welcome", "en", nil)
+	suite.ErrorIs(err, services.ErrUnknownEmailTemplate)
+}
+
+func (suite *EmailTemplatesTestSuite) TestDirectoryOverridesAndAddsLocales() {
+	dir := suite.T().TempDir()
+	write := func(path, content string) {
+		suite.Require().NoError(os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0o755))
+		suite.Require().NoError(os.WriteFile(filepath.Join(dir, path), []byte(content), 0o644))
+	}
+	write("en/verify_account.subject.txt", "Confirm your email, {{.Name}}\n")
+	write("de/verify_account.subject.txt", "E-Mail bestätigen")
+	write("de/verify_account.txt", "Hallo {{.Name}}, dein Code: {{.Code}}")
+	write("de/verify_account.html", `{{define "content"}}<p>Hallo {{.Name}}</p>{{end}}`)
+
+	templates, err := services.NewEmailTemplates(dir)
+	suite.Require().NoError(err)
+	suite.Equal([]string{"en", "de", "fr"}, templates.Locales())
+
+	rendered, err := templates.Render(services.EmailTemplateVerifyAccount, "en", map[string]any{"Name": "ari", "Code": "1"})
+	suite.Require().NoError(err)
+	suite.Equal("Confirm your email, ari", rendered.Subject)
+	suite.Contains(rendered.Text, "This is your verification code: 1")
+
+	rendered, err = templates.Render(services.EmailTemplateVerifyAccount, "de-AT", map[string]any{"Name": "ari", "Code": "1"})
+	suite.Require().NoError(err)
+	suite.Equal("de", rendered.Locale)
+	suite.Equal("E-Mail bestätigen", rendered.Subject)
+	suite.Contains(rendered.HTML, `<html lang="de">`)
+
+	// templates the locale does not have come from the default locale
+	rendered, err = templates.Render(services.EmailTemplateMagicLink, "de", map[string]any{"Name": "ari", "Code": "1", "Link": "http://localhost"})
+	suite.Require().NoError(err)
+	suite.Equal("en", rendered.Locale)
+}
+
+func TestEmailTemplatesTestSuite(t *testing.T) {
+	suite.Run(t, new(EmailTemplatesTestSuite))
+}
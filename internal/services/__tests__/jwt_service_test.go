@@ -1,11 +1,21 @@
 package services_test
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
+	"my-go-api/internal/config"
 	"my-go-api/internal/services"
 	mockservices "my-go-api/mocks/mock_services"
 	"testing"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
@@ -24,7 +34,9 @@ func (suite *JwtServiceTestSuite) SetupTest() {
 	suite.ctrl = gomock.NewController(suite.T())
 	suite.mockRedis = mockservices.NewMockIRedisService(suite.ctrl)
 	suite.secretKey = "test-secret"
-	suite.jwtService = services.NewJwtService(suite.secretKey, suite.mockRedis)
+	keyring, err := services.NewStaticJwtKeyring(config.JwtConfig{Algorithm: config.JwtAlgorithmHS256, SecretKey: suite.secretKey})
+	suite.Require().NoError(err)
+	suite.jwtService = services.NewJwtService(keyring, suite.mockRedis)
 
 	suite.testPayload = services.JWTPayload{
 		UserId:     "user123",
@@ -78,6 +90,126 @@ func (suite *JwtServiceTestSuite) TestVerify_TokenNotInRedis() {
 	assert.Equal(suite.T(), "not found", err.Error())
 }
 
+func (suite *JwtServiceTestSuite) TestVerify_AcceptsTokensWithoutKeyId() {
+	// tokens signed before they carried a kid
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, services.CustomClaims{UserID: "user123", JTI: "jti-abc"}).
+		SignedString([]byte(suite.secretKey))
+	suite.Require().NoError(err)
+	suite.mockRedis.EXPECT().GetAccessToken("jti-abc").Return(services.AccessTokenData{}, nil)
+	_, err = suite.jwtService.Verify(legacy)
+	suite.NoError(err)
+}
+
+func (suite *JwtServiceTestSuite) TestHS256PublishesNoKeys() {
+	assert.Empty(suite.T(), suite.jwtService.JWKS().Keys)
+}
+
+func (suite *JwtServiceTestSuite) asymmetricService(algorithm string, key crypto.Signer) services.IJwtService {
+	keyring, err := services.NewStaticJwtKeyring(config.JwtConfig{Algorithm: algorithm, SigningKey: key})
+	suite.Require().NoError(err)
+	return services.NewJwtService(keyring, suite.mockRedis)
+}
+
+func (suite *JwtServiceTestSuite) TestAsymmetricAlgorithms() {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	suite.Require().NoError(err)
+
+	for algorithm, key := range map[string]crypto.Signer{
+		config.JwtAlgorithmRS256: rsaKey,
+		config.JwtAlgorithmES256: ecKey,
+		config.JwtAlgorithmEdDSA: edKey,
+	} {
+		suite.Run(algorithm, func() {
+			jwtService := suite.asymmetricService(algorithm, key)
+			token, err := jwtService.Create(suite.testPayload)
+			suite.Require().NoError(err)
+
+			keys := jwtService.JWKS().Keys
+			suite.Require().Len(keys, 1)
+			suite.Equal(algorithm, keys[0].Alg)
+			suite.Equal("sig", keys[0].Use)
+			suite.Equal(keys[0].Thumbprint(), keys[0].Kid)
+
+			// a downstream service verifies with nothing but the published key
+			public, err := keys[0].PublicKey()
+			suite.Require().NoError(err)
+			parsed, err := jwt.Parse(token, func(token *jwt.Token) (any, error) {
+				suite.Equal(keys[0].Kid, token.Header["kid"])
+				return public, nil
+			}, jwt.WithValidMethods([]string{algorithm}))
+			suite.Require().NoError(err)
+			suite.True(parsed.Valid)
+
+			suite.mockRedis.EXPECT().GetAccessToken(suite.testPayload.Jti).Return(services.AccessTokenData{}, nil)
+			payload, err := jwtService.Verify(token)
+			suite.Require().NoError(err)
+			suite.Equal(suite.testPayload.UserId, payload.UserId)
+		})
+	}
+}
+
+func (suite *JwtServiceTestSuite) TestVerify_RejectsOtherAlgorithms() {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err)
+	jwtService := suite.asymmetricService(config.JwtAlgorithmES256, key)
+	kid := jwtService.JWKS().Keys[0].Kid
+
+	// an HMAC token keyed with the public key must not pass as ES256
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	suite.Require().NoError(err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, services.CustomClaims{UserID: "user123", JTI: "jti-abc"})
+	forged.Header["kid"] = kid
+	token, err := forged.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	suite.Require().NoError(err)
+	_, err = jwtService.Verify(token)
+	suite.ErrorContains(err, "token parsing failed")
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, services.CustomClaims{UserID: "user123"}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	suite.Require().NoError(err)
+	_, err = jwtService.Verify(unsigned)
+	suite.ErrorContains(err, "token parsing failed")
+}
+
+func (suite *JwtServiceTestSuite) TestVerify_RejectsUnknownKeyId() {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err)
+	jwtService := suite.asymmetricService(config.JwtAlgorithmES256, key)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, services.CustomClaims{UserID: "user123", JTI: "jti-abc"})
+	token.Header["kid"] = "another-key"
+	signed, err := token.SignedString(key)
+	suite.Require().NoError(err)
+	_, err = jwtService.Verify(signed)
+	suite.ErrorContains(err, "unknown signing key")
+}
+
+func (suite *JwtServiceTestSuite) TestParseJwtSigningKey() {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	suite.Require().NoError(err)
+	encoded := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	parsed, err := config.ParseJwtSigningKey(config.JwtAlgorithmES256, encoded)
+	suite.Require().NoError(err)
+	suite.True(key.Equal(parsed))
+
+	_, err = config.ParseJwtSigningKey(config.JwtAlgorithmRS256, encoded)
+	suite.Error(err, "an EC key does not fit RS256")
+	_, err = config.ParseJwtSigningKey(config.JwtAlgorithmES256, []byte("not a key"))
+	suite.Error(err)
+}
+
+func (suite *JwtServiceTestSuite) TestJSONWebKeyThumbprint() {
+	// RFC 8037, appendix A.3
+	jwk := services.JSONWebKey{Kty: "OKP", Crv: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}
+	suite.Equal("kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", jwk.Thumbprint())
+}
+
 func TestJwtServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(JwtServiceTestSuite))
 }
@@ -0,0 +1,114 @@
+package services_test
+
+import (
+	"context"
+	"my-go-api/internal/config"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	mockservices "my-go-api/mocks/mock_services"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type LoginProtectionServiceTestSuite struct {
+	suite.Suite
+	mockEmail  *mockservices.MockIEmailService
+	mockEvents *mockservices.MockISecurityEventService
+	service    services.ILoginProtectionService
+	user       *models.User
+	subject    string
+}
+
+func (suite *LoginProtectionServiceTestSuite) SetupTest() {
+	ctrl := gomock.NewController(suite.T())
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(suite.T()).Addr()})
+	suite.mockEmail = mockservices.NewMockIEmailService(ctrl)
+	suite.mockEvents = mockservices.NewMockISecurityEventService(ctrl)
+	suite.service = services.NewLoginProtectionService(config.LoginProtectionConfig{
+		MaxAttempts:     3,
+		IpMaxAttempts:   5,
+		LockoutDuration: 15 * time.Minute,
+		BackoffBase:     time.Second,
+		BackoffMax:      4 * time.Second,
+	}, services.NewRedisService(repositories.NewRedisRepository(rdb)), suite.mockEmail, suite.mockEvents)
+	suite.user = &models.User{ID: uuid.New(), Username: "ari00", Email: "ari@mail.com"}
+	suite.subject = services.LoginSubject(suite.user, "ari00")
+}
+
+func (suite *LoginProtectionServiceTestSuite) fail(user *models.User, subject, ip string) {
+	suite.Require().NoError(suite.service.RecordFailure(context.Background(), user, subject, ip, "test"))
+}
+
+func (suite *LoginProtectionServiceTestSuite) wait(subject, ip string) time.Duration {
+	wait, err := suite.service.Check(context.Background(), subject, ip)
+	suite.Require().NoError(err)
+	return wait
+}
+
+func (suite *LoginProtectionServiceTestSuite) TestBackOffDoublesWithEveryFailure() {
+	suite.Zero(suite.wait(suite.subject, "10.0.0.1"))
+
+	suite.fail(suite.user, suite.subject, "10.0.0.1")
+	first := suite.wait(suite.subject, "10.0.0.1")
+	suite.InDelta(time.Second, first, float64(200*time.Millisecond))
+
+	suite.fail(suite.user, suite.subject, "10.0.0.1")
+	second := suite.wait(suite.subject, "10.0.0.1")
+	suite.InDelta(2*time.Second, second, float64(200*time.Millisecond))
+
+	suite.Require().NoError(suite.service.RecordSuccess(context.Background(), suite.subject))
+	suite.Zero(suite.wait(suite.subject, "10.0.0.1"))
+}
+
+func (suite *LoginProtectionServiceTestSuite) TestLocksAccountAndEmailsOwner() {
+	suite.mockEvents.EXPECT().Emit(gomock.Any(), gomock.Cond(func(x any) bool {
+		return x.(services.SecurityEvent).Type == services.SecurityEventAccountLocked
+	}))
+	suite.mockEmail.EXPECT().SendAccountLockedEmail(gomock.Cond(func(x any) bool {
+		return x.(services.SendAccountLockedParams).Email == "ari@mail.com"
+	})).Return(nil)
+
+	for range 3 {
+		suite.fail(suite.user, suite.subject, "10.0.0.1")
+	}
+	suite.Greater(suite.wait(suite.subject, "10.0.0.2"), 14*time.Minute)
+
+	state, err := suite.service.GetLockState(context.Background(), suite.user.ID)
+	suite.Require().NoError(err)
+	suite.True(state.Locked)
+
+	suite.Require().NoError(suite.service.Unlock(context.Background(), suite.user.ID))
+	suite.Zero(suite.wait(suite.subject, "10.0.0.2"))
+}
+
+func (suite *LoginProtectionServiceTestSuite) TestUnknownIdentitiesLockWithoutEmail() {
+	subject := services.LoginSubject(nil, " Nobody@Mail.com ")
+	suite.Equal("identity:nobody@mail.com", subject)
+
+	for range 3 {
+		suite.fail(nil, subject, "10.0.0.1")
+	}
+	suite.Greater(suite.wait(subject, "10.0.0.2"), 14*time.Minute)
+}
+
+func (suite *LoginProtectionServiceTestSuite) TestBlocksAddressGuessingManyAccounts() {
+	for i := range 5 {
+		suite.fail(nil, services.LoginSubject(nil, uuid.NewString()), "10.0.0.1")
+		if i < 4 {
+			suite.Zero(suite.wait(suite.subject, "10.0.0.1"))
+		}
+	}
+	suite.Greater(suite.wait(suite.subject, "10.0.0.1"), 14*time.Minute)
+	suite.Zero(suite.wait(suite.subject, "10.0.0.2"))
+}
+
+func TestLoginProtectionServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(LoginProtectionServiceTestSuite))
+}
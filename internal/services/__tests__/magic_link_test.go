@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	"my-go-api/internal/utils"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+type MagicLinkTestSuite struct {
+	suite.Suite
+	authService services.IAuthService
+	userId      uuid.UUID
+	data        services.MagicLinkTokenData
+}
+
+func (suite *MagicLinkTestSuite) SetupTest() {
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(suite.T()).Addr()})
+	suite.authService = services.NewAuthService(
+		services.NewRedisService(repositories.NewRedisRepository(rdb)),
+		utils.NewUtilities("secret", "http://localhost:5000"),
+		nil,
+		nil,
+		nil,
+		nil,
+		0,
+	)
+	suite.userId = uuid.New()
+	data, err := suite.authService.CreateMagicLinkToken(suite.userId)
+	suite.Require().NoError(err)
+	suite.data = data
+}
+
+func (suite *MagicLinkTestSuite) TestCodeSignsInOnce() {
+	userId, err := suite.authService.RedeemMagicLinkCode(suite.data.RawToken, suite.data.Code)
+	suite.NoError(err)
+	suite.Equal(suite.userId, userId)
+
+	_, err = suite.authService.RedeemMagicLinkCode(suite.data.RawToken, suite.data.Code)
+	suite.ErrorIs(err, services.ErrInvalidMagicLink)
+	_, err = suite.authService.RedeemMagicLink(suite.data.LinkToken)
+	suite.ErrorIs(err, services.ErrInvalidMagicLink)
+}
+
+func (suite *MagicLinkTestSuite) TestLinkSignsInOnce() {
+	userId, err := suite.authService.RedeemMagicLink(suite.data.LinkToken)
+	suite.NoError(err)
+	suite.Equal(suite.userId, userId)
+
+	_, err = suite.authService.RedeemMagicLink(suite.data.LinkToken)
+	suite.ErrorIs(err, services.ErrInvalidMagicLink)
+	_, err = suite.authService.RedeemMagicLinkCode(suite.data.RawToken, suite.data.Code)
+	suite.ErrorIs(err, services.ErrInvalidMagicLink)
+}
+
+func (suite *MagicLinkTestSuite) TestWrongCodesAreLimited() {
+	for range services.MagicLinkMaxAttempts - 1 {
+		_, err := suite.authService.RedeemMagicLinkCode(suite.data.RawToken, "00000000")
+		suite.ErrorIs(err, services.ErrInvalidMagicLinkCode)
+	}
+	_, err := suite.authService.RedeemMagicLinkCode(suite.data.RawToken, "00000000")
+	suite.ErrorIs(err, services.ErrInvalidMagicLink)
+
+	_, err = suite.authService.RedeemMagicLinkCode(suite.data.RawToken, suite.data.Code)
+	suite.ErrorIs(err, services.ErrInvalidMagicLink)
+	_, err = suite.authService.RedeemMagicLink(suite.data.LinkToken)
+	suite.ErrorIs(err, services.ErrInvalidMagicLink)
+}
+
+func (suite *MagicLinkTestSuite) TestTokenCannotBeUsedAsLink() {
+	_, err := suite.authService.RedeemMagicLink(suite.data.RawToken)
+	suite.ErrorIs(err, services.ErrInvalidMagicLink)
+}
+
+func (suite *MagicLinkTestSuite) TestUnknownEmailRequestNeverSignsIn() {
+	data, err := suite.authService.CreateMagicLinkToken(uuid.Nil)
+	suite.Require().NoError(err)
+
+	_, err = suite.authService.RedeemMagicLinkCode(data.RawToken, data.Code)
+	suite.ErrorIs(err, services.ErrInvalidMagicLink)
+	data, err = suite.authService.CreateMagicLinkToken(uuid.Nil)
+	suite.Require().NoError(err)
+	_, err = suite.authService.RedeemMagicLink(data.LinkToken)
+	suite.ErrorIs(err, services.ErrInvalidMagicLink)
+}
+
+func TestMagicLinkTestSuite(t *testing.T) {
+	suite.Run(t, new(MagicLinkTestSuite))
+}
@@ -0,0 +1,46 @@
+package services_test
+
+import (
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test vectors from RFC 6238 appendix B (SHA1), truncated to six digits.
+func TestGenerateTotpCode(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	cases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, expected := range cases {
+		step := services.TotpStep(time.Unix(unix, 0))
+		assert.Equal(t, expected, services.GenerateTotpCode(secret, step), "time %d", unix)
+	}
+}
+
+func TestMfaChallengeAttemptsAreLimited(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	redisService := services.NewRedisService(repositories.NewRedisRepository(rdb))
+
+	err := redisService.SaveMfaChallenge(services.MfaChallengeData{HashedToken: "hashed", UserId: "user-1"})
+	assert.NoError(t, err)
+
+	for range services.MfaChallengeMaxAttempts {
+		challenge, err := redisService.UseMfaChallengeAttempt("hashed")
+		assert.NoError(t, err)
+		assert.Equal(t, "user-1", challenge.UserId)
+	}
+	_, err = redisService.UseMfaChallengeAttempt("hashed")
+	assert.Error(t, err)
+
+	_, err = redisService.UseMfaChallengeAttempt("unknown")
+	assert.Error(t, err)
+}
@@ -0,0 +1,453 @@
+package services_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"my-go-api/internal/config"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	"my-go-api/internal/utils"
+	mockrepositories "my-go-api/mocks/mock_repositories"
+	mockservices "my-go-api/mocks/mock_services"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	oidcIssuer             = "https://id.example.com"
+	oidcRedirectUri        = "https://wiki.example.com/callback"
+	oidcCodeVerifier       = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	oidcConsentUri         = "https://app.example.com/oauth/consent"
+	oidcConfidentialSecret = "wiki-secret"
+)
+
+type OAuthServerServiceTestSuite struct {
+	suite.Suite
+	ctrl              *gomock.Controller
+	clientRepo        *mockrepositories.MockIOAuthClientRepository
+	authorizationRepo *mockrepositories.MockIOAuthAuthorizationRepository
+	authService       *mockservices.MockIAuthService
+	sessionService    *mockservices.MockISessionService
+	userService       *mockservices.MockIUserService
+	jwtService        services.IJwtService
+	utils             utils.IUtils
+	service           services.IOAuthServerService
+
+	client *models.OAuthClient
+	user   *models.User
+}
+
+func (suite *OAuthServerServiceTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.clientRepo = mockrepositories.NewMockIOAuthClientRepository(suite.ctrl)
+	suite.authorizationRepo = mockrepositories.NewMockIOAuthAuthorizationRepository(suite.ctrl)
+	suite.authService = mockservices.NewMockIAuthService(suite.ctrl)
+	suite.sessionService = mockservices.NewMockISessionService(suite.ctrl)
+	suite.userService = mockservices.NewMockIUserService(suite.ctrl)
+	suite.utils = utils.NewUtilities("secret", "http://localhost")
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err)
+	keyring, err := services.NewStaticJwtKeyring(config.JwtConfig{Algorithm: config.JwtAlgorithmES256, SigningKey: key})
+	suite.Require().NoError(err)
+	suite.jwtService = services.NewJwtService(keyring, mockservices.NewMockIRedisService(suite.ctrl))
+
+	suite.service = services.NewOAuthServerService(
+		config.OidcConfig{
+			Issuer:           oidcIssuer,
+			ConsentUri:       oidcConsentUri,
+			AuthorizationTTL: 10 * time.Minute,
+			CodeTTL:          time.Minute,
+			IdTokenTTL:       time.Hour,
+		},
+		suite.clientRepo,
+		suite.authorizationRepo,
+		suite.authService,
+		suite.sessionService,
+		suite.userService,
+		suite.jwtService,
+		suite.utils,
+	)
+
+	secretHash := suite.utils.HashWithSHA256(oidcConfidentialSecret)
+	suite.client = &models.OAuthClient{
+		ID:           "wiki",
+		Name:         "Wiki",
+		SecretHash:   &secretHash,
+		Confidential: true,
+		RedirectUris: []string{oidcRedirectUri},
+	}
+	suite.user = &models.User{
+		ID:         uuid.New(),
+		Username:   "ada",
+		Name:       "Ada Lovelace",
+		Email:      "ada@example.com",
+		IsVerified: true,
+		JwtVersion: "v1",
+	}
+}
+
+func (suite *OAuthServerServiceTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *OAuthServerServiceTestSuite) authorizeParams() services.AuthorizeParams {
+	challenge := sha256.Sum256([]byte(oidcCodeVerifier))
+	return services.AuthorizeParams{
+		ResponseType:        "code",
+		ClientId:            suite.client.ID,
+		RedirectUri:         oidcRedirectUri,
+		Scope:               "openid email",
+		State:               "xyz",
+		Nonce:               "n-0S6",
+		CodeChallenge:       base64.RawURLEncoding.EncodeToString(challenge[:]),
+		CodeChallengeMethod: "S256",
+	}
+}
+
+// approved is the authorization the code of the suite redeems.
+func (suite *OAuthServerServiceTestSuite) approved() *models.OAuthAuthorization {
+	params := suite.authorizeParams()
+	return &models.OAuthAuthorization{
+		ClientId:      suite.client.ID,
+		RedirectUri:   oidcRedirectUri,
+		Scope:         "openid email",
+		Nonce:         params.Nonce,
+		CodeChallenge: params.CodeChallenge,
+		UserId:        &suite.user.ID,
+	}
+}
+
+func (suite *OAuthServerServiceTestSuite) oauthErrorCode(err error) string {
+	var oauthErr *services.OAuthError
+	suite.Require().True(errors.As(err, &oauthErr), "expected an OAuthError, got %v", err)
+	return oauthErr.Code
+}
+
+func (suite *OAuthServerServiceTestSuite) TestRegisterClient_ValidatesRedirectUris() {
+	for _, uri := range []string{
+		"http://wiki.example.com/callback",
+		"https://wiki.example.com/callback#fragment",
+		"/callback",
+		"myapp:/callback",
+	} {
+		_, _, err := suite.service.RegisterClient(context.Background(), services.RegisterOAuthClientParams{
+			Name:         "Wiki",
+			RedirectUris: []string{uri},
+		})
+		suite.ErrorIs(err, services.ErrInvalidRedirectUri, uri)
+	}
+
+	uris := []string{oidcRedirectUri, "http://127.0.0.1:8765/callback", "http://localhost/callback", "com.example.wiki:/callback"}
+	var stored repositories.CreateOAuthClientParams
+	suite.clientRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, params repositories.CreateOAuthClientParams) (*models.OAuthClient, error) {
+			stored = params
+			return &models.OAuthClient{ID: params.Id, RedirectUris: params.RedirectUris}, nil
+		})
+
+	client, secret, err := suite.service.RegisterClient(context.Background(), services.RegisterOAuthClientParams{
+		Name:         "Wiki",
+		RedirectUris: uris,
+		Confidential: true,
+	})
+
+	suite.Require().NoError(err)
+	suite.NotEmpty(client.ID)
+	suite.NotEmpty(secret)
+	suite.Equal(suite.utils.HashWithSHA256(secret), stored.SecretHash)
+	suite.Equal(uris, stored.RedirectUris)
+}
+
+func (suite *OAuthServerServiceTestSuite) TestRegisterClient_PublicClientsGetNoSecret() {
+	suite.clientRepo.EXPECT().Create(gomock.Any(), gomock.Cond(func(x any) bool {
+		return x.(repositories.CreateOAuthClientParams).SecretHash == ""
+	})).Return(&models.OAuthClient{ID: "spa"}, nil)
+
+	_, secret, err := suite.service.RegisterClient(context.Background(), services.RegisterOAuthClientParams{
+		Name:         "Spa",
+		RedirectUris: []string{oidcRedirectUri},
+	})
+
+	suite.Require().NoError(err)
+	suite.Empty(secret)
+}
+
+func (suite *OAuthServerServiceTestSuite) TestAuthorize_NeverRedirectsToUnregisteredUris() {
+	suite.clientRepo.EXPECT().GetOne(gomock.Any(), "unknown").Return(nil, sql.ErrNoRows)
+	params := suite.authorizeParams()
+	params.ClientId = "unknown"
+	_, err := suite.service.Authorize(context.Background(), params)
+	suite.ErrorIs(err, services.ErrOAuthClientNotFound)
+
+	suite.clientRepo.EXPECT().GetOne(gomock.Any(), suite.client.ID).Return(suite.client, nil)
+	params = suite.authorizeParams()
+	params.RedirectUri = oidcRedirectUri + "/../evil"
+	_, err = suite.service.Authorize(context.Background(), params)
+	suite.ErrorIs(err, services.ErrRedirectUriMismatch)
+}
+
+func (suite *OAuthServerServiceTestSuite) TestAuthorize_RedirectsErrorsToTheClient() {
+	cases := map[string]func(*services.AuthorizeParams){
+		services.OAuthErrorUnsupportedResponseType: func(p *services.AuthorizeParams) { p.ResponseType = "token" },
+		services.OAuthErrorInvalidScope:            func(p *services.AuthorizeParams) { p.Scope = "email profile" },
+		services.OAuthErrorInvalidRequest:          func(p *services.AuthorizeParams) { p.CodeChallengeMethod = "plain" },
+		services.OAuthErrorInteractionRequired:     func(p *services.AuthorizeParams) { p.Prompt = "none" },
+	}
+	for code, modify := range cases {
+		suite.clientRepo.EXPECT().GetOne(gomock.Any(), suite.client.ID).Return(suite.client, nil)
+		params := suite.authorizeParams()
+		modify(&params)
+
+		target, err := suite.service.Authorize(context.Background(), params)
+
+		suite.Require().NoError(err)
+		redirect, err := url.Parse(target)
+		suite.Require().NoError(err)
+		suite.Equal("wiki.example.com", redirect.Host)
+		suite.Equal(code, redirect.Query().Get("error"))
+		suite.Equal("xyz", redirect.Query().Get("state"))
+		suite.Equal(oidcIssuer, redirect.Query().Get("iss"))
+	}
+}
+
+func (suite *OAuthServerServiceTestSuite) TestAuthorize_StoresTheHashedRequest() {
+	suite.clientRepo.EXPECT().GetOne(gomock.Any(), suite.client.ID).Return(suite.client, nil)
+	var stored repositories.CreateOAuthAuthorizationParams
+	suite.authorizationRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, params repositories.CreateOAuthAuthorizationParams) (*models.OAuthAuthorization, error) {
+			stored = params
+			return &models.OAuthAuthorization{}, nil
+		})
+	params := suite.authorizeParams()
+	params.Scope = "openid admin email openid"
+	params.Prompt = "login consent"
+
+	target, err := suite.service.Authorize(context.Background(), params)
+
+	suite.Require().NoError(err)
+	consent, err := url.Parse(target)
+	suite.Require().NoError(err)
+	suite.Equal("app.example.com", consent.Host)
+	requestId := consent.Query().Get("request")
+	suite.Equal(suite.utils.HashWithSHA256(requestId), stored.Id)
+	suite.Equal("openid email", stored.Scope)
+	suite.Equal("consent", stored.Prompt)
+	suite.Equal(params.CodeChallenge, stored.CodeChallenge)
+}
+
+func (suite *OAuthServerServiceTestSuite) TestGetAuthorization_AsksOnlyForNewScopes() {
+	pending := &models.OAuthAuthorization{ClientId: suite.client.ID, Scope: "openid email"}
+	suite.authorizationRepo.EXPECT().GetPending(gomock.Any(), suite.utils.HashWithSHA256("request")).Return(pending, nil).Times(3)
+	suite.clientRepo.EXPECT().GetOne(gomock.Any(), suite.client.ID).Return(suite.client, nil).Times(3)
+
+	suite.clientRepo.EXPECT().GetConsent(gomock.Any(), suite.user.ID, suite.client.ID).Return(nil, sql.ErrNoRows)
+	prompt, err := suite.service.GetAuthorization(context.Background(), "request", suite.user.ID)
+	suite.Require().NoError(err)
+	suite.True(prompt.ConsentRequired)
+	suite.Equal([]string{"openid", "email"}, prompt.Scopes)
+
+	suite.clientRepo.EXPECT().GetConsent(gomock.Any(), suite.user.ID, suite.client.ID).Return([]string{"email", "openid", "profile"}, nil)
+	prompt, err = suite.service.GetAuthorization(context.Background(), "request", suite.user.ID)
+	suite.Require().NoError(err)
+	suite.False(prompt.ConsentRequired)
+
+	pending.Prompt = "consent"
+	suite.clientRepo.EXPECT().GetConsent(gomock.Any(), suite.user.ID, suite.client.ID).Return([]string{"email", "openid"}, nil)
+	prompt, err = suite.service.GetAuthorization(context.Background(), "request", suite.user.ID)
+	suite.Require().NoError(err)
+	suite.True(prompt.ConsentRequired)
+}
+
+func (suite *OAuthServerServiceTestSuite) TestDecide_ApprovalStoresConsentAndIssuesACode() {
+	var codeHash string
+	suite.authorizationRepo.EXPECT().Approve(gomock.Any(), suite.utils.HashWithSHA256("request"), suite.user.ID, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, _ uuid.UUID, hash string, expiresAt time.Time) (*models.OAuthAuthorization, error) {
+			codeHash = hash
+			suite.WithinDuration(time.Now().Add(time.Minute), expiresAt, 5*time.Second)
+			return &models.OAuthAuthorization{ClientId: suite.client.ID, RedirectUri: oidcRedirectUri, Scope: "openid email", State: "xyz"}, nil
+		})
+	suite.clientRepo.EXPECT().GetConsent(gomock.Any(), suite.user.ID, suite.client.ID).Return([]string{"openid", "profile"}, nil)
+	suite.clientRepo.EXPECT().SaveConsent(gomock.Any(), suite.user.ID, suite.client.ID, []string{"openid", "profile", "email"}).Return(nil)
+
+	target, err := suite.service.Decide(context.Background(), "request", suite.user.ID, true)
+
+	suite.Require().NoError(err)
+	redirect, err := url.Parse(target)
+	suite.Require().NoError(err)
+	suite.Equal("xyz", redirect.Query().Get("state"))
+	suite.Equal(oidcIssuer, redirect.Query().Get("iss"))
+	suite.Equal(codeHash, suite.utils.HashWithSHA256(redirect.Query().Get("code")))
+}
+
+func (suite *OAuthServerServiceTestSuite) TestDecide_Denial() {
+	suite.authorizationRepo.EXPECT().Deny(gomock.Any(), suite.utils.HashWithSHA256("request")).
+		Return(&models.OAuthAuthorization{RedirectUri: oidcRedirectUri, State: "xyz"}, nil)
+
+	target, err := suite.service.Decide(context.Background(), "request", suite.user.ID, false)
+
+	suite.Require().NoError(err)
+	redirect, err := url.Parse(target)
+	suite.Require().NoError(err)
+	suite.Equal(services.OAuthErrorAccessDenied, redirect.Query().Get("error"))
+	suite.Empty(redirect.Query().Get("code"))
+
+	suite.authorizationRepo.EXPECT().Deny(gomock.Any(), gomock.Any()).Return(nil, sql.ErrNoRows)
+	_, err = suite.service.Decide(context.Background(), "request", suite.user.ID, false)
+	suite.ErrorIs(err, services.ErrAuthorizationNotFound)
+}
+
+func (suite *OAuthServerServiceTestSuite) TestExchange_AuthenticatesClients() {
+	suite.clientRepo.EXPECT().GetOne(gomock.Any(), suite.client.ID).Return(suite.client, nil)
+	_, err := suite.service.Exchange(context.Background(), services.OAuthTokenParams{
+		GrantType:    "authorization_code",
+		ClientId:     suite.client.ID,
+		ClientSecret: "wrong",
+	})
+	suite.Equal(services.OAuthErrorInvalidClient, suite.oauthErrorCode(err))
+
+	public := &models.OAuthClient{ID: "spa", RedirectUris: []string{oidcRedirectUri}}
+	suite.clientRepo.EXPECT().GetOne(gomock.Any(), "spa").Return(public, nil)
+	_, err = suite.service.Exchange(context.Background(), services.OAuthTokenParams{
+		GrantType:    "authorization_code",
+		ClientId:     "spa",
+		ClientSecret: "made-up",
+	})
+	suite.Equal(services.OAuthErrorInvalidClient, suite.oauthErrorCode(err))
+}
+
+func (suite *OAuthServerServiceTestSuite) TestExchange_ChecksTheCodeVerifierAndRedirectUri() {
+	suite.clientRepo.EXPECT().GetOne(gomock.Any(), suite.client.ID).Return(suite.client, nil).Times(2)
+	suite.authorizationRepo.EXPECT().Redeem(gomock.Any(), suite.utils.HashWithSHA256("code")).Return(suite.approved(), nil).Times(2)
+
+	_, err := suite.service.Exchange(context.Background(), services.OAuthTokenParams{
+		GrantType:    "authorization_code",
+		ClientId:     suite.client.ID,
+		ClientSecret: oidcConfidentialSecret,
+		Code:         "code",
+		RedirectUri:  oidcRedirectUri,
+		CodeVerifier: "not-the-verifier-not-the-verifier-not-the-verifier",
+	})
+	suite.Equal(services.OAuthErrorInvalidGrant, suite.oauthErrorCode(err))
+
+	_, err = suite.service.Exchange(context.Background(), services.OAuthTokenParams{
+		GrantType:    "authorization_code",
+		ClientId:     suite.client.ID,
+		ClientSecret: oidcConfidentialSecret,
+		Code:         "code",
+		RedirectUri:  "https://wiki.example.com/other",
+		CodeVerifier: oidcCodeVerifier,
+	})
+	suite.Equal(services.OAuthErrorInvalidGrant, suite.oauthErrorCode(err))
+}
+
+func (suite *OAuthServerServiceTestSuite) TestExchange_UsedCode() {
+	suite.clientRepo.EXPECT().GetOne(gomock.Any(), suite.client.ID).Return(suite.client, nil)
+	suite.authorizationRepo.EXPECT().Redeem(gomock.Any(), gomock.Any()).Return(nil, sql.ErrNoRows)
+
+	_, err := suite.service.Exchange(context.Background(), services.OAuthTokenParams{
+		GrantType:    "authorization_code",
+		ClientId:     suite.client.ID,
+		ClientSecret: oidcConfidentialSecret,
+		Code:         "code",
+		RedirectUri:  oidcRedirectUri,
+		CodeVerifier: oidcCodeVerifier,
+	})
+
+	suite.Equal(services.OAuthErrorInvalidGrant, suite.oauthErrorCode(err))
+}
+
+func (suite *OAuthServerServiceTestSuite) TestExchange_IssuesClientTokensAndAnIdToken() {
+	suite.clientRepo.EXPECT().GetOne(gomock.Any(), suite.client.ID).Return(suite.client, nil)
+	suite.authorizationRepo.EXPECT().Redeem(gomock.Any(), suite.utils.HashWithSHA256("code")).Return(suite.approved(), nil)
+	suite.userService.EXPECT().GetUserById(gomock.Any(), suite.user.ID).Return(suite.user, nil)
+	suite.authService.EXPECT().CreateAuthTokens(gomock.Any(), gomock.Cond(func(x any) bool {
+		params := x.(services.CreateAuthTokenParams)
+		return params.UserId == suite.user.ID && params.ClientId == suite.client.ID &&
+			params.Scope == "openid email" && params.OldRefToken == nil && params.DeviceId != uuid.Nil
+	})).Return(services.CreateAuthTokensResult{AccessToken: "access", RefreshToken: "refresh"}, nil)
+
+	response, err := suite.service.Exchange(context.Background(), services.OAuthTokenParams{
+		GrantType:    "authorization_code",
+		ClientId:     suite.client.ID,
+		ClientSecret: oidcConfidentialSecret,
+		Code:         "code",
+		RedirectUri:  oidcRedirectUri,
+		CodeVerifier: oidcCodeVerifier,
+	})
+
+	suite.Require().NoError(err)
+	suite.Equal("access", response.AccessToken)
+	suite.Equal("refresh", response.RefreshToken)
+	suite.Equal("Bearer", response.TokenType)
+	suite.Equal("openid email", response.Scope)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(response.IdToken, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		for _, key := range suite.jwtService.JWKS().Keys {
+			if key.Kid == kid {
+				return key.PublicKey()
+			}
+		}
+		return nil, errors.New("unknown kid")
+	}, jwt.WithIssuer(oidcIssuer), jwt.WithAudience(suite.client.ID), jwt.WithValidMethods([]string{"ES256"}))
+	suite.Require().NoError(err)
+	suite.Equal(suite.user.ID.String(), claims["sub"])
+	suite.Equal("n-0S6", claims["nonce"])
+	suite.Equal("ada@example.com", claims["email"])
+	suite.Equal(true, claims["email_verified"])
+	suite.NotContains(claims, "name")
+}
+
+func (suite *OAuthServerServiceTestSuite) TestExchange_RefreshTokenOfAnotherClient() {
+	other := "other"
+	suite.clientRepo.EXPECT().GetOne(gomock.Any(), suite.client.ID).Return(suite.client, nil)
+	suite.sessionService.EXPECT().GetSessionByHash(gomock.Any(), suite.utils.HashWithSHA256("refresh")).
+		Return(&models.Token{ClientId: &other}, nil)
+
+	_, err := suite.service.Exchange(context.Background(), services.OAuthTokenParams{
+		GrantType:    "refresh_token",
+		ClientId:     suite.client.ID,
+		ClientSecret: oidcConfidentialSecret,
+		RefreshToken: "refresh",
+	})
+
+	suite.Equal(services.OAuthErrorInvalidGrant, suite.oauthErrorCode(err))
+}
+
+func (suite *OAuthServerServiceTestSuite) TestUserInfo_ClaimsFollowTheScope() {
+	claims := suite.service.UserInfo(suite.user, "openid")
+	suite.Equal(map[string]any{"sub": suite.user.ID.String()}, claims)
+
+	claims = suite.service.UserInfo(suite.user, "openid profile")
+	suite.Equal("Ada Lovelace", claims["name"])
+	suite.Equal("ada", claims["preferred_username"])
+	suite.NotContains(claims, "email")
+}
+
+func (suite *OAuthServerServiceTestSuite) TestConfiguration_AdvertisesTheKeyringAlgorithm() {
+	configuration := suite.service.Configuration()
+
+	suite.Equal(oidcIssuer, configuration.Issuer)
+	suite.Equal(oidcIssuer+"/api/v1/oauth/token", configuration.TokenEndpoint)
+	suite.Equal([]string{"ES256"}, configuration.IdTokenSigningAlgValuesSupported)
+	suite.Equal([]string{"S256"}, configuration.CodeChallengeMethodsSupported)
+}
+
+func TestOAuthServerServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(OAuthServerServiceTestSuite))
+}
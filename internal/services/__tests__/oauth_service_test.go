@@ -0,0 +1,401 @@
+package services_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"my-go-api/internal/config"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	"my-go-api/internal/utils"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeIssuer is a minimal OpenID provider: it serves discovery, a JWKS and a
+// token endpoint that enforces PKCE and signs ID tokens with an RSA key.
+type fakeIssuer struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	clientId string
+
+	mu       sync.Mutex
+	pending  map[string]fakeAuthorization
+	audience string
+	email    string
+	verified any
+	// multiTenant makes discovery announce a "{tenantid}" issuer.
+	multiTenant bool
+	// kid is the key id in the header of issued ID tokens.
+	kid         string
+	jwksFetches int
+}
+
+type fakeAuthorization struct {
+	challenge string
+	nonce     string
+}
+
+func newFakeIssuer(t *testing.T, clientId string) *fakeIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := &fakeIssuer{
+		key:      key,
+		clientId: clientId,
+		pending:  make(map[string]fakeAuthorization),
+		audience: clientId,
+		email:    "Ari@Mail.com",
+		verified: true,
+		kid:      "test-key",
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		discoveryIssuer := issuer.server.URL
+		if issuer.multiTenant {
+			discoveryIssuer += "/{tenantid}"
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 discoveryIssuer,
+			"authorization_endpoint": issuer.server.URL + "/authorize",
+			"token_endpoint":         issuer.server.URL + "/token",
+			"jwks_uri":               issuer.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		issuer.mu.Lock()
+		issuer.jwksFetches++
+		issuer.mu.Unlock()
+		json.NewEncoder(w).Encode(services.JSONWebKeySet{Keys: []services.JSONWebKey{{
+			Kty: "RSA",
+			Kid: "test-key",
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", issuer.token)
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+// authorize simulates the user consenting on the provider: it records the
+// PKCE challenge and nonce from the authorization URL against a new code.
+func (f *fakeIssuer) authorize(t *testing.T, authURL string) (state, code string) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, f.clientId, query.Get("client_id"))
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	code = "code-" + query.Get("state")
+	f.pending[code] = fakeAuthorization{challenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	return query.Get("state"), code
+}
+
+func (f *fakeIssuer) token(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	f.mu.Lock()
+	auth, ok := f.pending[r.Form.Get("code")]
+	delete(f.pending, r.Form.Get("code"))
+	f.mu.Unlock()
+	verifierHash := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(verifierHash[:]) != auth.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            f.server.URL,
+		"sub":            "google-subject-1",
+		"aud":            f.audience,
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          auth.nonce,
+		"email":          f.email,
+		"email_verified": f.verified,
+		"name":           "Ari",
+	})
+	idToken.Header["kid"] = f.kid
+	signed, _ := idToken.SignedString(f.key)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "provider-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     signed,
+	})
+}
+
+// fakeGithub mimics the GitHub OAuth endpoints and the REST API calls used to
+// build the profile.
+type fakeGithub struct {
+	server *httptest.Server
+	mu     sync.Mutex
+	codes  map[string]string
+}
+
+func newFakeGithub(t *testing.T) *fakeGithub {
+	github := &fakeGithub{codes: make(map[string]string)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		github.mu.Lock()
+		challenge, ok := github.codes[r.Form.Get("code")]
+		delete(github.codes, r.Form.Get("code"))
+		github.mu.Unlock()
+		verifierHash := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(verifierHash[:]) != challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "gh-token", "token_type": "bearer"})
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"id": 42, "login": "ari-gh", "name": ""})
+	})
+	mux.HandleFunc("/user/emails", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]any{
+			{"email": "old@mail.com", "primary": false, "verified": true},
+			{"email": "Ari@mail.com", "primary": true, "verified": true},
+		})
+	})
+	github.server = httptest.NewServer(mux)
+	t.Cleanup(github.server.Close)
+	return github
+}
+
+func (f *fakeGithub) authorize(t *testing.T, authURL string) (state, code string) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	code = "code-" + query.Get("state")
+	f.codes[code] = query.Get("code_challenge")
+	return query.Get("state"), code
+}
+
+type OAuthServiceTestSuite struct {
+	suite.Suite
+	issuer       *fakeIssuer
+	github       *fakeGithub
+	rdb          *redis.Client
+	oauthService services.IOAuthService
+}
+
+func (suite *OAuthServiceTestSuite) SetupTest() {
+	suite.issuer = newFakeIssuer(suite.T(), "client-123")
+	suite.github = newFakeGithub(suite.T())
+	suite.rdb = redis.NewClient(&redis.Options{Addr: miniredis.RunT(suite.T()).Addr()})
+	redisService := services.NewRedisService(repositories.NewRedisRepository(suite.rdb))
+	providers, err := services.NewOAuthProviders([]config.OAuthProviderConfig{
+		{
+			Name:         "google",
+			Type:         config.OAuthProviderTypeOIDC,
+			Issuer:       suite.issuer.server.URL,
+			ClientId:     "client-123",
+			ClientSecret: "secret",
+			RedirectUri:  "http://localhost/callback",
+			Scopes:       []string{"openid", "email", "profile"},
+		},
+		{
+			Name:         "github",
+			Type:         config.OAuthProviderTypeGithub,
+			AuthUrl:      suite.github.server.URL + "/login/oauth/authorize",
+			TokenUrl:     suite.github.server.URL + "/login/oauth/access_token",
+			UserInfoUrl:  suite.github.server.URL,
+			ClientId:     "client-123",
+			ClientSecret: "secret",
+			RedirectUri:  "http://localhost/callback",
+		},
+	})
+	assert.NoError(suite.T(), err)
+	suite.oauthService = services.NewOAuthService(providers, redisService, utils.NewUtilities("secret", "http://localhost"))
+}
+
+func (suite *OAuthServiceTestSuite) TearDownTest() {
+	suite.rdb.Close()
+}
+
+func (suite *OAuthServiceTestSuite) TestExchangeReturnsVerifiedProfile() {
+	flow, err := suite.oauthService.AuthCodeURL(context.Background(), "google")
+	assert.NoError(suite.T(), err)
+	state, code := suite.issuer.authorize(suite.T(), flow.URL)
+
+	result, err := suite.oauthService.Exchange(context.Background(), "google", state, code, flow.Binding)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), services.OAuthProfile{
+		Provider:      "google",
+		Subject:       "google-subject-1",
+		Email:         "ari@mail.com",
+		EmailVerified: true,
+		Name:          "Ari",
+	}, result.Profile)
+}
+
+func (suite *OAuthServiceTestSuite) TestStateCannotBeReplayed() {
+	flow, err := suite.oauthService.AuthCodeURL(context.Background(), "google")
+	assert.NoError(suite.T(), err)
+	state, code := suite.issuer.authorize(suite.T(), flow.URL)
+
+	_, err = suite.oauthService.Exchange(context.Background(), "google", state, code, flow.Binding)
+	assert.NoError(suite.T(), err)
+	_, err = suite.oauthService.Exchange(context.Background(), "google", state, code, flow.Binding)
+	assert.ErrorIs(suite.T(), err, services.ErrInvalidOAuthState)
+}
+
+func (suite *OAuthServiceTestSuite) TestStateIsBoundToBrowser() {
+	flow, err := suite.oauthService.AuthCodeURL(context.Background(), "google")
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), flow.Binding)
+	state, code := suite.issuer.authorize(suite.T(), flow.URL)
+
+	_, err = suite.oauthService.Exchange(context.Background(), "google", state, code, "attacker-binding")
+	assert.ErrorIs(suite.T(), err, services.ErrInvalidOAuthState)
+	_, err = suite.oauthService.Exchange(context.Background(), "google", state, code, flow.Binding)
+	assert.ErrorIs(suite.T(), err, services.ErrInvalidOAuthState)
+}
+
+func (suite *OAuthServiceTestSuite) TestMissingBindingIsRejected() {
+	flow, err := suite.oauthService.AuthCodeURL(context.Background(), "google")
+	assert.NoError(suite.T(), err)
+	state, code := suite.issuer.authorize(suite.T(), flow.URL)
+
+	_, err = suite.oauthService.Exchange(context.Background(), "google", state, code, "")
+	assert.ErrorIs(suite.T(), err, services.ErrInvalidOAuthState)
+}
+
+func (suite *OAuthServiceTestSuite) TestRejectsTokenForAnotherAudience() {
+	suite.issuer.audience = "someone-else"
+	flow, err := suite.oauthService.AuthCodeURL(context.Background(), "google")
+	assert.NoError(suite.T(), err)
+	state, code := suite.issuer.authorize(suite.T(), flow.URL)
+
+	_, err = suite.oauthService.Exchange(context.Background(), "google", state, code, flow.Binding)
+	assert.ErrorContains(suite.T(), err, "invalid id_token")
+}
+
+func (suite *OAuthServiceTestSuite) TestUnknownKeyIdRefetchesKeysOnce() {
+	suite.issuer.kid = "made-up-key"
+	for range 3 {
+		flow, err := suite.oauthService.AuthCodeURL(context.Background(), "google")
+		suite.Require().NoError(err)
+		state, code := suite.issuer.authorize(suite.T(), flow.URL)
+
+		_, err = suite.oauthService.Exchange(context.Background(), "google", state, code, flow.Binding)
+		assert.ErrorContains(suite.T(), err, "unknown signing key: made-up-key")
+	}
+
+	suite.issuer.mu.Lock()
+	defer suite.issuer.mu.Unlock()
+	assert.Equal(suite.T(), 1, suite.issuer.jwksFetches)
+}
+
+func (suite *OAuthServiceTestSuite) TestAcceptsStringEmailVerified() {
+	suite.issuer.verified = "false"
+	flow, err := suite.oauthService.AuthCodeURL(context.Background(), "google")
+	assert.NoError(suite.T(), err)
+	state, code := suite.issuer.authorize(suite.T(), flow.URL)
+
+	result, err := suite.oauthService.Exchange(context.Background(), "google", state, code, flow.Binding)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), result.Profile.EmailVerified)
+}
+
+func (suite *OAuthServiceTestSuite) TestStateIsBoundToProvider() {
+	flow, err := suite.oauthService.AuthCodeURL(context.Background(), "google")
+	assert.NoError(suite.T(), err)
+	state, code := suite.issuer.authorize(suite.T(), flow.URL)
+
+	_, err = suite.oauthService.Exchange(context.Background(), "github", state, code, flow.Binding)
+	assert.ErrorIs(suite.T(), err, services.ErrInvalidOAuthState)
+}
+
+func (suite *OAuthServiceTestSuite) TestLinkFlowCarriesUser() {
+	userId := uuid.New()
+	flow, err := suite.oauthService.LinkCodeURL(context.Background(), "google", userId)
+	assert.NoError(suite.T(), err)
+	state, code := suite.issuer.authorize(suite.T(), flow.URL)
+
+	result, err := suite.oauthService.Exchange(context.Background(), "google", state, code, flow.Binding)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), userId, result.LinkUserId)
+}
+
+func (suite *OAuthServiceTestSuite) TestLinkFlowIsBoundToBrowser() {
+	flow, err := suite.oauthService.LinkCodeURL(context.Background(), "google", uuid.New())
+	assert.NoError(suite.T(), err)
+	state, code := suite.issuer.authorize(suite.T(), flow.URL)
+
+	_, err = suite.oauthService.Exchange(context.Background(), "google", state, code, "")
+	assert.ErrorIs(suite.T(), err, services.ErrInvalidOAuthState)
+}
+
+func (suite *OAuthServiceTestSuite) TestRefusesTrustedEmailsFromMultiTenantIssuer() {
+	suite.issuer.multiTenant = true
+	provider := services.NewOIDCProvider(config.OAuthProviderConfig{
+		Name:        "microsoft",
+		Type:        config.OAuthProviderTypeOIDC,
+		Issuer:      suite.issuer.server.URL,
+		ClientId:    "client-123",
+		RedirectUri: "http://localhost/callback",
+		TrustEmail:  true,
+	}, http.DefaultClient)
+
+	_, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	assert.ErrorContains(suite.T(), err, "multi-tenant")
+}
+
+func (suite *OAuthServiceTestSuite) TestUnknownProvider() {
+	_, err := suite.oauthService.AuthCodeURL(context.Background(), "myspace")
+	assert.ErrorIs(suite.T(), err, services.ErrUnknownOAuthProvider)
+}
+
+func (suite *OAuthServiceTestSuite) TestGithubProfileUsesPrimaryEmail() {
+	flow, err := suite.oauthService.AuthCodeURL(context.Background(), "github")
+	assert.NoError(suite.T(), err)
+	state, code := suite.github.authorize(suite.T(), flow.URL)
+
+	result, err := suite.oauthService.Exchange(context.Background(), "github", state, code, flow.Binding)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), services.OAuthProfile{
+		Provider:      "github",
+		Subject:       "42",
+		Email:         "ari@mail.com",
+		EmailVerified: true,
+		Name:          "ari-gh",
+	}, result.Profile)
+}
+
+func TestOAuthServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(OAuthServiceTestSuite))
+}
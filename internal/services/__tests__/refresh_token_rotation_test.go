@@ -0,0 +1,178 @@
+package services_test
+
+import (
+	"context"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	"my-go-api/internal/utils"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RefreshTokenRotationTestSuite struct {
+	suite.Suite
+	server       *miniredis.Miniredis
+	rdb          *redis.Client
+	redisService services.IRedisService
+}
+
+func (suite *RefreshTokenRotationTestSuite) SetupTest() {
+	suite.server = miniredis.RunT(suite.T())
+	suite.rdb = redis.NewClient(&redis.Options{Addr: suite.server.Addr()})
+	suite.redisService = services.NewRedisService(repositories.NewRedisRepository(suite.rdb))
+	assert.NoError(suite.T(), suite.redisService.SaveRefreshToken(services.RefreshTokenData{
+		HashedToken: "hashed-refresh",
+		UserId:      "user123",
+		Jti:         "jti-abc",
+	}))
+}
+
+func (suite *RefreshTokenRotationTestSuite) TearDownTest() {
+	suite.rdb.Close()
+}
+
+func (suite *RefreshTokenRotationTestSuite) TestOnlyOneConcurrentClaimWins() {
+	const workers = 10
+	var wg sync.WaitGroup
+	claims := make(chan services.RefreshTokenClaim, workers)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			claim, err := suite.redisService.ClaimRefreshToken("hashed-refresh", nil)
+			assert.NoError(suite.T(), err)
+			claims <- claim
+		}()
+	}
+	wg.Wait()
+	close(claims)
+
+	claimed := 0
+	for claim := range claims {
+		switch claim.Status {
+		case services.RefreshTokenClaimed:
+			claimed++
+			assert.Equal(suite.T(), "user123", claim.Data.UserId)
+			assert.Equal(suite.T(), "jti-abc", claim.Data.Jti)
+		default:
+			assert.Equal(suite.T(), services.RefreshTokenRotationPending, claim.Status)
+		}
+	}
+	assert.Equal(suite.T(), 1, claimed)
+}
+
+func (suite *RefreshTokenRotationTestSuite) TestReplayWithinGracePeriodReturnsSamePair() {
+	claim, err := suite.redisService.ClaimRefreshToken("hashed-refresh", nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), services.RefreshTokenClaimed, claim.Status)
+
+	pair := services.RotatedRefreshTokenData{RefreshToken: "new-refresh", AccessToken: "new-access"}
+	assert.NoError(suite.T(), suite.redisService.CompleteRefreshTokenRotation("hashed-refresh", pair, 10*time.Second))
+
+	replay, err := suite.redisService.ClaimRefreshToken("hashed-refresh", nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), services.RefreshTokenRotated, replay.Status)
+	assert.Equal(suite.T(), pair, replay.Rotated)
+
+	suite.server.FastForward(11 * time.Second)
+
+	expired, err := suite.redisService.ClaimRefreshToken("hashed-refresh", nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), services.RefreshTokenMissing, expired.Status)
+}
+
+func (suite *RefreshTokenRotationTestSuite) TestClaimWithSeedAfterFlush() {
+	suite.server.FlushAll()
+
+	claim, err := suite.redisService.ClaimRefreshToken("hashed-refresh", nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), services.RefreshTokenMissing, claim.Status)
+
+	claim, err = suite.redisService.ClaimRefreshToken("hashed-refresh", &services.RefreshTokenData{
+		UserId: "user123",
+		Jti:    "jti-abc",
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), services.RefreshTokenClaimed, claim.Status)
+	assert.Equal(suite.T(), "jti-abc", claim.Data.Jti)
+
+	again, err := suite.redisService.ClaimRefreshToken("hashed-refresh", &services.RefreshTokenData{
+		UserId: "user123",
+		Jti:    "jti-abc",
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), services.RefreshTokenRotationPending, again.Status)
+}
+
+func (suite *RefreshTokenRotationTestSuite) TestReleaseAllowsRetry() {
+	_, err := suite.redisService.ClaimRefreshToken("hashed-refresh", nil)
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.redisService.ReleaseRefreshTokenClaim("hashed-refresh"))
+
+	claim, err := suite.redisService.ClaimRefreshToken("hashed-refresh", &services.RefreshTokenData{
+		UserId: "user123",
+		Jti:    "jti-abc",
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), services.RefreshTokenClaimed, claim.Status)
+}
+
+func (suite *RefreshTokenRotationTestSuite) TestRotatedPairIsSealedInRedis() {
+	utilities := utils.NewUtilities("secret", "http://localhost")
+	authService := services.NewAuthService(suite.redisService, utilities, nil, nil, nil, nil, 10*time.Second)
+	assert.NoError(suite.T(), suite.redisService.SaveRefreshToken(services.RefreshTokenData{
+		HashedToken: utilities.HashWithSHA256("raw-refresh"),
+		UserId:      "user123",
+		Jti:         "jti-abc",
+	}))
+	claim, err := authService.ClaimRefreshToken(context.Background(), "raw-refresh", nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), services.RefreshTokenClaimed, claim.Status)
+
+	authService.CompleteRefreshTokenRotation("raw-refresh", services.CreateAuthTokensResult{
+		RefreshToken: "new-refresh",
+		AccessToken:  "new-access",
+	})
+	for _, key := range suite.server.Keys() {
+		if suite.server.Type(key) != "hash" {
+			continue
+		}
+		fields, _ := suite.server.HKeys(key)
+		for _, field := range fields {
+			assert.NotContains(suite.T(), suite.server.HGet(key, field), "new-refresh")
+		}
+	}
+
+	replay, err := authService.ClaimRefreshToken(context.Background(), "raw-refresh", nil)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), services.RefreshTokenRotated, replay.Status)
+	assert.Equal(suite.T(), "new-refresh", replay.Rotated.RefreshToken)
+	assert.Equal(suite.T(), "new-access", replay.Rotated.AccessToken)
+}
+
+func (suite *RefreshTokenRotationTestSuite) TestPendingWaitStopsWithTheRequest() {
+	utilities := utils.NewUtilities("secret", "http://localhost")
+	authService := services.NewAuthService(suite.redisService, utilities, nil, nil, nil, nil, 10*time.Second)
+	seed := &services.RefreshTokenData{UserId: "user123", Jti: "jti-abc"}
+	first, err := suite.redisService.ClaimRefreshToken(utilities.HashWithSHA256("raw-refresh"), seed)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), services.RefreshTokenClaimed, first.Status)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	claim, err := authService.ClaimRefreshToken(ctx, "raw-refresh", seed)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), services.RefreshTokenRotationPending, claim.Status)
+	assert.Less(suite.T(), time.Since(started), services.RefreshTokenClaimTTL)
+}
+
+func TestRefreshTokenRotationTestSuite(t *testing.T) {
+	suite.Run(t, new(RefreshTokenRotationTestSuite))
+}
@@ -0,0 +1,141 @@
+package services_test
+
+import (
+	"context"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	mockrepositories "my-go-api/mocks/mock_repositories"
+	mockservices "my-go-api/mocks/mock_services"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type RoleServiceTestSuite struct {
+	suite.Suite
+	mockRepo  *mockrepositories.MockIRoleRepository
+	mockSess  *mockservices.MockISessionService
+	mockRedis *mockservices.MockIRedisService
+	service   services.IRoleService
+}
+
+func (suite *RoleServiceTestSuite) SetupTest() {
+	ctrl := gomock.NewController(suite.T())
+	suite.mockRepo = mockrepositories.NewMockIRoleRepository(ctrl)
+	suite.mockSess = mockservices.NewMockISessionService(ctrl)
+	suite.mockRedis = mockservices.NewMockIRedisService(ctrl)
+	suite.service = services.NewRoleService(suite.mockRepo, suite.mockSess, suite.mockRedis)
+}
+
+func (suite *RoleServiceTestSuite) TestCreateRoleRejectsUnknownPermission() {
+	suite.mockRepo.EXPECT().GetAll(gomock.Any()).Return([]models.Role{{ID: 1, Name: "admin", IsSystem: true}}, nil)
+	suite.mockRepo.EXPECT().GetPermissions(gomock.Any()).Return([]models.Permission{{ID: 1, Name: models.PermissionUsersRead}}, nil)
+
+	_, err := suite.service.CreateRole(context.Background(), uuid.New(), repositories.CreateRoleParams{
+		Name:        "support",
+		Permissions: []string{models.PermissionUsersRead, "users:delete"},
+	})
+	suite.ErrorIs(err, services.ErrUnknownPermission)
+}
+
+func (suite *RoleServiceTestSuite) TestCreateRoleRejectsDuplicateName() {
+	suite.mockRepo.EXPECT().GetAll(gomock.Any()).Return([]models.Role{{ID: 3, Name: "support"}}, nil)
+
+	_, err := suite.service.CreateRole(context.Background(), uuid.New(), repositories.CreateRoleParams{Name: "support"})
+	suite.ErrorIs(err, services.ErrRoleExists)
+}
+
+func (suite *RoleServiceTestSuite) TestSystemRolesCannotChange() {
+	suite.mockRepo.EXPECT().GetOne(gomock.Any(), 1).Return(&models.Role{ID: 1, Name: "admin", IsSystem: true}, nil).Times(2)
+
+	_, err := suite.service.SetRolePermissions(context.Background(), uuid.New(), 1, []string{})
+	suite.ErrorIs(err, services.ErrSystemRole)
+	suite.ErrorIs(suite.service.DeleteRole(context.Background(), 1), services.ErrSystemRole)
+}
+
+func (suite *RoleServiceTestSuite) TestSetUserRolesExpiresAccessTokens() {
+	actorId := uuid.New()
+	userId := uuid.New()
+	jti := uuid.New()
+	roles := []models.Role{{ID: 3, Name: "support", Permissions: []string{models.PermissionUsersRead}}}
+
+	suite.mockRepo.EXPECT().GetAll(gomock.Any()).Return(roles, nil)
+	suite.mockRepo.EXPECT().GetUserRoles(gomock.Any(), userId).Return([]models.Role{}, nil)
+	suite.mockRepo.EXPECT().GetUserPermissions(gomock.Any(), actorId).Return([]string{models.PermissionRolesAssign, models.PermissionUsersRead}, nil)
+	suite.mockRepo.EXPECT().SetUserRoles(gomock.Any(), userId, []string{"support"}).Return(nil)
+	suite.mockSess.EXPECT().GetActiveSessions(gomock.Any(), userId).Return([]models.Token{{Jti: jti}}, nil)
+	suite.mockRedis.EXPECT().DeleteAccessToken(jti.String()).Return(nil)
+	suite.mockRepo.EXPECT().GetUserRoles(gomock.Any(), userId).Return(roles, nil)
+
+	assigned, err := suite.service.SetUserRoles(context.Background(), actorId, userId, []string{"support"})
+	suite.NoError(err)
+	suite.Equal(roles, assigned)
+}
+
+func (suite *RoleServiceTestSuite) TestSetUserRolesRejectsUnknownRole() {
+	suite.mockRepo.EXPECT().GetAll(gomock.Any()).Return([]models.Role{{ID: 1, Name: "admin"}}, nil)
+
+	_, err := suite.service.SetUserRoles(context.Background(), uuid.New(), uuid.New(), []string{"owner"})
+	suite.ErrorIs(err, services.ErrUnknownRole)
+}
+
+func (suite *RoleServiceTestSuite) TestSetUserRolesRejectsPermissionsTheActorLacks() {
+	actorId := uuid.New()
+	roles := []models.Role{{ID: 3, Name: "support", Permissions: []string{models.PermissionUsersRead, models.PermissionUsersManage}}}
+
+	suite.mockRepo.EXPECT().GetAll(gomock.Any()).Return(roles, nil)
+	suite.mockRepo.EXPECT().GetUserRoles(gomock.Any(), actorId).Return([]models.Role{}, nil)
+	suite.mockRepo.EXPECT().GetUserPermissions(gomock.Any(), actorId).Return([]string{models.PermissionRolesAssign, models.PermissionUsersRead}, nil)
+
+	_, err := suite.service.SetUserRoles(context.Background(), actorId, actorId, []string{"support"})
+	suite.ErrorIs(err, services.ErrRoleEscalation)
+}
+
+func (suite *RoleServiceTestSuite) TestAdminRoleNeedsItsOwnPermission() {
+	actorId := uuid.New()
+	held := []string{models.PermissionRolesAssign, models.PermissionUsersRead}
+	roles := []models.Role{{ID: 1, Name: models.RoleAdmin, IsSystem: true, Permissions: []string{models.PermissionUsersRead}}}
+
+	suite.mockRepo.EXPECT().GetAll(gomock.Any()).Return(roles, nil).Times(2)
+	suite.mockRepo.EXPECT().GetUserPermissions(gomock.Any(), actorId).Return(held, nil).Times(2)
+
+	suite.ErrorIs(suite.service.CheckRoleChange(context.Background(), actorId, []string{models.RoleAdmin}, []string{models.RoleUser}), services.ErrRoleEscalation)
+	suite.ErrorIs(suite.service.CheckRoleChange(context.Background(), actorId, []string{models.RoleUser}, []string{models.RoleAdmin}), services.ErrRoleEscalation)
+}
+
+func (suite *RoleServiceTestSuite) TestSetRolePermissionsOnlyAddsHeldPermissions() {
+	actorId := uuid.New()
+	role := &models.Role{ID: 3, Name: "support", Permissions: []string{models.PermissionUsersRead}}
+
+	suite.mockRepo.EXPECT().GetOne(gomock.Any(), 3).Return(role, nil)
+	suite.mockRepo.EXPECT().GetPermissions(gomock.Any()).Return([]models.Permission{{Name: models.PermissionUsersRead}, {Name: models.PermissionRolesAssign}}, nil)
+	suite.mockRepo.EXPECT().GetUserPermissions(gomock.Any(), actorId).Return([]string{models.PermissionRolesWrite}, nil)
+
+	_, err := suite.service.SetRolePermissions(context.Background(), actorId, 3, []string{models.PermissionUsersRead, models.PermissionRolesAssign})
+	suite.ErrorIs(err, services.ErrRoleEscalation)
+}
+
+func (suite *RoleServiceTestSuite) TestSetRolePermissionsExpiresHoldersAccessTokens() {
+	actorId := uuid.New()
+	holderId := uuid.New()
+	jti := uuid.New()
+	role := &models.Role{ID: 3, Name: "support", Permissions: []string{models.PermissionUsersRead, models.PermissionUsersManage}}
+
+	suite.mockRepo.EXPECT().GetOne(gomock.Any(), 3).Return(role, nil)
+	suite.mockRepo.EXPECT().GetPermissions(gomock.Any()).Return([]models.Permission{{Name: models.PermissionUsersRead}, {Name: models.PermissionUsersManage}}, nil)
+	suite.mockRepo.EXPECT().GetRoleUserIds(gomock.Any(), 3).Return([]uuid.UUID{holderId}, nil)
+	suite.mockRepo.EXPECT().SetPermissions(gomock.Any(), 3, []string{models.PermissionUsersRead}).Return(nil)
+	suite.mockSess.EXPECT().GetActiveSessions(gomock.Any(), holderId).Return([]models.Token{{Jti: jti}}, nil)
+	suite.mockRedis.EXPECT().DeleteAccessToken(jti.String()).Return(nil)
+	suite.mockRepo.EXPECT().GetOne(gomock.Any(), 3).Return(role, nil)
+
+	_, err := suite.service.SetRolePermissions(context.Background(), actorId, 3, []string{models.PermissionUsersRead})
+	suite.NoError(err)
+}
+
+func TestRoleServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(RoleServiceTestSuite))
+}
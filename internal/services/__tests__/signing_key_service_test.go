@@ -0,0 +1,345 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"my-go-api/internal/config"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	mockservices "my-go-api/mocks/mock_services"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+// memorySigningKeyRepository keeps the keyring the way the signing_keys table
+// does, with timestamps tests can move into the past.
+type memorySigningKeyRepository struct {
+	mu   sync.Mutex
+	keys []models.SigningKey
+}
+
+func dbNow() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+func (r *memorySigningKeyRepository) find(id string) *models.SigningKey {
+	for i := range r.keys {
+		if r.keys[i].ID == id {
+			return &r.keys[i]
+		}
+	}
+	return nil
+}
+
+func (r *memorySigningKeyRepository) List(_ context.Context) ([]models.SigningKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys := []models.SigningKey{}
+	for _, key := range r.keys {
+		if key.Status != models.SigningKeyRetired {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (r *memorySigningKeyRepository) ListAll(_ context.Context) ([]models.SigningKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.keys), nil
+}
+
+func (r *memorySigningKeyRepository) GetOne(_ context.Context, id string) (*models.SigningKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if key := r.find(id); key != nil {
+		copied := *key
+		return &copied, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *memorySigningKeyRepository) Create(_ context.Context, params repositories.CreateSigningKeyParams) (*models.SigningKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := models.SigningKey{ID: params.Id, Algorithm: params.Algorithm, Status: models.SigningKeyVerify, PrivateKey: params.PrivateKey, CreatedAt: dbNow()}
+	r.keys = append(r.keys, key)
+	return &key, nil
+}
+
+func (r *memorySigningKeyRepository) Bootstrap(_ context.Context, params repositories.CreateSigningKeyParams) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.keys) == 0 {
+		now := dbNow()
+		r.keys = append(r.keys, models.SigningKey{ID: params.Id, Algorithm: params.Algorithm, Status: models.SigningKeyActive, PrivateKey: params.PrivateKey, CreatedAt: now, ActivatedAt: &now})
+	}
+	return nil
+}
+
+func (r *memorySigningKeyRepository) Promote(_ context.Context, id string) (*models.SigningKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := r.find(id)
+	if key == nil || key.Status != models.SigningKeyVerify {
+		return nil, sql.ErrNoRows
+	}
+	now := dbNow()
+	for i := range r.keys {
+		if r.keys[i].Status == models.SigningKeyActive {
+			r.keys[i].Status = models.SigningKeyVerify
+			r.keys[i].DeactivatedAt = &now
+		}
+	}
+	key.Status, key.ActivatedAt, key.DeactivatedAt = models.SigningKeyActive, &now, nil
+	copied := *key
+	return &copied, nil
+}
+
+func (r *memorySigningKeyRepository) Retire(_ context.Context, id string) (*models.SigningKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := r.find(id)
+	if key == nil || key.Status != models.SigningKeyVerify {
+		return nil, sql.ErrNoRows
+	}
+	now := dbNow()
+	key.Status, key.RetiredAt = models.SigningKeyRetired, &now
+	copied := *key
+	return &copied, nil
+}
+
+// age moves every timestamp of key id back by d.
+func (r *memorySigningKeyRepository) age(id string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	back := func(value string) string {
+		parsed, _ := time.Parse(time.RFC3339, value)
+		return parsed.Add(-d).Format(time.RFC3339)
+	}
+	key := r.find(id)
+	key.CreatedAt = back(key.CreatedAt)
+	for _, field := range []**string{&key.ActivatedAt, &key.DeactivatedAt} {
+		if *field != nil {
+			value := back(**field)
+			*field = &value
+		}
+	}
+}
+
+// corrupt replaces the deactivation time of key id with an unreadable value.
+func (r *memorySigningKeyRepository) corrupt(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	value := "not a timestamp"
+	r.find(id).DeactivatedAt = &value
+}
+
+func (r *memorySigningKeyRepository) status(id string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.find(id).Status
+}
+
+type SigningKeyServiceTestSuite struct {
+	suite.Suite
+	ctrl       *gomock.Controller
+	mockRedis  *mockservices.MockIRedisService
+	repo       *memorySigningKeyRepository
+	config     config.JwtConfig
+	service    services.ISigningKeyService
+	jwtService services.IJwtService
+}
+
+func (suite *SigningKeyServiceTestSuite) SetupTest() {
+	suite.ctrl = gomock.NewController(suite.T())
+	suite.mockRedis = mockservices.NewMockIRedisService(suite.ctrl)
+	suite.mockRedis.EXPECT().GetAccessToken(gomock.Any()).Return(services.AccessTokenData{}, nil).AnyTimes()
+	suite.repo = &memorySigningKeyRepository{}
+	suite.config = config.JwtConfig{
+		Algorithm:        config.JwtAlgorithmHS256,
+		SecretKey:        "test-secret",
+		KeyEncryptionKey: "key-encryption-key",
+		KeyringRefresh:   time.Minute,
+		RotationInterval: 24 * time.Hour,
+		PublishDelay:     10 * time.Minute,
+		DrainPeriod:      70 * time.Minute,
+	}
+	suite.service = suite.newService()
+	suite.jwtService = services.NewJwtService(suite.service, suite.mockRedis)
+}
+
+func (suite *SigningKeyServiceTestSuite) TearDownTest() {
+	suite.ctrl.Finish()
+}
+
+func (suite *SigningKeyServiceTestSuite) newService() services.ISigningKeyService {
+	service, err := services.NewSigningKeyService(context.Background(), suite.config, suite.repo)
+	suite.Require().NoError(err)
+	return service
+}
+
+func (suite *SigningKeyServiceTestSuite) token() string {
+	token, err := suite.jwtService.Create(services.JWTPayload{UserId: "user123", Jti: "jti-abc"})
+	suite.Require().NoError(err)
+	return token
+}
+
+func (suite *SigningKeyServiceTestSuite) activeKey() string {
+	key, err := suite.service.SigningKey()
+	suite.Require().NoError(err)
+	return key.Id
+}
+
+func (suite *SigningKeyServiceTestSuite) introduce() string {
+	key, err := suite.service.Introduce(context.Background(), config.JwtAlgorithmES256)
+	suite.Require().NoError(err)
+	return key.ID
+}
+
+func (suite *SigningKeyServiceTestSuite) TestBootstrapsWithTheConfiguredKey() {
+	keys, err := suite.service.List(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Len(keys, 1)
+	suite.Equal(models.SigningKeyActive, keys[0].Status)
+	suite.NotContains(keys[0].PrivateKey, "test-secret")
+
+	// a second instance finds the keyring seeded already
+	suite.newService()
+	keys, _ = suite.service.List(context.Background())
+	suite.Len(keys, 1)
+}
+
+func (suite *SigningKeyServiceTestSuite) TestRotatesWithoutRejectingTokens() {
+	ctx := context.Background()
+	oldKey := suite.activeKey()
+	oldToken := suite.token()
+
+	newKey := suite.introduce()
+	suite.Equal(oldKey, suite.activeKey(), "an introduced key does not sign yet")
+	suite.Len(suite.jwtService.JWKS().Keys, 1, "its public key is published right away")
+
+	_, err := suite.service.Promote(ctx, newKey, false)
+	suite.ErrorIs(err, services.ErrSigningKeyUnpublished)
+	suite.repo.age(newKey, suite.config.PublishDelay)
+	_, err = suite.service.Promote(ctx, newKey, false)
+	suite.Require().NoError(err)
+	suite.Equal(newKey, suite.activeKey())
+
+	newToken := suite.token()
+	for _, token := range []string{oldToken, newToken} {
+		_, err := suite.jwtService.Verify(token)
+		suite.NoError(err)
+	}
+
+	_, err = suite.service.Retire(ctx, newKey, true)
+	suite.ErrorIs(err, services.ErrSigningKeyActive)
+	_, err = suite.service.Retire(ctx, oldKey, false)
+	suite.ErrorIs(err, services.ErrSigningKeyDraining)
+	suite.repo.age(oldKey, suite.config.DrainPeriod)
+	_, err = suite.service.Retire(ctx, oldKey, false)
+	suite.Require().NoError(err)
+
+	_, err = suite.jwtService.Verify(oldToken)
+	suite.ErrorContains(err, "unknown signing key")
+	_, err = suite.jwtService.Verify(newToken)
+	suite.NoError(err)
+
+	_, err = suite.service.Promote(ctx, oldKey, true)
+	suite.ErrorIs(err, services.ErrSigningKeyRetired)
+	_, err = suite.service.Promote(ctx, "missing", true)
+	suite.ErrorIs(err, services.ErrSigningKeyNotFound)
+}
+
+func (suite *SigningKeyServiceTestSuite) TestScheduledRotation() {
+	ctx := context.Background()
+	oldKey := suite.activeKey()
+
+	suite.Require().NoError(suite.service.Rotate(ctx))
+	keys, _ := suite.service.List(ctx)
+	suite.Len(keys, 1, "nothing is due yet")
+
+	suite.repo.age(oldKey, suite.config.RotationInterval-suite.config.PublishDelay)
+	suite.Require().NoError(suite.service.Rotate(ctx))
+	keys, _ = suite.service.List(ctx)
+	suite.Require().Len(keys, 2)
+	newKey := keys[1].ID
+	suite.Equal(models.SigningKeyVerify, keys[1].Status)
+
+	suite.Require().NoError(suite.service.Rotate(ctx))
+	suite.Equal(oldKey, suite.activeKey(), "the new key is promoted after the publish delay")
+
+	suite.repo.age(newKey, suite.config.PublishDelay)
+	suite.Require().NoError(suite.service.Rotate(ctx))
+	suite.Equal(newKey, suite.activeKey())
+	suite.Equal(models.SigningKeyVerify, suite.repo.status(oldKey))
+
+	suite.repo.age(oldKey, suite.config.DrainPeriod)
+	suite.Require().NoError(suite.service.Rotate(ctx))
+	suite.Equal(models.SigningKeyRetired, suite.repo.status(oldKey))
+}
+
+func (suite *SigningKeyServiceTestSuite) TestRotationRetiresPassedOverKeys() {
+	ctx := context.Background()
+	// two instances introduced a key each
+	first, second := suite.introduce(), suite.introduce()
+	suite.repo.age(first, suite.config.PublishDelay)
+	suite.repo.age(second, suite.config.PublishDelay)
+	_, err := suite.service.Promote(ctx, second, false)
+	suite.Require().NoError(err)
+
+	suite.repo.age(first, time.Second)
+	suite.Require().NoError(suite.service.Rotate(ctx))
+	suite.Equal(models.SigningKeyRetired, suite.repo.status(first))
+	suite.Equal(second, suite.activeKey())
+}
+
+func (suite *SigningKeyServiceTestSuite) TestMalformedTimestampsDoNotRetireKeys() {
+	ctx := context.Background()
+	oldKey := suite.activeKey()
+	_, err := suite.service.Promote(ctx, suite.introduce(), true)
+	suite.Require().NoError(err)
+	suite.repo.corrupt(oldKey)
+
+	suite.Require().NoError(suite.service.Rotate(ctx))
+	suite.Equal(models.SigningKeyVerify, suite.repo.status(oldKey))
+	_, err = suite.service.Retire(ctx, oldKey, false)
+	suite.ErrorContains(err, "malformed timestamp")
+	suite.Equal(models.SigningKeyVerify, suite.repo.status(oldKey))
+}
+
+func (suite *SigningKeyServiceTestSuite) TestPicksUpKeysOfOtherInstances() {
+	other := suite.newService()
+	newKey, err := other.Introduce(context.Background(), config.JwtAlgorithmEdDSA)
+	suite.Require().NoError(err)
+	_, err = other.Promote(context.Background(), newKey.ID, true)
+	suite.Require().NoError(err)
+
+	token, err := services.NewJwtService(other, suite.mockRedis).Create(services.JWTPayload{UserId: "user123", Jti: "jti-abc"})
+	suite.Require().NoError(err)
+	// refreshes are throttled right after the last one
+	_, err = suite.jwtService.Verify(token)
+	suite.Error(err)
+
+	suite.Require().NoError(suite.service.Refresh(context.Background()))
+	_, err = suite.jwtService.Verify(token)
+	suite.NoError(err)
+	suite.Equal(newKey.ID, suite.activeKey())
+}
+
+func (suite *SigningKeyServiceTestSuite) TestNeedsTheKeyEncryptionKey() {
+	suite.introduce()
+	suite.config.KeyEncryptionKey = "another-key"
+	_, err := services.NewSigningKeyService(context.Background(), suite.config, suite.repo)
+	suite.ErrorContains(err, "message authentication failed")
+}
+
+func TestSigningKeyServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SigningKeyServiceTestSuite))
+}
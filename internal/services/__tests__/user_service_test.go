@@ -0,0 +1,85 @@
+package services_test
+
+import (
+	"context"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	mockrepositories "my-go-api/mocks/mock_repositories"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type UserServiceTestSuite struct {
+	suite.Suite
+	mockRepo *mockrepositories.MockIUserRepository
+	service  services.IUserService
+}
+
+func (suite *UserServiceTestSuite) SetupTest() {
+	ctrl := gomock.NewController(suite.T())
+	suite.mockRepo = mockrepositories.NewMockIUserRepository(ctrl)
+	suite.service = services.NewUserService(suite.mockRepo)
+}
+
+func (suite *UserServiceTestSuite) TestListUsersHandsOutCursorForNextPage() {
+	users := []models.User{
+		{ID: uuid.New(), Username: "alice"},
+		{ID: uuid.New(), Username: "bobby"},
+		{ID: uuid.New(), Username: "carol"},
+	}
+	suite.mockRepo.EXPECT().List(gomock.Any(), gomock.Cond(func(x any) bool {
+		params := x.(repositories.ListUsersParams)
+		return params.Limit == 3 && params.After == nil
+	})).Return(users, nil)
+	suite.mockRepo.EXPECT().EstimateCount(gomock.Any(), gomock.Any()).Return(int64(40), nil)
+
+	page, err := suite.service.ListUsers(context.Background(), repositories.ListUsersParams{SortBy: "username", Limit: 2}, "")
+	suite.Require().NoError(err)
+	suite.Len(page.Users, 2)
+	suite.Equal(int64(40), page.TotalEstimate)
+	suite.Require().NotNil(page.NextCursor)
+
+	suite.mockRepo.EXPECT().List(gomock.Any(), gomock.Cond(func(x any) bool {
+		params := x.(repositories.ListUsersParams)
+		return params.After != nil && params.After.Value == "bobby" && params.After.Id == users[1].ID
+	})).Return(users[2:], nil)
+	suite.mockRepo.EXPECT().EstimateCount(gomock.Any(), gomock.Any()).Return(int64(40), nil)
+
+	page, err = suite.service.ListUsers(context.Background(), repositories.ListUsersParams{SortBy: "username", Limit: 2}, *page.NextCursor)
+	suite.Require().NoError(err)
+	suite.Len(page.Users, 1)
+	suite.Nil(page.NextCursor)
+}
+
+func (suite *UserServiceTestSuite) TestListUsersDefaultsToNewestFirst() {
+	suite.mockRepo.EXPECT().List(gomock.Any(), repositories.ListUsersParams{SortBy: "created_at", Desc: true, Limit: 21}).Return([]models.User{}, nil)
+	suite.mockRepo.EXPECT().EstimateCount(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+
+	page, err := suite.service.ListUsers(context.Background(), repositories.ListUsersParams{}, "")
+	suite.Require().NoError(err)
+	suite.Empty(page.Users)
+	suite.Nil(page.NextCursor)
+}
+
+func (suite *UserServiceTestSuite) TestListUsersRejectsCursorOfAnotherSort() {
+	users := []models.User{{ID: uuid.New(), Username: "alice"}, {ID: uuid.New(), Username: "bobby"}}
+	suite.mockRepo.EXPECT().List(gomock.Any(), gomock.Any()).Return(users, nil)
+	suite.mockRepo.EXPECT().EstimateCount(gomock.Any(), gomock.Any()).Return(int64(2), nil)
+
+	page, err := suite.service.ListUsers(context.Background(), repositories.ListUsersParams{SortBy: "username", Limit: 1}, "")
+	suite.Require().NoError(err)
+	suite.Require().NotNil(page.NextCursor)
+
+	_, err = suite.service.ListUsers(context.Background(), repositories.ListUsersParams{SortBy: "email", Limit: 1}, *page.NextCursor)
+	suite.ErrorIs(err, services.ErrInvalidCursor)
+	_, err = suite.service.ListUsers(context.Background(), repositories.ListUsersParams{}, "not-a-cursor")
+	suite.ErrorIs(err, services.ErrInvalidCursor)
+}
+
+func TestUserServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(UserServiceTestSuite))
+}
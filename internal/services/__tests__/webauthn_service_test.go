@@ -0,0 +1,325 @@
+package services_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"my-go-api/internal/config"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/services"
+	"my-go-api/internal/utils"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testRPID   = "localhost"
+	testOrigin = "http://localhost:5000"
+)
+
+// softAuthenticator is a software passkey: it answers creation and assertion
+// options the way a browser and platform authenticator would.
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialId []byte
+	userHandle   []byte
+	signCount    uint32
+}
+
+func newSoftAuthenticator(userId uuid.UUID) *softAuthenticator {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	credentialId := make([]byte, 32)
+	rand.Read(credentialId)
+	return &softAuthenticator{key: key, credentialId: credentialId, userHandle: userId[:]}
+}
+
+func (a *softAuthenticator) authData(attested bool) []byte {
+	rpIdHash := sha256.Sum256([]byte(testRPID))
+	// user present and user verified
+	flags := byte(0x01 | 0x04)
+	if attested {
+		flags |= 0x40
+	}
+	data := append(rpIdHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if !attested {
+		return data
+	}
+	publicKey, _ := cbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{KeyType: int64(webauthncose.EllipticKey), Algorithm: int64(webauthncose.AlgES256)},
+		Curve:         int64(webauthncose.P256),
+		XCoord:        a.key.X.FillBytes(make([]byte, 32)),
+		YCoord:        a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	data = append(data, make([]byte, 16)...) // aaguid
+	data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialId)))
+	data = append(data, a.credentialId...)
+	return append(data, publicKey...)
+}
+
+func clientData(ceremony string, challenge []byte) []byte {
+	data, _ := json.Marshal(map[string]any{
+		"type":      ceremony,
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    testOrigin,
+	})
+	return data
+}
+
+func (a *softAuthenticator) create(options any) []byte {
+	creation := options.(*protocol.CredentialCreation)
+	a.signCount++
+	attestation, _ := cbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authData(true),
+	})
+	response, _ := json.Marshal(map[string]any{
+		"id":    base64.RawURLEncoding.EncodeToString(a.credentialId),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.credentialId),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData("webauthn.create", creation.Response.Challenge)),
+			"attestationObject": base64.RawURLEncoding.EncodeToString(attestation),
+			"transports":        []string{"internal"},
+		},
+	})
+	return response
+}
+
+func (a *softAuthenticator) get(options any) []byte {
+	assertion := options.(*protocol.CredentialAssertion)
+	a.signCount++
+	return a.sign(assertion.Response.Challenge)
+}
+
+// sign answers challenge without moving the sign counter, like a cloned
+// authenticator would.
+func (a *softAuthenticator) sign(challenge []byte) []byte {
+	authData := a.authData(false)
+	clientDataJSON := clientData("webauthn.get", challenge)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	signature, _ := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	response, _ := json.Marshal(map[string]any{
+		"id":    base64.RawURLEncoding.EncodeToString(a.credentialId),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.credentialId),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientDataJSON),
+			"authenticatorData": base64.RawURLEncoding.EncodeToString(authData),
+			"signature":         base64.RawURLEncoding.EncodeToString(signature),
+			"userHandle":        base64.RawURLEncoding.EncodeToString(a.userHandle),
+		},
+	})
+	return response
+}
+
+// memoryWebauthnRepository keeps credentials in memory with the same sign
+// counter rule as the Postgres repository.
+type memoryWebauthnRepository struct {
+	mu          sync.Mutex
+	credentials []models.WebauthnCredential
+}
+
+func (r *memoryWebauthnRepository) CreateOne(_ context.Context, credential *models.WebauthnCredential) (*models.WebauthnCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	credential.ID = len(r.credentials) + 1
+	r.credentials = append(r.credentials, *credential)
+	return credential, nil
+}
+
+func (r *memoryWebauthnRepository) GetByUserId(_ context.Context, userId uuid.UUID) ([]models.WebauthnCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	credentials := []models.WebauthnCredential{}
+	for _, credential := range r.credentials {
+		if credential.UserId == userId {
+			credentials = append(credentials, credential)
+		}
+	}
+	return credentials, nil
+}
+
+func (r *memoryWebauthnRepository) CountByUserId(ctx context.Context, userId uuid.UUID) (int, error) {
+	credentials, err := r.GetByUserId(ctx, userId)
+	return len(credentials), err
+}
+
+func (r *memoryWebauthnRepository) UpdateUsage(_ context.Context, credentialId []byte, signCount uint32, backupState bool) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, credential := range r.credentials {
+		if string(credential.CredentialId) == string(credentialId) && (credential.SignCount < signCount || signCount == 0) {
+			r.credentials[i].SignCount = signCount
+			r.credentials[i].BackupState = backupState
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryWebauthnRepository) DeleteOne(_ context.Context, userId uuid.UUID, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, credential := range r.credentials {
+		if credential.ID == id && credential.UserId == userId {
+			r.credentials = append(r.credentials[:i], r.credentials[i+1:]...)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+var _ repositories.IWebauthnRepository = &memoryWebauthnRepository{}
+
+type WebauthnServiceTestSuite struct {
+	suite.Suite
+	repo          *memoryWebauthnRepository
+	service       services.IWebauthnService
+	user          *models.User
+	authenticator *softAuthenticator
+}
+
+func (suite *WebauthnServiceTestSuite) SetupTest() {
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(suite.T()).Addr()})
+	suite.repo = &memoryWebauthnRepository{}
+	service, err := services.NewWebauthnService(
+		config.WebauthnConfig{RPID: testRPID, RPDisplayName: "Test", RPOrigins: []string{testOrigin}},
+		suite.repo,
+		services.NewRedisService(repositories.NewRedisRepository(rdb)),
+		utils.NewUtilities("secret", testOrigin),
+	)
+	suite.Require().NoError(err)
+	suite.service = service
+	suite.user = &models.User{ID: uuid.New(), Email: "ari@mail.com", Name: "Ari"}
+	suite.authenticator = newSoftAuthenticator(suite.user.ID)
+}
+
+func (suite *WebauthnServiceTestSuite) register() {
+	ctx := context.Background()
+	ceremony, err := suite.service.BeginRegistration(ctx, suite.user)
+	suite.Require().NoError(err)
+	passkey, err := suite.service.FinishRegistration(ctx, suite.user, ceremony.SessionId, "Laptop", suite.authenticator.create(ceremony.Options))
+	suite.Require().NoError(err)
+	suite.Equal("Laptop", passkey.Name)
+	suite.Equal("internal", passkey.Transports)
+}
+
+func (suite *WebauthnServiceTestSuite) TestPasswordlessLogin() {
+	suite.register()
+	ctx := context.Background()
+
+	ceremony, err := suite.service.BeginLogin(ctx)
+	suite.Require().NoError(err)
+	userId, err := suite.service.FinishLogin(ctx, ceremony.SessionId, suite.authenticator.get(ceremony.Options))
+	suite.Require().NoError(err)
+	suite.Equal(suite.user.ID, userId)
+	suite.Equal(uint32(2), suite.repo.credentials[0].SignCount)
+}
+
+func (suite *WebauthnServiceTestSuite) TestSessionIsSingleUse() {
+	suite.register()
+	ctx := context.Background()
+
+	ceremony, err := suite.service.BeginLogin(ctx)
+	suite.Require().NoError(err)
+	response := suite.authenticator.get(ceremony.Options)
+	_, err = suite.service.FinishLogin(ctx, ceremony.SessionId, response)
+	suite.Require().NoError(err)
+
+	_, err = suite.service.FinishLogin(ctx, ceremony.SessionId, response)
+	suite.ErrorIs(err, services.ErrInvalidWebauthnSession)
+}
+
+func (suite *WebauthnServiceTestSuite) TestRejectsStaleSignCount() {
+	suite.register()
+	ctx := context.Background()
+
+	ceremony, err := suite.service.BeginLogin(ctx)
+	suite.Require().NoError(err)
+	_, err = suite.service.FinishLogin(ctx, ceremony.SessionId, suite.authenticator.get(ceremony.Options))
+	suite.Require().NoError(err)
+
+	ceremony, err = suite.service.BeginLogin(ctx)
+	suite.Require().NoError(err)
+	assertion := ceremony.Options.(*protocol.CredentialAssertion)
+	_, err = suite.service.FinishLogin(ctx, ceremony.SessionId, suite.authenticator.sign(assertion.Response.Challenge))
+	suite.ErrorIs(err, services.ErrWebauthnVerification)
+}
+
+func (suite *WebauthnServiceTestSuite) TestSecondFactor() {
+	suite.register()
+	ctx := context.Background()
+
+	ceremony, err := suite.service.BeginSecondFactor(ctx, suite.user.ID)
+	suite.Require().NoError(err)
+	err = suite.service.FinishSecondFactor(ctx, suite.user.ID, ceremony.SessionId, suite.authenticator.get(ceremony.Options))
+	suite.NoError(err)
+}
+
+func (suite *WebauthnServiceTestSuite) TestSecondFactorSessionIsBoundToUser() {
+	suite.register()
+	ctx := context.Background()
+
+	ceremony, err := suite.service.BeginSecondFactor(ctx, suite.user.ID)
+	suite.Require().NoError(err)
+	err = suite.service.FinishSecondFactor(ctx, uuid.New(), ceremony.SessionId, suite.authenticator.get(ceremony.Options))
+	suite.ErrorIs(err, services.ErrInvalidWebauthnSession)
+}
+
+func (suite *WebauthnServiceTestSuite) TestSecondFactorWithoutPasskeys() {
+	_, err := suite.service.BeginSecondFactor(context.Background(), suite.user.ID)
+	suite.ErrorIs(err, services.ErrNoPasskeys)
+}
+
+func TestWebauthnSessionIsTakenOnce(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	redisService := services.NewRedisService(repositories.NewRedisRepository(rdb))
+	if err := redisService.SaveWebauthnSession(services.WebauthnSessionData{
+		SessionId: "session-1",
+		Ceremony:  "login",
+		Session:   "{}",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	taken := 0
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := redisService.TakeWebauthnSession("session-1"); err == nil {
+				mu.Lock()
+				taken++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if taken != 1 {
+		t.Fatalf("session taken %d times", taken)
+	}
+}
+
+func TestWebauthnServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(WebauthnServiceTestSuite))
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type IAdminActionService interface {
+	Record(ctx context.Context, adminId, targetUserId uuid.UUID, action string, details map[string]any) (*models.AdminAction, error)
+	GetUserActions(ctx context.Context, userId uuid.UUID) ([]models.AdminAction, error)
+}
+
+type adminActionService struct {
+	adminActionRepo repositories.IAdminActionRepository
+}
+
+func NewAdminActionService(adminActionRepo repositories.IAdminActionRepository) IAdminActionService {
+	return &adminActionService{adminActionRepo: adminActionRepo}
+}
+
+// Record stores which admin performed action on the target user. The time is
+// set by the database.
+func (s *adminActionService) Record(ctx context.Context, adminId, targetUserId uuid.UUID, action string, details map[string]any) (*models.AdminAction, error) {
+	if details == nil {
+		details = map[string]any{}
+	}
+	payload, err := json.Marshal(details)
+	if err != nil {
+		return nil, err
+	}
+	return s.adminActionRepo.CreateOne(ctx, repositories.CreateAdminActionParams{
+		AdminId:      adminId,
+		TargetUserId: targetUserId,
+		Action:       action,
+		Details:      payload,
+	})
+}
+
+func (s *adminActionService) GetUserActions(ctx context.Context, userId uuid.UUID) ([]models.AdminAction, error) {
+	return s.adminActionRepo.GetByTargetUserId(ctx, userId)
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"my-go-api/internal/config"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAuditSigningDisabled = errors.New("no audit signing key is configured")
+)
+
+// IAuditService keeps the audit log as hash chains. Each chain, named by
+// AuditParams.Chain, is linked, locked, checkpointed and verified on its own,
+// so a tenant's trail can be proven without the others. The API has no
+// tenants yet, so every event is recorded on models.AuditChainDefault.
+type IAuditService interface {
+	// Record appends an event to the audit log. The request id, IP and user
+	// agent are taken from the RequestInfo in ctx. A failure to store the
+	// event is logged, never returned: the action it describes already
+	// happened.
+	Record(ctx context.Context, params AuditParams)
+	List(ctx context.Context, params repositories.ListAuditEventsParams) ([]models.AuditEvent, error)
+	Export(ctx context.Context, params repositories.ListAuditEventsParams, fn func(event models.AuditEvent) error) error
+	// Chains returns the name of every chain in the log.
+	Chains(ctx context.Context) ([]string, error)
+	// Checkpoint signs the current head of chain. It returns nil when the
+	// chain has no chained event yet.
+	Checkpoint(ctx context.Context, chain string) (*models.AuditCheckpoint, error)
+	// VerifyChain walks the whole of chain and reports the first break.
+	// Checkpoint signatures are checked with publicKey, or with the public
+	// half of the configured signing key when publicKey is nil.
+	VerifyChain(ctx context.Context, chain string, publicKey ed25519.PublicKey) (*AuditChainReport, error)
+}
+
+type auditService struct {
+	cfg            config.AuditConfig
+	auditEventRepo repositories.IAuditEventRepository
+
+	mu sync.Mutex
+	// sinceCheckpoint counts the events recorded on each chain since its
+	// last checkpoint
+	sinceCheckpoint map[string]int
+}
+
+func NewAuditService(cfg config.AuditConfig, auditEventRepo repositories.IAuditEventRepository) IAuditService {
+	return &auditService{cfg: cfg, auditEventRepo: auditEventRepo, sinceCheckpoint: map[string]int{}}
+}
+
+func (s *auditService) Record(ctx context.Context, params AuditParams) {
+	if params.Chain == "" {
+		params.Chain = models.AuditChainDefault
+	}
+	if params.Outcome == "" {
+		params.Outcome = models.AuditOutcomeSuccess
+	}
+	if params.Details == nil {
+		params.Details = map[string]any{}
+	}
+	details, err := json.Marshal(params.Details)
+	if err != nil {
+		log.Printf("failed to encode audit event %s: %s", params.Type, err.Error())
+		return
+	}
+	info := RequestInfoFrom(ctx)
+	// the event is stored even when the client has gone away
+	ctx = context.WithoutCancel(ctx)
+	if _, err := s.auditEventRepo.CreateOne(ctx, repositories.CreateAuditEventParams{
+		Type:      params.Type,
+		Outcome:   params.Outcome,
+		ActorId:   params.ActorId,
+		TargetId:  params.TargetId,
+		IpAddress: info.IpAddress,
+		UserAgent: info.UserAgent,
+		RequestId: info.RequestId,
+		Details:   details,
+		Chain:     params.Chain,
+	}, sealAuditEvent); err != nil {
+		log.Printf("failed to record audit event %s (request %s): %s", params.Type, info.RequestId, err.Error())
+		return
+	}
+
+	if s.cfg.SigningKey != nil && s.checkpointDue(params.Chain) {
+		if _, err := s.Checkpoint(ctx, params.Chain); err != nil {
+			log.Printf("failed to write audit checkpoint of chain %s: %s", params.Chain, err.Error())
+		}
+	}
+}
+
+// checkpointDue counts an event recorded on chain and reports whether the
+// chain is due for a checkpoint.
+func (s *auditService) checkpointDue(chain string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sinceCheckpoint[chain]++
+	if s.sinceCheckpoint[chain] < s.cfg.CheckpointInterval {
+		return false
+	}
+	delete(s.sinceCheckpoint, chain)
+	return true
+}
+
+func sealAuditEvent(event *models.AuditEvent) error {
+	hash, err := auditEventHash(*event)
+	if err != nil {
+		return err
+	}
+	event.Hash = hash
+	return nil
+}
+
+func (s *auditService) List(ctx context.Context, params repositories.ListAuditEventsParams) ([]models.AuditEvent, error) {
+	return s.auditEventRepo.List(ctx, params)
+}
+
+func (s *auditService) Export(ctx context.Context, params repositories.ListAuditEventsParams, fn func(event models.AuditEvent) error) error {
+	return s.auditEventRepo.Each(ctx, params, fn)
+}
+
+func (s *auditService) Chains(ctx context.Context) ([]string, error) {
+	return s.auditEventRepo.GetChains(ctx)
+}
+
+func (s *auditService) Checkpoint(ctx context.Context, chain string) (*models.AuditCheckpoint, error) {
+	if s.cfg.SigningKey == nil {
+		return nil, ErrAuditSigningDisabled
+	}
+	head, err := s.auditEventRepo.GetHead(ctx, chain)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if head.Hash == "" {
+		return nil, nil
+	}
+	signature := ed25519.Sign(s.cfg.SigningKey, auditCheckpointPayload(chain, head.ID, head.Hash))
+	return s.auditEventRepo.CreateCheckpoint(ctx, repositories.CreateAuditCheckpointParams{
+		Chain:       chain,
+		LastEventId: head.ID,
+		LastHash:    head.Hash,
+		Signature:   base64.StdEncoding.EncodeToString(signature),
+	})
+}
+
+// errChainBroken stops the walk at the first broken event.
+var errChainBroken = errors.New("audit chain broken")
+
+func (s *auditService) VerifyChain(ctx context.Context, chain string, publicKey ed25519.PublicKey) (*AuditChainReport, error) {
+	if publicKey == nil && s.cfg.SigningKey != nil {
+		publicKey = s.cfg.SigningKey.Public().(ed25519.PublicKey)
+	}
+	checkpoints, err := s.auditEventRepo.GetCheckpoints(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+	report := &AuditChainReport{Chain: chain, Checkpoints: len(checkpoints), SignaturesChecked: publicKey != nil}
+	breaks := []AuditChainBreak{}
+
+	pending := map[int64][]models.AuditCheckpoint{}
+	for _, checkpoint := range checkpoints {
+		if publicKey != nil && !verifyAuditCheckpoint(publicKey, checkpoint) {
+			breaks = append(breaks, AuditChainBreak{EventId: checkpoint.LastEventId, CheckpointId: checkpoint.ID, Reason: "checkpoint signature does not verify"})
+			continue
+		}
+		pending[checkpoint.LastEventId] = append(pending[checkpoint.LastEventId], checkpoint)
+	}
+
+	prevHash, chained := "", false
+	var walkBreak *AuditChainBreak
+	err = s.auditEventRepo.Walk(ctx, chain, func(event models.AuditEvent) error {
+		fail := func(reason string) error {
+			walkBreak = &AuditChainBreak{EventId: event.ID, Reason: reason}
+			return errChainBroken
+		}
+		if !chained && event.Hash == "" {
+			report.Unchained++
+			return nil
+		}
+		chained = true
+		if event.Hash == "" {
+			return fail("event is not chained")
+		}
+		if event.PrevHash != prevHash {
+			return fail("prev_hash does not match the previous event")
+		}
+		hash, err := auditEventHash(event)
+		if err != nil || hash != event.Hash {
+			return fail("hash does not match the event")
+		}
+		for _, checkpoint := range pending[event.ID] {
+			if checkpoint.LastHash != event.Hash {
+				walkBreak = &AuditChainBreak{EventId: event.ID, CheckpointId: checkpoint.ID, Reason: "event differs from the checkpoint"}
+				return errChainBroken
+			}
+		}
+		delete(pending, event.ID)
+		prevHash = event.Hash
+		report.Events++
+		report.HeadId = event.ID
+		return nil
+	})
+	if err != nil && !errors.Is(err, errChainBroken) {
+		return nil, err
+	}
+	if walkBreak != nil {
+		breaks = append(breaks, *walkBreak)
+	}
+	// checkpointed events the walk should have reached but never did were
+	// removed, including ones at the end that no later event links to
+	for eventId, eventCheckpoints := range pending {
+		if walkBreak == nil || eventId < walkBreak.EventId {
+			breaks = append(breaks, AuditChainBreak{EventId: eventId, CheckpointId: eventCheckpoints[0].ID, Reason: "checkpointed event is missing"})
+		}
+	}
+
+	for i := range breaks {
+		if report.Break == nil || breaks[i].EventId < report.Break.EventId {
+			report.Break = &breaks[i]
+		}
+	}
+	return report, nil
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo stores info in ctx for the audit log. The request id
+// middleware calls it for every request.
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFrom returns the RequestInfo stored in ctx, or an empty one.
+func RequestInfoFrom(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
+
+// AuditParams describes an audit event. ActorId and TargetId are uuid.Nil when
+// unknown; Outcome defaults to success and Chain to models.AuditChainDefault.
+type AuditParams struct {
+	Chain    string
+	Type     string
+	Outcome  string
+	ActorId  uuid.UUID
+	TargetId uuid.UUID
+	Details  map[string]any
+}
+
+type RequestInfo struct {
+	RequestId string
+	IpAddress string
+	UserAgent string
+}
@@ -1,40 +1,90 @@
 package services
 
 import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"log"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
 	"my-go-api/internal/utils"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrInvalidMagicLink     = errors.New("invalid or expired sign in link")
+	ErrInvalidMagicLinkCode = errors.New("invalid sign in code")
+)
+
 type authService struct {
-	redisService IRedisService
-	utils        utils.IUtils
-	jwtService   IJwtService
+	redisService         IRedisService
+	utils                utils.IUtils
+	jwtService           IJwtService
+	sessionService       ISessionService
+	roleService          IRoleService
+	securityEventService ISecurityEventService
+	gracePeriod          time.Duration
 }
 
 type IAuthService interface {
-	CreateAuthTokens(params CreateAuthTokenParams) (CreateAuthTokensResult, error)
+	CreateAuthTokens(ctx context.Context, params CreateAuthTokenParams) (CreateAuthTokensResult, error)
 	CreateVerificationToken(userId uuid.UUID) (VerificationTokenData, error)
 	VerifyVerificationToken(params VerificationTokenData) (string, error)
+	CreateMagicLinkToken(userId uuid.UUID) (MagicLinkTokenData, error)
+	RedeemMagicLinkCode(rawToken, code string) (uuid.UUID, error)
+	RedeemMagicLink(linkToken string) (uuid.UUID, error)
+	RevokeSessionTokens(sessions []models.Token)
+	HandleRefreshTokenReuse(ctx context.Context, hashedToken, ipAddress, userAgent string) bool
+	ClaimRefreshToken(ctx context.Context, rawToken string, seed *RefreshTokenData) (RefreshTokenClaim, error)
+	CompleteRefreshTokenRotation(rawToken string, result CreateAuthTokensResult)
+	ReleaseRefreshTokenClaim(hashedToken string)
 
 	// helpers (not exported)
 	GeneratePairToken() (TokenPair, error)
 }
 
-func NewAuthService(redisService IRedisService, utils utils.IUtils, jwtService IJwtService) IAuthService {
+func NewAuthService(
+	redisService IRedisService,
+	utils utils.IUtils,
+	jwtService IJwtService,
+	sessionService ISessionService,
+	roleService IRoleService,
+	securityEventService ISecurityEventService,
+	gracePeriod time.Duration,
+) IAuthService {
 	return &authService{
-		redisService: redisService,
-		utils:        utils,
-		jwtService:   jwtService,
+		redisService:         redisService,
+		utils:                utils,
+		jwtService:           jwtService,
+		sessionService:       sessionService,
+		roleService:          roleService,
+		securityEventService: securityEventService,
+		gracePeriod:          gracePeriod,
 	}
 }
 
-func (s *authService) CreateAuthTokens(params CreateAuthTokenParams) (CreateAuthTokensResult, error) {
+func (s *authService) CreateAuthTokens(ctx context.Context, params CreateAuthTokenParams) (CreateAuthTokensResult, error) {
+	// resolved first so a failed lookup leaves the old tokens untouched
+	var permissions []string
+	if params.ClientId == "" {
+		var err error
+		if permissions, err = s.roleService.GetUserPermissions(ctx, params.UserId); err != nil {
+			return CreateAuthTokensResult{}, err
+		}
+	}
 	// delete old refresh token record from redis (refresh token behavior)
+	var oldHashedToken string
 	if params.OldRefToken != nil {
-		if err := s.redisService.DeleteRefreshToken(s.utils.HashWithSHA256(*params.OldRefToken)); err != nil {
+		oldHashedToken = s.utils.HashWithSHA256(*params.OldRefToken)
+		if err := s.redisService.DeleteRefreshToken(oldHashedToken); err != nil {
 			log.Printf("failed to delete refresh token: %s", err.Error())
 			return CreateAuthTokensResult{}, err
 		}
@@ -54,14 +104,46 @@ func (s *authService) CreateAuthTokens(params CreateAuthTokenParams) (CreateAuth
 		HashedToken: refTokenPair.Hashed,
 		UserId:      params.UserId.String(),
 		Jti:         newJti.String(),
+		ClientId:    params.ClientId,
+		Scope:       params.Scope,
 	}); err != nil {
 		log.Println("failed to store refresh token in redis")
 		return CreateAuthTokensResult{}, err
 	}
+	// persist the session in postgres so it survives a redis flush
+	session := repositories.CreateSessionParams{
+		Hash:      refTokenPair.Hashed,
+		Jti:       newJti,
+		FamilyId:  uuid.New(),
+		DeviceId:  params.DeviceId,
+		UserId:    params.UserId,
+		UserAgent: params.UserAgent,
+		IpAddress: params.IpAddress,
+		ExpiredAt: time.Now().Add(RefreshTokenTTL),
+		ClientId:  params.ClientId,
+		Scope:     params.Scope,
+	}
+	if params.OldRefToken != nil {
+		_, err = s.sessionService.RotateSession(ctx, oldHashedToken, session)
+	} else {
+		s.revokeDeviceSessions(ctx, params.UserId, params.DeviceId)
+		_, err = s.sessionService.CreateSession(ctx, session)
+	}
+	if err != nil {
+		log.Printf("failed to store session: %s", err.Error())
+		// the new refresh token is never handed out, do not leave it usable
+		if err := s.redisService.DeleteRefreshToken(refTokenPair.Hashed); err != nil {
+			log.Printf("failed to delete refresh token: %s", err.Error())
+		}
+		return CreateAuthTokensResult{}, err
+	}
 	accessToken, err := s.jwtService.Create(JWTPayload{
-		UserId:     params.UserId.String(),
-		Jti:        newJti.String(),
-		JwtVersion: params.JwtVersion,
+		UserId:      params.UserId.String(),
+		Jti:         newJti.String(),
+		JwtVersion:  params.JwtVersion,
+		Permissions: permissions,
+		ClientId:    params.ClientId,
+		Scope:       params.Scope,
 	})
 	if err != nil {
 		return CreateAuthTokensResult{}, err
@@ -82,11 +164,7 @@ func (s *authService) CreateAuthTokens(params CreateAuthTokenParams) (CreateAuth
 }
 
 func (s *authService) CreateVerificationToken(userId uuid.UUID) (VerificationTokenData, error) {
-	tokenPair, err := s.GeneratePairToken()
-	if err != nil {
-		return VerificationTokenData{}, err
-	}
-	code, err := s.utils.GenerateRandomBytes(4)
+	tokenPair, code, err := s.generateCodedToken()
 	if err != nil {
 		return VerificationTokenData{}, err
 	}
@@ -103,6 +181,20 @@ func (s *authService) CreateVerificationToken(userId uuid.UUID) (VerificationTok
 	}, nil
 }
 
+// generateCodedToken generates a token for the client together with the short
+// code that is emailed to the user, as used by verification and magic links.
+func (s *authService) generateCodedToken() (TokenPair, string, error) {
+	tokenPair, err := s.GeneratePairToken()
+	if err != nil {
+		return TokenPair{}, "", err
+	}
+	code, err := s.utils.GenerateRandomBytes(4)
+	if err != nil {
+		return TokenPair{}, "", err
+	}
+	return tokenPair, code, nil
+}
+
 func (s *authService) VerifyVerificationToken(params VerificationTokenData) (string, error) {
 	data, err := s.redisService.GetVerificationToken(s.utils.HashWithSHA256(params.RawToken))
 	if err != nil {
@@ -114,7 +206,170 @@ func (s *authService) VerifyVerificationToken(params VerificationTokenData) (str
 	return data.UserId, nil
 }
 
+// CreateMagicLinkToken creates a passwordless sign in request. RawToken goes
+// back to the client that asked for it and is redeemed together with Code;
+// LinkToken is only ever sent by email and is redeemed on its own. Redeeming
+// either one consumes both. A request for uuid.Nil never signs in; it lets
+// unknown emails be answered with the same work as known ones.
+func (s *authService) CreateMagicLinkToken(userId uuid.UUID) (MagicLinkTokenData, error) {
+	tokenPair, code, err := s.generateCodedToken()
+	if err != nil {
+		return MagicLinkTokenData{}, err
+	}
+	linkPair, err := s.GeneratePairToken()
+	if err != nil {
+		return MagicLinkTokenData{}, err
+	}
+	if err := s.redisService.SaveMagicLink(MagicLinkData{
+		HashedToken:     tokenPair.Hashed,
+		HashedLinkToken: linkPair.Hashed,
+		Code:            code,
+		UserId:          userId.String(),
+	}); err != nil {
+		return MagicLinkTokenData{}, err
+	}
+	return MagicLinkTokenData{
+		RawToken:  tokenPair.Raw,
+		LinkToken: linkPair.Raw,
+		Code:      code,
+	}, nil
+}
+
+func (s *authService) RedeemMagicLinkCode(rawToken, code string) (uuid.UUID, error) {
+	strUserId, err := s.redisService.UseMagicLinkCode(s.utils.HashWithSHA256(rawToken), strings.ToLower(code))
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return parseMagicLinkUser(strUserId)
+}
+
+func (s *authService) RedeemMagicLink(linkToken string) (uuid.UUID, error) {
+	strUserId, err := s.redisService.UseMagicLink(s.utils.HashWithSHA256(linkToken))
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return parseMagicLinkUser(strUserId)
+}
+
+// parseMagicLinkUser refuses the requests made for unknown emails.
+func parseMagicLinkUser(strUserId string) (uuid.UUID, error) {
+	userId, err := uuid.Parse(strUserId)
+	if err != nil || userId == uuid.Nil {
+		return uuid.Nil, ErrInvalidMagicLink
+	}
+	return userId, nil
+}
+
+// RevokeSessionTokens removes the redis records of revoked sessions so their
+// refresh token and the access token linked through the jti stop working
+// immediately.
+func (s *authService) RevokeSessionTokens(sessions []models.Token) {
+	for _, session := range sessions {
+		if err := s.redisService.DeleteRefreshToken(session.Hash); err != nil {
+			log.Printf("failed to delete refresh token: %s", err.Error())
+		}
+		if err := s.redisService.DeleteAccessToken(session.Jti.String()); err != nil {
+			log.Printf("failed to delete access token: %s", err.Error())
+		}
+	}
+}
+
+// HandleRefreshTokenReuse reports whether hashedToken belongs to a refresh
+// token that has already been rotated. Presenting such a token means it has
+// leaked, so the whole token family is revoked and a security event emitted.
+func (s *authService) HandleRefreshTokenReuse(ctx context.Context, hashedToken, ipAddress, userAgent string) bool {
+	session, err := s.sessionService.GetSessionByHash(ctx, hashedToken)
+	if err != nil {
+		return false
+	}
+	if !session.IsRevoked || session.RevokeReason == nil || *session.RevokeReason != models.RevokeReasonRotated {
+		return false
+	}
+	revoked, err := s.sessionService.RevokeTokenFamily(ctx, session.FamilyId)
+	if err != nil {
+		log.Printf("failed to revoke token family: %s", err.Error())
+	}
+	s.RevokeSessionTokens(revoked)
+	s.securityEventService.Emit(ctx, SecurityEvent{
+		Type:      SecurityEventRefreshTokenReuse,
+		UserId:    session.UserId.String(),
+		DeviceId:  session.DeviceId.String(),
+		IpAddress: ipAddress,
+		UserAgent: userAgent,
+		Details: map[string]string{
+			"family_id":      session.FamilyId.String(),
+			"revoked_tokens": strconv.Itoa(len(revoked)),
+		},
+	})
+	return true
+}
+
+// ClaimRefreshToken claims a refresh token for rotation. While another request
+// is rotating the same token it waits for that rotation to finish, at most
+// RefreshTokenClaimTTL and no longer than ctx, so parallel refreshes within
+// the grace period end up with the same token pair.
+func (s *authService) ClaimRefreshToken(ctx context.Context, rawToken string, seed *RefreshTokenData) (RefreshTokenClaim, error) {
+	hashedToken := s.utils.HashWithSHA256(rawToken)
+	ctx, cancel := context.WithTimeout(ctx, RefreshTokenClaimTTL)
+	defer cancel()
+	ticker := time.NewTicker(refreshTokenClaimPollInterval)
+	defer ticker.Stop()
+	for {
+		claim, err := s.redisService.ClaimRefreshToken(hashedToken, seed)
+		if err != nil {
+			return RefreshTokenClaim{}, err
+		}
+		switch claim.Status {
+		case RefreshTokenRotated:
+			return openRotatedTokens(rawToken, claim)
+		case RefreshTokenRotationPending:
+			select {
+			case <-ctx.Done():
+				return claim, nil
+			case <-ticker.C:
+			}
+		default:
+			return claim, nil
+		}
+	}
+}
+
+// CompleteRefreshTokenRotation publishes the new token pair to concurrent
+// requests presenting the old token. The pair is sealed with a key derived
+// from the old raw token, which redis never sees, so the tokens cannot be
+// read from redis.
+func (s *authService) CompleteRefreshTokenRotation(rawToken string, result CreateAuthTokensResult) {
+	rotated, err := sealRotatedTokens(rawToken, RotatedRefreshTokenData{
+		RefreshToken: result.RefreshToken,
+		AccessToken:  result.AccessToken,
+	})
+	if err == nil {
+		err = s.redisService.CompleteRefreshTokenRotation(s.utils.HashWithSHA256(rawToken), rotated, s.gracePeriod)
+	}
+	if err != nil {
+		log.Printf("failed to publish rotated refresh token: %s", err.Error())
+	}
+}
+
+func (s *authService) ReleaseRefreshTokenClaim(hashedToken string) {
+	if err := s.redisService.ReleaseRefreshTokenClaim(hashedToken); err != nil {
+		log.Printf("failed to release refresh token claim: %s", err.Error())
+	}
+}
+
 // Helpers
+
+// revokeDeviceSessions ends any session still open on the device so a fresh
+// login replaces it instead of leaving a second valid refresh token behind.
+func (s *authService) revokeDeviceSessions(ctx context.Context, userId, deviceId uuid.UUID) {
+	sessions, err := s.sessionService.RevokeDeviceSessions(ctx, userId, deviceId)
+	if err != nil {
+		log.Printf("failed to revoke device sessions: %s", err.Error())
+		return
+	}
+	s.RevokeSessionTokens(sessions)
+}
+
 func (s *authService) GeneratePairToken() (TokenPair, error) {
 	rawToken, err := s.utils.GenerateRandomBytes(32)
 	if err != nil {
@@ -128,11 +383,78 @@ func (s *authService) GeneratePairToken() (TokenPair, error) {
 	}, nil
 }
 
+// sealRotatedTokens encrypts a rotated token pair for the requests holding
+// rawToken, the refresh token it replaces.
+func sealRotatedTokens(rawToken string, pair RotatedRefreshTokenData) (RotatedRefreshTokenData, error) {
+	gcm, err := rotationGCM(rawToken)
+	if err != nil {
+		return RotatedRefreshTokenData{}, err
+	}
+	seal := func(value string) (string, error) {
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(value), nil)), nil
+	}
+	if pair.RefreshToken, err = seal(pair.RefreshToken); err != nil {
+		return RotatedRefreshTokenData{}, err
+	}
+	if pair.AccessToken, err = seal(pair.AccessToken); err != nil {
+		return RotatedRefreshTokenData{}, err
+	}
+	return pair, nil
+}
+
+// openRotatedTokens decrypts the token pair of a rotated claim.
+func openRotatedTokens(rawToken string, claim RefreshTokenClaim) (RefreshTokenClaim, error) {
+	gcm, err := rotationGCM(rawToken)
+	if err != nil {
+		return RefreshTokenClaim{}, err
+	}
+	open := func(sealed string) (string, error) {
+		data, err := base64.StdEncoding.DecodeString(sealed)
+		if err != nil {
+			return "", err
+		}
+		if len(data) < gcm.NonceSize() {
+			return "", errors.New("malformed rotated refresh token")
+		}
+		plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+		return string(plain), err
+	}
+	if claim.Rotated.RefreshToken, err = open(claim.Rotated.RefreshToken); err != nil {
+		return RefreshTokenClaim{}, err
+	}
+	if claim.Rotated.AccessToken, err = open(claim.Rotated.AccessToken); err != nil {
+		return RefreshTokenClaim{}, err
+	}
+	return claim, nil
+}
+
+func rotationGCM(rawToken string) (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte("refresh-token-rotation:" + rawToken))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+const refreshTokenClaimPollInterval = 50 * time.Millisecond
+
 type CreateAuthTokenParams struct {
 	UserId      uuid.UUID
 	JwtVersion  string
+	DeviceId    uuid.UUID
+	UserAgent   string
+	IpAddress   string
 	OldRefToken *string
 	OldTokenJti *uuid.UUID
+	// ClientId issues the tokens to an OAuth client, limited to Scope. They
+	// carry no permissions and only routes accepting client tokens take them.
+	ClientId string
+	Scope    string
 }
 
 type CreateAuthTokensResult struct {
@@ -145,6 +467,12 @@ type VerificationTokenData struct {
 	Code     string
 }
 
+type MagicLinkTokenData struct {
+	RawToken  string
+	LinkToken string
+	Code      string
+}
+
 type TokenPair struct {
 	Hashed string
 	Raw    string
@@ -0,0 +1,379 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"my-go-api/internal/config"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"strings"
+)
+
+var (
+	ErrInvalidRelationTuple = errors.New("invalid relation tuple")
+	ErrUnknownNamespace     = errors.New("unknown namespace")
+	ErrUnknownRelation      = errors.New("unknown relation")
+	ErrAuthzDepthExceeded   = errors.New("relationship graph is too deep")
+)
+
+// authzMaxDepth bounds how many usersets a check or expand may follow.
+const authzMaxDepth = 16
+
+type IAuthzService interface {
+	Check(ctx context.Context, object, relation, subjectId string) (bool, error)
+	Expand(ctx context.Context, object, relation string) (AuthzTree, error)
+	WriteTuples(ctx context.Context, tuples []string) error
+	DeleteTuples(ctx context.Context, tuples []string) error
+}
+
+type authzService struct {
+	schema       config.AuthzSchema
+	tupleRepo    repositories.IRelationTupleRepository
+	redisService IRedisService
+}
+
+// NewAuthzService creates the relationship based authorization service. It
+// fails when a relation of schema refers to a relation that does not exist.
+func NewAuthzService(schema config.AuthzSchema, tupleRepo repositories.IRelationTupleRepository, redisService IRedisService) (IAuthzService, error) {
+	for namespace, definition := range schema {
+		for name, relation := range definition.Relations {
+			for _, userset := range relation.Union {
+				switch {
+				case userset.This:
+				case userset.ComputedUserset != "":
+					if _, ok := definition.Relations[userset.ComputedUserset]; !ok {
+						return nil, fmt.Errorf("%s#%s: %w %s", namespace, name, ErrUnknownRelation, userset.ComputedUserset)
+					}
+				case userset.TupleToUserset != nil:
+					if _, ok := definition.Relations[userset.TupleToUserset.Tupleset]; !ok {
+						return nil, fmt.Errorf("%s#%s: %w %s", namespace, name, ErrUnknownRelation, userset.TupleToUserset.Tupleset)
+					}
+					if userset.TupleToUserset.ComputedUserset == "" {
+						return nil, fmt.Errorf("%s#%s: tuple_to_userset needs a computed_userset", namespace, name)
+					}
+				default:
+					return nil, fmt.Errorf("%s#%s: empty userset in union", namespace, name)
+				}
+			}
+		}
+	}
+	return &authzService{
+		schema:       schema,
+		tupleRepo:    tupleRepo,
+		redisService: redisService,
+	}, nil
+}
+
+// Check reports whether subjectId has relation to object, e.g. whether a user
+// is a viewer of document:readme. Answers are cached until the next write.
+func (s *authzService) Check(ctx context.Context, object, relation, subjectId string) (bool, error) {
+	namespace, objectId, err := parseAuthzObject(object)
+	if err != nil {
+		return false, err
+	}
+	if err := s.checkRelation(namespace, relation); err != nil {
+		return false, err
+	}
+	if !isAuthzIdentifier(subjectId) {
+		return false, fmt.Errorf("%w: subject %q", ErrInvalidRelationTuple, subjectId)
+	}
+	key := FormatRelationTuple(models.RelationTuple{
+		Namespace: namespace,
+		ObjectId:  objectId,
+		Relation:  relation,
+		SubjectId: subjectId,
+	})
+	revision, err := s.redisService.GetAuthzRevision()
+	if err != nil {
+		log.Printf("failed to load authz revision: %s", err.Error())
+	} else if allowed, err := s.redisService.GetAuthzCheck(revision, key); err == nil {
+		return allowed, nil
+	}
+
+	allowed, err := s.check(ctx, namespace, objectId, relation, subjectId, map[string]bool{}, 0)
+	if err != nil {
+		return false, err
+	}
+	if revision != "" {
+		if err := s.redisService.SaveAuthzCheck(revision, key, allowed); err != nil {
+			log.Printf("failed to cache authz check: %s", err.Error())
+		}
+	}
+	return allowed, nil
+}
+
+// Expand returns the tree of usersets that make up relation on object, with
+// the users found directly at each node.
+func (s *authzService) Expand(ctx context.Context, object, relation string) (AuthzTree, error) {
+	namespace, objectId, err := parseAuthzObject(object)
+	if err != nil {
+		return AuthzTree{}, err
+	}
+	if err := s.checkRelation(namespace, relation); err != nil {
+		return AuthzTree{}, err
+	}
+	return s.expand(ctx, namespace, objectId, relation, map[string]bool{}, 0)
+}
+
+func (s *authzService) WriteTuples(ctx context.Context, tuples []string) error {
+	parsed, err := s.parseTuples(tuples)
+	if err != nil {
+		return err
+	}
+	if err := s.tupleRepo.Write(ctx, parsed); err != nil {
+		return err
+	}
+	return s.redisService.BumpAuthzRevision()
+}
+
+func (s *authzService) DeleteTuples(ctx context.Context, tuples []string) error {
+	parsed, err := s.parseTuples(tuples)
+	if err != nil {
+		return err
+	}
+	if err := s.tupleRepo.Delete(ctx, parsed); err != nil {
+		return err
+	}
+	return s.redisService.BumpAuthzRevision()
+}
+
+// check walks the usersets of relation depth first. path holds the usersets
+// being evaluated, so cycles in the data end a branch instead of looping.
+func (s *authzService) check(ctx context.Context, namespace, objectId, relation, subjectId string, path map[string]bool, depth int) (bool, error) {
+	userset := formatUserset(namespace, objectId, relation)
+	if path[userset] {
+		return false, nil
+	}
+	if depth > authzMaxDepth {
+		return false, ErrAuthzDepthExceeded
+	}
+	definition, ok := s.schema[namespace].Relations[relation]
+	if !ok {
+		return false, nil
+	}
+	path[userset] = true
+	defer delete(path, userset)
+
+	for _, rewrite := range unionOf(definition) {
+		switch {
+		case rewrite.This:
+			tuples, err := s.tupleRepo.GetByObjectRelation(ctx, namespace, objectId, relation)
+			if err != nil {
+				return false, err
+			}
+			for _, tuple := range tuples {
+				if tuple.SubjectId != "" {
+					if tuple.SubjectId == subjectId {
+						return true, nil
+					}
+					continue
+				}
+				if tuple.SubjectRelation == "" {
+					continue
+				}
+				if ok, err := s.check(ctx, tuple.SubjectNamespace, tuple.SubjectObjectId, tuple.SubjectRelation, subjectId, path, depth+1); err != nil || ok {
+					return ok, err
+				}
+			}
+		case rewrite.ComputedUserset != "":
+			if ok, err := s.check(ctx, namespace, objectId, rewrite.ComputedUserset, subjectId, path, depth+1); err != nil || ok {
+				return ok, err
+			}
+		case rewrite.TupleToUserset != nil:
+			tuples, err := s.tupleRepo.GetByObjectRelation(ctx, namespace, objectId, rewrite.TupleToUserset.Tupleset)
+			if err != nil {
+				return false, err
+			}
+			for _, tuple := range tuples {
+				if tuple.SubjectObjectId == "" {
+					continue
+				}
+				if ok, err := s.check(ctx, tuple.SubjectNamespace, tuple.SubjectObjectId, rewrite.TupleToUserset.ComputedUserset, subjectId, path, depth+1); err != nil || ok {
+					return ok, err
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+func (s *authzService) expand(ctx context.Context, namespace, objectId, relation string, path map[string]bool, depth int) (AuthzTree, error) {
+	userset := formatUserset(namespace, objectId, relation)
+	node := AuthzTree{Userset: userset}
+	if path[userset] {
+		return node, nil
+	}
+	if depth > authzMaxDepth {
+		return AuthzTree{}, ErrAuthzDepthExceeded
+	}
+	definition, ok := s.schema[namespace].Relations[relation]
+	if !ok {
+		return node, nil
+	}
+	path[userset] = true
+	defer delete(path, userset)
+
+	for _, rewrite := range unionOf(definition) {
+		switch {
+		case rewrite.This:
+			tuples, err := s.tupleRepo.GetByObjectRelation(ctx, namespace, objectId, relation)
+			if err != nil {
+				return AuthzTree{}, err
+			}
+			for _, tuple := range tuples {
+				if tuple.SubjectId != "" {
+					node.Subjects = append(node.Subjects, tuple.SubjectId)
+					continue
+				}
+				if tuple.SubjectRelation == "" {
+					continue
+				}
+				child, err := s.expand(ctx, tuple.SubjectNamespace, tuple.SubjectObjectId, tuple.SubjectRelation, path, depth+1)
+				if err != nil {
+					return AuthzTree{}, err
+				}
+				node.Children = append(node.Children, child)
+			}
+		case rewrite.ComputedUserset != "":
+			child, err := s.expand(ctx, namespace, objectId, rewrite.ComputedUserset, path, depth+1)
+			if err != nil {
+				return AuthzTree{}, err
+			}
+			node.Children = append(node.Children, child)
+		case rewrite.TupleToUserset != nil:
+			tuples, err := s.tupleRepo.GetByObjectRelation(ctx, namespace, objectId, rewrite.TupleToUserset.Tupleset)
+			if err != nil {
+				return AuthzTree{}, err
+			}
+			for _, tuple := range tuples {
+				if tuple.SubjectObjectId == "" {
+					continue
+				}
+				child, err := s.expand(ctx, tuple.SubjectNamespace, tuple.SubjectObjectId, rewrite.TupleToUserset.ComputedUserset, path, depth+1)
+				if err != nil {
+					return AuthzTree{}, err
+				}
+				node.Children = append(node.Children, child)
+			}
+		}
+	}
+	return node, nil
+}
+
+func (s *authzService) parseTuples(tuples []string) ([]models.RelationTuple, error) {
+	parsed := make([]models.RelationTuple, 0, len(tuples))
+	for _, raw := range tuples {
+		tuple, err := ParseRelationTuple(raw)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkRelation(tuple.Namespace, tuple.Relation); err != nil {
+			return nil, fmt.Errorf("%s: %w", raw, err)
+		}
+		if tuple.SubjectNamespace != "" {
+			if _, ok := s.schema[tuple.SubjectNamespace]; !ok {
+				return nil, fmt.Errorf("%s: %w %s", raw, ErrUnknownNamespace, tuple.SubjectNamespace)
+			}
+		}
+		if tuple.SubjectRelation != "" {
+			if err := s.checkRelation(tuple.SubjectNamespace, tuple.SubjectRelation); err != nil {
+				return nil, fmt.Errorf("%s: %w", raw, err)
+			}
+		}
+		parsed = append(parsed, tuple)
+	}
+	return parsed, nil
+}
+
+func (s *authzService) checkRelation(namespace, relation string) error {
+	definition, ok := s.schema[namespace]
+	if !ok {
+		return fmt.Errorf("%w %s", ErrUnknownNamespace, namespace)
+	}
+	if _, ok := definition.Relations[relation]; !ok {
+		return fmt.Errorf("%w %s#%s", ErrUnknownRelation, namespace, relation)
+	}
+	return nil
+}
+
+// ParseRelationTuple reads a tuple written as namespace:object_id#relation@subject,
+// where subject is a user id, an object such as folder:docs, or a userset such
+// as group:eng#member.
+func ParseRelationTuple(raw string) (models.RelationTuple, error) {
+	object, subject, ok := strings.Cut(raw, "@")
+	if !ok {
+		return models.RelationTuple{}, fmt.Errorf("%w: %q", ErrInvalidRelationTuple, raw)
+	}
+	object, relation, ok := strings.Cut(object, "#")
+	if !ok || !isAuthzIdentifier(relation) {
+		return models.RelationTuple{}, fmt.Errorf("%w: %q", ErrInvalidRelationTuple, raw)
+	}
+	namespace, objectId, err := parseAuthzObject(object)
+	if err != nil {
+		return models.RelationTuple{}, fmt.Errorf("%w: %q", ErrInvalidRelationTuple, raw)
+	}
+	tuple := models.RelationTuple{Namespace: namespace, ObjectId: objectId, Relation: relation}
+	if !strings.Contains(subject, ":") {
+		if !isAuthzIdentifier(subject) {
+			return models.RelationTuple{}, fmt.Errorf("%w: %q", ErrInvalidRelationTuple, raw)
+		}
+		tuple.SubjectId = subject
+		return tuple, nil
+	}
+	subjectObject, subjectRelation, hasRelation := strings.Cut(subject, "#")
+	if hasRelation && !isAuthzIdentifier(subjectRelation) {
+		return models.RelationTuple{}, fmt.Errorf("%w: %q", ErrInvalidRelationTuple, raw)
+	}
+	tuple.SubjectNamespace, tuple.SubjectObjectId, err = parseAuthzObject(subjectObject)
+	if err != nil {
+		return models.RelationTuple{}, fmt.Errorf("%w: %q", ErrInvalidRelationTuple, raw)
+	}
+	tuple.SubjectRelation = subjectRelation
+	return tuple, nil
+}
+
+func FormatRelationTuple(tuple models.RelationTuple) string {
+	subject := tuple.SubjectId
+	if subject == "" {
+		subject = tuple.SubjectNamespace + ":" + tuple.SubjectObjectId
+		if tuple.SubjectRelation != "" {
+			subject += "#" + tuple.SubjectRelation
+		}
+	}
+	return formatUserset(tuple.Namespace, tuple.ObjectId, tuple.Relation) + "@" + subject
+}
+
+func formatUserset(namespace, objectId, relation string) string {
+	return namespace + ":" + objectId + "#" + relation
+}
+
+func parseAuthzObject(object string) (string, string, error) {
+	namespace, objectId, ok := strings.Cut(object, ":")
+	if !ok || !isAuthzIdentifier(namespace) || !isAuthzIdentifier(objectId) {
+		return "", "", fmt.Errorf("%w: object %q", ErrInvalidRelationTuple, object)
+	}
+	return namespace, objectId, nil
+}
+
+func isAuthzIdentifier(value string) bool {
+	return value != "" && !strings.ContainsAny(value, ":#@ ")
+}
+
+// unionOf returns the usersets of a relation; a relation without any only
+// holds the subjects written for it directly.
+func unionOf(relation config.AuthzRelation) []config.AuthzUserset {
+	if len(relation.Union) == 0 {
+		return []config.AuthzUserset{{This: true}}
+	}
+	return relation.Union
+}
+
+// AuthzTree is one userset of an expanded relation: the users written for it
+// directly and the usersets it includes.
+type AuthzTree struct {
+	Userset  string      `json:"userset"`
+	Subjects []string    `json:"subjects,omitempty"`
+	Children []AuthzTree `json:"children,omitempty"`
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// fileEmailSender writes every message to a maildir for development, where
+// any mail client can open it. Messages are written to tmp and moved to new
+// once complete, so a reader never sees half a message.
+type fileEmailSender struct {
+	dir  string
+	from string
+}
+
+func NewFileEmailSender(dir, from string) (IEmailSender, error) {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o700); err != nil {
+			return nil, fmt.Errorf("could not create maildir %s: %w", dir, err)
+		}
+	}
+	if from == "" {
+		from = "no-reply@localhost"
+	}
+	return &fileEmailSender{dir: dir, from: from}, nil
+}
+
+func (s *fileEmailSender) Send(ctx context.Context, message EmailMessage) error {
+	raw, err := composeEmail(s.from, message)
+	if err != nil {
+		return err
+	}
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return err
+	}
+	hostname, _ := os.Hostname()
+	name := fmt.Sprintf("%d.%s.%s", time.Now().UnixNano(), hex.EncodeToString(random), hostname)
+
+	tmpPath := filepath.Join(s.dir, "tmp", name)
+	if err := os.WriteFile(tmpPath, raw, 0o600); err != nil {
+		return fmt.Errorf("could not write email: %w", err)
+	}
+	if err := os.Rename(tmpPath, filepath.Join(s.dir, "new", name)); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("could not write email: %w", err)
+	}
+	return nil
+}
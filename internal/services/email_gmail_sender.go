@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"my-go-api/internal/config"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+// gmailEmailSender sends through the Gmail API as the account the refresh
+// token belongs to. Gmail sets the From header itself when from is empty.
+type gmailEmailSender struct {
+	tokenSource oauth2.TokenSource
+	from        string
+}
+
+func NewGmailEmailSender(cfg config.GoogleOAuth2Config, from string) IEmailSender {
+	oauthConfig := &oauth2.Config{
+		ClientID:     cfg.ClientId,
+		ClientSecret: cfg.ClientSecret,
+		Endpoint:     google.Endpoint,
+		Scopes:       []string{gmail.GmailSendScope},
+	}
+	return &gmailEmailSender{
+		// refreshes the access token only when it has expired
+		tokenSource: oauthConfig.TokenSource(context.Background(), &oauth2.Token{RefreshToken: cfg.RefreshToken}),
+		from:        from,
+	}
+}
+
+func (s *gmailEmailSender) Send(ctx context.Context, message EmailMessage) error {
+	raw, err := composeEmail(s.from, message)
+	if err != nil {
+		return err
+	}
+	service, err := gmail.NewService(ctx, option.WithTokenSource(s.tokenSource))
+	if err != nil {
+		return fmt.Errorf("could not create gmail client: %w", err)
+	}
+	if _, err := service.Users.Messages.Send("me", &gmail.Message{
+		Raw: base64.URLEncoding.EncodeToString(raw),
+	}).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("gmail send failed: %w", err)
+	}
+	return nil
+}
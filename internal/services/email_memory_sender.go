@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// MemoryEmailSender keeps sent messages in memory so tests can read them
+// back. Messages are still composed, so invalid ones fail like they would on
+// a real transport.
+type MemoryEmailSender struct {
+	mu       sync.Mutex
+	messages []EmailMessage
+}
+
+func NewMemoryEmailSender() *MemoryEmailSender {
+	return &MemoryEmailSender{}
+}
+
+func (s *MemoryEmailSender) Send(ctx context.Context, message EmailMessage) error {
+	if _, err := composeEmail("", message); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+// Messages returns the messages sent so far, oldest first.
+func (s *MemoryEmailSender) Messages() []EmailMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.messages)
+}
+
+// Reset forgets every message sent so far.
+func (s *MemoryEmailSender) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = nil
+}
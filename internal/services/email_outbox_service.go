@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"my-go-api/internal/config"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"time"
+)
+
+var (
+	ErrOutboxEmailNotFound = errors.New("email not found")
+	ErrOutboxEmailNotDead  = errors.New("only dead emails can be retried")
+)
+
+// IEmailOutboxService delivers the emails queued by IEmailService. Emails are
+// delivered at least once: a worker stopping between sending and recording
+// the result leaves the email to be sent again after its lease.
+type IEmailOutboxService interface {
+	// Start runs the workers until ctx is done.
+	Start(ctx context.Context)
+	// DeliverPending delivers one batch of due emails and returns how many it
+	// tried.
+	DeliverPending(ctx context.Context) (int, error)
+	List(ctx context.Context, params repositories.ListOutboxEmailsParams) ([]models.OutboxEmail, error)
+	Retry(ctx context.Context, id int64) (*models.OutboxEmail, error)
+}
+
+type emailOutboxService struct {
+	config       config.EmailOutboxConfig
+	outboxRepo   repositories.IEmailOutboxRepository
+	emailService IEmailService
+}
+
+func NewEmailOutboxService(
+	config config.EmailOutboxConfig,
+	outboxRepo repositories.IEmailOutboxRepository,
+	emailService IEmailService,
+) IEmailOutboxService {
+	return &emailOutboxService{
+		config:       config,
+		outboxRepo:   outboxRepo,
+		emailService: emailService,
+	}
+}
+
+func (s *emailOutboxService) Start(ctx context.Context) {
+	for range s.config.Workers {
+		go s.work(ctx)
+	}
+}
+
+// work delivers batches back to back while the outbox has due emails and
+// polls it otherwise.
+func (s *emailOutboxService) work(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		count, err := s.DeliverPending(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("email outbox: %v", err)
+		}
+		wait := s.config.PollInterval
+		if err == nil && count == s.config.BatchSize {
+			wait = 0
+		}
+		timer.Reset(wait)
+	}
+}
+
+func (s *emailOutboxService) DeliverPending(ctx context.Context) (int, error) {
+	emails, err := s.outboxRepo.Claim(ctx, s.config.BatchSize, s.config.Lease)
+	if err != nil {
+		return 0, err
+	}
+	for _, email := range emails {
+		s.deliver(ctx, email)
+	}
+	return len(emails), nil
+}
+
+func (s *emailOutboxService) deliver(ctx context.Context, email models.OutboxEmail) {
+	err := s.emailService.Deliver(ctx, email)
+	// the result is recorded even when ctx was canceled during the delivery
+	ctx = context.WithoutCancel(ctx)
+	if err == nil {
+		if err := s.outboxRepo.MarkSent(ctx, email.ID); err != nil {
+			log.Printf("email outbox: could not mark email %d as sent: %v", email.ID, err)
+		}
+		return
+	}
+
+	var retryAt *time.Time
+	if !errors.Is(err, ErrInvalidEmailMessage) && !errors.Is(err, ErrUnknownEmailTemplate) && email.Attempts < s.config.MaxAttempts {
+		at := time.Now().Add(s.backoff(email.Attempts))
+		retryAt = &at
+	}
+	if retryAt == nil {
+		log.Printf("email outbox: email %d to %s is dead after %d attempts: %v", email.ID, email.Recipient, email.Attempts, err)
+	}
+	if err := s.outboxRepo.MarkFailed(ctx, email.ID, err.Error(), retryAt); err != nil {
+		log.Printf("email outbox: could not record the failure of email %d: %v", email.ID, err)
+	}
+}
+
+// backoff returns how long to wait after the given number of failed attempts.
+func (s *emailOutboxService) backoff(attempts int) time.Duration {
+	wait := s.config.BackoffBase
+	for i := 1; i < attempts && wait < s.config.BackoffMax; i++ {
+		wait *= 2
+	}
+	return min(wait, s.config.BackoffMax)
+}
+
+func (s *emailOutboxService) List(ctx context.Context, params repositories.ListOutboxEmailsParams) ([]models.OutboxEmail, error) {
+	return s.outboxRepo.List(ctx, params)
+}
+
+func (s *emailOutboxService) Retry(ctx context.Context, id int64) (*models.OutboxEmail, error) {
+	email, err := s.outboxRepo.Retry(ctx, id)
+	if err == nil {
+		return email, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if _, err := s.outboxRepo.GetOne(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOutboxEmailNotFound
+		}
+		return nil, err
+	}
+	return nil, ErrOutboxEmailNotDead
+}
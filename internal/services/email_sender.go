@@ -0,0 +1,145 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"my-go-api/internal/config"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidEmailMessage = errors.New("invalid email message")
+)
+
+// EmailMessage is an email to a single recipient. It is sent as plain text,
+// or as multipart/alternative when HTML is set.
+type EmailMessage struct {
+	To      string
+	ToName  string
+	Subject string
+	Body    string
+	HTML    string
+}
+
+// IEmailSender delivers composed emails. Every transport returns its errors
+// instead of exiting, so a mail outage fails a request, not the server.
+type IEmailSender interface {
+	Send(ctx context.Context, message EmailMessage) error
+}
+
+// NewEmailSender builds the transport selected by the configuration.
+func NewEmailSender(cfg config.EmailConfig, google config.GoogleOAuth2Config) (IEmailSender, error) {
+	switch cfg.Transport {
+	case config.EmailTransportGmail:
+		return NewGmailEmailSender(google, cfg.From), nil
+	case config.EmailTransportSMTP:
+		return NewSmtpEmailSender(cfg.Smtp, cfg.From), nil
+	case config.EmailTransportFile:
+		return NewFileEmailSender(cfg.FileDir, cfg.From)
+	case config.EmailTransportMemory:
+		return NewMemoryEmailSender(), nil
+	default:
+		return nil, fmt.Errorf("unknown email transport %q", cfg.Transport)
+	}
+}
+
+// composeEmail renders message as an RFC 5322 email with quoted-printable
+// UTF-8 parts and encoded non-ASCII headers. The From header is left out when
+// from is empty, for transports that fill it in themselves.
+func composeEmail(from string, message EmailMessage) ([]byte, error) {
+	if _, err := mail.ParseAddress(message.To); err != nil {
+		return nil, fmt.Errorf("%w: bad recipient: %s", ErrInvalidEmailMessage, err.Error())
+	}
+	// header values must not be able to start a header of their own
+	if strings.ContainsAny(message.To+message.ToName+message.Subject+from, "\r\n") {
+		return nil, fmt.Errorf("%w: line break in a header", ErrInvalidEmailMessage)
+	}
+
+	var buf bytes.Buffer
+	header := func(name, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+	}
+	if from != "" {
+		header("From", from)
+	}
+	to := message.To
+	if message.ToName != "" {
+		to = (&mail.Address{Name: message.ToName, Address: message.To}).String()
+	}
+	header("To", to)
+	header("Subject", mime.QEncoding.Encode("utf-8", message.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", emailMessageId(from))
+	header("MIME-Version", "1.0")
+
+	if message.HTML == "" {
+		if err := writeEmailPart(&buf, "text/plain", message.Body); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(&buf)
+	header("Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": parts.Boundary()}))
+	buf.WriteString("\r\n")
+	// the last part is the preferred one
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain", message.Body},
+		{"text/html", message.HTML},
+	} {
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + "; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(writer, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeEmailPart finishes the headers of a single part message and writes
+// its body.
+func writeEmailPart(buf *bytes.Buffer, contentType, content string) error {
+	fmt.Fprintf(buf, "Content-Type: %s; charset=utf-8\r\n", contentType)
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	return writeQuotedPrintable(buf, content)
+}
+
+func writeQuotedPrintable(w io.Writer, content string) error {
+	body := quotedprintable.NewWriter(w)
+	text := strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\n", "\r\n")
+	if _, err := body.Write([]byte(text)); err != nil {
+		return err
+	}
+	return body.Close()
+}
+
+func emailMessageId(from string) string {
+	domain := "localhost"
+	if address, err := mail.ParseAddress(from); err == nil {
+		if _, host, ok := strings.Cut(address.Address, "@"); ok {
+			domain = host
+		}
+	}
+	random := make([]byte, 12)
+	rand.Read(random)
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(random), domain)
+}
@@ -1,71 +1,194 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"my-go-api/internal/utils"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"time"
+)
+
+// emailSendTimeout bounds the time a delivery waits for the mail transport.
+const emailSendTimeout = 30 * time.Second
+
+const (
+	EmailTemplateVerifyAccount = "verify_account"
+	EmailTemplatePasswordReset = "password_reset"
+	EmailTemplateMagicLink     = "magic_link"
+	EmailTemplateAccountLocked = "account_locked"
 )
 
 type SendEmailVerificationParams struct {
-	Name  string
-	Email string
-	Code  string
+	Name   string
+	Email  string
+	Code   string
+	Locale string
 }
 type SendPasswordResetParams struct {
-	Name  string
-	Email string
-	Token string
+	Name   string
+	Email  string
+	Token  string
+	Locale string
+}
+
+type SendMagicLinkParams struct {
+	Name   string
+	Email  string
+	Code   string
+	Token  string
+	Locale string
 }
 
+type SendAccountLockedParams struct {
+	Name        string
+	Email       string
+	LockedUntil time.Time
+	Locale      string
+}
+
+// IEmailService queues emails in the outbox and renders and sends them when
+// the outbox delivers them, see IEmailOutboxService. The Locale of every email
+// is a language tag or an Accept-Language value; the default locale is used
+// when it is empty or has no templates.
 type IEmailService interface {
 	SendVerificationEmail(params SendEmailVerificationParams) error
 	SendPasswordResetRequest(params SendPasswordResetParams) error
+	SendMagicLinkEmail(params SendMagicLinkParams) error
+	SendAccountLockedEmail(params SendAccountLockedParams) error
+	// VerificationEmail builds the verification email for callers queueing it
+	// in their own transaction, see repositories.CreateOneParams.
+	VerificationEmail(params SendEmailVerificationParams) (repositories.EnqueueEmailParams, error)
+	// Deliver renders a queued email and hands it to the mail transport.
+	Deliver(ctx context.Context, email models.OutboxEmail) error
+	// Preview renders a template with sample data instead of sending it.
+	Preview(template, locale string) (RenderedEmail, error)
+	Templates() (names []string, locales []string)
 }
 
 type emailService struct {
-	appUri  string
-	utility utils.IUtils
+	appUri     string
+	sender     IEmailSender
+	templates  IEmailTemplates
+	outboxRepo repositories.IEmailOutboxRepository
 }
 
-func NewEmailService(appUri string, utility utils.IUtils) IEmailService {
+func NewEmailService(
+	appUri string,
+	sender IEmailSender,
+	templates IEmailTemplates,
+	outboxRepo repositories.IEmailOutboxRepository,
+) IEmailService {
 	return &emailService{
-		appUri:  appUri,
-		utility: utility,
+		appUri:     appUri,
+		sender:     sender,
+		templates:  templates,
+		outboxRepo: outboxRepo,
 	}
 }
 
-func (s *emailService) SendVerificationEmail(params SendEmailVerificationParams) error {
-	var subject = "Email verification"
+func outboxEmail(template, locale, name, address string, data map[string]any) (repositories.EnqueueEmailParams, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return repositories.EnqueueEmailParams{}, err
+	}
+	return repositories.EnqueueEmailParams{
+		Template:      template,
+		Recipient:     address,
+		RecipientName: name,
+		Locale:        locale,
+		Data:          encoded,
+	}, nil
+}
 
-	var emailBody = fmt.Sprintf(`
-	Hello %s.
-	This is your verification code: %s`,
-		params.Name, params.Code)
+func (s *emailService) enqueue(template, locale, name, address string, data map[string]any) error {
+	params, err := outboxEmail(template, locale, name, address, data)
+	if err != nil {
+		return err
+	}
+	_, err = s.outboxRepo.Enqueue(context.Background(), params)
+	return err
+}
 
-	err := s.utility.SendEmailWithGmail(subject, emailBody, params.Email)
+func (s *emailService) Deliver(ctx context.Context, email models.OutboxEmail) error {
+	var data map[string]any
+	if err := json.Unmarshal(email.Data, &data); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEmailMessage, err)
+	}
+	rendered, err := s.templates.Render(email.Template, email.Locale, data)
 	if err != nil {
 		return err
 	}
+	ctx, cancel := context.WithTimeout(ctx, emailSendTimeout)
+	defer cancel()
+	return s.sender.Send(ctx, EmailMessage{
+		To:      email.Recipient,
+		ToName:  email.RecipientName,
+		Subject: rendered.Subject,
+		Body:    rendered.Text,
+		HTML:    rendered.HTML,
+	})
+}
 
-	return nil
+func (s *emailService) VerificationEmail(params SendEmailVerificationParams) (repositories.EnqueueEmailParams, error) {
+	return outboxEmail(EmailTemplateVerifyAccount, params.Locale, params.Name, params.Email, s.verificationData(params))
+}
+
+func (s *emailService) SendVerificationEmail(params SendEmailVerificationParams) error {
+	return s.enqueue(EmailTemplateVerifyAccount, params.Locale, params.Name, params.Email, s.verificationData(params))
 }
 
 func (s *emailService) SendPasswordResetRequest(params SendPasswordResetParams) error {
-	var subject = "Reset Password"
-	link := fmt.Sprintf("%s/reset-password/%s", s.appUri, params.Token)
-
-	var emailBody = fmt.Sprintf(`
-	Hello %s.
-	You receive this email because you sent a request to update your password.
-	You can ignore this email if you didn't.
-	Please follow this link to update your password
-	%s
-	`,
-		params.Name, link)
-
-	err := s.utility.SendEmailWithGmail(subject, emailBody, params.Email)
-	if err != nil {
-		return err
+	return s.enqueue(EmailTemplatePasswordReset, params.Locale, params.Name, params.Email, s.passwordResetData(params))
+}
+
+func (s *emailService) SendMagicLinkEmail(params SendMagicLinkParams) error {
+	return s.enqueue(EmailTemplateMagicLink, params.Locale, params.Name, params.Email, s.magicLinkData(params))
+}
+
+func (s *emailService) SendAccountLockedEmail(params SendAccountLockedParams) error {
+	return s.enqueue(EmailTemplateAccountLocked, params.Locale, params.Name, params.Email, s.accountLockedData(params))
+}
+
+func (s *emailService) verificationData(params SendEmailVerificationParams) map[string]any {
+	return map[string]any{"Name": params.Name, "Code": params.Code}
+}
+
+func (s *emailService) passwordResetData(params SendPasswordResetParams) map[string]any {
+	return map[string]any{"Name": params.Name, "Link": fmt.Sprintf("%s/reset-password/%s", s.appUri, params.Token)}
+}
+
+func (s *emailService) magicLinkData(params SendMagicLinkParams) map[string]any {
+	return map[string]any{
+		"Name": params.Name,
+		"Code": params.Code,
+		"Link": fmt.Sprintf("%s/magic-link/%s", s.appUri, params.Token),
+	}
+}
+
+func (s *emailService) accountLockedData(params SendAccountLockedParams) map[string]any {
+	return map[string]any{
+		"Name":        params.Name,
+		"LockedUntil": params.LockedUntil.UTC().Format(time.RFC1123),
+		"Link":        fmt.Sprintf("%s/forgot-password", s.appUri),
 	}
+}
+
+func (s *emailService) Preview(template, locale string) (RenderedEmail, error) {
+	var data map[string]any
+	switch template {
+	case EmailTemplateVerifyAccount:
+		data = s.verificationData(SendEmailVerificationParams{Name: "Jane Doe", Code: "123456"})
+	case EmailTemplatePasswordReset:
+		data = s.passwordResetData(SendPasswordResetParams{Name: "Jane Doe", Token: "preview-token"})
+	case EmailTemplateMagicLink:
+		data = s.magicLinkData(SendMagicLinkParams{Name: "Jane Doe", Code: "123456", Token: "preview-token"})
+	case EmailTemplateAccountLocked:
+		data = s.accountLockedData(SendAccountLockedParams{Name: "Jane Doe", LockedUntil: time.Now().Add(15 * time.Minute)})
+	}
+	return s.templates.Render(template, locale, data)
+}
 
-	return nil
+func (s *emailService) Templates() ([]string, []string) {
+	return s.templates.Names(), s.templates.Locales()
 }
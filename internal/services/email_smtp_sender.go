@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"my-go-api/internal/config"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+const smtpTimeout = 30 * time.Second
+
+// smtpEmailSender delivers through a mail server, opening one connection per
+// message.
+type smtpEmailSender struct {
+	config config.SmtpConfig
+	from   string
+}
+
+func NewSmtpEmailSender(cfg config.SmtpConfig, from string) IEmailSender {
+	return &smtpEmailSender{config: cfg, from: from}
+}
+
+func (s *smtpEmailSender) Send(ctx context.Context, message EmailMessage) error {
+	raw, err := composeEmail(s.from, message)
+	if err != nil {
+		return err
+	}
+	sender, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return fmt.Errorf("%w: bad sender: %s", ErrInvalidEmailMessage, err.Error())
+	}
+	recipient, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("%w: bad recipient: %s", ErrInvalidEmailMessage, err.Error())
+	}
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("smtp connect failed: %w", err)
+	}
+	defer client.Close()
+
+	if s.config.TLS == config.SmtpTLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("smtp server %s does not offer STARTTLS", s.config.Host)
+		}
+		if err := client.StartTLS(&tls.Config{ServerName: s.config.Host}); err != nil {
+			return fmt.Errorf("smtp starttls failed: %w", err)
+		}
+	}
+	if s.config.Username != "" {
+		// PlainAuth refuses to send the password over a connection that is
+		// neither encrypted nor to localhost
+		auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+	if err := client.Mail(sender.Address); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	if err := client.Rcpt(recipient.Address); err != nil {
+		return fmt.Errorf("smtp RCPT TO failed: %w", err)
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := writer.Write(raw); err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	return client.Quit()
+}
+
+// dial connects to the server, with TLS from the start for implicit TLS. The
+// whole conversation must finish within smtpTimeout or the context deadline.
+func (s *smtpEmailSender) dial(ctx context.Context) (*smtp.Client, error) {
+	address := net.JoinHostPort(s.config.Host, s.config.Port)
+	deadline := time.Now().Add(smtpTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	dialer := &net.Dialer{Deadline: deadline}
+	var conn net.Conn
+	var err error
+	if s.config.TLS == config.SmtpTLSImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: s.config.Host}}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
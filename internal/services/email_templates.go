@@ -0,0 +1,213 @@
+package services
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+
+	"golang.org/x/text/language"
+)
+
+//go:embed templates/email
+var embeddedEmailTemplates embed.FS
+
+// DefaultEmailLocale is used when none of the preferred languages has
+// templates, and for templates missing from a locale.
+const DefaultEmailLocale = "en"
+
+var (
+	ErrUnknownEmailTemplate = errors.New("unknown email template")
+)
+
+// RenderedEmail is a template rendered in Locale.
+type RenderedEmail struct {
+	Locale  string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// IEmailTemplates renders the email templates. Each locale is a directory
+// holding <name>.subject.txt, <name>.txt and <name>.html for every template;
+// the HTML part defines "content", which layout.html wraps.
+type IEmailTemplates interface {
+	// Render renders the template in the best match for locale, which is a
+	// language tag or an Accept-Language header value.
+	Render(name, locale string, data map[string]any) (RenderedEmail, error)
+	Names() []string
+	Locales() []string
+}
+
+type emailTemplates struct {
+	// sources are searched in order, the override directory first
+	sources []fs.FS
+	names   []string
+	locales []string
+	matcher language.Matcher
+}
+
+// NewEmailTemplates loads the embedded templates. Files in dir, laid out the
+// same way, take precedence and can add locales; they are read on every render
+// so edits show up without a restart.
+func NewEmailTemplates(dir string) (IEmailTemplates, error) {
+	embedded, err := fs.Sub(embeddedEmailTemplates, "templates/email")
+	if err != nil {
+		return nil, err
+	}
+	t := &emailTemplates{sources: []fs.FS{embedded}}
+	if dir != "" {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("email template directory: %w", err)
+		}
+		t.sources = []fs.FS{os.DirFS(dir), embedded}
+	}
+
+	for _, source := range t.sources {
+		entries, err := fs.ReadDir(source, ".")
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && !slices.Contains(t.locales, entry.Name()) {
+				if _, err := language.Parse(entry.Name()); err != nil {
+					return nil, fmt.Errorf("email template directory %q is not a language tag", entry.Name())
+				}
+				t.locales = append(t.locales, entry.Name())
+			}
+		}
+		files, err := fs.Glob(source, DefaultEmailLocale+"/*.subject.txt")
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			name := strings.TrimSuffix(strings.TrimPrefix(file, DefaultEmailLocale+"/"), ".subject.txt")
+			if !slices.Contains(t.names, name) {
+				t.names = append(t.names, name)
+			}
+		}
+	}
+	slices.Sort(t.names)
+	slices.Sort(t.locales)
+	// the matcher falls back to the first tag
+	t.locales = slices.DeleteFunc(t.locales, func(locale string) bool { return locale == DefaultEmailLocale })
+	t.locales = append([]string{DefaultEmailLocale}, t.locales...)
+	tags := []language.Tag{}
+	for _, locale := range t.locales {
+		tags = append(tags, language.Make(locale))
+	}
+	t.matcher = language.NewMatcher(tags)
+	return t, nil
+}
+
+func (t *emailTemplates) Names() []string {
+	return slices.Clone(t.names)
+}
+
+func (t *emailTemplates) Locales() []string {
+	return slices.Clone(t.locales)
+}
+
+func (t *emailTemplates) Render(name, locale string, data map[string]any) (RenderedEmail, error) {
+	if !slices.Contains(t.names, name) {
+		return RenderedEmail{}, fmt.Errorf("%w: %s", ErrUnknownEmailTemplate, name)
+	}
+	locale = t.match(locale)
+	if _, err := t.read(locale + "/" + name + ".subject.txt"); err != nil {
+		locale = DefaultEmailLocale
+	}
+	values := map[string]any{}
+	for key, value := range data {
+		values[key] = value
+	}
+	values["Locale"] = locale
+
+	subject, err := t.renderText(locale+"/"+name+".subject.txt", values)
+	if err != nil {
+		return RenderedEmail{}, err
+	}
+	// a subject is a single line whatever the file looks like
+	subject = strings.Join(strings.Fields(subject), " ")
+	values["Subject"] = subject
+
+	text, err := t.renderText(locale+"/"+name+".txt", values)
+	if err != nil {
+		return RenderedEmail{}, err
+	}
+	html, err := t.renderHTML(locale+"/"+name+".html", values)
+	if err != nil {
+		return RenderedEmail{}, err
+	}
+	return RenderedEmail{Locale: locale, Subject: subject, Text: strings.TrimSpace(text) + "\n", HTML: html}, nil
+}
+
+// match picks the locale with templates closest to the preferred languages.
+func (t *emailTemplates) match(preferred string) string {
+	tags, _, err := language.ParseAcceptLanguage(preferred)
+	if err != nil || len(tags) == 0 {
+		return DefaultEmailLocale
+	}
+	_, index, confidence := t.matcher.Match(tags...)
+	if confidence == language.No {
+		return DefaultEmailLocale
+	}
+	return t.locales[index]
+}
+
+func (t *emailTemplates) read(path string) (string, error) {
+	for _, source := range t.sources {
+		content, err := fs.ReadFile(source, path)
+		if err == nil {
+			return string(content), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("email template %s: %w", path, fs.ErrNotExist)
+}
+
+func (t *emailTemplates) renderText(path string, values map[string]any) (string, error) {
+	content, err := t.read(path)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := texttemplate.New(path).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, values); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (t *emailTemplates) renderHTML(path string, values map[string]any) (string, error) {
+	layout, err := t.read("layout.html")
+	if err != nil {
+		return "", err
+	}
+	content, err := t.read(path)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := htmltemplate.New("layout.html").Option("missingkey=error").Parse(layout)
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmpl.New(path).Parse(content); err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "layout.html", values); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
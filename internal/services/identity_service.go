@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type IIdentityService interface {
+	LinkIdentity(ctx context.Context, params repositories.CreateIdentityParams) (*models.UserIdentity, error)
+	LinkCredentials(ctx context.Context, user *models.User) (*models.UserIdentity, error)
+	GetIdentity(ctx context.Context, provider, subject string) (*models.UserIdentity, error)
+	GetUserIdentities(ctx context.Context, userId uuid.UUID) ([]models.UserIdentity, error)
+	UnlinkIdentity(ctx context.Context, userId uuid.UUID, id int) (*models.UserIdentity, error)
+	RemoveCredentials(ctx context.Context, userId uuid.UUID) error
+}
+
+type identityService struct {
+	identityRepo repositories.IIdentityRepository
+}
+
+func NewIdentityService(identityRepo repositories.IIdentityRepository) IIdentityService {
+	return &identityService{identityRepo: identityRepo}
+}
+
+func (s *identityService) LinkIdentity(ctx context.Context, params repositories.CreateIdentityParams) (*models.UserIdentity, error) {
+	return s.identityRepo.CreateOne(ctx, params)
+}
+
+func (s *identityService) LinkCredentials(ctx context.Context, user *models.User) (*models.UserIdentity, error) {
+	return s.identityRepo.CreateOne(ctx, repositories.CreateIdentityParams{
+		UserId:   user.ID,
+		Provider: models.IdentityProviderCredentials,
+		Subject:  user.ID.String(),
+		Email:    user.Email,
+	})
+}
+
+func (s *identityService) GetIdentity(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	return s.identityRepo.GetByProviderSubject(ctx, provider, subject)
+}
+
+func (s *identityService) GetUserIdentities(ctx context.Context, userId uuid.UUID) ([]models.UserIdentity, error) {
+	return s.identityRepo.GetByUserId(ctx, userId)
+}
+
+func (s *identityService) UnlinkIdentity(ctx context.Context, userId uuid.UUID, id int) (*models.UserIdentity, error) {
+	return s.identityRepo.DeleteById(ctx, userId, id)
+}
+
+func (s *identityService) RemoveCredentials(ctx context.Context, userId uuid.UUID) error {
+	return s.identityRepo.DeleteByUserProvider(ctx, userId, models.IdentityProviderCredentials)
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"my-go-api/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrUnknownSigningKey = errors.New("unknown signing key")
+
+// JwtKey is a keyring key ready to sign or verify with. Signing is an HMAC
+// secret or a crypto.Signer, Verifying the same secret or the public key.
+type JwtKey struct {
+	Id        string
+	Method    jwt.SigningMethod
+	Signing   any
+	Verifying any
+}
+
+// IJwtKeyring hands jwtService the key to sign with and the keys to verify
+// with, selected by the kid header. Tokens without a kid were signed with the
+// configured key before the keyring existed.
+type IJwtKeyring interface {
+	SigningKey() (*JwtKey, error)
+	VerifyingKey(kid string) (*JwtKey, error)
+	// JWKS returns the public keys of the keyring, HMAC secrets are never
+	// published.
+	JWKS() JSONWebKeySet
+}
+
+// jwtKeyringState is an immutable snapshot of a keyring.
+type jwtKeyringState struct {
+	active *JwtKey
+	keys   map[string]*JwtKey
+	// legacyId is the key tokens without a kid are verified with.
+	legacyId string
+	jwks     JSONWebKeySet
+}
+
+func newJwtKeyringState(legacyId string) *jwtKeyringState {
+	return &jwtKeyringState{
+		keys:     map[string]*JwtKey{},
+		legacyId: legacyId,
+		jwks:     JSONWebKeySet{Keys: []JSONWebKey{}},
+	}
+}
+
+func (r *jwtKeyringState) add(key *JwtKey, active bool) error {
+	r.keys[key.Id] = key
+	if active {
+		r.active = key
+	}
+	if _, ok := key.Verifying.([]byte); ok {
+		return nil
+	}
+	jwk, err := NewJSONWebKey(key.Verifying)
+	if err != nil {
+		return err
+	}
+	jwk.Kid = key.Id
+	jwk.Use = "sig"
+	jwk.Alg = key.Method.Alg()
+	r.jwks.Keys = append(r.jwks.Keys, jwk)
+	return nil
+}
+
+func (r *jwtKeyringState) SigningKey() (*JwtKey, error) {
+	if r.active == nil {
+		return nil, errors.New("the keyring has no active key")
+	}
+	return r.active, nil
+}
+
+func (r *jwtKeyringState) VerifyingKey(kid string) (*JwtKey, error) {
+	if kid == "" {
+		kid = r.legacyId
+	}
+	if key, ok := r.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrUnknownSigningKey
+}
+
+func (r *jwtKeyringState) JWKS() JSONWebKeySet {
+	return r.jwks
+}
+
+// NewStaticJwtKeyring creates a keyring holding only the configured key.
+func NewStaticJwtKeyring(cfg config.JwtConfig) (IJwtKeyring, error) {
+	key, _, err := configuredJwtKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+	keyring := newJwtKeyringState(key.Id)
+	if err := keyring.add(key, true); err != nil {
+		return nil, err
+	}
+	return keyring, nil
+}
+
+// configuredJwtKey returns the key of cfg and its private key material, see
+// marshalJwtKey.
+func configuredJwtKey(cfg config.JwtConfig) (*JwtKey, []byte, error) {
+	algorithm := cfg.Algorithm
+	var private any = cfg.SigningKey
+	if algorithm == "" || algorithm == config.JwtAlgorithmHS256 {
+		algorithm = config.JwtAlgorithmHS256
+		private = []byte(cfg.SecretKey)
+	} else if cfg.SigningKey == nil {
+		return nil, nil, fmt.Errorf("%s needs a signing key", algorithm)
+	}
+	material, err := marshalJwtKey(private)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := parseJwtKey(cfg.KeyId, algorithm, material)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, material, nil
+}
+
+// generateJwtKey creates a fresh key for algorithm and returns its private key
+// material.
+func generateJwtKey(algorithm string) ([]byte, error) {
+	var private any
+	var err error
+	switch algorithm {
+	case config.JwtAlgorithmHS256:
+		secret := make([]byte, 32)
+		_, err = rand.Read(secret)
+		private = secret
+	case config.JwtAlgorithmRS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case config.JwtAlgorithmES256:
+		private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case config.JwtAlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return marshalJwtKey(private)
+}
+
+// marshalJwtKey encodes an HMAC secret as is and private keys as PKCS #8.
+func marshalJwtKey(private any) ([]byte, error) {
+	if secret, ok := private.([]byte); ok {
+		return secret, nil
+	}
+	return x509.MarshalPKCS8PrivateKey(private)
+}
+
+// parseJwtKey turns private key material back into a key. An empty id is
+// derived from the key, the RFC 7638 thumbprint for public keys and the
+// thumbprint of the secret as an oct JWK for HMAC.
+func parseJwtKey(id, algorithm string, material []byte) (*JwtKey, error) {
+	method := jwt.GetSigningMethod(algorithm)
+	if method == nil {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+	key := &JwtKey{Id: id, Method: method}
+	if algorithm == config.JwtAlgorithmHS256 {
+		if len(material) == 0 {
+			return nil, errors.New("HS256 needs a secret key")
+		}
+		key.Signing, key.Verifying = material, material
+		if key.Id == "" {
+			sum := sha256.Sum256([]byte(`{"k":"` + base64.RawURLEncoding.EncodeToString(material) + `","kty":"oct"}`))
+			key.Id = base64.RawURLEncoding.EncodeToString(sum[:])
+		}
+		return key, nil
+	}
+
+	private, err := x509.ParsePKCS8PrivateKey(material)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key %T", private)
+	}
+	key.Signing, key.Verifying = signer, signer.Public()
+	if key.Id == "" {
+		jwk, err := NewJSONWebKey(key.Verifying)
+		if err != nil {
+			return nil, err
+		}
+		key.Id = jwk.Thumbprint()
+	}
+	return key, nil
+}
@@ -9,18 +9,25 @@ import (
 )
 
 type jwtService struct {
-	secretKey    string
+	keyring      IJwtKeyring
 	redisService IRedisService
 }
 
 type IJwtService interface {
 	Verify(tokenString string) (JWTPayload, error)
 	Create(JWTPayload) (string, error)
+	// Sign signs claims with the active key, for tokens other than access
+	// tokens such as ID tokens.
+	Sign(claims jwt.Claims) (string, error)
+	// JWKS returns the public keys verifying access tokens, none with HS256.
+	JWKS() JSONWebKeySet
 }
 
-func NewJwtService(secretKey string, redisService IRedisService) IJwtService {
+// NewJwtService creates the service issuing access tokens with the active key
+// of keyring and verifying them with the key named by their kid header.
+func NewJwtService(keyring IJwtKeyring, redisService IRedisService) IJwtService {
 	return &jwtService{
-		secretKey:    secretKey,
+		keyring:      keyring,
 		redisService: redisService,
 	}
 }
@@ -29,10 +36,17 @@ func (s *jwtService) Verify(tokenString string) (JWTPayload, error) {
 	claims := &CustomClaims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := token.Header["kid"].(string)
+		key, err := s.keyring.VerifyingKey(kid)
+		if err != nil {
+			return nil, err
+		}
+		// only the algorithm of the key is accepted, so a token can never be
+		// checked against a public key as if it were an HMAC secret
+		if token.Method.Alg() != key.Method.Alg() {
 			return nil, errors.New("unexpected signing method")
 		}
-		return []byte(s.secretKey), nil
+		return key.Verifying, nil
 	})
 	if err != nil {
 		return JWTPayload{}, fmt.Errorf("token parsing failed: %w", err)
@@ -47,26 +61,44 @@ func (s *jwtService) Verify(tokenString string) (JWTPayload, error) {
 	}
 
 	return JWTPayload{
-		UserId:     claims.UserID,
-		Jti:        claims.JTI,
-		JwtVersion: claims.JwtVersion,
+		UserId:      claims.UserID,
+		Jti:         claims.JTI,
+		JwtVersion:  claims.JwtVersion,
+		Permissions: claims.Permissions,
+		ClientId:    claims.ClientID,
+		Scope:       claims.Scope,
 	}, nil
 }
 
 func (s *jwtService) Create(params JWTPayload) (string, error) {
 	claims := CustomClaims{
-		UserID:     params.UserId,
-		JTI:        params.Jti,
-		JwtVersion: params.JwtVersion,
+		UserID:      params.UserId,
+		JTI:         params.Jti,
+		JwtVersion:  params.JwtVersion,
+		Permissions: params.Permissions,
+		ClientID:    params.ClientId,
+		Scope:       params.Scope,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(1 * time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    "go-api",
 		},
 	}
+	return s.Sign(claims)
+}
+
+func (s *jwtService) Sign(claims jwt.Claims) (string, error) {
+	key, err := s.keyring.SigningKey()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.Id
+	return token.SignedString(key.Signing)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.secretKey))
+func (s *jwtService) JWKS() JSONWebKeySet {
+	return s.keyring.JWKS()
 }
 
 // helpers
@@ -74,11 +106,21 @@ type CustomClaims struct {
 	UserID     string `json:"userId"`
 	JTI        string `json:"jti"`
 	JwtVersion string `json:"jwtVersion"`
+	// Permissions lets other services authorize a request from the token
+	// alone.
+	Permissions []string `json:"permissions,omitempty"`
+	// ClientID and Scope are set on tokens issued to an OAuth client, named
+	// after RFC 9068.
+	ClientID string `json:"client_id,omitempty"`
+	Scope    string `json:"scope,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type JWTPayload struct {
-	UserId     string
-	Jti        string
-	JwtVersion string
+	UserId      string
+	Jti         string
+	JwtVersion  string
+	Permissions []string
+	ClientId    string
+	Scope       string
 }
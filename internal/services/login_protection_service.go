@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"log"
+	"my-go-api/internal/config"
+	"my-go-api/internal/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ILoginProtectionService interface {
+	Check(ctx context.Context, subject, ipAddress string) (time.Duration, error)
+	RecordFailure(ctx context.Context, user *models.User, subject, ipAddress, userAgent string) error
+	RecordSuccess(ctx context.Context, subject string) error
+	GetLockState(ctx context.Context, userId uuid.UUID) (LoginLockState, error)
+	Unlock(ctx context.Context, userId uuid.UUID) error
+}
+
+type loginProtectionService struct {
+	config               config.LoginProtectionConfig
+	redisService         IRedisService
+	emailService         IEmailService
+	securityEventService ISecurityEventService
+}
+
+// NewLoginProtectionService creates the service throttling password sign in,
+// see config.LoginProtectionConfig.
+func NewLoginProtectionService(
+	config config.LoginProtectionConfig,
+	redisService IRedisService,
+	emailService IEmailService,
+	securityEventService ISecurityEventService,
+) ILoginProtectionService {
+	return &loginProtectionService{
+		config:               config,
+		redisService:         redisService,
+		emailService:         emailService,
+		securityEventService: securityEventService,
+	}
+}
+
+// LoginSubject names what failed attempts are counted against. Identities
+// without an account are counted too, so they are throttled and locked exactly
+// like real accounts and responses do not tell the two apart.
+func LoginSubject(user *models.User, identity string) string {
+	if user != nil {
+		return userLoginSubject(user.ID)
+	}
+	return "identity:" + strings.ToLower(strings.TrimSpace(identity))
+}
+
+func userLoginSubject(userId uuid.UUID) string {
+	return "user:" + userId.String()
+}
+
+func ipLoginSubject(ipAddress string) string {
+	return "ip:" + ipAddress
+}
+
+// Check returns how long the caller has to wait before subject may try to
+// sign in from ipAddress again, zero when it may try right away.
+func (s *loginProtectionService) Check(ctx context.Context, subject, ipAddress string) (time.Duration, error) {
+	account, err := s.redisService.GetLoginFailures(subject)
+	if err != nil {
+		return 0, err
+	}
+	ip, err := s.redisService.GetLoginFailures(ipLoginSubject(ipAddress))
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	wait := max(account.RetryAt.Sub(now), account.LockedUntil.Sub(now), ip.LockedUntil.Sub(now), 0)
+	return wait, nil
+}
+
+// RecordFailure counts a failed sign in against subject and ipAddress. When
+// this locks an existing account its owner is told by email.
+func (s *loginProtectionService) RecordFailure(ctx context.Context, user *models.User, subject, ipAddress, userAgent string) error {
+	if _, err := s.redisService.RecordLoginFailure(ipLoginSubject(ipAddress), LoginFailurePolicy{
+		Threshold:    s.config.IpMaxAttempts,
+		LockDuration: s.config.LockoutDuration,
+	}); err != nil {
+		return err
+	}
+	failure, err := s.redisService.RecordLoginFailure(subject, LoginFailurePolicy{
+		Threshold:    s.config.MaxAttempts,
+		LockDuration: s.config.LockoutDuration,
+		BackoffBase:  s.config.BackoffBase,
+		BackoffMax:   s.config.BackoffMax,
+	})
+	if err != nil {
+		return err
+	}
+	if failure.LockedUntil.IsZero() || user == nil {
+		return nil
+	}
+
+	s.securityEventService.Emit(ctx, SecurityEvent{
+		Type:      SecurityEventAccountLocked,
+		UserId:    user.ID.String(),
+		IpAddress: ipAddress,
+		UserAgent: userAgent,
+		Details:   map[string]string{"locked_until": failure.LockedUntil.UTC().Format(time.RFC3339)},
+	})
+	if err := s.emailService.SendAccountLockedEmail(SendAccountLockedParams{
+		Name:        user.Username,
+		Email:       user.Email,
+		LockedUntil: failure.LockedUntil,
+		Locale:      user.Locale,
+	}); err != nil {
+		log.Printf("failed to send account locked email: %s", err.Error())
+	}
+	return nil
+}
+
+// RecordSuccess forgets the failed attempts of subject. Failures counted
+// against the IP address are kept, or one valid account would be enough to
+// keep guessing the passwords of others.
+func (s *loginProtectionService) RecordSuccess(ctx context.Context, subject string) error {
+	return s.redisService.ClearLoginFailures(subject)
+}
+
+func (s *loginProtectionService) GetLockState(ctx context.Context, userId uuid.UUID) (LoginLockState, error) {
+	failure, err := s.redisService.GetLoginFailures(userLoginSubject(userId))
+	if err != nil {
+		return LoginLockState{}, err
+	}
+	state := LoginLockState{FailedAttempts: failure.Count}
+	if failure.LockedUntil.After(time.Now()) {
+		state.Locked = true
+		state.LockedUntil = &failure.LockedUntil
+	}
+	return state, nil
+}
+
+// Unlock lifts a lockout and forgets the failed attempts of a user.
+func (s *loginProtectionService) Unlock(ctx context.Context, userId uuid.UUID) error {
+	return s.redisService.ClearLoginFailures(userLoginSubject(userId))
+}
+
+// LoginLockState is the lockout of an account as shown to admins.
+type LoginLockState struct {
+	FailedAttempts int        `json:"failed_attempts"`
+	Locked         bool       `json:"locked"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+}
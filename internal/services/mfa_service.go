@@ -0,0 +1,334 @@
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/skip2/go-qrcode"
+)
+
+var (
+	ErrMfaAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrMfaNotEnabled       = errors.New("two-factor authentication is not enabled")
+	ErrMfaNotEnrolled      = errors.New("two-factor enrollment has not been started")
+	ErrInvalidMfaCode      = errors.New("invalid two-factor code")
+	ErrInvalidMfaChallenge = errors.New("invalid or expired mfa token")
+)
+
+const (
+	mfaRecoveryCodeCount = 10
+	// mfaRecoveryCodeSize is the number of random bytes per recovery code,
+	// printed as ten hex characters.
+	mfaRecoveryCodeSize = 5
+)
+
+type IMfaService interface {
+	GetStatus(ctx context.Context, userId uuid.UUID) (MfaStatus, error)
+	IsEnabled(ctx context.Context, userId uuid.UUID) (bool, error)
+	BeginEnrollment(ctx context.Context, user *models.User) (TotpEnrollment, error)
+	ConfirmEnrollment(ctx context.Context, userId uuid.UUID, code string) ([]string, error)
+	VerifyCode(ctx context.Context, userId uuid.UUID, code string) error
+	Disable(ctx context.Context, userId uuid.UUID) error
+	RegenerateRecoveryCodes(ctx context.Context, userId uuid.UUID) ([]string, error)
+	CreateChallenge(userId uuid.UUID) (string, error)
+	VerifyChallenge(ctx context.Context, token, code string) (uuid.UUID, error)
+	UseChallenge(token string) (uuid.UUID, error)
+	CompleteChallenge(token string) error
+}
+
+type mfaService struct {
+	mfaRepo      repositories.IMfaRepository
+	redisService IRedisService
+	utils        utils.IUtils
+	issuer       string
+	cipherKey    [32]byte
+}
+
+// NewMfaService creates the TOTP service. TOTP secrets are encrypted at rest
+// with a key derived from encryptionKey.
+func NewMfaService(mfaRepo repositories.IMfaRepository, redisService IRedisService, utils utils.IUtils, issuer, encryptionKey string) IMfaService {
+	return &mfaService{
+		mfaRepo:      mfaRepo,
+		redisService: redisService,
+		utils:        utils,
+		issuer:       issuer,
+		cipherKey:    sha256.Sum256([]byte(encryptionKey)),
+	}
+}
+
+func (s *mfaService) GetStatus(ctx context.Context, userId uuid.UUID) (MfaStatus, error) {
+	mfa, err := s.mfaRepo.GetByUserId(ctx, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return MfaStatus{}, nil
+		}
+		return MfaStatus{}, err
+	}
+	if !mfa.Enabled {
+		return MfaStatus{}, nil
+	}
+	remaining, err := s.mfaRepo.CountRecoveryCodes(ctx, userId)
+	if err != nil {
+		return MfaStatus{}, err
+	}
+	return MfaStatus{Enabled: true, EnabledAt: mfa.EnabledAt, RecoveryCodesRemaining: remaining}, nil
+}
+
+func (s *mfaService) IsEnabled(ctx context.Context, userId uuid.UUID) (bool, error) {
+	status, err := s.GetStatus(ctx, userId)
+	return status.Enabled, err
+}
+
+// BeginEnrollment generates a new secret that stays inactive until a code from
+// it is confirmed. Starting again replaces a pending secret.
+func (s *mfaService) BeginEnrollment(ctx context.Context, user *models.User) (TotpEnrollment, error) {
+	if enabled, err := s.IsEnabled(ctx, user.ID); err != nil {
+		return TotpEnrollment{}, err
+	} else if enabled {
+		return TotpEnrollment{}, ErrMfaAlreadyEnabled
+	}
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return TotpEnrollment{}, err
+	}
+	sealed, err := s.seal(secret)
+	if err != nil {
+		return TotpEnrollment{}, err
+	}
+	if err := s.mfaRepo.SavePendingSecret(ctx, user.ID, sealed); err != nil {
+		return TotpEnrollment{}, err
+	}
+	uri := totpURI(s.issuer, user.Email, secret)
+	qrCode, err := qrcode.Encode(uri, qrcode.Medium, 256)
+	if err != nil {
+		return TotpEnrollment{}, err
+	}
+	return TotpEnrollment{
+		Secret: totpEncoding.EncodeToString(secret),
+		URI:    uri,
+		QRCode: qrCode,
+	}, nil
+}
+
+// ConfirmEnrollment enables the pending secret when code matches it and
+// returns the recovery codes, which are only ever shown this once.
+func (s *mfaService) ConfirmEnrollment(ctx context.Context, userId uuid.UUID, code string) ([]string, error) {
+	mfa, err := s.mfaRepo.GetByUserId(ctx, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMfaNotEnrolled
+		}
+		return nil, err
+	}
+	if mfa.Enabled {
+		return nil, ErrMfaAlreadyEnabled
+	}
+	secret, err := s.open(mfa.TotpSecret)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := matchTotpCode(secret, normalizeMfaCode(code), time.Now())
+	if !ok {
+		return nil, ErrInvalidMfaCode
+	}
+	codes, hashes, err := s.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.Enable(ctx, userId, step, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// VerifyCode accepts either a current TOTP code or an unused recovery code.
+// Both are single use.
+func (s *mfaService) VerifyCode(ctx context.Context, userId uuid.UUID, code string) error {
+	mfa, err := s.mfaRepo.GetByUserId(ctx, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrMfaNotEnabled
+		}
+		return err
+	}
+	if !mfa.Enabled {
+		return ErrMfaNotEnabled
+	}
+	code = normalizeMfaCode(code)
+	if len(code) == TotpDigits {
+		secret, err := s.open(mfa.TotpSecret)
+		if err != nil {
+			return err
+		}
+		step, ok := matchTotpCode(secret, code, time.Now())
+		if !ok {
+			return ErrInvalidMfaCode
+		}
+		used, err := s.mfaRepo.UseStep(ctx, userId, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidMfaCode
+		}
+		return nil
+	}
+	used, err := s.mfaRepo.UseRecoveryCode(ctx, userId, s.utils.HashWithSHA256(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidMfaCode
+	}
+	return nil
+}
+
+func (s *mfaService) Disable(ctx context.Context, userId uuid.UUID) error {
+	return s.mfaRepo.Delete(ctx, userId)
+}
+
+func (s *mfaService) RegenerateRecoveryCodes(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	if enabled, err := s.IsEnabled(ctx, userId); err != nil {
+		return nil, err
+	} else if !enabled {
+		return nil, ErrMfaNotEnabled
+	}
+	codes, hashes, err := s.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, userId, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// CreateChallenge issues the short-lived token a client trades, together with
+// a second factor code, for real tokens after the password step.
+func (s *mfaService) CreateChallenge(userId uuid.UUID) (string, error) {
+	token, err := s.utils.GenerateRandomBytes(32)
+	if err != nil {
+		return "", err
+	}
+	if err := s.redisService.SaveMfaChallenge(MfaChallengeData{
+		HashedToken: s.utils.HashWithSHA256(token),
+		UserId:      userId.String(),
+	}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// VerifyChallenge checks code against the user behind the challenge token and
+// consumes the challenge on success.
+func (s *mfaService) VerifyChallenge(ctx context.Context, token, code string) (uuid.UUID, error) {
+	userId, err := s.UseChallenge(token)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := s.VerifyCode(ctx, userId, code); err != nil {
+		return uuid.Nil, err
+	}
+	if err := s.CompleteChallenge(token); err != nil {
+		return uuid.Nil, err
+	}
+	return userId, nil
+}
+
+// UseChallenge counts one attempt against the challenge and returns the user
+// it was issued for. Other second factors use it together with
+// CompleteChallenge.
+func (s *mfaService) UseChallenge(token string) (uuid.UUID, error) {
+	challenge, err := s.redisService.UseMfaChallengeAttempt(s.utils.HashWithSHA256(token))
+	if err != nil {
+		return uuid.Nil, ErrInvalidMfaChallenge
+	}
+	userId, err := uuid.Parse(challenge.UserId)
+	if err != nil {
+		return uuid.Nil, ErrInvalidMfaChallenge
+	}
+	return userId, nil
+}
+
+// CompleteChallenge consumes the challenge once a second factor succeeded.
+func (s *mfaService) CompleteChallenge(token string) error {
+	return s.redisService.DeleteMfaChallenge(s.utils.HashWithSHA256(token))
+}
+
+func (s *mfaService) generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, mfaRecoveryCodeCount)
+	hashes := make([]string, 0, mfaRecoveryCodeCount)
+	for range mfaRecoveryCodeCount {
+		raw, err := s.utils.GenerateRandomBytes(mfaRecoveryCodeSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, s.utils.HashWithSHA256(raw))
+	}
+	return codes, hashes, nil
+}
+
+func (s *mfaService) seal(secret []byte) (string, error) {
+	gcm, err := s.gcm()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, secret, nil)), nil
+}
+
+func (s *mfaService) open(sealed string) ([]byte, error) {
+	gcm, err := s.gcm()
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("malformed totp secret")
+	}
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+}
+
+func (s *mfaService) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.cipherKey[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// normalizeMfaCode strips the separators users tend to type or paste.
+func normalizeMfaCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code)))
+}
+
+type MfaStatus struct {
+	Enabled                bool    `json:"enabled"`
+	EnabledAt              *string `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int     `json:"recovery_codes_remaining"`
+}
+
+type TotpEnrollment struct {
+	Secret string
+	URI    string
+	QRCode []byte
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"my-go-api/internal/config"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// githubProvider signs users in with GitHub, which speaks plain OAuth2: the
+// profile comes from the REST API instead of an ID token.
+type githubProvider struct {
+	config     config.OAuthProviderConfig
+	httpClient *http.Client
+}
+
+func NewGithubProvider(cfg config.OAuthProviderConfig, httpClient *http.Client) IOAuthProvider {
+	return &githubProvider{config: cfg, httpClient: httpClient}
+}
+
+func (p *githubProvider) Name() string {
+	return p.config.Name
+}
+
+func (p *githubProvider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	return p.oauth2Config().AuthCodeURL(state, oauth2.S256ChallengeOption(verifier)), nil
+}
+
+func (p *githubProvider) Exchange(ctx context.Context, code, nonce, verifier string) (OAuthProfile, error) {
+	token, err := p.oauth2Config().Exchange(
+		context.WithValue(ctx, oauth2.HTTPClient, p.httpClient),
+		code,
+		oauth2.VerifierOption(verifier),
+	)
+	if err != nil {
+		return OAuthProfile{}, fmt.Errorf("code exchange failed: %w", err)
+	}
+	apiUrl := strings.TrimSuffix(p.config.UserInfoUrl, "/")
+	user := githubUser{}
+	if err := getJSON(ctx, p.httpClient, apiUrl+"/user", token.AccessToken, &user); err != nil {
+		return OAuthProfile{}, fmt.Errorf("failed to fetch github user: %w", err)
+	}
+	emails := []githubEmail{}
+	if err := getJSON(ctx, p.httpClient, apiUrl+"/user/emails", token.AccessToken, &emails); err != nil {
+		return OAuthProfile{}, fmt.Errorf("failed to fetch github emails: %w", err)
+	}
+	profile := OAuthProfile{
+		Provider: p.config.Name,
+		Subject:  strconv.FormatInt(user.Id, 10),
+		Name:     user.Name,
+	}
+	if profile.Name == "" {
+		profile.Name = user.Login
+	}
+	for _, email := range emails {
+		if email.Primary {
+			profile.Email = strings.ToLower(email.Email)
+			profile.EmailVerified = p.config.TrustEmail || email.Verified
+			break
+		}
+	}
+	return profile, nil
+}
+
+func (p *githubProvider) oauth2Config() *oauth2.Config {
+	return oauth2Config(p.config, oauth2.Endpoint{
+		AuthURL:  p.config.AuthUrl,
+		TokenURL: p.config.TokenUrl,
+	})
+}
+
+type githubUser struct {
+	Id    int64  `json:"id"`
+	Login string `json:"login"`
+	Name  string `json:"name"`
+}
+
+type githubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
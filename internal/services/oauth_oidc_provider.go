@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"my-go-api/internal/config"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+// tenantIssuerPlaceholder appears in the discovery issuer of multi-tenant
+// issuers such as Microsoft's "common" endpoint; the real issuer carries the
+// tenant id from the token's tid claim.
+const tenantIssuerPlaceholder = "{tenantid}"
+
+// jwksRefetchInterval is the least time between two fetches of a provider's
+// key set, so tokens with made up key ids cannot make us hammer the provider.
+const jwksRefetchInterval = time.Minute
+
+type oidcProvider struct {
+	config     config.OAuthProviderConfig
+	httpClient *http.Client
+
+	mu            sync.Mutex
+	discovery     *oidcDiscovery
+	keys          map[string]any
+	keysFetchedAt time.Time
+}
+
+func NewOIDCProvider(cfg config.OAuthProviderConfig, httpClient *http.Client) IOAuthProvider {
+	return &oidcProvider{
+		config:     cfg,
+		httpClient: httpClient,
+		keys:       make(map[string]any),
+	}
+}
+
+func (p *oidcProvider) Name() string {
+	return p.config.Name
+}
+
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+	return p.oauth2Config(discovery).AuthCodeURL(
+		state,
+		oauth2.S256ChallengeOption(verifier),
+		oauth2.SetAuthURLParam("nonce", nonce),
+	), nil
+}
+
+// Exchange redeems the authorization code and returns the profile carried by
+// the validated ID token.
+func (p *oidcProvider) Exchange(ctx context.Context, code, nonce, verifier string) (OAuthProfile, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return OAuthProfile{}, err
+	}
+	token, err := p.oauth2Config(discovery).Exchange(
+		context.WithValue(ctx, oauth2.HTTPClient, p.httpClient),
+		code,
+		oauth2.VerifierOption(verifier),
+	)
+	if err != nil {
+		return OAuthProfile{}, fmt.Errorf("code exchange failed: %w", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return OAuthProfile{}, errors.New("id_token missing from token response")
+	}
+	claims, err := p.verifyIDToken(ctx, discovery, rawIDToken)
+	if err != nil {
+		return OAuthProfile{}, err
+	}
+	if claims.Nonce != nonce {
+		return OAuthProfile{}, errors.New("id_token nonce mismatch")
+	}
+	return OAuthProfile{
+		Provider:      p.config.Name,
+		Subject:       claims.Subject,
+		Email:         strings.ToLower(claims.Email),
+		EmailVerified: p.config.TrustEmail || claims.emailVerified(),
+		Name:          claims.Name,
+	}, nil
+}
+
+func (p *oidcProvider) oauth2Config(discovery *oidcDiscovery) *oauth2.Config {
+	return oauth2Config(p.config, oauth2.Endpoint{
+		AuthURL:  discovery.AuthorizationEndpoint,
+		TokenURL: discovery.TokenEndpoint,
+	})
+}
+
+func (p *oidcProvider) verifyIDToken(ctx context.Context, discovery *oidcDiscovery, rawIDToken string) (*idTokenClaims, error) {
+	claims := &idTokenClaims{}
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithAudience(p.config.ClientId),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	}
+	multiTenant := strings.Contains(discovery.Issuer, tenantIssuerPlaceholder)
+	if !multiTenant {
+		options = append(options, jwt.WithIssuer(discovery.Issuer))
+	}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.getKey(ctx, discovery, kid)
+	}, options...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+	if multiTenant {
+		expected := strings.Replace(discovery.Issuer, tenantIssuerPlaceholder, claims.Tid, 1)
+		if claims.Tid == "" || claims.Issuer != expected {
+			return nil, errors.New("invalid id_token: token has invalid issuer")
+		}
+	}
+	return claims, nil
+}
+
+func (p *oidcProvider) getDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	cached := p.discovery
+	p.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+	issuer := strings.TrimSuffix(p.config.Issuer, "/")
+	discovery := &oidcDiscovery{}
+	if err := getJSON(ctx, p.httpClient, issuer+"/.well-known/openid-configuration", "", discovery); err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+	multiTenant := strings.Contains(discovery.Issuer, tenantIssuerPlaceholder)
+	if !multiTenant && strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc discovery issuer mismatch: %s", discovery.Issuer)
+	}
+	if multiTenant && p.config.TrustEmail {
+		return nil, fmt.Errorf("trusting emails is not allowed for the multi-tenant issuer %s", discovery.Issuer)
+	}
+	p.mu.Lock()
+	p.discovery = discovery
+	p.mu.Unlock()
+	return discovery, nil
+}
+
+// getKey returns the verification key for kid, refreshing the cached key set
+// when the provider has rotated its keys, at most once per
+// jwksRefetchInterval. The key set is fetched without holding the lock, so a
+// slow provider does not block other sign ins.
+func (p *oidcProvider) getKey(ctx context.Context, discovery *oidcDiscovery, kid string) (any, error) {
+	p.mu.Lock()
+	key, ok := p.keys[kid]
+	fetchedAt := p.keysFetchedAt
+	claimed := !ok && time.Since(fetchedAt) >= jwksRefetchInterval
+	if claimed {
+		// claimed before fetching, so concurrent sign ins fetch only once
+		p.keysFetchedAt = time.Now()
+	}
+	p.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	if !claimed {
+		return nil, fmt.Errorf("unknown signing key: %s", kid)
+	}
+	keySet := &JSONWebKeySet{}
+	if err := getJSON(ctx, p.httpClient, discovery.JwksUri, "", keySet); err != nil {
+		// a failed fetch does not count, the next sign in tries again
+		p.mu.Lock()
+		p.keysFetchedAt = fetchedAt
+		p.mu.Unlock()
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	keys := make(map[string]any)
+	for _, jwk := range keySet.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+	key, ok = keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %s", kid)
+	}
+	return key, nil
+}
+
+// getJSON fetches url and decodes the JSON body into target, sending the
+// bearer token when one is given.
+func getJSON(ctx context.Context, httpClient *http.Client, url, bearer string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", res.StatusCode, url)
+	}
+	return json.NewDecoder(res.Body).Decode(target)
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+type idTokenClaims struct {
+	Nonce         string `json:"nonce"`
+	Email         string `json:"email"`
+	EmailVerified any    `json:"email_verified"`
+	Name          string `json:"name"`
+	Tid           string `json:"tid"`
+	jwt.RegisteredClaims
+}
+
+// emailVerified accepts both the boolean defined by the spec and the string
+// form some providers still send.
+func (c *idTokenClaims) emailVerified() bool {
+	switch v := c.EmailVerified.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
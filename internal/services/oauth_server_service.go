@@ -0,0 +1,740 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"log"
+	"my-go-api/internal/config"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/utils"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrOAuthClientNotFound    = errors.New("oauth client not found")
+	ErrInvalidRedirectUri     = errors.New("redirect uris must be absolute without a fragment and use https, http on a loopback address or a private scheme like com.example.app")
+	ErrRedirectUriMismatch    = errors.New("redirect_uri is not registered for the client")
+	ErrAuthorizationNotFound  = errors.New("authorization request not found or expired")
+	oauthCodeChallengePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{43,128}$`)
+)
+
+// Error codes of RFC 6749 and OpenID Connect Core used by the provider.
+const (
+	OAuthErrorInvalidRequest          = "invalid_request"
+	OAuthErrorInvalidClient           = "invalid_client"
+	OAuthErrorInvalidGrant            = "invalid_grant"
+	OAuthErrorInvalidScope            = "invalid_scope"
+	OAuthErrorUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthErrorUnsupportedResponseType = "unsupported_response_type"
+	OAuthErrorAccessDenied            = "access_denied"
+	OAuthErrorInteractionRequired     = "interaction_required"
+)
+
+// Scopes the provider knows. openid is required, profile and email add the
+// matching claims to ID tokens and /userinfo. Other scopes are ignored.
+const (
+	OAuthScopeOpenId  = "openid"
+	OAuthScopeProfile = "profile"
+	OAuthScopeEmail   = "email"
+)
+
+var oauthSupportedScopes = []string{OAuthScopeOpenId, OAuthScopeProfile, OAuthScopeEmail}
+
+// Paths of the provider endpoints below the issuer.
+const (
+	OAuthAuthorizePath = "/api/v1/oauth/authorize"
+	OAuthTokenPath     = "/api/v1/oauth/token"
+	OAuthUserInfoPath  = "/api/v1/oauth/userinfo"
+	OAuthJWKSPath      = "/.well-known/jwks.json"
+)
+
+// OAuthError is an error response of RFC 6749. The authorization endpoint
+// sends it to the redirect URI, the token endpoint in its body.
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+func oauthError(code, description string) *OAuthError {
+	return &OAuthError{Code: code, Description: description}
+}
+
+// IOAuthServerService is the OpenID Connect provider letting registered
+// clients sign users in with the authorization code flow. PKCE with S256 is
+// required of every client. The tokens it issues are sessions of the refresh
+// token store, bound to the client and the granted scopes.
+type IOAuthServerService interface {
+	// Start removes expired authorization requests and codes until ctx is
+	// done.
+	Start(ctx context.Context)
+	// RegisterClient returns the client along with its secret, which is only
+	// ever shown then. Public clients get no secret.
+	RegisterClient(ctx context.Context, params RegisterOAuthClientParams) (*models.OAuthClient, string, error)
+	ListClients(ctx context.Context) ([]models.OAuthClient, error)
+	// DeleteClient removes the client and ends the sessions it holds.
+	DeleteClient(ctx context.Context, clientId string) error
+	// Authorize checks an authorization request and returns where to send the
+	// browser: the consent page, or the redirect URI with an error. An unknown
+	// client or redirect URI is returned as an error instead, since the
+	// request cannot be redirected safely.
+	Authorize(ctx context.Context, params AuthorizeParams) (string, error)
+	// GetAuthorization describes a pending request to the consent page.
+	GetAuthorization(ctx context.Context, requestId string, userId uuid.UUID) (*OAuthAuthorizationPrompt, error)
+	// Decide records the user's decision and returns the redirect URI carrying
+	// the authorization code, or access_denied.
+	Decide(ctx context.Context, requestId string, userId uuid.UUID, approve bool) (string, error)
+	// Exchange serves the token endpoint. Failures are *OAuthError unless
+	// something unexpected broke.
+	Exchange(ctx context.Context, params OAuthTokenParams) (*OAuthTokenResponse, error)
+	// UserInfo returns the claims about user the scope of an access token
+	// allows.
+	UserInfo(user *models.User, scope string) map[string]any
+	Configuration() OpenIDConfiguration
+}
+
+type RegisterOAuthClientParams struct {
+	Name         string
+	RedirectUris []string
+	Confidential bool
+	CreatedBy    uuid.UUID
+}
+
+type AuthorizeParams struct {
+	ResponseType        string
+	ClientId            string
+	RedirectUri         string
+	Scope               string
+	State               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Prompt              string
+}
+
+type OAuthAuthorizationPrompt struct {
+	Client *models.OAuthClient `json:"client"`
+	Scopes []string            `json:"scopes"`
+	// ConsentRequired is false when the user already granted every scope and
+	// the client did not ask for consent again, the consent page may then
+	// approve the request without asking.
+	ConsentRequired bool `json:"consent_required"`
+}
+
+type OAuthTokenParams struct {
+	GrantType    string
+	ClientId     string
+	ClientSecret string
+	Code         string
+	RedirectUri  string
+	CodeVerifier string
+	RefreshToken string
+	UserAgent    string
+	IpAddress    string
+}
+
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	IdToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope"`
+}
+
+// OpenIDConfiguration is the discovery document of OpenID Connect Discovery.
+type OpenIDConfiguration struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JwksUri                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	ResponseModesSupported            []string `json:"response_modes_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IdTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+	AuthorizationResponseIssParameter bool     `json:"authorization_response_iss_parameter_supported"`
+}
+
+type oauthServerService struct {
+	config            config.OidcConfig
+	clientRepo        repositories.IOAuthClientRepository
+	authorizationRepo repositories.IOAuthAuthorizationRepository
+	authService       IAuthService
+	sessionService    ISessionService
+	userService       IUserService
+	jwtService        IJwtService
+	utils             utils.IUtils
+}
+
+func NewOAuthServerService(
+	cfg config.OidcConfig,
+	clientRepo repositories.IOAuthClientRepository,
+	authorizationRepo repositories.IOAuthAuthorizationRepository,
+	authService IAuthService,
+	sessionService ISessionService,
+	userService IUserService,
+	jwtService IJwtService,
+	utils utils.IUtils,
+) IOAuthServerService {
+	return &oauthServerService{
+		config:            cfg,
+		clientRepo:        clientRepo,
+		authorizationRepo: authorizationRepo,
+		authService:       authService,
+		sessionService:    sessionService,
+		userService:       userService,
+		jwtService:        jwtService,
+		utils:             utils,
+	}
+}
+
+func (s *oauthServerService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.config.AuthorizationTTL)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if _, err := s.authorizationRepo.DeleteExpired(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("oauth: removing expired authorizations failed: %v", err)
+			}
+		}
+	}()
+}
+
+func (s *oauthServerService) RegisterClient(ctx context.Context, params RegisterOAuthClientParams) (*models.OAuthClient, string, error) {
+	for _, uri := range params.RedirectUris {
+		if !validRedirectUri(uri) {
+			return nil, "", ErrInvalidRedirectUri
+		}
+	}
+	clientId, err := s.utils.GenerateRandomBytes(16)
+	if err != nil {
+		return nil, "", err
+	}
+	var secret, secretHash string
+	if params.Confidential {
+		if secret, err = s.utils.GenerateRandomBytes(32); err != nil {
+			return nil, "", err
+		}
+		secretHash = s.utils.HashWithSHA256(secret)
+	}
+	client, err := s.clientRepo.Create(ctx, repositories.CreateOAuthClientParams{
+		Id:           clientId,
+		Name:         params.Name,
+		SecretHash:   secretHash,
+		RedirectUris: params.RedirectUris,
+		CreatedBy:    params.CreatedBy,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return client, secret, nil
+}
+
+func (s *oauthServerService) ListClients(ctx context.Context) ([]models.OAuthClient, error) {
+	return s.clientRepo.List(ctx)
+}
+
+func (s *oauthServerService) DeleteClient(ctx context.Context, clientId string) error {
+	if _, err := s.getClient(ctx, clientId); err != nil {
+		return err
+	}
+	// the rows go with the client, the tokens in redis have to be revoked
+	sessions, err := s.sessionService.RevokeClientSessions(ctx, clientId)
+	if err != nil {
+		return err
+	}
+	s.authService.RevokeSessionTokens(sessions)
+	err = s.clientRepo.Delete(ctx, clientId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrOAuthClientNotFound
+	}
+	return err
+}
+
+func (s *oauthServerService) Authorize(ctx context.Context, params AuthorizeParams) (string, error) {
+	client, err := s.getClient(ctx, params.ClientId)
+	if err != nil {
+		return "", err
+	}
+	// redirect URIs are compared exactly, anything else would let an attacker
+	// receive codes on a URI the client never registered
+	if !slices.Contains(client.RedirectUris, params.RedirectUri) {
+		return "", ErrRedirectUriMismatch
+	}
+
+	reject := func(code, description string) (string, error) {
+		return s.redirect(params.RedirectUri, url.Values{
+			"error":             {code},
+			"error_description": {description},
+			"state":             {params.State},
+			"iss":               {s.config.Issuer},
+		}), nil
+	}
+	if params.ResponseType != "code" {
+		return reject(OAuthErrorUnsupportedResponseType, "only the code response type is supported")
+	}
+	scopes := parseOAuthScope(params.Scope)
+	if !slices.Contains(scopes, OAuthScopeOpenId) {
+		return reject(OAuthErrorInvalidScope, "the openid scope is required")
+	}
+	if params.CodeChallengeMethod != "S256" || !oauthCodeChallengePattern.MatchString(params.CodeChallenge) {
+		return reject(OAuthErrorInvalidRequest, "a PKCE code_challenge with the S256 method is required")
+	}
+	if len(params.State) > 512 || len(params.Nonce) > 512 {
+		return reject(OAuthErrorInvalidRequest, "state and nonce are limited to 512 characters")
+	}
+	prompt := ""
+	for _, value := range strings.Fields(params.Prompt) {
+		switch value {
+		case "none":
+			// the consent page is where users sign in and decide, there is no
+			// session to answer from without it
+			return reject(OAuthErrorInteractionRequired, "the user has to be asked")
+		case "consent":
+			prompt = value
+		}
+	}
+
+	requestId, err := s.utils.GenerateRandomBytes(32)
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.authorizationRepo.Create(ctx, repositories.CreateOAuthAuthorizationParams{
+		Id:            s.utils.HashWithSHA256(requestId),
+		ClientId:      client.ID,
+		RedirectUri:   params.RedirectUri,
+		Scope:         strings.Join(scopes, " "),
+		State:         params.State,
+		Nonce:         params.Nonce,
+		CodeChallenge: params.CodeChallenge,
+		Prompt:        prompt,
+		ExpiresAt:     time.Now().Add(s.config.AuthorizationTTL),
+	}); err != nil {
+		return "", err
+	}
+	return s.redirect(s.config.ConsentUri, url.Values{"request": {requestId}}), nil
+}
+
+func (s *oauthServerService) GetAuthorization(ctx context.Context, requestId string, userId uuid.UUID) (*OAuthAuthorizationPrompt, error) {
+	authorization, err := s.authorizationRepo.GetPending(ctx, s.utils.HashWithSHA256(requestId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAuthorizationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.getClient(ctx, authorization.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	scopes := strings.Fields(authorization.Scope)
+	granted, err := s.clientRepo.GetConsent(ctx, userId, client.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	consentRequired := authorization.Prompt == "consent"
+	for _, scope := range scopes {
+		if !slices.Contains(granted, scope) {
+			consentRequired = true
+		}
+	}
+	return &OAuthAuthorizationPrompt{
+		Client:          client,
+		Scopes:          scopes,
+		ConsentRequired: consentRequired,
+	}, nil
+}
+
+func (s *oauthServerService) Decide(ctx context.Context, requestId string, userId uuid.UUID, approve bool) (string, error) {
+	id := s.utils.HashWithSHA256(requestId)
+	if !approve {
+		authorization, err := s.authorizationRepo.Deny(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrAuthorizationNotFound
+		}
+		if err != nil {
+			return "", err
+		}
+		return s.redirect(authorization.RedirectUri, url.Values{
+			"error":             {OAuthErrorAccessDenied},
+			"error_description": {"the user denied the request"},
+			"state":             {authorization.State},
+			"iss":               {s.config.Issuer},
+		}), nil
+	}
+
+	code, err := s.utils.GenerateRandomBytes(32)
+	if err != nil {
+		return "", err
+	}
+	authorization, err := s.authorizationRepo.Approve(ctx, id, userId, s.utils.HashWithSHA256(code), time.Now().Add(s.config.CodeTTL))
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrAuthorizationNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	granted, err := s.clientRepo.GetConsent(ctx, userId, authorization.ClientId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	for _, scope := range strings.Fields(authorization.Scope) {
+		if !slices.Contains(granted, scope) {
+			granted = append(granted, scope)
+		}
+	}
+	if err := s.clientRepo.SaveConsent(ctx, userId, authorization.ClientId, granted); err != nil {
+		return "", err
+	}
+	return s.redirect(authorization.RedirectUri, url.Values{
+		"code":  {code},
+		"state": {authorization.State},
+		"iss":   {s.config.Issuer},
+	}), nil
+}
+
+func (s *oauthServerService) Exchange(ctx context.Context, params OAuthTokenParams) (*OAuthTokenResponse, error) {
+	client, err := s.authenticateClient(ctx, params.ClientId, params.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	switch params.GrantType {
+	case "authorization_code":
+		return s.exchangeCode(ctx, client, params)
+	case "refresh_token":
+		return s.exchangeRefreshToken(ctx, client, params)
+	}
+	return nil, oauthError(OAuthErrorUnsupportedGrantType, "only authorization_code and refresh_token are supported")
+}
+
+func (s *oauthServerService) exchangeCode(ctx context.Context, client *models.OAuthClient, params OAuthTokenParams) (*OAuthTokenResponse, error) {
+	if params.Code == "" || params.CodeVerifier == "" {
+		return nil, oauthError(OAuthErrorInvalidRequest, "code and code_verifier are required")
+	}
+	authorization, err := s.authorizationRepo.Redeem(ctx, s.utils.HashWithSHA256(params.Code))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, oauthError(OAuthErrorInvalidGrant, "the code is invalid, expired or was used already")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if authorization.ClientId != client.ID || authorization.RedirectUri != params.RedirectUri || authorization.UserId == nil {
+		return nil, oauthError(OAuthErrorInvalidGrant, "the code was issued to another client or redirect_uri")
+	}
+	verifier := sha256.Sum256([]byte(params.CodeVerifier))
+	challenge := base64.RawURLEncoding.EncodeToString(verifier[:])
+	if subtle.ConstantTimeCompare([]byte(challenge), []byte(authorization.CodeChallenge)) != 1 {
+		return nil, oauthError(OAuthErrorInvalidGrant, "the code_verifier does not match the code_challenge")
+	}
+	user, err := s.activeUser(ctx, *authorization.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.authService.CreateAuthTokens(ctx, CreateAuthTokenParams{
+		UserId:     user.ID,
+		JwtVersion: user.JwtVersion,
+		// every grant is a session of its own, so signing in to the client
+		// again does not end other sessions of the user
+		DeviceId:  uuid.New(),
+		UserAgent: params.UserAgent,
+		IpAddress: params.IpAddress,
+		ClientId:  client.ID,
+		Scope:     authorization.Scope,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.tokenResponse(client, user, authorization.Scope, authorization.Nonce, tokens)
+}
+
+// exchangeRefreshToken rotates the refresh token the same way the first party
+// refresh does, including the grace period for concurrent requests and
+// revoking the token family when a rotated token is used again.
+func (s *oauthServerService) exchangeRefreshToken(ctx context.Context, client *models.OAuthClient, params OAuthTokenParams) (*OAuthTokenResponse, error) {
+	invalid := oauthError(OAuthErrorInvalidGrant, "the refresh token is invalid or expired")
+	if params.RefreshToken == "" {
+		return nil, oauthError(OAuthErrorInvalidRequest, "refresh_token is required")
+	}
+	hashedToken := s.utils.HashWithSHA256(params.RefreshToken)
+	session, err := s.sessionService.GetSessionByHash(ctx, hashedToken)
+	if err != nil || session.ClientId == nil || *session.ClientId != client.ID {
+		return nil, invalid
+	}
+
+	claim, err := s.authService.ClaimRefreshToken(ctx, params.RefreshToken, nil)
+	if err != nil {
+		return nil, err
+	}
+	if claim.Status == RefreshTokenMissing {
+		active, err := s.sessionService.GetActiveSessionByHash(ctx, hashedToken)
+		if err != nil {
+			s.authService.HandleRefreshTokenReuse(ctx, hashedToken, params.IpAddress, params.UserAgent)
+			return nil, invalid
+		}
+		if claim, err = s.authService.ClaimRefreshToken(ctx, params.RefreshToken, &RefreshTokenData{
+			HashedToken: active.Hash,
+			UserId:      active.UserId.String(),
+			Jti:         active.Jti.String(),
+			ClientId:    client.ID,
+			Scope:       active.Scope,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	switch claim.Status {
+	case RefreshTokenRotated:
+		return &OAuthTokenResponse{
+			AccessToken:  claim.Rotated.AccessToken,
+			TokenType:    "Bearer",
+			ExpiresIn:    int(AccessTokenTTL.Seconds()),
+			RefreshToken: claim.Rotated.RefreshToken,
+			Scope:        session.Scope,
+		}, nil
+	case RefreshTokenRotationPending:
+		return nil, oauthError(OAuthErrorInvalidGrant, "the refresh token is being rotated by another request")
+	case RefreshTokenMissing:
+		return nil, invalid
+	}
+
+	data := claim.Data
+	if data.ClientId != client.ID {
+		s.authService.ReleaseRefreshTokenClaim(hashedToken)
+		return nil, invalid
+	}
+	userId, userErr := uuid.Parse(data.UserId)
+	oldJti, jtiErr := uuid.Parse(data.Jti)
+	if userErr != nil || jtiErr != nil {
+		s.authService.ReleaseRefreshTokenClaim(hashedToken)
+		return nil, invalid
+	}
+	user, err := s.activeUser(ctx, userId)
+	if err != nil {
+		s.authService.ReleaseRefreshTokenClaim(hashedToken)
+		return nil, err
+	}
+	tokens, err := s.authService.CreateAuthTokens(ctx, CreateAuthTokenParams{
+		UserId:      user.ID,
+		JwtVersion:  user.JwtVersion,
+		DeviceId:    session.DeviceId,
+		UserAgent:   params.UserAgent,
+		IpAddress:   params.IpAddress,
+		OldRefToken: &params.RefreshToken,
+		OldTokenJti: &oldJti,
+		ClientId:    client.ID,
+		Scope:       data.Scope,
+	})
+	if err != nil {
+		s.authService.ReleaseRefreshTokenClaim(hashedToken)
+		if errors.Is(err, repositories.ErrSessionNotActive) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	s.authService.CompleteRefreshTokenRotation(params.RefreshToken, tokens)
+	return s.tokenResponse(client, user, data.Scope, "", tokens)
+}
+
+func (s *oauthServerService) tokenResponse(client *models.OAuthClient, user *models.User, scope, nonce string, tokens CreateAuthTokensResult) (*OAuthTokenResponse, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss": s.config.Issuer,
+		"sub": user.ID.String(),
+		"aud": client.ID,
+		"azp": client.ID,
+		"iat": now.Unix(),
+		"exp": now.Add(s.config.IdTokenTTL).Unix(),
+	}
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+	for name, value := range s.UserInfo(user, scope) {
+		claims[name] = value
+	}
+	idToken, err := s.jwtService.Sign(claims)
+	if err != nil {
+		return nil, err
+	}
+	return &OAuthTokenResponse{
+		AccessToken:  tokens.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(AccessTokenTTL.Seconds()),
+		RefreshToken: tokens.RefreshToken,
+		IdToken:      idToken,
+		Scope:        scope,
+	}, nil
+}
+
+func (s *oauthServerService) UserInfo(user *models.User, scope string) map[string]any {
+	scopes := strings.Fields(scope)
+	claims := map[string]any{"sub": user.ID.String()}
+	if slices.Contains(scopes, OAuthScopeProfile) {
+		claims["name"] = user.Name
+		claims["preferred_username"] = user.Username
+		if user.Locale != "" {
+			claims["locale"] = user.Locale
+		}
+		if updatedAt, err := parseDbTime(user.UpdatedAt); err == nil {
+			claims["updated_at"] = updatedAt.Unix()
+		}
+	}
+	if slices.Contains(scopes, OAuthScopeEmail) {
+		claims["email"] = user.Email
+		claims["email_verified"] = user.IsVerified
+	}
+	return claims
+}
+
+func (s *oauthServerService) Configuration() OpenIDConfiguration {
+	algorithms := []string{}
+	for _, key := range s.jwtService.JWKS().Keys {
+		if !slices.Contains(algorithms, key.Alg) {
+			algorithms = append(algorithms, key.Alg)
+		}
+	}
+	return OpenIDConfiguration{
+		Issuer:                            s.config.Issuer,
+		AuthorizationEndpoint:             s.config.Issuer + OAuthAuthorizePath,
+		TokenEndpoint:                     s.config.Issuer + OAuthTokenPath,
+		UserInfoEndpoint:                  s.config.Issuer + OAuthUserInfoPath,
+		JwksUri:                           s.config.Issuer + OAuthJWKSPath,
+		ScopesSupported:                   oauthSupportedScopes,
+		ResponseTypesSupported:            []string{"code"},
+		ResponseModesSupported:            []string{"query"},
+		GrantTypesSupported:               []string{"authorization_code", "refresh_token"},
+		SubjectTypesSupported:             []string{"public"},
+		IdTokenSigningAlgValuesSupported:  algorithms,
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported:                   []string{"iss", "sub", "aud", "azp", "iat", "exp", "nonce", "name", "preferred_username", "locale", "updated_at", "email", "email_verified"},
+		AuthorizationResponseIssParameter: true,
+	}
+}
+
+func (s *oauthServerService) getClient(ctx context.Context, clientId string) (*models.OAuthClient, error) {
+	client, err := s.clientRepo.GetOne(ctx, clientId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrOAuthClientNotFound
+	}
+	return client, err
+}
+
+// authenticateClient checks the secret of confidential clients. Public
+// clients must not send one, they are bound to the code by PKCE alone.
+func (s *oauthServerService) authenticateClient(ctx context.Context, clientId, secret string) (*models.OAuthClient, error) {
+	invalid := oauthError(OAuthErrorInvalidClient, "client authentication failed")
+	if clientId == "" {
+		return nil, invalid
+	}
+	client, err := s.getClient(ctx, clientId)
+	if errors.Is(err, ErrOAuthClientNotFound) {
+		return nil, invalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if client.SecretHash == nil {
+		if secret != "" {
+			return nil, invalid
+		}
+		return client, nil
+	}
+	if secret == "" || subtle.ConstantTimeCompare([]byte(s.utils.HashWithSHA256(secret)), []byte(*client.SecretHash)) != 1 {
+		return nil, invalid
+	}
+	return client, nil
+}
+
+// activeUser returns the user tokens are issued for, refusing deleted and
+// locked accounts.
+func (s *oauthServerService) activeUser(ctx context.Context, userId uuid.UUID) (*models.User, error) {
+	user, err := s.userService.GetUserById(ctx, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, oauthError(OAuthErrorInvalidGrant, "the user no longer exists")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.LockedAt != nil {
+		return nil, oauthError(OAuthErrorInvalidGrant, "the account is locked")
+	}
+	return user, nil
+}
+
+// redirect appends values to uri, keeping the query it already has and
+// leaving out empty values.
+func (s *oauthServerService) redirect(uri string, values url.Values) string {
+	target, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	query := target.Query()
+	for key, value := range values {
+		if len(value) > 0 && value[0] != "" {
+			query.Set(key, value[0])
+		}
+	}
+	target.RawQuery = query.Encode()
+	return target.String()
+}
+
+// parseOAuthScope keeps the supported scopes of a scope parameter, in the
+// order they were requested.
+func parseOAuthScope(scope string) []string {
+	scopes := []string{}
+	for _, value := range strings.Fields(scope) {
+		if slices.Contains(oauthSupportedScopes, value) && !slices.Contains(scopes, value) {
+			scopes = append(scopes, value)
+		}
+	}
+	return scopes
+}
+
+// validRedirectUri accepts the redirect URIs of RFC 8252: https, http on a
+// loopback address for native apps, and private schemes in reverse domain
+// notation. Fragments are never allowed.
+func validRedirectUri(uri string) bool {
+	target, err := url.Parse(uri)
+	if err != nil || target.Fragment != "" || strings.Contains(uri, "#") {
+		return false
+	}
+	switch target.Scheme {
+	case "https":
+		return target.Host != ""
+	case "http":
+		host := target.Hostname()
+		ip := net.ParseIP(host)
+		return host == "localhost" || ip != nil && ip.IsLoopback()
+	case "":
+		return false
+	}
+	return strings.Contains(target.Scheme, ".")
+}
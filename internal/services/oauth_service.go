@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"my-go-api/internal/config"
+	"my-go-api/internal/utils"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+)
+
+var (
+	ErrInvalidOAuthState    = errors.New("invalid or expired oauth state")
+	ErrUnknownOAuthProvider = errors.New("unknown oauth provider")
+)
+
+// OAuthProfile is the provider independent view of the signed in account.
+type OAuthProfile struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// OAuthFlow is a started authorization-code flow. Binding goes into a cookie
+// of the browser that started it; the callback is only accepted together with
+// it, so an authorization response cannot be replayed in another browser.
+type OAuthFlow struct {
+	URL     string
+	Binding string
+}
+
+type OAuthExchangeResult struct {
+	Profile OAuthProfile
+	// LinkUserId is the user who started a link flow, uuid.Nil for sign in.
+	LinkUserId uuid.UUID
+}
+
+// IOAuthProvider is implemented by every social login provider. The oauth
+// service owns state, nonce and PKCE verifier; providers only build the
+// authorize URL and turn an authorization code into a profile.
+type IOAuthProvider interface {
+	Name() string
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	Exchange(ctx context.Context, code, nonce, verifier string) (OAuthProfile, error)
+}
+
+type IOAuthService interface {
+	Providers() []string
+	AuthCodeURL(ctx context.Context, provider string) (OAuthFlow, error)
+	LinkCodeURL(ctx context.Context, provider string, userId uuid.UUID) (OAuthFlow, error)
+	Exchange(ctx context.Context, provider, state, code, binding string) (OAuthExchangeResult, error)
+}
+
+type oauthService struct {
+	providers    map[string]IOAuthProvider
+	names        []string
+	redisService IRedisService
+	utils        utils.IUtils
+}
+
+func NewOAuthService(providers []IOAuthProvider, redisService IRedisService, utils utils.IUtils) IOAuthService {
+	s := &oauthService{
+		providers:    make(map[string]IOAuthProvider),
+		names:        []string{},
+		redisService: redisService,
+		utils:        utils,
+	}
+	for _, provider := range providers {
+		s.providers[provider.Name()] = provider
+		s.names = append(s.names, provider.Name())
+	}
+	return s
+}
+
+// NewOAuthProviders builds the providers described by the configuration.
+func NewOAuthProviders(cfgs []config.OAuthProviderConfig) ([]IOAuthProvider, error) {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	providers := []IOAuthProvider{}
+	for _, cfg := range cfgs {
+		switch cfg.Type {
+		case config.OAuthProviderTypeOIDC:
+			providers = append(providers, NewOIDCProvider(cfg, httpClient))
+		case config.OAuthProviderTypeGithub:
+			providers = append(providers, NewGithubProvider(cfg, httpClient))
+		default:
+			return nil, fmt.Errorf("unsupported oauth provider type %q", cfg.Type)
+		}
+	}
+	return providers, nil
+}
+
+func (s *oauthService) Providers() []string {
+	return s.names
+}
+
+// AuthCodeURL starts an authorization-code flow with PKCE. The state, nonce
+// and code verifier are kept in redis until the provider redirects back, the
+// state together with the hash of the browser binding.
+func (s *oauthService) AuthCodeURL(ctx context.Context, name string) (OAuthFlow, error) {
+	return s.startFlow(ctx, name, "")
+}
+
+// LinkCodeURL starts the same flow on behalf of a signed in user; the callback
+// then links the provider account to that user.
+func (s *oauthService) LinkCodeURL(ctx context.Context, name string, userId uuid.UUID) (OAuthFlow, error) {
+	return s.startFlow(ctx, name, userId.String())
+}
+
+func (s *oauthService) startFlow(ctx context.Context, name, linkUserId string) (OAuthFlow, error) {
+	provider, ok := s.providers[name]
+	if !ok {
+		return OAuthFlow{}, ErrUnknownOAuthProvider
+	}
+	state, err := s.utils.GenerateRandomBytes(16)
+	if err != nil {
+		return OAuthFlow{}, err
+	}
+	nonce, err := s.utils.GenerateRandomBytes(16)
+	if err != nil {
+		return OAuthFlow{}, err
+	}
+	binding, err := s.utils.GenerateRandomBytes(32)
+	if err != nil {
+		return OAuthFlow{}, err
+	}
+	verifier := oauth2.GenerateVerifier()
+	if err := s.redisService.SaveOAuthState(OAuthStateData{
+		State:        state,
+		Provider:     name,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		LinkUserId:   linkUserId,
+		BindingHash:  s.utils.HashWithSHA256(binding),
+	}); err != nil {
+		return OAuthFlow{}, err
+	}
+	url, err := provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return OAuthFlow{}, err
+	}
+	return OAuthFlow{URL: url, Binding: binding}, nil
+}
+
+// Exchange redeems the authorization code and returns the normalized profile.
+// The state is single use and must have been issued for the same provider and
+// to the browser presenting binding.
+func (s *oauthService) Exchange(ctx context.Context, name, state, code, binding string) (OAuthExchangeResult, error) {
+	provider, ok := s.providers[name]
+	if !ok {
+		return OAuthExchangeResult{}, ErrUnknownOAuthProvider
+	}
+	data, err := s.redisService.TakeOAuthState(state)
+	if err != nil || data.Provider != name {
+		return OAuthExchangeResult{}, ErrInvalidOAuthState
+	}
+	if binding == "" || subtle.ConstantTimeCompare([]byte(data.BindingHash), []byte(s.utils.HashWithSHA256(binding))) != 1 {
+		return OAuthExchangeResult{}, ErrInvalidOAuthState
+	}
+	result := OAuthExchangeResult{}
+	if data.LinkUserId != "" {
+		if result.LinkUserId, err = uuid.Parse(data.LinkUserId); err != nil {
+			return OAuthExchangeResult{}, ErrInvalidOAuthState
+		}
+	}
+	result.Profile, err = provider.Exchange(ctx, code, data.Nonce, data.CodeVerifier)
+	if err != nil {
+		return OAuthExchangeResult{}, err
+	}
+	return result, nil
+}
+
+func oauth2Config(cfg config.OAuthProviderConfig, endpoint oauth2.Endpoint) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     cfg.ClientId,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectUri,
+		Scopes:       cfg.Scopes,
+		Endpoint:     endpoint,
+	}
+}
@@ -6,6 +6,10 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// DummyPasswordHash is verified against when there is no password to check,
+// so that a failed sign in takes as long whether or not the account exists.
+const DummyPasswordHash = "$2a$10$Lf3EJWZzv3JNnpRbnnlAhuwAQSwpYkjZ0/cK2ttkM3sKcu7digLCm"
+
 type passwordService struct{}
 
 type IPasswordService interface {
@@ -4,7 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"my-go-api/internal/repositories"
+	"strconv"
 	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 )
 
 type redisService struct {
@@ -20,6 +24,10 @@ type IRedisService interface {
 	GetRefreshToken(hashedToken string) (RefreshTokenData, error)
 	SaveRefreshToken(params RefreshTokenData) error
 	DeleteRefreshToken(hashedToken string) error
+	// refresh token rotation
+	ClaimRefreshToken(hashedToken string, seed *RefreshTokenData) (RefreshTokenClaim, error)
+	CompleteRefreshTokenRotation(hashedToken string, params RotatedRefreshTokenData, gracePeriod time.Duration) error
+	ReleaseRefreshTokenClaim(hashedToken string) error
 	// verification token
 	SaveVerificationToken(params VerificationData) error
 	DeleteVerificationToken(hashedToken string) error
@@ -28,6 +36,31 @@ type IRedisService interface {
 	SavePasswordResetToken(params PasswordResetData) error
 	DeletePasswordResetToken(hashedToken string) error
 	GetPasswordResetToken(hashedToken string) (PasswordResetData, error)
+	// oauth state
+	SaveOAuthState(params OAuthStateData) error
+	TakeOAuthState(state string) (OAuthStateData, error)
+	// mfa challenge
+	SaveMfaChallenge(params MfaChallengeData) error
+	UseMfaChallengeAttempt(hashedToken string) (MfaChallengeData, error)
+	DeleteMfaChallenge(hashedToken string) error
+	// magic link
+	SaveMagicLink(params MagicLinkData) error
+	UseMagicLinkCode(hashedToken, code string) (string, error)
+	UseMagicLink(hashedLinkToken string) (string, error)
+	// webauthn ceremonies
+	SaveWebauthnSession(params WebauthnSessionData) error
+	TakeWebauthnSession(sessionId string) (WebauthnSessionData, error)
+	// authz check cache
+	GetAuthzRevision() (string, error)
+	BumpAuthzRevision() error
+	GetAuthzCheck(revision, check string) (bool, error)
+	SaveAuthzCheck(revision, check string, allowed bool) error
+	// login failures
+	GetLoginFailures(subject string) (LoginFailureData, error)
+	RecordLoginFailure(subject string, policy LoginFailurePolicy) (LoginFailureData, error)
+	ClearLoginFailures(subject string) error
+	// rate limits
+	TakeRateLimit(name, key string, limit int, window time.Duration) (RateLimitResult, error)
 }
 
 func NewRedisService(redisRepository repositories.IRedisRepository) IRedisService {
@@ -106,6 +139,8 @@ func (s *redisService) GetRefreshToken(hashedToken string) (RefreshTokenData, er
 		UserId:      strUserId,
 		Jti:         strJti,
 		HashedToken: hashedToken,
+		ClientId:    data["clientId"],
+		Scope:       data["scope"],
 	}, nil
 }
 
@@ -147,11 +182,15 @@ func (s *redisService) GetVerificationToken(hashedToken string) (VerificationDat
 
 func (s *redisService) SaveRefreshToken(params RefreshTokenData) error {
 	key := setRefreshTokenKey(params.HashedToken)
-	err := s.redisRepository.HSet(key, map[string]any{
+	fields := map[string]any{
 		"userId": params.UserId,
 		"jti":    params.Jti,
-	}, RefreshTokenTTL)
-	return err
+	}
+	if params.ClientId != "" {
+		fields["clientId"] = params.ClientId
+		fields["scope"] = params.Scope
+	}
+	return s.redisRepository.HSet(key, fields, RefreshTokenTTL)
 }
 
 func (s *redisService) SaveAccessToken(params AccessTokenData) error {
@@ -181,6 +220,499 @@ func (s *redisService) GetAccessToken(jti string) (AccessTokenData, error) {
 	}, nil
 }
 
+func (s *redisService) SaveOAuthState(params OAuthStateData) error {
+	key := setOAuthStateKey(params.State)
+	return s.redisRepository.HSet(key, map[string]any{
+		"provider":     params.Provider,
+		"codeVerifier": params.CodeVerifier,
+		"nonce":        params.Nonce,
+		"linkUserId":   params.LinkUserId,
+		"bindingHash":  params.BindingHash,
+	}, OAuthStateTTL)
+}
+
+// TakeOAuthState returns the state record and deletes it, so an authorization
+// response can only be redeemed once.
+func (s *redisService) TakeOAuthState(state string) (OAuthStateData, error) {
+	data, err := s.takeHash(setOAuthStateKey(state))
+	if err != nil {
+		return OAuthStateData{}, err
+	}
+	if len(data) == 0 {
+		return OAuthStateData{}, errors.New("oauth state not found")
+	}
+	return OAuthStateData{
+		State:        state,
+		Provider:     data["provider"],
+		CodeVerifier: data["codeVerifier"],
+		Nonce:        data["nonce"],
+		LinkUserId:   data["linkUserId"],
+		BindingHash:  data["bindingHash"],
+	}, nil
+}
+
+// claimRefreshTokenScript atomically consumes a refresh token record. The
+// first caller receives the record and leaves a pending marker behind; callers
+// arriving while the marker lives get the marker instead, which holds the new
+// token pair once the rotation completes. When the record is gone from redis
+// (e.g. after a flush) the caller may pass the record it loaded from postgres
+// as ARGV[2..] to claim it the same way.
+var claimRefreshTokenScript = redis.NewScript(`
+local rotated = redis.call('HGETALL', KEYS[2])
+if #rotated > 0 then
+	return {'rotated', unpack(rotated)}
+end
+local data = redis.call('HGETALL', KEYS[1])
+if #data == 0 then
+	if #ARGV < 2 then
+		return {'missing'}
+	end
+	data = {unpack(ARGV, 2)}
+end
+redis.call('DEL', KEYS[1])
+redis.call('HSET', KEYS[2], 'status', 'pending')
+redis.call('PEXPIRE', KEYS[2], ARGV[1])
+return {'claimed', unpack(data)}
+`)
+
+func (s *redisService) ClaimRefreshToken(hashedToken string, seed *RefreshTokenData) (RefreshTokenClaim, error) {
+	args := []any{RefreshTokenClaimTTL.Milliseconds()}
+	if seed != nil {
+		args = append(args, "userId", seed.UserId, "jti", seed.Jti)
+		if seed.ClientId != "" {
+			args = append(args, "clientId", seed.ClientId, "scope", seed.Scope)
+		}
+	}
+	result, err := s.redisRepository.EvalScript(
+		claimRefreshTokenScript,
+		[]string{setRefreshTokenKey(hashedToken), setRotatedRefreshTokenKey(hashedToken)},
+		args...,
+	)
+	if err != nil {
+		return RefreshTokenClaim{}, err
+	}
+	values, ok := result.([]any)
+	if !ok || len(values) == 0 {
+		return RefreshTokenClaim{}, errors.New("malformed claim result")
+	}
+	status, _ := values[0].(string)
+	fields := make(map[string]string)
+	for i := 1; i+1 < len(values); i += 2 {
+		key, _ := values[i].(string)
+		value, _ := values[i+1].(string)
+		fields[key] = value
+	}
+	switch status {
+	case "claimed":
+		return RefreshTokenClaim{
+			Status: RefreshTokenClaimed,
+			Data: RefreshTokenData{
+				HashedToken: hashedToken,
+				UserId:      fields["userId"],
+				Jti:         fields["jti"],
+				ClientId:    fields["clientId"],
+				Scope:       fields["scope"],
+			},
+		}, nil
+	case "rotated":
+		if fields["status"] != "done" {
+			return RefreshTokenClaim{Status: RefreshTokenRotationPending}, nil
+		}
+		return RefreshTokenClaim{
+			Status: RefreshTokenRotated,
+			Rotated: RotatedRefreshTokenData{
+				RefreshToken: fields["refreshToken"],
+				AccessToken:  fields["accessToken"],
+			},
+		}, nil
+	case "missing":
+		return RefreshTokenClaim{Status: RefreshTokenMissing}, nil
+	}
+	return RefreshTokenClaim{}, fmt.Errorf("unknown claim status: %s", status)
+}
+
+// CompleteRefreshTokenRotation publishes the new token pair on the rotation
+// marker for the grace period so concurrent requests presenting the old token
+// get the same pair. Callers pass the pair sealed; it is kept only for that
+// window.
+func (s *redisService) CompleteRefreshTokenRotation(hashedToken string, params RotatedRefreshTokenData, gracePeriod time.Duration) error {
+	key := setRotatedRefreshTokenKey(hashedToken)
+	if gracePeriod <= 0 {
+		return s.redisRepository.Delete(key)
+	}
+	return s.redisRepository.HSet(key, map[string]any{
+		"status":       "done",
+		"refreshToken": params.RefreshToken,
+		"accessToken":  params.AccessToken,
+	}, gracePeriod)
+}
+
+func (s *redisService) ReleaseRefreshTokenClaim(hashedToken string) error {
+	return s.redisRepository.Delete(setRotatedRefreshTokenKey(hashedToken))
+}
+
+func (s *redisService) SaveMfaChallenge(params MfaChallengeData) error {
+	key := setMfaChallengeKey(params.HashedToken)
+	return s.redisRepository.HSet(key, map[string]any{
+		"userId":   params.UserId,
+		"attempts": 0,
+	}, MfaChallengeTTL)
+}
+
+// useMfaChallengeAttemptScript counts one verification attempt against the
+// challenge and drops the challenge once the attempts are used up, so a
+// challenge token cannot be used to brute force the second factor.
+var useMfaChallengeAttemptScript = redis.NewScript(`
+local userId = redis.call('HGET', KEYS[1], 'userId')
+if not userId then
+	return {'missing'}
+end
+local attempts = redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+if attempts > tonumber(ARGV[1]) then
+	redis.call('DEL', KEYS[1])
+	return {'exhausted'}
+end
+return {'ok', userId}
+`)
+
+func (s *redisService) UseMfaChallengeAttempt(hashedToken string) (MfaChallengeData, error) {
+	result, err := s.redisRepository.EvalScript(
+		useMfaChallengeAttemptScript,
+		[]string{setMfaChallengeKey(hashedToken)},
+		MfaChallengeMaxAttempts,
+	)
+	if err != nil {
+		return MfaChallengeData{}, err
+	}
+	values, ok := result.([]any)
+	if !ok || len(values) == 0 {
+		return MfaChallengeData{}, errors.New("malformed mfa challenge result")
+	}
+	if status, _ := values[0].(string); status != "ok" || len(values) < 2 {
+		return MfaChallengeData{}, errors.New("mfa challenge not found")
+	}
+	userId, _ := values[1].(string)
+	return MfaChallengeData{HashedToken: hashedToken, UserId: userId}, nil
+}
+
+func (s *redisService) DeleteMfaChallenge(hashedToken string) error {
+	return s.redisRepository.Delete(setMfaChallengeKey(hashedToken))
+}
+
+func (s *redisService) SaveMagicLink(params MagicLinkData) error {
+	key := setMagicLinkKey(params.HashedToken)
+	linkKey := setMagicLinkUrlKey(params.HashedLinkToken)
+	if err := s.redisRepository.HSet(key, map[string]any{
+		"code":     params.Code,
+		"userId":   params.UserId,
+		"attempts": 0,
+		"linkKey":  linkKey,
+	}, MagicLinkTTL); err != nil {
+		return err
+	}
+	return s.redisRepository.HSet(linkKey, map[string]any{
+		"tokenKey": key,
+	}, MagicLinkTTL)
+}
+
+// useMagicLinkCodeScript checks a sign in code and consumes the request, and
+// its link, on success. Wrong codes count against the request, which is
+// dropped once the attempts are used up.
+var useMagicLinkCodeScript = redis.NewScript(`
+local userId = redis.call('HGET', KEYS[1], 'userId')
+if not userId then
+	return {'missing'}
+end
+local attempts = redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+local linkKey = redis.call('HGET', KEYS[1], 'linkKey')
+if redis.call('HGET', KEYS[1], 'code') == ARGV[1] then
+	redis.call('DEL', KEYS[1], linkKey)
+	return {'ok', userId}
+end
+if attempts >= tonumber(ARGV[2]) then
+	redis.call('DEL', KEYS[1], linkKey)
+	return {'missing'}
+end
+return {'invalid'}
+`)
+
+func (s *redisService) UseMagicLinkCode(hashedToken, code string) (string, error) {
+	result, err := s.redisRepository.EvalScript(
+		useMagicLinkCodeScript,
+		[]string{setMagicLinkKey(hashedToken)},
+		code,
+		MagicLinkMaxAttempts,
+	)
+	if err != nil {
+		return "", err
+	}
+	return parseMagicLinkResult(result)
+}
+
+// useMagicLinkScript consumes a request through its emailed link.
+var useMagicLinkScript = redis.NewScript(`
+local tokenKey = redis.call('HGET', KEYS[1], 'tokenKey')
+if not tokenKey then
+	return {'missing'}
+end
+local userId = redis.call('HGET', tokenKey, 'userId')
+redis.call('DEL', KEYS[1], tokenKey)
+if not userId then
+	return {'missing'}
+end
+return {'ok', userId}
+`)
+
+func (s *redisService) UseMagicLink(hashedLinkToken string) (string, error) {
+	result, err := s.redisRepository.EvalScript(
+		useMagicLinkScript,
+		[]string{setMagicLinkUrlKey(hashedLinkToken)},
+	)
+	if err != nil {
+		return "", err
+	}
+	return parseMagicLinkResult(result)
+}
+
+func parseMagicLinkResult(result any) (string, error) {
+	values, ok := result.([]any)
+	if !ok || len(values) == 0 {
+		return "", errors.New("malformed magic link result")
+	}
+	switch status, _ := values[0].(string); {
+	case status == "ok" && len(values) == 2:
+		userId, _ := values[1].(string)
+		return userId, nil
+	case status == "invalid":
+		return "", ErrInvalidMagicLinkCode
+	default:
+		return "", ErrInvalidMagicLink
+	}
+}
+
+func (s *redisService) SaveWebauthnSession(params WebauthnSessionData) error {
+	key := setWebauthnSessionKey(params.SessionId)
+	return s.redisRepository.HSet(key, map[string]any{
+		"ceremony": params.Ceremony,
+		"userId":   params.UserId,
+		"session":  params.Session,
+	}, WebauthnSessionTTL)
+}
+
+// TakeWebauthnSession returns the ceremony state and deletes it, so every
+// challenge can only be answered once.
+func (s *redisService) TakeWebauthnSession(sessionId string) (WebauthnSessionData, error) {
+	data, err := s.takeHash(setWebauthnSessionKey(sessionId))
+	if err != nil {
+		return WebauthnSessionData{}, err
+	}
+	if len(data) == 0 {
+		return WebauthnSessionData{}, errors.New("webauthn session not found")
+	}
+	return WebauthnSessionData{
+		SessionId: sessionId,
+		Ceremony:  data["ceremony"],
+		UserId:    data["userId"],
+		Session:   data["session"],
+	}, nil
+}
+
+// takeHashScript reads and deletes a hash in one step, so a single use record
+// is handed to exactly one of several concurrent callers.
+var takeHashScript = redis.NewScript(`
+local fields = redis.call('HGETALL', KEYS[1])
+redis.call('DEL', KEYS[1])
+return fields
+`)
+
+// takeHash returns the fields of a single use hash and deletes it; the map is
+// empty when the hash does not exist.
+func (s *redisService) takeHash(key string) (map[string]string, error) {
+	result, err := s.redisRepository.EvalScript(takeHashScript, []string{key})
+	if err != nil {
+		return nil, err
+	}
+	values, ok := result.([]any)
+	if !ok {
+		return nil, errors.New("malformed hash result")
+	}
+	data := make(map[string]string, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		field, _ := values[i].(string)
+		value, _ := values[i+1].(string)
+		data[field] = value
+	}
+	return data, nil
+}
+
+// GetAuthzRevision returns the revision of the relationship store. Cached
+// checks are keyed by it, so bumping it on every write drops them all.
+func (s *redisService) GetAuthzRevision() (string, error) {
+	revision, err := s.redisRepository.HGet(setAuthzRevisionKey(), "revision")
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "0", nil
+		}
+		return "", err
+	}
+	return revision, nil
+}
+
+var bumpAuthzRevisionScript = redis.NewScript(`
+return redis.call('HINCRBY', KEYS[1], 'revision', 1)
+`)
+
+func (s *redisService) BumpAuthzRevision() error {
+	_, err := s.redisRepository.EvalScript(bumpAuthzRevisionScript, []string{setAuthzRevisionKey()})
+	return err
+}
+
+func (s *redisService) GetAuthzCheck(revision, check string) (bool, error) {
+	allowed, err := s.redisRepository.HGet(setAuthzCheckKey(revision, check), "allowed")
+	if err != nil {
+		return false, err
+	}
+	return allowed == "1", nil
+}
+
+func (s *redisService) SaveAuthzCheck(revision, check string, allowed bool) error {
+	value := "0"
+	if allowed {
+		value = "1"
+	}
+	return s.redisRepository.HSet(setAuthzCheckKey(revision, check), map[string]any{
+		"allowed": value,
+	}, AuthzCheckTTL)
+}
+
+func (s *redisService) GetLoginFailures(subject string) (LoginFailureData, error) {
+	data, err := s.redisRepository.HGetAll(setLoginFailuresKey(subject))
+	if err != nil {
+		return LoginFailureData{}, err
+	}
+	count, _ := strconv.Atoi(data["count"])
+	retryAt, _ := strconv.ParseInt(data["retryAt"], 10, 64)
+	lockedUntil, _ := strconv.ParseInt(data["lockedUntil"], 10, 64)
+	return LoginFailureData{
+		Subject:     subject,
+		Count:       count,
+		RetryAt:     fromUnixMilli(retryAt),
+		LockedUntil: fromUnixMilli(lockedUntil),
+	}, nil
+}
+
+// recordLoginFailureScript counts a failed sign in and sets when the next
+// attempt is allowed: after an exponential back-off, or once the threshold is
+// reached after a lockout, which also starts the count over. Times are unix
+// milliseconds.
+var recordLoginFailureScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local count = redis.call('HINCRBY', KEYS[1], 'count', 1)
+local threshold = tonumber(ARGV[4])
+if threshold > 0 and count >= threshold then
+	local lockedUntil = now + tonumber(ARGV[5])
+	redis.call('HSET', KEYS[1], 'count', 0, 'retryAt', lockedUntil, 'lockedUntil', lockedUntil)
+	redis.call('PEXPIRE', KEYS[1], math.max(tonumber(ARGV[5]), tonumber(ARGV[6])))
+	return {count, lockedUntil, lockedUntil}
+end
+local retryAt = now + math.floor(math.min(tonumber(ARGV[2]) * 2 ^ (count - 1), tonumber(ARGV[3])))
+redis.call('HSET', KEYS[1], 'retryAt', retryAt)
+redis.call('PEXPIRE', KEYS[1], tonumber(ARGV[6]))
+return {count, retryAt, 0}
+`)
+
+func (s *redisService) RecordLoginFailure(subject string, policy LoginFailurePolicy) (LoginFailureData, error) {
+	result, err := s.redisRepository.EvalScript(
+		recordLoginFailureScript,
+		[]string{setLoginFailuresKey(subject)},
+		time.Now().UnixMilli(),
+		policy.BackoffBase.Milliseconds(),
+		policy.BackoffMax.Milliseconds(),
+		policy.Threshold,
+		policy.LockDuration.Milliseconds(),
+		LoginFailureTTL.Milliseconds(),
+	)
+	if err != nil {
+		return LoginFailureData{}, err
+	}
+	values, ok := result.([]any)
+	if !ok || len(values) != 3 {
+		return LoginFailureData{}, errors.New("malformed login failure result")
+	}
+	count, _ := values[0].(int64)
+	retryAt, _ := values[1].(int64)
+	lockedUntil, _ := values[2].(int64)
+	return LoginFailureData{
+		Subject:     subject,
+		Count:       int(count),
+		RetryAt:     fromUnixMilli(retryAt),
+		LockedUntil: fromUnixMilli(lockedUntil),
+	}, nil
+}
+
+func (s *redisService) ClearLoginFailures(subject string) error {
+	return s.redisRepository.Delete(setLoginFailuresKey(subject))
+}
+
+// takeRateLimitScript implements a sliding window log: the sorted set holds
+// one member per request of the last window, scored by its time in unix
+// milliseconds. A request is only recorded when it is allowed. The reply is
+// whether it was allowed, how many requests remain and in how many
+// milliseconds the oldest request leaves the window.
+var takeRateLimitScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], window)
+local reset = window
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, limit - count, reset}
+`)
+
+func (s *redisService) TakeRateLimit(name, key string, limit int, window time.Duration) (RateLimitResult, error) {
+	result, err := s.redisRepository.EvalScript(
+		takeRateLimitScript,
+		[]string{setRateLimitKey(name, key)},
+		time.Now().UnixMilli(),
+		window.Milliseconds(),
+		limit,
+		uuid.NewString(),
+	)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	values, ok := result.([]any)
+	if !ok || len(values) != 3 {
+		return RateLimitResult{}, errors.New("malformed rate limit result")
+	}
+	allowed, _ := values[0].(int64)
+	remaining, _ := values[1].(int64)
+	reset, _ := values[2].(int64)
+	return RateLimitResult{
+		Allowed:   allowed == 1,
+		Remaining: int(remaining),
+		Reset:     time.Duration(reset) * time.Millisecond,
+	}, nil
+}
+
+func fromUnixMilli(ms int64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
 // helpers
 
 func setPasswordResetKey(hashedToken string) string {
@@ -195,6 +727,46 @@ func setRefreshTokenKey(hashedToken string) string {
 	return fmt.Sprintf("refreshToken:%s", hashedToken)
 }
 
+func setRotatedRefreshTokenKey(hashedToken string) string {
+	return fmt.Sprintf("rotatedRefreshToken:%s", hashedToken)
+}
+
+func setMfaChallengeKey(hashedToken string) string {
+	return fmt.Sprintf("mfaChallenge:%s", hashedToken)
+}
+
+func setMagicLinkKey(hashedToken string) string {
+	return fmt.Sprintf("magicLink:%s", hashedToken)
+}
+
+func setMagicLinkUrlKey(hashedLinkToken string) string {
+	return fmt.Sprintf("magicLinkUrl:%s", hashedLinkToken)
+}
+
+func setWebauthnSessionKey(sessionId string) string {
+	return fmt.Sprintf("webauthnSession:%s", sessionId)
+}
+
+func setOAuthStateKey(state string) string {
+	return fmt.Sprintf("oauthState:%s", state)
+}
+
+func setAuthzRevisionKey() string {
+	return "authzRevision"
+}
+
+func setAuthzCheckKey(revision, check string) string {
+	return fmt.Sprintf("authzCheck:%s:%s", revision, check)
+}
+
+func setLoginFailuresKey(subject string) string {
+	return fmt.Sprintf("loginFailures:%s", subject)
+}
+
+func setRateLimitKey(name, key string) string {
+	return fmt.Sprintf("rateLimit:%s:%s", name, key)
+}
+
 func setVerificationKey(hashedToken string) string {
 	return fmt.Sprintf("accountVerification:%s", hashedToken)
 }
@@ -203,6 +775,29 @@ type RefreshTokenData struct {
 	HashedToken string
 	UserId      string
 	Jti         string
+	// ClientId and Scope are set for refresh tokens of OAuth clients.
+	ClientId string
+	Scope    string
+}
+
+type RefreshTokenClaimStatus int
+
+const (
+	RefreshTokenClaimed RefreshTokenClaimStatus = iota
+	RefreshTokenRotated
+	RefreshTokenRotationPending
+	RefreshTokenMissing
+)
+
+type RefreshTokenClaim struct {
+	Status  RefreshTokenClaimStatus
+	Data    RefreshTokenData
+	Rotated RotatedRefreshTokenData
+}
+
+type RotatedRefreshTokenData struct {
+	RefreshToken string
+	AccessToken  string
 }
 
 type AccessTokenData struct {
@@ -217,6 +812,65 @@ type VerificationData struct {
 	HashedToken string
 }
 
+type MfaChallengeData struct {
+	HashedToken string
+	UserId      string
+}
+
+type MagicLinkData struct {
+	HashedToken     string
+	HashedLinkToken string
+	Code            string
+	UserId          string
+}
+
+// WebauthnSessionData holds a pending WebAuthn ceremony. Session is the
+// serialized webauthn.SessionData carrying the challenge.
+type WebauthnSessionData struct {
+	SessionId string
+	Ceremony  string
+	UserId    string
+	Session   string
+}
+
+type OAuthStateData struct {
+	State        string
+	Provider     string
+	CodeVerifier string
+	Nonce        string
+	// LinkUserId is set when a signed in user links the provider to their
+	// account instead of signing in with it.
+	LinkUserId string
+	// BindingHash is the hash of the cookie the flow is bound to.
+	BindingHash string
+}
+
+// LoginFailureData tracks failed sign ins of one subject, an account or an IP
+// address. Zero times mean no wait.
+type LoginFailureData struct {
+	Subject     string
+	Count       int
+	RetryAt     time.Time
+	LockedUntil time.Time
+}
+
+// LoginFailurePolicy says how a failed sign in is penalised. A zero threshold
+// never locks, a zero back-off never delays.
+type LoginFailurePolicy struct {
+	Threshold    int
+	LockDuration time.Duration
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+}
+
+// RateLimitResult is the outcome of counting one request. Reset is how long
+// until the window has room for another request.
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	Reset     time.Duration
+}
+
 type PasswordResetData struct {
 	HashedToken string
 	UserId      string
@@ -227,4 +881,21 @@ var (
 	RefreshTokenTTL       = 24 * 7 * time.Hour
 	VerificationTokenTTL  = 30 * time.Minute
 	PasswordResetTokenTTL = 30 * time.Minute
+	RefreshTokenClaimTTL  = 5 * time.Second
+	OAuthStateTTL         = 10 * time.Minute
+	MfaChallengeTTL       = 5 * time.Minute
+	WebauthnSessionTTL    = 5 * time.Minute
+	MagicLinkTTL          = 15 * time.Minute
+	AuthzCheckTTL         = 1 * time.Minute
+	// LoginFailureTTL is how long failed sign ins are remembered after the
+	// last one.
+	LoginFailureTTL = 1 * time.Hour
 )
+
+// MfaChallengeMaxAttempts is how many codes may be tried against a single
+// mfa challenge before the user has to sign in with the password again.
+const MfaChallengeMaxAttempts = 5
+
+// MagicLinkMaxAttempts is how many codes may be tried against a single magic
+// link request before it is dropped.
+const MagicLinkMaxAttempts = 5
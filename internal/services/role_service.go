@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrRoleNotFound      = errors.New("role not found")
+	ErrRoleExists        = errors.New("a role with this name already exists")
+	ErrSystemRole        = errors.New("built in roles cannot be changed")
+	ErrUnknownRole       = errors.New("unknown role")
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrRoleEscalation    = errors.New("cannot grant a role or permission you do not hold")
+)
+
+type IRoleService interface {
+	GetRoles(ctx context.Context) ([]models.Role, error)
+	GetPermissions(ctx context.Context) ([]models.Permission, error)
+	CreateRole(ctx context.Context, actorId uuid.UUID, params repositories.CreateRoleParams) (*models.Role, error)
+	SetRolePermissions(ctx context.Context, actorId uuid.UUID, id int, permissions []string) (*models.Role, error)
+	DeleteRole(ctx context.Context, id int) error
+	GetUserRoles(ctx context.Context, userId uuid.UUID) ([]models.Role, error)
+	SetUserRoles(ctx context.Context, actorId, userId uuid.UUID, roles []string) ([]models.Role, error)
+	// CheckRoleChange returns ErrRoleEscalation unless actorId holds every
+	// permission of the roles in granted, and roles:admin when the admin role
+	// is granted or revoked.
+	CheckRoleChange(ctx context.Context, actorId uuid.UUID, granted, revoked []string) error
+	GetUserPermissions(ctx context.Context, userId uuid.UUID) ([]string, error)
+	ExpireAccessTokens(ctx context.Context, userId uuid.UUID)
+}
+
+type roleService struct {
+	roleRepo       repositories.IRoleRepository
+	sessionService ISessionService
+	redisService   IRedisService
+}
+
+// NewRoleService creates the service behind roles and permissions. Access
+// tokens carry the permissions of their user, see IAuthService.CreateAuthTokens.
+func NewRoleService(roleRepo repositories.IRoleRepository, sessionService ISessionService, redisService IRedisService) IRoleService {
+	return &roleService{
+		roleRepo:       roleRepo,
+		sessionService: sessionService,
+		redisService:   redisService,
+	}
+}
+
+func (s *roleService) GetRoles(ctx context.Context) ([]models.Role, error) {
+	return s.roleRepo.GetAll(ctx)
+}
+
+func (s *roleService) GetPermissions(ctx context.Context) ([]models.Permission, error) {
+	return s.roleRepo.GetPermissions(ctx)
+}
+
+// CreateRole creates a custom role out of permissions actorId holds.
+func (s *roleService) CreateRole(ctx context.Context, actorId uuid.UUID, params repositories.CreateRoleParams) (*models.Role, error) {
+	roles, err := s.roleRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if slices.ContainsFunc(roles, func(role models.Role) bool { return role.Name == params.Name }) {
+		return nil, ErrRoleExists
+	}
+	if err := s.checkPermissions(ctx, params.Permissions); err != nil {
+		return nil, err
+	}
+	if err := s.checkGrant(ctx, actorId, params.Permissions); err != nil {
+		return nil, err
+	}
+	return s.roleRepo.CreateOne(ctx, params)
+}
+
+// SetRolePermissions replaces the permissions of a custom role. Permissions
+// the role does not have yet can only be added by an actor holding them. The
+// access tokens of the users holding the role are expired, so removed
+// permissions stop working at once.
+func (s *roleService) SetRolePermissions(ctx context.Context, actorId uuid.UUID, id int, permissions []string) (*models.Role, error) {
+	role, err := s.getCustomRole(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkPermissions(ctx, permissions); err != nil {
+		return nil, err
+	}
+	added := []string{}
+	for _, permission := range permissions {
+		if !slices.Contains(role.Permissions, permission) {
+			added = append(added, permission)
+		}
+	}
+	if err := s.checkGrant(ctx, actorId, added); err != nil {
+		return nil, err
+	}
+	holders, err := s.roleRepo.GetRoleUserIds(ctx, role.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.roleRepo.SetPermissions(ctx, role.ID, permissions); err != nil {
+		return nil, err
+	}
+	s.expireHolders(ctx, holders)
+	return s.roleRepo.GetOne(ctx, role.ID)
+}
+
+// DeleteRole deletes a custom role and expires the access tokens of the users
+// who held it.
+func (s *roleService) DeleteRole(ctx context.Context, id int) error {
+	role, err := s.getCustomRole(ctx, id)
+	if err != nil {
+		return err
+	}
+	holders, err := s.roleRepo.GetRoleUserIds(ctx, role.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.roleRepo.DeleteOne(ctx, role.ID); err != nil {
+		return err
+	}
+	s.expireHolders(ctx, holders)
+	return nil
+}
+
+func (s *roleService) expireHolders(ctx context.Context, userIds []uuid.UUID) {
+	for _, userId := range userIds {
+		s.ExpireAccessTokens(ctx, userId)
+	}
+}
+
+func (s *roleService) GetUserRoles(ctx context.Context, userId uuid.UUID) ([]models.Role, error) {
+	return s.roleRepo.GetUserRoles(ctx, userId)
+}
+
+// SetUserRoles replaces the roles assigned to the user and expires its access
+// tokens so the new permissions apply from the next refresh. The roles added
+// and removed are checked with CheckRoleChange.
+func (s *roleService) SetUserRoles(ctx context.Context, actorId, userId uuid.UUID, roles []string) ([]models.Role, error) {
+	existing, err := s.roleRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range roles {
+		if !slices.ContainsFunc(existing, func(role models.Role) bool { return role.Name == name }) {
+			return nil, ErrUnknownRole
+		}
+	}
+	assigned, err := s.roleRepo.GetUserRoles(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	granted, revoked := []string{}, []string{}
+	for _, name := range roles {
+		if !slices.ContainsFunc(assigned, func(role models.Role) bool { return role.Name == name }) {
+			granted = append(granted, name)
+		}
+	}
+	for _, role := range assigned {
+		if !slices.Contains(roles, role.Name) {
+			revoked = append(revoked, role.Name)
+		}
+	}
+	if err := s.checkRoleChange(ctx, actorId, existing, granted, revoked); err != nil {
+		return nil, err
+	}
+	if err := s.roleRepo.SetUserRoles(ctx, userId, roles); err != nil {
+		return nil, err
+	}
+	s.ExpireAccessTokens(ctx, userId)
+	return s.roleRepo.GetUserRoles(ctx, userId)
+}
+
+func (s *roleService) CheckRoleChange(ctx context.Context, actorId uuid.UUID, granted, revoked []string) error {
+	roles, err := s.roleRepo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	return s.checkRoleChange(ctx, actorId, roles, granted, revoked)
+}
+
+func (s *roleService) checkRoleChange(ctx context.Context, actorId uuid.UUID, roles []models.Role, granted, revoked []string) error {
+	permissions := []string{}
+	if slices.Contains(granted, models.RoleAdmin) || slices.Contains(revoked, models.RoleAdmin) {
+		permissions = append(permissions, models.PermissionRolesAdmin)
+	}
+	for _, role := range roles {
+		if slices.Contains(granted, role.Name) {
+			permissions = append(permissions, role.Permissions...)
+		}
+	}
+	return s.checkGrant(ctx, actorId, permissions)
+}
+
+// checkGrant makes sure actorId holds every permission it hands out, so
+// nobody can give themselves or anyone else more than they have.
+func (s *roleService) checkGrant(ctx context.Context, actorId uuid.UUID, permissions []string) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+	held, err := s.roleRepo.GetUserPermissions(ctx, actorId)
+	if err != nil {
+		return err
+	}
+	for _, permission := range permissions {
+		if !slices.Contains(held, permission) {
+			return ErrRoleEscalation
+		}
+	}
+	return nil
+}
+
+func (s *roleService) GetUserPermissions(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	return s.roleRepo.GetUserPermissions(ctx, userId)
+}
+
+// ExpireAccessTokens drops the access tokens of every active session of the
+// user. Refresh tokens stay valid, so clients refresh and receive a token with
+// the current permissions instead of being signed out.
+func (s *roleService) ExpireAccessTokens(ctx context.Context, userId uuid.UUID) {
+	sessions, err := s.sessionService.GetActiveSessions(ctx, userId)
+	if err != nil {
+		log.Printf("failed to load sessions: %s", err.Error())
+		return
+	}
+	for _, session := range sessions {
+		if err := s.redisService.DeleteAccessToken(session.Jti.String()); err != nil {
+			log.Printf("failed to delete access token: %s", err.Error())
+		}
+	}
+}
+
+func (s *roleService) getCustomRole(ctx context.Context, id int) (*models.Role, error) {
+	role, err := s.roleRepo.GetOne(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+	if role.IsSystem {
+		return nil, ErrSystemRole
+	}
+	return role, nil
+}
+
+func (s *roleService) checkPermissions(ctx context.Context, permissions []string) error {
+	known, err := s.roleRepo.GetPermissions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, name := range permissions {
+		if !slices.ContainsFunc(known, func(permission models.Permission) bool { return permission.Name == name }) {
+			return ErrUnknownPermission
+		}
+	}
+	return nil
+}
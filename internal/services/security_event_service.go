@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+const (
+	SecurityEventRefreshTokenReuse = "refresh_token_reuse"
+	SecurityEventAccountLocked     = "account_locked"
+)
+
+type SecurityEvent struct {
+	Type       string            `json:"type"`
+	UserId     string            `json:"user_id,omitempty"`
+	DeviceId   string            `json:"device_id,omitempty"`
+	IpAddress  string            `json:"ip_address,omitempty"`
+	UserAgent  string            `json:"user_agent,omitempty"`
+	Details    map[string]string `json:"details,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+type ISecurityEventService interface {
+	Emit(ctx context.Context, event SecurityEvent)
+}
+
+type securityEventService struct{}
+
+func NewSecurityEventService() ISecurityEventService {
+	return &securityEventService{}
+}
+
+// Emit writes the event as a single JSON line so it can be picked up by log
+// based alerting.
+func (s *securityEventService) Emit(ctx context.Context, event SecurityEvent) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("failed to encode security event: %s", err.Error())
+		return
+	}
+	log.Printf("security event: %s", payload)
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type ISessionService interface {
+	CreateSession(ctx context.Context, params repositories.CreateSessionParams) (*models.Token, error)
+	RotateSession(ctx context.Context, oldHash string, params repositories.CreateSessionParams) (*models.Token, error)
+	GetSessionByHash(ctx context.Context, hash string) (*models.Token, error)
+	GetActiveSessionByHash(ctx context.Context, hash string) (*models.Token, error)
+	GetActiveSessions(ctx context.Context, userId uuid.UUID) ([]models.Token, error)
+	RevokeSession(ctx context.Context, hash string) error
+	RevokeSessionById(ctx context.Context, userId uuid.UUID, id int) (*models.Token, error)
+	RevokeDeviceSessions(ctx context.Context, userId, deviceId uuid.UUID) ([]models.Token, error)
+	RevokeOtherSessions(ctx context.Context, userId, currentJti uuid.UUID) ([]models.Token, error)
+	RevokeTokenFamily(ctx context.Context, familyId uuid.UUID) ([]models.Token, error)
+	RevokeClientSessions(ctx context.Context, clientId string) ([]models.Token, error)
+}
+
+type sessionService struct {
+	sessionRepo repositories.ISessionRepository
+}
+
+func NewSessionService(sessionRepo repositories.ISessionRepository) ISessionService {
+	return &sessionService{sessionRepo: sessionRepo}
+}
+
+func (s *sessionService) CreateSession(ctx context.Context, params repositories.CreateSessionParams) (*models.Token, error) {
+	return s.sessionRepo.CreateOne(ctx, params)
+}
+
+func (s *sessionService) RotateSession(ctx context.Context, oldHash string, params repositories.CreateSessionParams) (*models.Token, error) {
+	return s.sessionRepo.Rotate(ctx, oldHash, params)
+}
+
+func (s *sessionService) GetSessionByHash(ctx context.Context, hash string) (*models.Token, error) {
+	return s.sessionRepo.GetByHash(ctx, hash)
+}
+
+func (s *sessionService) GetActiveSessionByHash(ctx context.Context, hash string) (*models.Token, error) {
+	return s.sessionRepo.GetActiveByHash(ctx, hash)
+}
+
+func (s *sessionService) GetActiveSessions(ctx context.Context, userId uuid.UUID) ([]models.Token, error) {
+	return s.sessionRepo.GetActiveByUserId(ctx, userId)
+}
+
+func (s *sessionService) RevokeSession(ctx context.Context, hash string) error {
+	return s.sessionRepo.RevokeByHash(ctx, hash)
+}
+
+func (s *sessionService) RevokeDeviceSessions(ctx context.Context, userId, deviceId uuid.UUID) ([]models.Token, error) {
+	return s.sessionRepo.RevokeByUserDevice(ctx, userId, deviceId)
+}
+
+func (s *sessionService) RevokeSessionById(ctx context.Context, userId uuid.UUID, id int) (*models.Token, error) {
+	return s.sessionRepo.RevokeById(ctx, userId, id)
+}
+
+func (s *sessionService) RevokeOtherSessions(ctx context.Context, userId, currentJti uuid.UUID) ([]models.Token, error) {
+	return s.sessionRepo.RevokeAllExcept(ctx, userId, currentJti)
+}
+
+func (s *sessionService) RevokeTokenFamily(ctx context.Context, familyId uuid.UUID) ([]models.Token, error) {
+	return s.sessionRepo.RevokeFamily(ctx, familyId)
+}
+
+func (s *sessionService) RevokeClientSessions(ctx context.Context, clientId string) ([]models.Token, error) {
+	return s.sessionRepo.RevokeByClient(ctx, clientId)
+}
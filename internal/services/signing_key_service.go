@@ -0,0 +1,390 @@
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"my-go-api/internal/config"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"sync"
+	"time"
+)
+
+var (
+	ErrSigningKeyNotFound    = errors.New("signing key not found")
+	ErrSigningKeyActive      = errors.New("the active signing key cannot be retired")
+	ErrSigningKeyRetired     = errors.New("retired signing keys cannot be used again")
+	ErrSigningKeyUnpublished = errors.New("the signing key has not been published long enough to be promoted")
+	ErrSigningKeyDraining    = errors.New("tokens signed with the key may not have expired yet")
+)
+
+// keyringRefreshBackoff keeps tokens with made up kids from querying the
+// keyring on every request.
+const keyringRefreshBackoff = 5 * time.Second
+
+// ISigningKeyService is the keyring stored in the signing_keys table. A new
+// key is introduced as verify only, promoted to active once verifiers had the
+// time to fetch it, and the key it replaced is retired once the tokens it
+// signed expired, so no valid token is ever rejected.
+type ISigningKeyService interface {
+	IJwtKeyring
+	// Start keeps the keyring in sync and, with a rotation interval, rotates
+	// it until ctx is done.
+	Start(ctx context.Context)
+	Refresh(ctx context.Context) error
+	// Rotate takes the step of the scheduled rotation that is due, if any.
+	Rotate(ctx context.Context) error
+	List(ctx context.Context) ([]models.SigningKey, error)
+	// Introduce adds a verify only key, of the configured algorithm when
+	// algorithm is empty.
+	Introduce(ctx context.Context, algorithm string) (*models.SigningKey, error)
+	// Promote makes kid the active key. Without force a new key has to be
+	// published for the publish delay first.
+	Promote(ctx context.Context, kid string, force bool) (*models.SigningKey, error)
+	// Retire stops accepting tokens signed with kid. Without force the tokens
+	// it signed have to drain first.
+	Retire(ctx context.Context, kid string, force bool) (*models.SigningKey, error)
+}
+
+type signingKeyService struct {
+	config         config.JwtConfig
+	signingKeyRepo repositories.ISigningKeyRepository
+	cipherKey      [32]byte
+	legacyId       string
+
+	mu          sync.RWMutex
+	state       *jwtKeyringState
+	refreshedAt time.Time
+}
+
+// NewSigningKeyService loads the keyring, seeding an empty one with the
+// configured key. Private keys are sealed with AES-GCM under a key derived
+// from cfg.KeyEncryptionKey.
+func NewSigningKeyService(ctx context.Context, cfg config.JwtConfig, signingKeyRepo repositories.ISigningKeyRepository) (ISigningKeyService, error) {
+	s := &signingKeyService{
+		config:         cfg,
+		signingKeyRepo: signingKeyRepo,
+		cipherKey:      sha256.Sum256([]byte(cfg.KeyEncryptionKey)),
+	}
+	key, material, err := configuredJwtKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s.legacyId = key.Id
+	sealed, err := s.seal(material)
+	if err != nil {
+		return nil, err
+	}
+	if err := signingKeyRepo.Bootstrap(ctx, repositories.CreateSigningKeyParams{
+		Id:         key.Id,
+		Algorithm:  key.Method.Alg(),
+		PrivateKey: sealed,
+	}); err != nil {
+		return nil, err
+	}
+	if err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *signingKeyService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.config.KeyringRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if s.config.RotationInterval > 0 {
+				if err := s.Rotate(ctx); err != nil && ctx.Err() == nil {
+					log.Printf("signing keys: rotation failed: %v", err)
+				}
+			}
+			if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("signing keys: refresh failed: %v", err)
+			}
+		}
+	}()
+}
+
+func (s *signingKeyService) Refresh(ctx context.Context) error {
+	keys, err := s.signingKeyRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	state := newJwtKeyringState(s.legacyId)
+	for _, stored := range keys {
+		material, err := s.open(stored.PrivateKey)
+		if err != nil {
+			return fmt.Errorf("signing key %s: %w", stored.ID, err)
+		}
+		key, err := parseJwtKey(stored.ID, stored.Algorithm, material)
+		if err != nil {
+			return fmt.Errorf("signing key %s: %w", stored.ID, err)
+		}
+		if err := state.add(key, stored.Status == models.SigningKeyActive); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	s.state = state
+	s.refreshedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *signingKeyService) current() *jwtKeyringState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+func (s *signingKeyService) SigningKey() (*JwtKey, error) {
+	return s.current().SigningKey()
+}
+
+// VerifyingKey reloads the keyring once for an unknown kid, since another
+// instance may have introduced the key since the last refresh.
+func (s *signingKeyService) VerifyingKey(kid string) (*JwtKey, error) {
+	key, err := s.current().VerifyingKey(kid)
+	if !errors.Is(err, ErrUnknownSigningKey) {
+		return key, err
+	}
+	s.mu.RLock()
+	stale := time.Since(s.refreshedAt) > keyringRefreshBackoff
+	s.mu.RUnlock()
+	if !stale {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s.current().VerifyingKey(kid)
+}
+
+func (s *signingKeyService) JWKS() JSONWebKeySet {
+	return s.current().JWKS()
+}
+
+func (s *signingKeyService) List(ctx context.Context) ([]models.SigningKey, error) {
+	return s.signingKeyRepo.ListAll(ctx)
+}
+
+func (s *signingKeyService) Introduce(ctx context.Context, algorithm string) (*models.SigningKey, error) {
+	if algorithm == "" {
+		algorithm = s.config.Algorithm
+	}
+	material, err := generateJwtKey(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parseJwtKey("", algorithm, material)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.seal(material)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.signingKeyRepo.Create(ctx, repositories.CreateSigningKeyParams{
+		Id:         key.Id,
+		Algorithm:  algorithm,
+		PrivateKey: sealed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stored, s.Refresh(ctx)
+}
+
+func (s *signingKeyService) Promote(ctx context.Context, kid string, force bool) (*models.SigningKey, error) {
+	key, err := s.getKey(ctx, kid)
+	if err != nil {
+		return nil, err
+	}
+	switch key.Status {
+	case models.SigningKeyActive:
+		return key, nil
+	case models.SigningKeyRetired:
+		return nil, ErrSigningKeyRetired
+	}
+	// a key that was active before has been published all along
+	if !force && key.ActivatedAt == nil {
+		createdAt, err := parseDbTime(key.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if time.Since(createdAt) < s.config.PublishDelay {
+			return nil, ErrSigningKeyUnpublished
+		}
+	}
+	if key, err = s.signingKeyRepo.Promote(ctx, kid); err != nil {
+		return nil, err
+	}
+	return key, s.Refresh(ctx)
+}
+
+func (s *signingKeyService) Retire(ctx context.Context, kid string, force bool) (*models.SigningKey, error) {
+	key, err := s.getKey(ctx, kid)
+	if err != nil {
+		return nil, err
+	}
+	switch key.Status {
+	case models.SigningKeyActive:
+		return nil, ErrSigningKeyActive
+	case models.SigningKeyRetired:
+		return nil, ErrSigningKeyRetired
+	}
+	if !force && key.DeactivatedAt != nil {
+		deactivatedAt, err := parseDbTime(*key.DeactivatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if time.Since(deactivatedAt) < s.config.DrainPeriod {
+			return nil, ErrSigningKeyDraining
+		}
+	}
+	if key, err = s.signingKeyRepo.Retire(ctx, kid); err != nil {
+		return nil, err
+	}
+	return key, s.Refresh(ctx)
+}
+
+func (s *signingKeyService) getKey(ctx context.Context, kid string) (*models.SigningKey, error) {
+	key, err := s.signingKeyRepo.GetOne(ctx, kid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSigningKeyNotFound
+	}
+	return key, err
+}
+
+// Rotate introduces a key PublishDelay before the active key is
+// RotationInterval old, promotes it when it is, and retires drained keys as
+// well as keys that were passed over by a promotion. Instances rotating at the
+// same time may introduce a key each; the extra ones are passed over.
+func (s *signingKeyService) Rotate(ctx context.Context) error {
+	if s.config.RotationInterval <= 0 {
+		return nil
+	}
+	keys, err := s.signingKeyRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	var active *models.SigningKey
+	for i := range keys {
+		if keys[i].Status == models.SigningKeyActive {
+			active = &keys[i]
+		}
+	}
+	if active == nil || active.ActivatedAt == nil {
+		return nil
+	}
+	activatedAt, err := parseDbTime(*active.ActivatedAt)
+	if err != nil {
+		return err
+	}
+
+	var next *models.SigningKey
+	var nextCreatedAt time.Time
+	for i := range keys {
+		key := &keys[i]
+		if key.Status != models.SigningKeyVerify {
+			continue
+		}
+		// a key whose timestamps cannot be read is left alone rather than
+		// treated as old enough to retire or promote
+		createdAt, err := parseDbTime(key.CreatedAt)
+		if err != nil {
+			log.Printf("skipping signing key %s: %s", key.ID, err.Error())
+			continue
+		}
+		drained := false
+		if key.DeactivatedAt != nil {
+			deactivatedAt, err := parseDbTime(*key.DeactivatedAt)
+			if err != nil {
+				log.Printf("skipping signing key %s: %s", key.ID, err.Error())
+				continue
+			}
+			drained = time.Since(deactivatedAt) >= s.config.DrainPeriod
+		}
+		passedOver := key.ActivatedAt == nil && createdAt.Before(activatedAt)
+		switch {
+		case drained || passedOver:
+			if _, err := s.signingKeyRepo.Retire(ctx, key.ID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+		case key.ActivatedAt == nil:
+			next = key
+			nextCreatedAt = createdAt
+		}
+	}
+
+	switch {
+	case next != nil && time.Since(nextCreatedAt) >= s.config.PublishDelay:
+		if _, err := s.signingKeyRepo.Promote(ctx, next.ID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	case next == nil && time.Since(activatedAt) >= s.config.RotationInterval-s.config.PublishDelay:
+		if _, err := s.Introduce(ctx, ""); err != nil {
+			return err
+		}
+	}
+	return s.Refresh(ctx)
+}
+
+func (s *signingKeyService) seal(material []byte) (string, error) {
+	gcm, err := s.gcm()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, material, nil)), nil
+}
+
+func (s *signingKeyService) open(sealed string) ([]byte, error) {
+	gcm, err := s.gcm()
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("malformed private key")
+	}
+	return gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+}
+
+func (s *signingKeyService) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.cipherKey[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// parseDbTime parses a timestamp column scanned into a string.
+func parseDbTime(value string) (time.Time, error) {
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed timestamp %q: %w", value, err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// TOTP parameters from RFC 6238 as understood by every authenticator app.
+const (
+	TotpDigits = 6
+	TotpPeriod = 30 * time.Second
+	// TotpSkew is the number of steps accepted on either side of the current
+	// one to tolerate clock drift between server and phone.
+	TotpSkew = 1
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TotpStep returns the RFC 6238 time step for t.
+func TotpStep(t time.Time) int64 {
+	return t.Unix() / int64(TotpPeriod/time.Second)
+}
+
+// GenerateTotpCode computes the HOTP value (RFC 4226) of secret for step.
+func GenerateTotpCode(secret []byte, step int64) string {
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for range TotpDigits {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", TotpDigits, value%mod)
+}
+
+// matchTotpCode returns the step within the allowed skew whose code equals
+// code, or false when none matches.
+func matchTotpCode(secret []byte, code string, now time.Time) (int64, bool) {
+	current := TotpStep(now)
+	for step := current - TotpSkew; step <= current+TotpSkew; step++ {
+		if hmac.Equal([]byte(GenerateTotpCode(secret, step)), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// totpURI builds the otpauth:// URI understood by authenticator apps.
+func totpURI(issuer, account string, secret []byte) string {
+	query := url.Values{}
+	query.Set("secret", totpEncoding.EncodeToString(secret))
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(TotpDigits))
+	query.Set("period", fmt.Sprint(int(TotpPeriod/time.Second)))
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: query.Encode(),
+	}).String()
+}
@@ -2,6 +2,9 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"my-go-api/internal/models"
 	"my-go-api/internal/repositories"
@@ -10,14 +13,24 @@ import (
 	"github.com/google/uuid"
 )
 
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
 type IUserService interface {
 	UpdateUser(ctx context.Context, user *models.User) (*models.User, error)
 	Store(ctx context.Context, params repositories.CreateOneParams) (*models.User, error)
 	GetUserById(ctx context.Context, userId uuid.UUID) (*models.User, error)
-	GetAllUsers(ctx context.Context) ([]models.User, error)
+	ListUsers(ctx context.Context, params repositories.ListUsersParams, cursor string) (*UserPage, error)
 	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	GetUserByIdentity(ctx context.Context, identity string) (*models.User, error)
+	GetUserByIdWithDeleted(ctx context.Context, userId uuid.UUID) (*models.User, error)
+	SetLocked(ctx context.Context, userId uuid.UUID, locked bool, reason string) (*models.User, error)
+	SetDeleted(ctx context.Context, userId uuid.UUID, deleted bool) (*models.User, error)
 }
 
 type userService struct {
@@ -44,6 +57,20 @@ func (s *userService) GetUserById(ctx context.Context, userId uuid.UUID) (*model
 	return user, nil
 }
 
+// GetUserByIdWithDeleted also finds soft deleted users, which every other
+// lookup leaves out.
+func (s *userService) GetUserByIdWithDeleted(ctx context.Context, userId uuid.UUID) (*models.User, error) {
+	return s.userRepo.GetOne(ctx, repositories.GetOneParams{Id: &userId, WithDeleted: true})
+}
+
+func (s *userService) SetLocked(ctx context.Context, userId uuid.UUID, locked bool, reason string) (*models.User, error) {
+	return s.userRepo.SetLocked(ctx, userId, locked, reason)
+}
+
+func (s *userService) SetDeleted(ctx context.Context, userId uuid.UUID, deleted bool) (*models.User, error) {
+	return s.userRepo.SetDeleted(ctx, userId, deleted)
+}
+
 func (s *userService) GetUserByIdentity(ctx context.Context, identity string) (*models.User, error) {
 	if strings.Contains(identity, "@") {
 		return s.GetUserByEmail(ctx, identity)
@@ -70,8 +97,45 @@ func (s *userService) GetUserByUsername(ctx context.Context, username string) (*
 	return user, nil
 }
 
-func (s *userService) GetAllUsers(ctx context.Context) ([]models.User, error) {
-	return s.userRepo.GetAll(ctx)
+// ListUsers returns the page of users following cursor, or the first page when
+// cursor is empty. Users are sorted by creation, newest first, unless
+// params.SortBy says otherwise.
+func (s *userService) ListUsers(ctx context.Context, params repositories.ListUsersParams, cursor string) (*UserPage, error) {
+	if params.SortBy == "" {
+		params.SortBy, params.Desc = "created_at", true
+	}
+	if params.Limit <= 0 {
+		params.Limit = defaultUserPageSize
+	}
+	params.Limit = min(params.Limit, maxUserPageSize)
+	if cursor != "" {
+		after, err := decodeUserCursor(cursor, params)
+		if err != nil {
+			return nil, err
+		}
+		params.After = after
+	}
+
+	limit := params.Limit
+	// one extra row tells whether another page follows
+	params.Limit++
+	users, err := s.userRepo.List(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	page := &UserPage{Users: users}
+	if len(users) > limit {
+		page.Users = users[:limit]
+		next := encodeUserCursor(params, page.Users[limit-1])
+		page.NextCursor = &next
+	}
+
+	estimate, err := s.userRepo.EstimateCount(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	page.TotalEstimate = estimate
+	return page, nil
 }
 
 func (s *userService) Store(ctx context.Context, params repositories.CreateOneParams) (*models.User, error) {
@@ -81,3 +145,51 @@ func (s *userService) Store(ctx context.Context, params repositories.CreateOnePa
 	}
 	return user, nil
 }
+
+// userCursor is the opaque position handed out as next_cursor. It remembers
+// the ordering it was made for so it cannot be replayed against another one.
+type userCursor struct {
+	SortBy string    `json:"s"`
+	Desc   bool      `json:"d"`
+	Value  string    `json:"v"`
+	Id     uuid.UUID `json:"id"`
+}
+
+func encodeUserCursor(params repositories.ListUsersParams, user models.User) string {
+	cursor := userCursor{SortBy: params.SortBy, Desc: params.Desc, Id: user.ID}
+	switch params.SortBy {
+	case "username":
+		cursor.Value = user.Username
+	case "name":
+		cursor.Value = user.Name
+	case "email":
+		cursor.Value = user.Email
+	default:
+		cursor.Value = user.CreatedAt
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeUserCursor(raw string, params repositories.ListUsersParams) (*repositories.UserCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor userCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.SortBy != params.SortBy || cursor.Desc != params.Desc || cursor.Id == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &repositories.UserCursor{Value: cursor.Value, Id: cursor.Id}, nil
+}
+
+// UserPage is one page of a user listing. NextCursor is nil on the last page.
+// TotalEstimate comes from the query planner and is only approximate.
+type UserPage struct {
+	Users         []models.User `json:"users"`
+	NextCursor    *string       `json:"next_cursor"`
+	TotalEstimate int64         `json:"total_estimate"`
+}
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"my-go-api/internal/config"
+	"my-go-api/internal/models"
+	"my-go-api/internal/repositories"
+	"my-go-api/internal/utils"
+	"strings"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidWebauthnSession = errors.New("invalid or expired webauthn session")
+	ErrWebauthnVerification   = errors.New("passkey verification failed")
+	ErrNoPasskeys             = errors.New("no passkeys registered")
+)
+
+const (
+	webauthnCeremonyRegistration = "registration"
+	webauthnCeremonyLogin        = "login"
+	webauthnCeremonySecondFactor = "second_factor"
+)
+
+type IWebauthnService interface {
+	BeginRegistration(ctx context.Context, user *models.User) (WebauthnCeremony, error)
+	FinishRegistration(ctx context.Context, user *models.User, sessionId, name string, response []byte) (*models.WebauthnCredential, error)
+	BeginLogin(ctx context.Context) (WebauthnCeremony, error)
+	FinishLogin(ctx context.Context, sessionId string, response []byte) (uuid.UUID, error)
+	BeginSecondFactor(ctx context.Context, userId uuid.UUID) (WebauthnCeremony, error)
+	FinishSecondFactor(ctx context.Context, userId uuid.UUID, sessionId string, response []byte) error
+	HasCredentials(ctx context.Context, userId uuid.UUID) (bool, error)
+	GetCredentials(ctx context.Context, userId uuid.UUID) ([]models.WebauthnCredential, error)
+	DeleteCredential(ctx context.Context, userId uuid.UUID, id int) error
+}
+
+type webauthnService struct {
+	webauthn     *webauthn.WebAuthn
+	webauthnRepo repositories.IWebauthnRepository
+	redisService IRedisService
+	utils        utils.IUtils
+}
+
+func NewWebauthnService(cfg config.WebauthnConfig, webauthnRepo repositories.IWebauthnRepository, redisService IRedisService, utils utils.IUtils) (IWebauthnService, error) {
+	relyingParty, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.RPID,
+		RPDisplayName: cfg.RPDisplayName,
+		RPOrigins:     cfg.RPOrigins,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &webauthnService{
+		webauthn:     relyingParty,
+		webauthnRepo: webauthnRepo,
+		redisService: redisService,
+		utils:        utils,
+	}, nil
+}
+
+// BeginRegistration starts adding a passkey to user. Credentials the user
+// already registered are excluded so an authenticator is not enrolled twice.
+func (s *webauthnService) BeginRegistration(ctx context.Context, user *models.User) (WebauthnCeremony, error) {
+	account, err := s.loadUser(ctx, user.ID)
+	if err != nil {
+		return WebauthnCeremony{}, err
+	}
+	account.name = user.Email
+	account.displayName = user.Name
+	creation, session, err := s.webauthn.BeginRegistration(
+		account,
+		webauthn.WithExclusions(webauthn.Credentials(account.credentials).CredentialDescriptors()),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementPreferred),
+	)
+	if err != nil {
+		return WebauthnCeremony{}, err
+	}
+	return s.saveSession(webauthnCeremonyRegistration, user.ID, session, creation)
+}
+
+func (s *webauthnService) FinishRegistration(ctx context.Context, user *models.User, sessionId, name string, response []byte) (*models.WebauthnCredential, error) {
+	session, err := s.takeSession(sessionId, webauthnCeremonyRegistration, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrWebauthnVerification, err)
+	}
+	account, err := s.loadUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := s.webauthn.CreateCredential(account, *session, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrWebauthnVerification, err)
+	}
+	if name == "" {
+		name = "Passkey"
+	}
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+	return s.webauthnRepo.CreateOne(ctx, &models.WebauthnCredential{
+		UserId:          user.ID,
+		CredentialId:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      strings.Join(transports, ","),
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+		Name:            name,
+	})
+}
+
+// BeginLogin starts a passwordless sign in with a discoverable credential.
+// User verification is required, so the passkey alone counts as two factors.
+func (s *webauthnService) BeginLogin(ctx context.Context) (WebauthnCeremony, error) {
+	assertion, session, err := s.webauthn.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		return WebauthnCeremony{}, err
+	}
+	return s.saveSession(webauthnCeremonyLogin, uuid.Nil, session, assertion)
+}
+
+// FinishLogin verifies a passwordless assertion and returns the user that owns
+// the credential.
+func (s *webauthnService) FinishLogin(ctx context.Context, sessionId string, response []byte) (uuid.UUID, error) {
+	session, err := s.takeSession(sessionId, webauthnCeremonyLogin, uuid.Nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %w", ErrWebauthnVerification, err)
+	}
+	var account *webauthnUser
+	credential, err := s.webauthn.ValidateDiscoverableLogin(func(_, userHandle []byte) (webauthn.User, error) {
+		userId, err := uuid.FromBytes(userHandle)
+		if err != nil {
+			return nil, err
+		}
+		account, err = s.loadUser(ctx, userId)
+		return account, err
+	}, *session, parsed)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %w", ErrWebauthnVerification, err)
+	}
+	if err := s.recordUsage(ctx, credential); err != nil {
+		return uuid.Nil, err
+	}
+	return account.id, nil
+}
+
+// BeginSecondFactor asks for an assertion from one of the user's registered
+// credentials after the password step.
+func (s *webauthnService) BeginSecondFactor(ctx context.Context, userId uuid.UUID) (WebauthnCeremony, error) {
+	account, err := s.loadUser(ctx, userId)
+	if err != nil {
+		return WebauthnCeremony{}, err
+	}
+	if len(account.credentials) == 0 {
+		return WebauthnCeremony{}, ErrNoPasskeys
+	}
+	assertion, session, err := s.webauthn.BeginLogin(account)
+	if err != nil {
+		return WebauthnCeremony{}, err
+	}
+	return s.saveSession(webauthnCeremonySecondFactor, userId, session, assertion)
+}
+
+func (s *webauthnService) FinishSecondFactor(ctx context.Context, userId uuid.UUID, sessionId string, response []byte) error {
+	session, err := s.takeSession(sessionId, webauthnCeremonySecondFactor, userId)
+	if err != nil {
+		return err
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrWebauthnVerification, err)
+	}
+	account, err := s.loadUser(ctx, userId)
+	if err != nil {
+		return err
+	}
+	credential, err := s.webauthn.ValidateLogin(account, *session, parsed)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrWebauthnVerification, err)
+	}
+	return s.recordUsage(ctx, credential)
+}
+
+func (s *webauthnService) HasCredentials(ctx context.Context, userId uuid.UUID) (bool, error) {
+	count, err := s.webauthnRepo.CountByUserId(ctx, userId)
+	return count > 0, err
+}
+
+func (s *webauthnService) GetCredentials(ctx context.Context, userId uuid.UUID) ([]models.WebauthnCredential, error) {
+	return s.webauthnRepo.GetByUserId(ctx, userId)
+}
+
+func (s *webauthnService) DeleteCredential(ctx context.Context, userId uuid.UUID, id int) error {
+	return s.webauthnRepo.DeleteOne(ctx, userId, id)
+}
+
+// recordUsage persists the sign counter of a verified assertion. A counter
+// that did not move forward means the credential may have been cloned, so the
+// assertion is rejected.
+func (s *webauthnService) recordUsage(ctx context.Context, credential *webauthn.Credential) error {
+	if credential.Authenticator.CloneWarning {
+		return fmt.Errorf("%w: sign counter did not increase", ErrWebauthnVerification)
+	}
+	updated, err := s.webauthnRepo.UpdateUsage(ctx, credential.ID, credential.Authenticator.SignCount, credential.Flags.BackupState)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return fmt.Errorf("%w: sign counter did not increase", ErrWebauthnVerification)
+	}
+	return nil
+}
+
+func (s *webauthnService) saveSession(ceremony string, userId uuid.UUID, session *webauthn.SessionData, options any) (WebauthnCeremony, error) {
+	sessionId, err := s.utils.GenerateRandomBytes(32)
+	if err != nil {
+		return WebauthnCeremony{}, err
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return WebauthnCeremony{}, err
+	}
+	if err := s.redisService.SaveWebauthnSession(WebauthnSessionData{
+		SessionId: sessionId,
+		Ceremony:  ceremony,
+		UserId:    userId.String(),
+		Session:   string(data),
+	}); err != nil {
+		return WebauthnCeremony{}, err
+	}
+	return WebauthnCeremony{SessionId: sessionId, Options: options}, nil
+}
+
+// takeSession consumes a pending ceremony, making sure it was started for the
+// same ceremony and user it is being finished for.
+func (s *webauthnService) takeSession(sessionId, ceremony string, userId uuid.UUID) (*webauthn.SessionData, error) {
+	data, err := s.redisService.TakeWebauthnSession(sessionId)
+	if err != nil {
+		return nil, ErrInvalidWebauthnSession
+	}
+	if data.Ceremony != ceremony || data.UserId != userId.String() {
+		return nil, ErrInvalidWebauthnSession
+	}
+	session := &webauthn.SessionData{}
+	if err := json.Unmarshal([]byte(data.Session), session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (s *webauthnService) loadUser(ctx context.Context, userId uuid.UUID) (*webauthnUser, error) {
+	stored, err := s.webauthnRepo.GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	account := &webauthnUser{id: userId, credentials: make([]webauthn.Credential, 0, len(stored))}
+	for _, credential := range stored {
+		var transports []protocol.AuthenticatorTransport
+		for _, transport := range strings.Split(credential.Transports, ",") {
+			if transport != "" {
+				transports = append(transports, protocol.AuthenticatorTransport(transport))
+			}
+		}
+		account.credentials = append(account.credentials, webauthn.Credential{
+			ID:              credential.CredentialId,
+			PublicKey:       credential.PublicKey,
+			AttestationType: credential.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: credential.BackupEligible,
+				BackupState:    credential.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    credential.AAGUID,
+				SignCount: credential.SignCount,
+			},
+		})
+	}
+	return account, nil
+}
+
+// webauthnUser adapts a user and the stored credentials to webauthn.User. The
+// user handle is the 16 byte user id.
+type webauthnUser struct {
+	id          uuid.UUID
+	name        string
+	displayName string
+	credentials []webauthn.Credential
+}
+
+func (u *webauthnUser) WebAuthnID() []byte {
+	return u.id[:]
+}
+
+func (u *webauthnUser) WebAuthnName() string {
+	return u.name
+}
+
+func (u *webauthnUser) WebAuthnDisplayName() string {
+	return u.displayName
+}
+
+func (u *webauthnUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+// WebauthnCeremony is returned to the client to start a ceremony. Options is
+// passed to navigator.credentials; SessionId comes back with the response.
+type WebauthnCeremony struct {
+	SessionId string `json:"session_id"`
+	Options   any    `json:"options"`
+}
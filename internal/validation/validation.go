@@ -18,10 +18,15 @@ func Init() *validator.Validate {
 }
 
 var Messages = map[string]string{
-	"email":          "Invalid email",
-	"min":            "Too short. A minimum of %s characters is required",
-	"required":       "This field is required",
-	"strongPassword": "A minimum of 5 characters including an uppercase letter, a lowercase letter, and a number is required",
+	"bcp47_language_tag": "Must be a language tag such as en or fr-CA",
+	"email":              "Invalid email",
+	"ip":                 "Must be a valid IP address",
+	"min":                "Too short. A minimum of %s characters is required",
+	"max":                "Must be at most %s",
+	"oneof":              "Must be one of: %s",
+	"required":           "This field is required",
+	"strongPassword":     "A minimum of 5 characters including an uppercase letter, a lowercase letter, and a number is required",
+	"uuid":               "Must be a valid UUID",
 }
 
 func ValidatePassword(fl validator.FieldLevel) bool {
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_token_hash;
+
+ALTER TABLE tokens
+DROP COLUMN IF EXISTS jti,
+DROP COLUMN IF EXISTS user_agent,
+DROP COLUMN IF EXISTS ip_address,
+DROP COLUMN IF EXISTS created_at,
+DROP COLUMN IF EXISTS last_used_at,
+DROP COLUMN IF EXISTS revoked_at;
@@ -0,0 +1,15 @@
+ALTER TABLE tokens
+ADD COLUMN jti UUID,
+ADD COLUMN user_agent TEXT NOT NULL DEFAULT '',
+ADD COLUMN ip_address VARCHAR(45) NOT NULL DEFAULT '',
+ADD COLUMN created_at TIMESTAMP(0)
+WITH
+  TIME ZONE NOT NULL DEFAULT NOW (),
+ADD COLUMN last_used_at TIMESTAMP(0)
+WITH
+  TIME ZONE NOT NULL DEFAULT NOW (),
+ADD COLUMN revoked_at TIMESTAMP(0)
+WITH
+  TIME ZONE;
+
+CREATE UNIQUE INDEX idx_token_hash ON tokens (hash);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_token_family;
+
+ALTER TABLE tokens
+DROP COLUMN IF EXISTS family_id,
+DROP COLUMN IF EXISTS revoke_reason;
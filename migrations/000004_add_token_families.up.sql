@@ -0,0 +1,5 @@
+ALTER TABLE tokens
+ADD COLUMN family_id UUID NOT NULL DEFAULT uuid_generate_v4 (),
+ADD COLUMN revoke_reason VARCHAR(20);
+
+CREATE INDEX idx_token_family ON tokens (family_id);
@@ -0,0 +1,11 @@
+CREATE TYPE providers AS ENUM ('credentials', 'google');
+
+ALTER TABLE users
+ALTER COLUMN provider DROP DEFAULT,
+ALTER COLUMN provider TYPE providers USING (
+  CASE
+    WHEN provider IN ('credentials', 'google') THEN provider
+    ELSE 'credentials'
+  END
+)::providers,
+ALTER COLUMN provider SET DEFAULT 'credentials';
@@ -0,0 +1,6 @@
+ALTER TABLE users
+ALTER COLUMN provider DROP DEFAULT,
+ALTER COLUMN provider TYPE VARCHAR(50) USING provider::TEXT,
+ALTER COLUMN provider SET DEFAULT 'credentials';
+
+DROP TYPE IF EXISTS providers;
@@ -0,0 +1,28 @@
+CREATE TABLE
+  user_identities (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP(0)
+    WITH
+      TIME ZONE NOT NULL DEFAULT NOW (),
+      UNIQUE (provider, subject),
+      UNIQUE (user_id, provider)
+  );
+
+CREATE INDEX idx_user_identities_user_id ON user_identities (user_id);
+
+-- Every account that has a password can sign in with credentials.
+INSERT INTO
+  user_identities (user_id, provider, subject, email)
+SELECT
+  id,
+  'credentials',
+  id::TEXT,
+  email
+FROM
+  users
+WHERE
+  password IS NOT NULL;
@@ -0,0 +1,24 @@
+CREATE TABLE
+  user_mfa (
+    user_id UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    totp_secret TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT false,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP(0)
+    WITH
+      TIME ZONE NOT NULL DEFAULT NOW (),
+      enabled_at TIMESTAMP(0)
+    WITH
+      TIME ZONE
+  );
+
+CREATE TABLE
+  mfa_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP(0)
+    WITH
+      TIME ZONE,
+      UNIQUE (user_id, code_hash)
+  );
@@ -0,0 +1,22 @@
+CREATE TABLE
+  webauthn_credentials (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    credential_id BYTEA NOT NULL UNIQUE,
+    public_key BYTEA NOT NULL,
+    attestation_type VARCHAR(50) NOT NULL DEFAULT '',
+    transports VARCHAR(255) NOT NULL DEFAULT '',
+    aaguid BYTEA,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    backup_eligible BOOLEAN NOT NULL DEFAULT false,
+    backup_state BOOLEAN NOT NULL DEFAULT false,
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP(0)
+    WITH
+      TIME ZONE NOT NULL DEFAULT NOW (),
+      last_used_at TIMESTAMP(0)
+    WITH
+      TIME ZONE
+  );
+
+CREATE INDEX idx_webauthn_credentials_user_id ON webauthn_credentials (user_id);
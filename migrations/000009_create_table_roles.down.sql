@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS user_role_assignments;
+
+DROP TABLE IF EXISTS role_permissions;
+
+DROP TABLE IF EXISTS permissions;
+
+DROP TABLE IF EXISTS roles;
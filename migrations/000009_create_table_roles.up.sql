@@ -0,0 +1,66 @@
+CREATE TABLE
+  roles (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    is_system BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP(0)
+    WITH
+      TIME ZONE NOT NULL DEFAULT NOW ()
+  );
+
+CREATE TABLE
+  permissions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description VARCHAR(255) NOT NULL DEFAULT ''
+  );
+
+CREATE TABLE
+  role_permissions (
+    role_id INT NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    permission_id INT NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+  );
+
+CREATE TABLE
+  user_role_assignments (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role_id INT NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    created_at TIMESTAMP(0)
+    WITH
+      TIME ZONE NOT NULL DEFAULT NOW (),
+      PRIMARY KEY (user_id, role_id)
+  );
+
+CREATE INDEX idx_user_role_assignments_role_id ON user_role_assignments (role_id);
+
+INSERT INTO
+  permissions (name, description)
+VALUES
+  ('users:read', 'List and view any user'),
+  ('users:write', 'Update any user'),
+  ('roles:read', 'View roles and permissions'),
+  ('roles:write', 'Create, edit and delete roles'),
+  ('roles:assign', 'Assign roles to users'),
+  ('sessions:read', 'View the sessions of any user'),
+  ('sessions:revoke', 'Revoke the sessions of any user');
+
+-- the built in roles mirror the user_roles enum, which stays the primary role
+-- of every user
+INSERT INTO
+  roles (name, description, is_system)
+VALUES
+  ('admin', 'Full access', true),
+  ('user', 'Default role for every account', true);
+
+INSERT INTO
+  role_permissions (role_id, permission_id)
+SELECT
+  roles.id,
+  permissions.id
+FROM
+  roles
+  CROSS JOIN permissions
+WHERE
+  roles.name = 'admin';
@@ -0,0 +1,5 @@
+DELETE FROM permissions
+WHERE
+  name IN ('authz:check', 'authz:write');
+
+DROP TABLE IF EXISTS relation_tuples;
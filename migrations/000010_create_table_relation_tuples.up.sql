@@ -0,0 +1,43 @@
+-- A tuple reads namespace:object_id#relation@subject. The subject is either a
+-- user (subject_id) or an object, optionally narrowed to one of its relations
+-- (a userset).
+CREATE TABLE
+  relation_tuples (
+    namespace VARCHAR(64) NOT NULL,
+    object_id VARCHAR(128) NOT NULL,
+    relation VARCHAR(64) NOT NULL,
+    subject_id VARCHAR(128) NOT NULL DEFAULT '',
+    subject_namespace VARCHAR(64) NOT NULL DEFAULT '',
+    subject_object_id VARCHAR(128) NOT NULL DEFAULT '',
+    subject_relation VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP(0)
+    WITH
+      TIME ZONE NOT NULL DEFAULT NOW (),
+      PRIMARY KEY (
+        namespace,
+        object_id,
+        relation,
+        subject_id,
+        subject_namespace,
+        subject_object_id,
+        subject_relation
+      )
+  );
+
+INSERT INTO
+  permissions (name, description)
+VALUES
+  ('authz:check', 'Check and expand relationships'),
+  ('authz:write', 'Write and delete relationship tuples');
+
+INSERT INTO
+  role_permissions (role_id, permission_id)
+SELECT
+  roles.id,
+  permissions.id
+FROM
+  roles
+  CROSS JOIN permissions
+WHERE
+  roles.name = 'admin'
+  AND permissions.name IN ('authz:check', 'authz:write');
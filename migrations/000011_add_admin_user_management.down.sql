@@ -0,0 +1,11 @@
+DELETE FROM permissions
+WHERE
+  name = 'users:manage';
+
+DROP TABLE IF EXISTS admin_actions;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS locked_at,
+DROP COLUMN IF EXISTS lock_reason,
+DROP COLUMN IF EXISTS password_reset_required,
+DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,40 @@
+ALTER TABLE users
+ADD COLUMN locked_at TIMESTAMP(0)
+WITH
+  TIME ZONE,
+ADD COLUMN lock_reason VARCHAR(255) NOT NULL DEFAULT '',
+ADD COLUMN password_reset_required BOOLEAN NOT NULL DEFAULT false,
+ADD COLUMN deleted_at TIMESTAMP(0)
+WITH
+  TIME ZONE;
+
+CREATE TABLE
+  admin_actions (
+    id BIGSERIAL PRIMARY KEY,
+    admin_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    target_user_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    action VARCHAR(50) NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP(0)
+    WITH
+      TIME ZONE NOT NULL DEFAULT NOW ()
+  );
+
+CREATE INDEX idx_admin_actions_target_user_id ON admin_actions (target_user_id);
+
+INSERT INTO
+  permissions (name, description)
+VALUES
+  ('users:manage', 'Create, lock, delete and restore users');
+
+INSERT INTO
+  role_permissions (role_id, permission_id)
+SELECT
+  roles.id,
+  permissions.id
+FROM
+  roles
+  CROSS JOIN permissions
+WHERE
+  roles.name = 'admin'
+  AND permissions.name = 'users:manage';
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_users_created_at_id;
+
+DROP INDEX IF EXISTS idx_users_email_trgm;
+
+DROP INDEX IF EXISTS idx_users_name_trgm;
+
+DROP INDEX IF EXISTS idx_users_username_trgm;
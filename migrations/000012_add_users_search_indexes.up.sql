@@ -0,0 +1,11 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_users_username_trgm ON users USING GIN (username gin_trgm_ops);
+
+CREATE INDEX idx_users_name_trgm ON users USING GIN (name gin_trgm_ops);
+
+CREATE INDEX idx_users_email_trgm ON users USING GIN (email gin_trgm_ops);
+
+CREATE INDEX idx_users_created_at_id ON users (created_at, id)
+WHERE
+  deleted_at IS NULL;
@@ -0,0 +1,54 @@
+-- audit_events is append only. actor_id and target_id carry no foreign key so
+-- the trail outlives the accounts it mentions.
+CREATE TABLE
+  audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    outcome VARCHAR(20) NOT NULL,
+    actor_id UUID,
+    target_id UUID,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP(0)
+    WITH
+      TIME ZONE NOT NULL DEFAULT NOW ()
+  );
+
+CREATE INDEX idx_audit_events_created_at ON audit_events (created_at);
+
+CREATE INDEX idx_audit_events_actor_id ON audit_events (actor_id, id);
+
+CREATE INDEX idx_audit_events_target_id ON audit_events (target_id, id);
+
+CREATE INDEX idx_audit_events_event_type ON audit_events (event_type, id);
+
+CREATE FUNCTION audit_events_append_only () RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_events is append only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only BEFORE
+UPDATE
+OR DELETE
+OR TRUNCATE ON audit_events FOR EACH STATEMENT
+EXECUTE FUNCTION audit_events_append_only ();
+
+INSERT INTO
+  permissions (name, description)
+VALUES
+  ('audit:read', 'Query and export the security audit log');
+
+INSERT INTO
+  role_permissions (role_id, permission_id)
+SELECT
+  roles.id,
+  permissions.id
+FROM
+  roles
+  CROSS JOIN permissions
+WHERE
+  roles.name = 'admin'
+  AND permissions.name = 'audit:read';
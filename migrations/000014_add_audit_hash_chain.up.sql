@@ -0,0 +1,31 @@
+-- Every event stores the hash of the one before it, so editing or removing an
+-- event breaks the chain from there on. Events written before this migration
+-- keep an empty hash and are reported as unchained.
+ALTER TABLE audit_events
+ADD COLUMN prev_hash VARCHAR(64) NOT NULL DEFAULT '',
+ADD COLUMN hash VARCHAR(64) NOT NULL DEFAULT '';
+
+-- A checkpoint signs the chain head, which also pins events at the end of the
+-- chain that no later event links to.
+CREATE TABLE
+  audit_checkpoints (
+    id BIGSERIAL PRIMARY KEY,
+    last_event_id BIGINT NOT NULL,
+    last_hash VARCHAR(64) NOT NULL,
+    signature TEXT NOT NULL,
+    created_at TIMESTAMP(0)
+    WITH
+      TIME ZONE NOT NULL DEFAULT NOW ()
+  );
+
+CREATE OR REPLACE FUNCTION audit_events_append_only () RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION '% is append only', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_checkpoints_append_only BEFORE
+UPDATE
+OR DELETE
+OR TRUNCATE ON audit_checkpoints FOR EACH STATEMENT
+EXECUTE FUNCTION audit_events_append_only ();
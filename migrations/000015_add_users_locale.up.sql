@@ -0,0 +1,3 @@
+-- preferred language of the user as a BCP 47 tag, empty when unknown
+ALTER TABLE users
+ADD COLUMN locale VARCHAR(35) NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+DELETE FROM permissions
+WHERE
+  name IN ('outbox:read', 'outbox:retry');
+
+DROP TABLE IF EXISTS email_outbox;
@@ -0,0 +1,53 @@
+CREATE TABLE
+  email_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    template VARCHAR(100) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    recipient_name VARCHAR(255) NOT NULL DEFAULT '',
+    locale VARCHAR(35) NOT NULL DEFAULT '',
+    -- template data, cleared once the email is sent since it holds codes and
+    -- links
+    data JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP(0)
+    WITH
+      TIME ZONE NOT NULL DEFAULT NOW (),
+      -- set while a worker is delivering the email
+      locked_until TIMESTAMP(0)
+    WITH
+      TIME ZONE,
+      created_at TIMESTAMP(0)
+    WITH
+      TIME ZONE NOT NULL DEFAULT NOW (),
+      sent_at TIMESTAMP(0)
+    WITH
+      TIME ZONE
+  );
+
+CREATE INDEX idx_email_outbox_due ON email_outbox (next_attempt_at, id)
+WHERE
+  status = 'pending';
+
+CREATE INDEX idx_email_outbox_status ON email_outbox (status, id);
+
+CREATE INDEX idx_email_outbox_recipient ON email_outbox (recipient);
+
+INSERT INTO
+  permissions (name, description)
+VALUES
+  ('outbox:read', 'Inspect queued, sent and failed emails'),
+  ('outbox:retry', 'Queue failed emails for delivery again');
+
+INSERT INTO
+  role_permissions (role_id, permission_id)
+SELECT
+  roles.id,
+  permissions.id
+FROM
+  roles
+  CROSS JOIN permissions
+WHERE
+  roles.name = 'admin'
+  AND permissions.name IN ('outbox:read', 'outbox:retry');
@@ -0,0 +1,5 @@
+DELETE FROM permissions
+WHERE
+  name = 'keys:manage';
+
+DROP TABLE IF EXISTS signing_keys;
@@ -0,0 +1,48 @@
+CREATE TABLE
+  signing_keys (
+    -- the kid header of tokens signed with the key
+    id VARCHAR(64) PRIMARY KEY,
+    algorithm VARCHAR(10) NOT NULL,
+    -- active signs tokens, verify only verifies them and retired does neither
+    status VARCHAR(10) NOT NULL DEFAULT 'verify',
+    -- PKCS #8 private key or HMAC secret, sealed with the key encryption key
+    private_key TEXT NOT NULL,
+    created_at TIMESTAMP(0)
+    WITH
+      TIME ZONE NOT NULL DEFAULT NOW (),
+      activated_at TIMESTAMP(0)
+    WITH
+      TIME ZONE,
+      -- when the key stopped signing, tokens it signed drain from then on
+      deactivated_at TIMESTAMP(0)
+    WITH
+      TIME ZONE,
+      retired_at TIMESTAMP(0)
+    WITH
+      TIME ZONE
+  );
+
+-- at most one key signs at a time
+CREATE UNIQUE INDEX idx_signing_keys_active ON signing_keys ((TRUE))
+WHERE
+  status = 'active';
+
+INSERT INTO
+  permissions (name, description)
+VALUES
+  (
+    'keys:manage',
+    'Introduce, promote and retire token signing keys'
+  );
+
+INSERT INTO
+  role_permissions (role_id, permission_id)
+SELECT
+  roles.id,
+  permissions.id
+FROM
+  roles
+  CROSS JOIN permissions
+WHERE
+  roles.name = 'admin'
+  AND permissions.name = 'keys:manage';
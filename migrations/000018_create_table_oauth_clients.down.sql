@@ -0,0 +1,17 @@
+DELETE FROM permissions
+WHERE
+  name = 'clients:manage';
+
+DELETE FROM tokens
+WHERE
+  client_id IS NOT NULL;
+
+ALTER TABLE tokens
+DROP COLUMN IF EXISTS scope,
+DROP COLUMN IF EXISTS client_id;
+
+DROP TABLE IF EXISTS oauth_authorizations;
+
+DROP TABLE IF EXISTS oauth_consents;
+
+DROP TABLE IF EXISTS oauth_clients;
@@ -0,0 +1,82 @@
+CREATE TABLE
+  oauth_clients (
+    -- the client_id relying parties authenticate with
+    id VARCHAR(64) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    -- SHA-256 of the client secret, NULL for public clients that rely on PKCE
+    secret_hash VARCHAR(64),
+    -- space separated, a redirect_uri has to match one of them exactly
+    redirect_uris TEXT NOT NULL,
+    created_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP(0)
+    WITH
+      TIME ZONE NOT NULL DEFAULT NOW ()
+  );
+
+CREATE TABLE
+  oauth_consents (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    client_id VARCHAR(64) NOT NULL REFERENCES oauth_clients (id) ON DELETE CASCADE,
+    -- space separated scopes the user granted the client
+    scope TEXT NOT NULL,
+    created_at TIMESTAMP(0)
+    WITH
+      TIME ZONE NOT NULL DEFAULT NOW (),
+      updated_at TIMESTAMP(0)
+    WITH
+      TIME ZONE NOT NULL DEFAULT NOW (),
+      PRIMARY KEY (user_id, client_id)
+  );
+
+-- an authorization request waits for the user's decision, once approved it
+-- holds the authorization code until the code is redeemed or expires
+CREATE TABLE
+  oauth_authorizations (
+    -- SHA-256 of the request id handed to the consent page
+    id VARCHAR(64) PRIMARY KEY,
+    client_id VARCHAR(64) NOT NULL REFERENCES oauth_clients (id) ON DELETE CASCADE,
+    redirect_uri TEXT NOT NULL,
+    scope TEXT NOT NULL,
+    state TEXT NOT NULL DEFAULT '',
+    nonce TEXT NOT NULL DEFAULT '',
+    code_challenge VARCHAR(128) NOT NULL,
+    -- consent when the client wants the user asked again
+    prompt VARCHAR(20) NOT NULL DEFAULT '',
+    user_id UUID REFERENCES users (id) ON DELETE CASCADE,
+    -- SHA-256 of the authorization code, set on approval
+    code_hash VARCHAR(64) UNIQUE,
+    created_at TIMESTAMP(0)
+    WITH
+      TIME ZONE NOT NULL DEFAULT NOW (),
+      expires_at TIMESTAMP(0)
+    WITH
+      TIME ZONE NOT NULL
+  );
+
+CREATE INDEX idx_oauth_authorizations_expires_at ON oauth_authorizations (expires_at);
+
+-- sessions of relying parties carry the client and the granted scopes, NULL
+-- and empty for first party sessions
+ALTER TABLE tokens
+ADD COLUMN client_id VARCHAR(64) REFERENCES oauth_clients (id) ON DELETE CASCADE,
+ADD COLUMN scope TEXT NOT NULL DEFAULT '';
+
+INSERT INTO
+  permissions (name, description)
+VALUES
+  (
+    'clients:manage',
+    'Register and delete OAuth clients'
+  );
+
+INSERT INTO
+  role_permissions (role_id, permission_id)
+SELECT
+  roles.id,
+  permissions.id
+FROM
+  roles
+  CROSS JOIN permissions
+WHERE
+  roles.name = 'admin'
+  AND permissions.name = 'clients:manage';
@@ -0,0 +1,21 @@
+-- granting or revoking the admin role needs a permission of its own, on top
+-- of roles:assign
+INSERT INTO
+  permissions (name, description)
+VALUES
+  (
+    'roles:admin',
+    'Grant and revoke the admin role'
+  );
+
+INSERT INTO
+  role_permissions (role_id, permission_id)
+SELECT
+  roles.id,
+  permissions.id
+FROM
+  roles
+  CROSS JOIN permissions
+WHERE
+  roles.name = 'admin'
+  AND permissions.name = 'roles:admin';
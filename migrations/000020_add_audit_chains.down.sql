@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_audit_checkpoints_chain;
+
+ALTER TABLE audit_checkpoints
+DROP COLUMN IF EXISTS chain;
+
+DROP INDEX IF EXISTS idx_audit_events_chain;
+
+ALTER TABLE audit_events
+DROP COLUMN IF EXISTS chain;
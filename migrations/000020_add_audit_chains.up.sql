@@ -0,0 +1,13 @@
+-- Events are chained per chain key instead of in one global chain, so writers
+-- of different chains do not wait for each other and a chain can be verified
+-- and exported on its own. The API has no tenants yet and records everything
+-- on the default chain, which the events chained so far belong to.
+ALTER TABLE audit_events
+ADD COLUMN chain VARCHAR(64) NOT NULL DEFAULT 'default';
+
+CREATE INDEX idx_audit_events_chain ON audit_events (chain, id);
+
+ALTER TABLE audit_checkpoints
+ADD COLUMN chain VARCHAR(64) NOT NULL DEFAULT 'default';
+
+CREATE INDEX idx_audit_checkpoints_chain ON audit_checkpoints (chain, id);
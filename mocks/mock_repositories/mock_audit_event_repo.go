@@ -0,0 +1,161 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/audit_event_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repositories/audit_event_repository.go -destination=mocks/mock_repositories/mock_audit_event_repo.go -package=mockrepositories
+//
+
+// Package mockrepositories is a generated GoMock package.
+package mockrepositories
+
+import (
+	context "context"
+	models "my-go-api/internal/models"
+	repositories "my-go-api/internal/repositories"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIAuditEventRepository is a mock of IAuditEventRepository interface.
+type MockIAuditEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAuditEventRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIAuditEventRepositoryMockRecorder is the mock recorder for MockIAuditEventRepository.
+type MockIAuditEventRepositoryMockRecorder struct {
+	mock *MockIAuditEventRepository
+}
+
+// NewMockIAuditEventRepository creates a new mock instance.
+func NewMockIAuditEventRepository(ctrl *gomock.Controller) *MockIAuditEventRepository {
+	mock := &MockIAuditEventRepository{ctrl: ctrl}
+	mock.recorder = &MockIAuditEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAuditEventRepository) EXPECT() *MockIAuditEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateCheckpoint mocks base method.
+func (m *MockIAuditEventRepository) CreateCheckpoint(ctx context.Context, params repositories.CreateAuditCheckpointParams) (*models.AuditCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCheckpoint", ctx, params)
+	ret0, _ := ret[0].(*models.AuditCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCheckpoint indicates an expected call of CreateCheckpoint.
+func (mr *MockIAuditEventRepositoryMockRecorder) CreateCheckpoint(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCheckpoint", reflect.TypeOf((*MockIAuditEventRepository)(nil).CreateCheckpoint), ctx, params)
+}
+
+// CreateOne mocks base method.
+func (m *MockIAuditEventRepository) CreateOne(ctx context.Context, params repositories.CreateAuditEventParams, seal func(*models.AuditEvent) error) (*models.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOne", ctx, params, seal)
+	ret0, _ := ret[0].(*models.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOne indicates an expected call of CreateOne.
+func (mr *MockIAuditEventRepositoryMockRecorder) CreateOne(ctx, params, seal any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOne", reflect.TypeOf((*MockIAuditEventRepository)(nil).CreateOne), ctx, params, seal)
+}
+
+// Each mocks base method.
+func (m *MockIAuditEventRepository) Each(ctx context.Context, params repositories.ListAuditEventsParams, fn func(models.AuditEvent) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Each", ctx, params, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Each indicates an expected call of Each.
+func (mr *MockIAuditEventRepositoryMockRecorder) Each(ctx, params, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Each", reflect.TypeOf((*MockIAuditEventRepository)(nil).Each), ctx, params, fn)
+}
+
+// GetChains mocks base method.
+func (m *MockIAuditEventRepository) GetChains(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChains", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChains indicates an expected call of GetChains.
+func (mr *MockIAuditEventRepositoryMockRecorder) GetChains(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChains", reflect.TypeOf((*MockIAuditEventRepository)(nil).GetChains), ctx)
+}
+
+// GetCheckpoints mocks base method.
+func (m *MockIAuditEventRepository) GetCheckpoints(ctx context.Context, chain string) ([]models.AuditCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCheckpoints", ctx, chain)
+	ret0, _ := ret[0].([]models.AuditCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCheckpoints indicates an expected call of GetCheckpoints.
+func (mr *MockIAuditEventRepositoryMockRecorder) GetCheckpoints(ctx, chain any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCheckpoints", reflect.TypeOf((*MockIAuditEventRepository)(nil).GetCheckpoints), ctx, chain)
+}
+
+// GetHead mocks base method.
+func (m *MockIAuditEventRepository) GetHead(ctx context.Context, chain string) (*models.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHead", ctx, chain)
+	ret0, _ := ret[0].(*models.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHead indicates an expected call of GetHead.
+func (mr *MockIAuditEventRepositoryMockRecorder) GetHead(ctx, chain any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHead", reflect.TypeOf((*MockIAuditEventRepository)(nil).GetHead), ctx, chain)
+}
+
+// List mocks base method.
+func (m *MockIAuditEventRepository) List(ctx context.Context, params repositories.ListAuditEventsParams) ([]models.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, params)
+	ret0, _ := ret[0].([]models.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockIAuditEventRepositoryMockRecorder) List(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockIAuditEventRepository)(nil).List), ctx, params)
+}
+
+// Walk mocks base method.
+func (m *MockIAuditEventRepository) Walk(ctx context.Context, chain string, fn func(models.AuditEvent) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Walk", ctx, chain, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Walk indicates an expected call of Walk.
+func (mr *MockIAuditEventRepositoryMockRecorder) Walk(ctx, chain, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Walk", reflect.TypeOf((*MockIAuditEventRepository)(nil).Walk), ctx, chain, fn)
+}
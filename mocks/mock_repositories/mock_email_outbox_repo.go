@@ -0,0 +1,191 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/email_outbox_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repositories/email_outbox_repository.go -destination=mocks/mock_repositories/mock_email_outbox_repo.go -package=mockrepositories
+//
+
+// Package mockrepositories is a generated GoMock package.
+package mockrepositories
+
+import (
+	context "context"
+	sql "database/sql"
+	models "my-go-api/internal/models"
+	repositories "my-go-api/internal/repositories"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIEmailOutboxRepository is a mock of IEmailOutboxRepository interface.
+type MockIEmailOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIEmailOutboxRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIEmailOutboxRepositoryMockRecorder is the mock recorder for MockIEmailOutboxRepository.
+type MockIEmailOutboxRepositoryMockRecorder struct {
+	mock *MockIEmailOutboxRepository
+}
+
+// NewMockIEmailOutboxRepository creates a new mock instance.
+func NewMockIEmailOutboxRepository(ctrl *gomock.Controller) *MockIEmailOutboxRepository {
+	mock := &MockIEmailOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockIEmailOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIEmailOutboxRepository) EXPECT() *MockIEmailOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockIEmailOutboxRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, limit, lease)
+	ret0, _ := ret[0].([]models.OutboxEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockIEmailOutboxRepositoryMockRecorder) Claim(ctx, limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockIEmailOutboxRepository)(nil).Claim), ctx, limit, lease)
+}
+
+// Enqueue mocks base method.
+func (m *MockIEmailOutboxRepository) Enqueue(ctx context.Context, params repositories.EnqueueEmailParams) (*models.OutboxEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", ctx, params)
+	ret0, _ := ret[0].(*models.OutboxEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockIEmailOutboxRepositoryMockRecorder) Enqueue(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockIEmailOutboxRepository)(nil).Enqueue), ctx, params)
+}
+
+// GetOne mocks base method.
+func (m *MockIEmailOutboxRepository) GetOne(ctx context.Context, id int64) (*models.OutboxEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOne", ctx, id)
+	ret0, _ := ret[0].(*models.OutboxEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOne indicates an expected call of GetOne.
+func (mr *MockIEmailOutboxRepositoryMockRecorder) GetOne(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOne", reflect.TypeOf((*MockIEmailOutboxRepository)(nil).GetOne), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockIEmailOutboxRepository) List(ctx context.Context, params repositories.ListOutboxEmailsParams) ([]models.OutboxEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, params)
+	ret0, _ := ret[0].([]models.OutboxEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockIEmailOutboxRepositoryMockRecorder) List(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockIEmailOutboxRepository)(nil).List), ctx, params)
+}
+
+// MarkFailed mocks base method.
+func (m *MockIEmailOutboxRepository) MarkFailed(ctx context.Context, id int64, lastError string, retryAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, id, lastError, retryAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockIEmailOutboxRepositoryMockRecorder) MarkFailed(ctx, id, lastError, retryAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockIEmailOutboxRepository)(nil).MarkFailed), ctx, id, lastError, retryAt)
+}
+
+// MarkSent mocks base method.
+func (m *MockIEmailOutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSent", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSent indicates an expected call of MarkSent.
+func (mr *MockIEmailOutboxRepositoryMockRecorder) MarkSent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSent", reflect.TypeOf((*MockIEmailOutboxRepository)(nil).MarkSent), ctx, id)
+}
+
+// Retry mocks base method.
+func (m *MockIEmailOutboxRepository) Retry(ctx context.Context, id int64) (*models.OutboxEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retry", ctx, id)
+	ret0, _ := ret[0].(*models.OutboxEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Retry indicates an expected call of Retry.
+func (mr *MockIEmailOutboxRepositoryMockRecorder) Retry(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retry", reflect.TypeOf((*MockIEmailOutboxRepository)(nil).Retry), ctx, id)
+}
+
+// MockqueryRower is a mock of queryRower interface.
+type MockqueryRower struct {
+	ctrl     *gomock.Controller
+	recorder *MockqueryRowerMockRecorder
+	isgomock struct{}
+}
+
+// MockqueryRowerMockRecorder is the mock recorder for MockqueryRower.
+type MockqueryRowerMockRecorder struct {
+	mock *MockqueryRower
+}
+
+// NewMockqueryRower creates a new mock instance.
+func NewMockqueryRower(ctrl *gomock.Controller) *MockqueryRower {
+	mock := &MockqueryRower{ctrl: ctrl}
+	mock.recorder = &MockqueryRowerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockqueryRower) EXPECT() *MockqueryRowerMockRecorder {
+	return m.recorder
+}
+
+// QueryRowContext mocks base method.
+func (m *MockqueryRower) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, query}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryRowContext", varargs...)
+	ret0, _ := ret[0].(*sql.Row)
+	return ret0
+}
+
+// QueryRowContext indicates an expected call of QueryRowContext.
+func (mr *MockqueryRowerMockRecorder) QueryRowContext(ctx, query any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, query}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRowContext", reflect.TypeOf((*MockqueryRower)(nil).QueryRowContext), varargs...)
+}
@@ -0,0 +1,135 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/oauth_authorization_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repositories/oauth_authorization_repository.go -destination=mocks/mock_repositories/mock_oauth_authorization_repo.go -package=mockrepositories
+//
+
+// Package mockrepositories is a generated GoMock package.
+package mockrepositories
+
+import (
+	context "context"
+	models "my-go-api/internal/models"
+	repositories "my-go-api/internal/repositories"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIOAuthAuthorizationRepository is a mock of IOAuthAuthorizationRepository interface.
+type MockIOAuthAuthorizationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOAuthAuthorizationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIOAuthAuthorizationRepositoryMockRecorder is the mock recorder for MockIOAuthAuthorizationRepository.
+type MockIOAuthAuthorizationRepositoryMockRecorder struct {
+	mock *MockIOAuthAuthorizationRepository
+}
+
+// NewMockIOAuthAuthorizationRepository creates a new mock instance.
+func NewMockIOAuthAuthorizationRepository(ctrl *gomock.Controller) *MockIOAuthAuthorizationRepository {
+	mock := &MockIOAuthAuthorizationRepository{ctrl: ctrl}
+	mock.recorder = &MockIOAuthAuthorizationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOAuthAuthorizationRepository) EXPECT() *MockIOAuthAuthorizationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Approve mocks base method.
+func (m *MockIOAuthAuthorizationRepository) Approve(ctx context.Context, id string, userId uuid.UUID, codeHash string, expiresAt time.Time) (*models.OAuthAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", ctx, id, userId, codeHash, expiresAt)
+	ret0, _ := ret[0].(*models.OAuthAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *MockIOAuthAuthorizationRepositoryMockRecorder) Approve(ctx, id, userId, codeHash, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockIOAuthAuthorizationRepository)(nil).Approve), ctx, id, userId, codeHash, expiresAt)
+}
+
+// Create mocks base method.
+func (m *MockIOAuthAuthorizationRepository) Create(ctx context.Context, params repositories.CreateOAuthAuthorizationParams) (*models.OAuthAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, params)
+	ret0, _ := ret[0].(*models.OAuthAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIOAuthAuthorizationRepositoryMockRecorder) Create(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIOAuthAuthorizationRepository)(nil).Create), ctx, params)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockIOAuthAuthorizationRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockIOAuthAuthorizationRepositoryMockRecorder) DeleteExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockIOAuthAuthorizationRepository)(nil).DeleteExpired), ctx)
+}
+
+// Deny mocks base method.
+func (m *MockIOAuthAuthorizationRepository) Deny(ctx context.Context, id string) (*models.OAuthAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deny", ctx, id)
+	ret0, _ := ret[0].(*models.OAuthAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Deny indicates an expected call of Deny.
+func (mr *MockIOAuthAuthorizationRepositoryMockRecorder) Deny(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deny", reflect.TypeOf((*MockIOAuthAuthorizationRepository)(nil).Deny), ctx, id)
+}
+
+// GetPending mocks base method.
+func (m *MockIOAuthAuthorizationRepository) GetPending(ctx context.Context, id string) (*models.OAuthAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPending", ctx, id)
+	ret0, _ := ret[0].(*models.OAuthAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPending indicates an expected call of GetPending.
+func (mr *MockIOAuthAuthorizationRepositoryMockRecorder) GetPending(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPending", reflect.TypeOf((*MockIOAuthAuthorizationRepository)(nil).GetPending), ctx, id)
+}
+
+// Redeem mocks base method.
+func (m *MockIOAuthAuthorizationRepository) Redeem(ctx context.Context, codeHash string) (*models.OAuthAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeem", ctx, codeHash)
+	ret0, _ := ret[0].(*models.OAuthAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeem indicates an expected call of Redeem.
+func (mr *MockIOAuthAuthorizationRepositoryMockRecorder) Redeem(ctx, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeem", reflect.TypeOf((*MockIOAuthAuthorizationRepository)(nil).Redeem), ctx, codeHash)
+}
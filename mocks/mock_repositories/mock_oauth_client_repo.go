@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/oauth_client_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repositories/oauth_client_repository.go -destination=mocks/mock_repositories/mock_oauth_client_repo.go -package=mockrepositories
+//
+
+// Package mockrepositories is a generated GoMock package.
+package mockrepositories
+
+import (
+	context "context"
+	models "my-go-api/internal/models"
+	repositories "my-go-api/internal/repositories"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIOAuthClientRepository is a mock of IOAuthClientRepository interface.
+type MockIOAuthClientRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOAuthClientRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIOAuthClientRepositoryMockRecorder is the mock recorder for MockIOAuthClientRepository.
+type MockIOAuthClientRepositoryMockRecorder struct {
+	mock *MockIOAuthClientRepository
+}
+
+// NewMockIOAuthClientRepository creates a new mock instance.
+func NewMockIOAuthClientRepository(ctrl *gomock.Controller) *MockIOAuthClientRepository {
+	mock := &MockIOAuthClientRepository{ctrl: ctrl}
+	mock.recorder = &MockIOAuthClientRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOAuthClientRepository) EXPECT() *MockIOAuthClientRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockIOAuthClientRepository) Create(ctx context.Context, params repositories.CreateOAuthClientParams) (*models.OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, params)
+	ret0, _ := ret[0].(*models.OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIOAuthClientRepositoryMockRecorder) Create(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIOAuthClientRepository)(nil).Create), ctx, params)
+}
+
+// Delete mocks base method.
+func (m *MockIOAuthClientRepository) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIOAuthClientRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIOAuthClientRepository)(nil).Delete), ctx, id)
+}
+
+// GetConsent mocks base method.
+func (m *MockIOAuthClientRepository) GetConsent(ctx context.Context, userId uuid.UUID, clientId string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConsent", ctx, userId, clientId)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConsent indicates an expected call of GetConsent.
+func (mr *MockIOAuthClientRepositoryMockRecorder) GetConsent(ctx, userId, clientId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConsent", reflect.TypeOf((*MockIOAuthClientRepository)(nil).GetConsent), ctx, userId, clientId)
+}
+
+// GetOne mocks base method.
+func (m *MockIOAuthClientRepository) GetOne(ctx context.Context, id string) (*models.OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOne", ctx, id)
+	ret0, _ := ret[0].(*models.OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOne indicates an expected call of GetOne.
+func (mr *MockIOAuthClientRepositoryMockRecorder) GetOne(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOne", reflect.TypeOf((*MockIOAuthClientRepository)(nil).GetOne), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockIOAuthClientRepository) List(ctx context.Context) ([]models.OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]models.OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockIOAuthClientRepositoryMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockIOAuthClientRepository)(nil).List), ctx)
+}
+
+// SaveConsent mocks base method.
+func (m *MockIOAuthClientRepository) SaveConsent(ctx context.Context, userId uuid.UUID, clientId string, scopes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveConsent", ctx, userId, clientId, scopes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveConsent indicates an expected call of SaveConsent.
+func (mr *MockIOAuthClientRepositoryMockRecorder) SaveConsent(ctx, userId, clientId, scopes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveConsent", reflect.TypeOf((*MockIOAuthClientRepository)(nil).SaveConsent), ctx, userId, clientId, scopes)
+}
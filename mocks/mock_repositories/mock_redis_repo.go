@@ -13,6 +13,7 @@ import (
 	reflect "reflect"
 	time "time"
 
+	redis "github.com/redis/go-redis/v9"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -54,6 +55,26 @@ func (mr *MockIRedisRepositoryMockRecorder) Delete(key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIRedisRepository)(nil).Delete), key)
 }
 
+// EvalScript mocks base method.
+func (m *MockIRedisRepository) EvalScript(script *redis.Script, keys []string, args ...any) (any, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{script, keys}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EvalScript", varargs...)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EvalScript indicates an expected call of EvalScript.
+func (mr *MockIRedisRepositoryMockRecorder) EvalScript(script, keys any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{script, keys}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvalScript", reflect.TypeOf((*MockIRedisRepository)(nil).EvalScript), varargs...)
+}
+
 // HGet mocks base method.
 func (m *MockIRedisRepository) HGet(key, field string) (string, error) {
 	m.ctrl.T.Helper()
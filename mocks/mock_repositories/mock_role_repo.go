@@ -0,0 +1,191 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/role_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repositories/role_repository.go -destination=mocks/mock_repositories/mock_role_repo.go -package=mockrepositories
+//
+
+// Package mockrepositories is a generated GoMock package.
+package mockrepositories
+
+import (
+	context "context"
+	models "my-go-api/internal/models"
+	repositories "my-go-api/internal/repositories"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIRoleRepository is a mock of IRoleRepository interface.
+type MockIRoleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRoleRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIRoleRepositoryMockRecorder is the mock recorder for MockIRoleRepository.
+type MockIRoleRepositoryMockRecorder struct {
+	mock *MockIRoleRepository
+}
+
+// NewMockIRoleRepository creates a new mock instance.
+func NewMockIRoleRepository(ctrl *gomock.Controller) *MockIRoleRepository {
+	mock := &MockIRoleRepository{ctrl: ctrl}
+	mock.recorder = &MockIRoleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRoleRepository) EXPECT() *MockIRoleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateOne mocks base method.
+func (m *MockIRoleRepository) CreateOne(ctx context.Context, params repositories.CreateRoleParams) (*models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOne", ctx, params)
+	ret0, _ := ret[0].(*models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOne indicates an expected call of CreateOne.
+func (mr *MockIRoleRepositoryMockRecorder) CreateOne(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOne", reflect.TypeOf((*MockIRoleRepository)(nil).CreateOne), ctx, params)
+}
+
+// DeleteOne mocks base method.
+func (m *MockIRoleRepository) DeleteOne(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOne", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOne indicates an expected call of DeleteOne.
+func (mr *MockIRoleRepositoryMockRecorder) DeleteOne(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOne", reflect.TypeOf((*MockIRoleRepository)(nil).DeleteOne), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockIRoleRepository) GetAll(ctx context.Context) ([]models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockIRoleRepositoryMockRecorder) GetAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockIRoleRepository)(nil).GetAll), ctx)
+}
+
+// GetOne mocks base method.
+func (m *MockIRoleRepository) GetOne(ctx context.Context, id int) (*models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOne", ctx, id)
+	ret0, _ := ret[0].(*models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOne indicates an expected call of GetOne.
+func (mr *MockIRoleRepositoryMockRecorder) GetOne(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOne", reflect.TypeOf((*MockIRoleRepository)(nil).GetOne), ctx, id)
+}
+
+// GetPermissions mocks base method.
+func (m *MockIRoleRepository) GetPermissions(ctx context.Context) ([]models.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPermissions", ctx)
+	ret0, _ := ret[0].([]models.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPermissions indicates an expected call of GetPermissions.
+func (mr *MockIRoleRepositoryMockRecorder) GetPermissions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermissions", reflect.TypeOf((*MockIRoleRepository)(nil).GetPermissions), ctx)
+}
+
+// GetRoleUserIds mocks base method.
+func (m *MockIRoleRepository) GetRoleUserIds(ctx context.Context, id int) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoleUserIds", ctx, id)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoleUserIds indicates an expected call of GetRoleUserIds.
+func (mr *MockIRoleRepositoryMockRecorder) GetRoleUserIds(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleUserIds", reflect.TypeOf((*MockIRoleRepository)(nil).GetRoleUserIds), ctx, id)
+}
+
+// GetUserPermissions mocks base method.
+func (m *MockIRoleRepository) GetUserPermissions(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPermissions", ctx, userId)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPermissions indicates an expected call of GetUserPermissions.
+func (mr *MockIRoleRepositoryMockRecorder) GetUserPermissions(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPermissions", reflect.TypeOf((*MockIRoleRepository)(nil).GetUserPermissions), ctx, userId)
+}
+
+// GetUserRoles mocks base method.
+func (m *MockIRoleRepository) GetUserRoles(ctx context.Context, userId uuid.UUID) ([]models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRoles", ctx, userId)
+	ret0, _ := ret[0].([]models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRoles indicates an expected call of GetUserRoles.
+func (mr *MockIRoleRepositoryMockRecorder) GetUserRoles(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRoles", reflect.TypeOf((*MockIRoleRepository)(nil).GetUserRoles), ctx, userId)
+}
+
+// SetPermissions mocks base method.
+func (m *MockIRoleRepository) SetPermissions(ctx context.Context, id int, permissions []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPermissions", ctx, id, permissions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPermissions indicates an expected call of SetPermissions.
+func (mr *MockIRoleRepositoryMockRecorder) SetPermissions(ctx, id, permissions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPermissions", reflect.TypeOf((*MockIRoleRepository)(nil).SetPermissions), ctx, id, permissions)
+}
+
+// SetUserRoles mocks base method.
+func (m *MockIRoleRepository) SetUserRoles(ctx context.Context, userId uuid.UUID, roles []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRoles", ctx, userId, roles)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserRoles indicates an expected call of SetUserRoles.
+func (mr *MockIRoleRepositoryMockRecorder) SetUserRoles(ctx, userId, roles any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRoles", reflect.TypeOf((*MockIRoleRepository)(nil).SetUserRoles), ctx, userId, roles)
+}
@@ -0,0 +1,147 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repositories/signing_key_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repositories/signing_key_repository.go -destination=mocks/mock_repositories/mock_signing_key_repo.go -package=mockrepositories
+//
+
+// Package mockrepositories is a generated GoMock package.
+package mockrepositories
+
+import (
+	context "context"
+	models "my-go-api/internal/models"
+	repositories "my-go-api/internal/repositories"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockISigningKeyRepository is a mock of ISigningKeyRepository interface.
+type MockISigningKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockISigningKeyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockISigningKeyRepositoryMockRecorder is the mock recorder for MockISigningKeyRepository.
+type MockISigningKeyRepositoryMockRecorder struct {
+	mock *MockISigningKeyRepository
+}
+
+// NewMockISigningKeyRepository creates a new mock instance.
+func NewMockISigningKeyRepository(ctrl *gomock.Controller) *MockISigningKeyRepository {
+	mock := &MockISigningKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockISigningKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISigningKeyRepository) EXPECT() *MockISigningKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Bootstrap mocks base method.
+func (m *MockISigningKeyRepository) Bootstrap(ctx context.Context, params repositories.CreateSigningKeyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Bootstrap", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Bootstrap indicates an expected call of Bootstrap.
+func (mr *MockISigningKeyRepositoryMockRecorder) Bootstrap(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bootstrap", reflect.TypeOf((*MockISigningKeyRepository)(nil).Bootstrap), ctx, params)
+}
+
+// Create mocks base method.
+func (m *MockISigningKeyRepository) Create(ctx context.Context, params repositories.CreateSigningKeyParams) (*models.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, params)
+	ret0, _ := ret[0].(*models.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockISigningKeyRepositoryMockRecorder) Create(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockISigningKeyRepository)(nil).Create), ctx, params)
+}
+
+// GetOne mocks base method.
+func (m *MockISigningKeyRepository) GetOne(ctx context.Context, id string) (*models.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOne", ctx, id)
+	ret0, _ := ret[0].(*models.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOne indicates an expected call of GetOne.
+func (mr *MockISigningKeyRepositoryMockRecorder) GetOne(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOne", reflect.TypeOf((*MockISigningKeyRepository)(nil).GetOne), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockISigningKeyRepository) List(ctx context.Context) ([]models.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]models.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockISigningKeyRepositoryMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockISigningKeyRepository)(nil).List), ctx)
+}
+
+// ListAll mocks base method.
+func (m *MockISigningKeyRepository) ListAll(ctx context.Context) ([]models.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAll", ctx)
+	ret0, _ := ret[0].([]models.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAll indicates an expected call of ListAll.
+func (mr *MockISigningKeyRepositoryMockRecorder) ListAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockISigningKeyRepository)(nil).ListAll), ctx)
+}
+
+// Promote mocks base method.
+func (m *MockISigningKeyRepository) Promote(ctx context.Context, id string) (*models.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Promote", ctx, id)
+	ret0, _ := ret[0].(*models.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Promote indicates an expected call of Promote.
+func (mr *MockISigningKeyRepositoryMockRecorder) Promote(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Promote", reflect.TypeOf((*MockISigningKeyRepository)(nil).Promote), ctx, id)
+}
+
+// Retire mocks base method.
+func (m *MockISigningKeyRepository) Retire(ctx context.Context, id string) (*models.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retire", ctx, id)
+	ret0, _ := ret[0].(*models.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Retire indicates an expected call of Retire.
+func (mr *MockISigningKeyRepositoryMockRecorder) Retire(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retire", reflect.TypeOf((*MockISigningKeyRepository)(nil).Retire), ctx, id)
+}
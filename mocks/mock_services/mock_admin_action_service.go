@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/admin_action_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/admin_action_service.go -destination=mocks/mock_services/mock_admin_action_service.go -package=mockservices
+//
+
+// Package mockservices is a generated GoMock package.
+package mockservices
+
+import (
+	context "context"
+	models "my-go-api/internal/models"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIAdminActionService is a mock of IAdminActionService interface.
+type MockIAdminActionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAdminActionServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockIAdminActionServiceMockRecorder is the mock recorder for MockIAdminActionService.
+type MockIAdminActionServiceMockRecorder struct {
+	mock *MockIAdminActionService
+}
+
+// NewMockIAdminActionService creates a new mock instance.
+func NewMockIAdminActionService(ctrl *gomock.Controller) *MockIAdminActionService {
+	mock := &MockIAdminActionService{ctrl: ctrl}
+	mock.recorder = &MockIAdminActionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAdminActionService) EXPECT() *MockIAdminActionServiceMockRecorder {
+	return m.recorder
+}
+
+// GetUserActions mocks base method.
+func (m *MockIAdminActionService) GetUserActions(ctx context.Context, userId uuid.UUID) ([]models.AdminAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserActions", ctx, userId)
+	ret0, _ := ret[0].([]models.AdminAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserActions indicates an expected call of GetUserActions.
+func (mr *MockIAdminActionServiceMockRecorder) GetUserActions(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserActions", reflect.TypeOf((*MockIAdminActionService)(nil).GetUserActions), ctx, userId)
+}
+
+// Record mocks base method.
+func (m *MockIAdminActionService) Record(ctx context.Context, adminId, targetUserId uuid.UUID, action string, details map[string]any) (*models.AdminAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, adminId, targetUserId, action, details)
+	ret0, _ := ret[0].(*models.AdminAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockIAdminActionServiceMockRecorder) Record(ctx, adminId, targetUserId, action, details any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockIAdminActionService)(nil).Record), ctx, adminId, targetUserId, action, details)
+}
@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/audit_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/audit_service.go -destination=mocks/mock_services/mock_audit_service.go -package=mockservices
+//
+
+// Package mockservices is a generated GoMock package.
+package mockservices
+
+import (
+	context "context"
+	ed25519 "crypto/ed25519"
+	models "my-go-api/internal/models"
+	repositories "my-go-api/internal/repositories"
+	services "my-go-api/internal/services"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIAuditService is a mock of IAuditService interface.
+type MockIAuditService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAuditServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockIAuditServiceMockRecorder is the mock recorder for MockIAuditService.
+type MockIAuditServiceMockRecorder struct {
+	mock *MockIAuditService
+}
+
+// NewMockIAuditService creates a new mock instance.
+func NewMockIAuditService(ctrl *gomock.Controller) *MockIAuditService {
+	mock := &MockIAuditService{ctrl: ctrl}
+	mock.recorder = &MockIAuditServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAuditService) EXPECT() *MockIAuditServiceMockRecorder {
+	return m.recorder
+}
+
+// Chains mocks base method.
+func (m *MockIAuditService) Chains(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Chains", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Chains indicates an expected call of Chains.
+func (mr *MockIAuditServiceMockRecorder) Chains(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Chains", reflect.TypeOf((*MockIAuditService)(nil).Chains), ctx)
+}
+
+// Checkpoint mocks base method.
+func (m *MockIAuditService) Checkpoint(ctx context.Context, chain string) (*models.AuditCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkpoint", ctx, chain)
+	ret0, _ := ret[0].(*models.AuditCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Checkpoint indicates an expected call of Checkpoint.
+func (mr *MockIAuditServiceMockRecorder) Checkpoint(ctx, chain any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkpoint", reflect.TypeOf((*MockIAuditService)(nil).Checkpoint), ctx, chain)
+}
+
+// Export mocks base method.
+func (m *MockIAuditService) Export(ctx context.Context, params repositories.ListAuditEventsParams, fn func(models.AuditEvent) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, params, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockIAuditServiceMockRecorder) Export(ctx, params, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockIAuditService)(nil).Export), ctx, params, fn)
+}
+
+// List mocks base method.
+func (m *MockIAuditService) List(ctx context.Context, params repositories.ListAuditEventsParams) ([]models.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, params)
+	ret0, _ := ret[0].([]models.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockIAuditServiceMockRecorder) List(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockIAuditService)(nil).List), ctx, params)
+}
+
+// Record mocks base method.
+func (m *MockIAuditService) Record(ctx context.Context, params services.AuditParams) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Record", ctx, params)
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockIAuditServiceMockRecorder) Record(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockIAuditService)(nil).Record), ctx, params)
+}
+
+// VerifyChain mocks base method.
+func (m *MockIAuditService) VerifyChain(ctx context.Context, chain string, publicKey ed25519.PublicKey) (*services.AuditChainReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyChain", ctx, chain, publicKey)
+	ret0, _ := ret[0].(*services.AuditChainReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyChain indicates an expected call of VerifyChain.
+func (mr *MockIAuditServiceMockRecorder) VerifyChain(ctx, chain, publicKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyChain", reflect.TypeOf((*MockIAuditService)(nil).VerifyChain), ctx, chain, publicKey)
+}
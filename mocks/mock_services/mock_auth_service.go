@@ -10,6 +10,8 @@
 package mockservices
 
 import (
+	context "context"
+	models "my-go-api/internal/models"
 	services "my-go-api/internal/services"
 	reflect "reflect"
 
@@ -41,19 +43,61 @@ func (m *MockIAuthService) EXPECT() *MockIAuthServiceMockRecorder {
 	return m.recorder
 }
 
+// ClaimRefreshToken mocks base method.
+func (m *MockIAuthService) ClaimRefreshToken(ctx context.Context, rawToken string, seed *services.RefreshTokenData) (services.RefreshTokenClaim, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimRefreshToken", ctx, rawToken, seed)
+	ret0, _ := ret[0].(services.RefreshTokenClaim)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimRefreshToken indicates an expected call of ClaimRefreshToken.
+func (mr *MockIAuthServiceMockRecorder) ClaimRefreshToken(ctx, rawToken, seed any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimRefreshToken", reflect.TypeOf((*MockIAuthService)(nil).ClaimRefreshToken), ctx, rawToken, seed)
+}
+
+// CompleteRefreshTokenRotation mocks base method.
+func (m *MockIAuthService) CompleteRefreshTokenRotation(rawToken string, result services.CreateAuthTokensResult) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CompleteRefreshTokenRotation", rawToken, result)
+}
+
+// CompleteRefreshTokenRotation indicates an expected call of CompleteRefreshTokenRotation.
+func (mr *MockIAuthServiceMockRecorder) CompleteRefreshTokenRotation(rawToken, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteRefreshTokenRotation", reflect.TypeOf((*MockIAuthService)(nil).CompleteRefreshTokenRotation), rawToken, result)
+}
+
 // CreateAuthTokens mocks base method.
-func (m *MockIAuthService) CreateAuthTokens(params services.CreateAuthTokenParams) (services.CreateAuthTokensResult, error) {
+func (m *MockIAuthService) CreateAuthTokens(ctx context.Context, params services.CreateAuthTokenParams) (services.CreateAuthTokensResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAuthTokens", params)
+	ret := m.ctrl.Call(m, "CreateAuthTokens", ctx, params)
 	ret0, _ := ret[0].(services.CreateAuthTokensResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateAuthTokens indicates an expected call of CreateAuthTokens.
-func (mr *MockIAuthServiceMockRecorder) CreateAuthTokens(params any) *gomock.Call {
+func (mr *MockIAuthServiceMockRecorder) CreateAuthTokens(ctx, params any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthTokens", reflect.TypeOf((*MockIAuthService)(nil).CreateAuthTokens), params)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthTokens", reflect.TypeOf((*MockIAuthService)(nil).CreateAuthTokens), ctx, params)
+}
+
+// CreateMagicLinkToken mocks base method.
+func (m *MockIAuthService) CreateMagicLinkToken(userId uuid.UUID) (services.MagicLinkTokenData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMagicLinkToken", userId)
+	ret0, _ := ret[0].(services.MagicLinkTokenData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMagicLinkToken indicates an expected call of CreateMagicLinkToken.
+func (mr *MockIAuthServiceMockRecorder) CreateMagicLinkToken(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMagicLinkToken", reflect.TypeOf((*MockIAuthService)(nil).CreateMagicLinkToken), userId)
 }
 
 // CreateVerificationToken mocks base method.
@@ -86,6 +130,74 @@ func (mr *MockIAuthServiceMockRecorder) GeneratePairToken() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GeneratePairToken", reflect.TypeOf((*MockIAuthService)(nil).GeneratePairToken))
 }
 
+// HandleRefreshTokenReuse mocks base method.
+func (m *MockIAuthService) HandleRefreshTokenReuse(ctx context.Context, hashedToken, ipAddress, userAgent string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleRefreshTokenReuse", ctx, hashedToken, ipAddress, userAgent)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HandleRefreshTokenReuse indicates an expected call of HandleRefreshTokenReuse.
+func (mr *MockIAuthServiceMockRecorder) HandleRefreshTokenReuse(ctx, hashedToken, ipAddress, userAgent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleRefreshTokenReuse", reflect.TypeOf((*MockIAuthService)(nil).HandleRefreshTokenReuse), ctx, hashedToken, ipAddress, userAgent)
+}
+
+// RedeemMagicLink mocks base method.
+func (m *MockIAuthService) RedeemMagicLink(linkToken string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeemMagicLink", linkToken)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeemMagicLink indicates an expected call of RedeemMagicLink.
+func (mr *MockIAuthServiceMockRecorder) RedeemMagicLink(linkToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemMagicLink", reflect.TypeOf((*MockIAuthService)(nil).RedeemMagicLink), linkToken)
+}
+
+// RedeemMagicLinkCode mocks base method.
+func (m *MockIAuthService) RedeemMagicLinkCode(rawToken, code string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeemMagicLinkCode", rawToken, code)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeemMagicLinkCode indicates an expected call of RedeemMagicLinkCode.
+func (mr *MockIAuthServiceMockRecorder) RedeemMagicLinkCode(rawToken, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemMagicLinkCode", reflect.TypeOf((*MockIAuthService)(nil).RedeemMagicLinkCode), rawToken, code)
+}
+
+// ReleaseRefreshTokenClaim mocks base method.
+func (m *MockIAuthService) ReleaseRefreshTokenClaim(hashedToken string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReleaseRefreshTokenClaim", hashedToken)
+}
+
+// ReleaseRefreshTokenClaim indicates an expected call of ReleaseRefreshTokenClaim.
+func (mr *MockIAuthServiceMockRecorder) ReleaseRefreshTokenClaim(hashedToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseRefreshTokenClaim", reflect.TypeOf((*MockIAuthService)(nil).ReleaseRefreshTokenClaim), hashedToken)
+}
+
+// RevokeSessionTokens mocks base method.
+func (m *MockIAuthService) RevokeSessionTokens(sessions []models.Token) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RevokeSessionTokens", sessions)
+}
+
+// RevokeSessionTokens indicates an expected call of RevokeSessionTokens.
+func (mr *MockIAuthServiceMockRecorder) RevokeSessionTokens(sessions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessionTokens", reflect.TypeOf((*MockIAuthService)(nil).RevokeSessionTokens), sessions)
+}
+
 // VerifyVerificationToken mocks base method.
 func (m *MockIAuthService) VerifyVerificationToken(params services.VerificationTokenData) (string, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/email_outbox_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/email_outbox_service.go -destination=mocks/mock_services/mock_email_outbox_service.go -package=mockservices
+//
+
+// Package mockservices is a generated GoMock package.
+package mockservices
+
+import (
+	context "context"
+	models "my-go-api/internal/models"
+	repositories "my-go-api/internal/repositories"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIEmailOutboxService is a mock of IEmailOutboxService interface.
+type MockIEmailOutboxService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIEmailOutboxServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockIEmailOutboxServiceMockRecorder is the mock recorder for MockIEmailOutboxService.
+type MockIEmailOutboxServiceMockRecorder struct {
+	mock *MockIEmailOutboxService
+}
+
+// NewMockIEmailOutboxService creates a new mock instance.
+func NewMockIEmailOutboxService(ctrl *gomock.Controller) *MockIEmailOutboxService {
+	mock := &MockIEmailOutboxService{ctrl: ctrl}
+	mock.recorder = &MockIEmailOutboxServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIEmailOutboxService) EXPECT() *MockIEmailOutboxServiceMockRecorder {
+	return m.recorder
+}
+
+// DeliverPending mocks base method.
+func (m *MockIEmailOutboxService) DeliverPending(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverPending", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeliverPending indicates an expected call of DeliverPending.
+func (mr *MockIEmailOutboxServiceMockRecorder) DeliverPending(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverPending", reflect.TypeOf((*MockIEmailOutboxService)(nil).DeliverPending), ctx)
+}
+
+// List mocks base method.
+func (m *MockIEmailOutboxService) List(ctx context.Context, params repositories.ListOutboxEmailsParams) ([]models.OutboxEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, params)
+	ret0, _ := ret[0].([]models.OutboxEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockIEmailOutboxServiceMockRecorder) List(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockIEmailOutboxService)(nil).List), ctx, params)
+}
+
+// Retry mocks base method.
+func (m *MockIEmailOutboxService) Retry(ctx context.Context, id int64) (*models.OutboxEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retry", ctx, id)
+	ret0, _ := ret[0].(*models.OutboxEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Retry indicates an expected call of Retry.
+func (mr *MockIEmailOutboxServiceMockRecorder) Retry(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retry", reflect.TypeOf((*MockIEmailOutboxService)(nil).Retry), ctx, id)
+}
+
+// Start mocks base method.
+func (m *MockIEmailOutboxService) Start(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Start", ctx)
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockIEmailOutboxServiceMockRecorder) Start(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockIEmailOutboxService)(nil).Start), ctx)
+}
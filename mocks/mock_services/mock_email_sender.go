@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/email_sender.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/email_sender.go -destination=mocks/mock_services/mock_email_sender.go -package=mockservices
+//
+
+// Package mockservices is a generated GoMock package.
+package mockservices
+
+import (
+	context "context"
+	services "my-go-api/internal/services"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIEmailSender is a mock of IEmailSender interface.
+type MockIEmailSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockIEmailSenderMockRecorder
+	isgomock struct{}
+}
+
+// MockIEmailSenderMockRecorder is the mock recorder for MockIEmailSender.
+type MockIEmailSenderMockRecorder struct {
+	mock *MockIEmailSender
+}
+
+// NewMockIEmailSender creates a new mock instance.
+func NewMockIEmailSender(ctrl *gomock.Controller) *MockIEmailSender {
+	mock := &MockIEmailSender{ctrl: ctrl}
+	mock.recorder = &MockIEmailSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIEmailSender) EXPECT() *MockIEmailSenderMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockIEmailSender) Send(ctx context.Context, message services.EmailMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockIEmailSenderMockRecorder) Send(ctx, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockIEmailSender)(nil).Send), ctx, message)
+}
@@ -10,6 +10,9 @@
 package mockservices
 
 import (
+	context "context"
+	models "my-go-api/internal/models"
+	repositories "my-go-api/internal/repositories"
 	services "my-go-api/internal/services"
 	reflect "reflect"
 
@@ -40,6 +43,77 @@ func (m *MockIEmailService) EXPECT() *MockIEmailServiceMockRecorder {
 	return m.recorder
 }
 
+// Deliver mocks base method.
+func (m *MockIEmailService) Deliver(ctx context.Context, email models.OutboxEmail) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deliver", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Deliver indicates an expected call of Deliver.
+func (mr *MockIEmailServiceMockRecorder) Deliver(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deliver", reflect.TypeOf((*MockIEmailService)(nil).Deliver), ctx, email)
+}
+
+// Preview mocks base method.
+func (m *MockIEmailService) Preview(template, locale string) (services.RenderedEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Preview", template, locale)
+	ret0, _ := ret[0].(services.RenderedEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Preview indicates an expected call of Preview.
+func (mr *MockIEmailServiceMockRecorder) Preview(template, locale any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preview", reflect.TypeOf((*MockIEmailService)(nil).Preview), template, locale)
+}
+
+// SendAccountLockedEmail mocks base method.
+func (m *MockIEmailService) SendAccountLockedEmail(params services.SendAccountLockedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendAccountLockedEmail", params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendAccountLockedEmail indicates an expected call of SendAccountLockedEmail.
+func (mr *MockIEmailServiceMockRecorder) SendAccountLockedEmail(params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendAccountLockedEmail", reflect.TypeOf((*MockIEmailService)(nil).SendAccountLockedEmail), params)
+}
+
+// SendMagicLinkEmail mocks base method.
+func (m *MockIEmailService) SendMagicLinkEmail(params services.SendMagicLinkParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMagicLinkEmail", params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMagicLinkEmail indicates an expected call of SendMagicLinkEmail.
+func (mr *MockIEmailServiceMockRecorder) SendMagicLinkEmail(params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMagicLinkEmail", reflect.TypeOf((*MockIEmailService)(nil).SendMagicLinkEmail), params)
+}
+
+// SendPasswordResetRequest mocks base method.
+func (m *MockIEmailService) SendPasswordResetRequest(params services.SendPasswordResetParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendPasswordResetRequest", params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendPasswordResetRequest indicates an expected call of SendPasswordResetRequest.
+func (mr *MockIEmailServiceMockRecorder) SendPasswordResetRequest(params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendPasswordResetRequest", reflect.TypeOf((*MockIEmailService)(nil).SendPasswordResetRequest), params)
+}
+
 // SendVerificationEmail mocks base method.
 func (m *MockIEmailService) SendVerificationEmail(params services.SendEmailVerificationParams) error {
 	m.ctrl.T.Helper()
@@ -53,3 +127,33 @@ func (mr *MockIEmailServiceMockRecorder) SendVerificationEmail(params any) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendVerificationEmail", reflect.TypeOf((*MockIEmailService)(nil).SendVerificationEmail), params)
 }
+
+// Templates mocks base method.
+func (m *MockIEmailService) Templates() ([]string, []string) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Templates")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].([]string)
+	return ret0, ret1
+}
+
+// Templates indicates an expected call of Templates.
+func (mr *MockIEmailServiceMockRecorder) Templates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Templates", reflect.TypeOf((*MockIEmailService)(nil).Templates))
+}
+
+// VerificationEmail mocks base method.
+func (m *MockIEmailService) VerificationEmail(params services.SendEmailVerificationParams) (repositories.EnqueueEmailParams, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerificationEmail", params)
+	ret0, _ := ret[0].(repositories.EnqueueEmailParams)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerificationEmail indicates an expected call of VerificationEmail.
+func (mr *MockIEmailServiceMockRecorder) VerificationEmail(params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerificationEmail", reflect.TypeOf((*MockIEmailService)(nil).VerificationEmail), params)
+}
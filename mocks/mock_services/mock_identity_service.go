@@ -0,0 +1,133 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/identity_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/identity_service.go -destination=mocks/mock_services/mock_identity_service.go -package=mockservices
+//
+
+// Package mockservices is a generated GoMock package.
+package mockservices
+
+import (
+	context "context"
+	models "my-go-api/internal/models"
+	repositories "my-go-api/internal/repositories"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIIdentityService is a mock of IIdentityService interface.
+type MockIIdentityService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIIdentityServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockIIdentityServiceMockRecorder is the mock recorder for MockIIdentityService.
+type MockIIdentityServiceMockRecorder struct {
+	mock *MockIIdentityService
+}
+
+// NewMockIIdentityService creates a new mock instance.
+func NewMockIIdentityService(ctrl *gomock.Controller) *MockIIdentityService {
+	mock := &MockIIdentityService{ctrl: ctrl}
+	mock.recorder = &MockIIdentityServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIIdentityService) EXPECT() *MockIIdentityServiceMockRecorder {
+	return m.recorder
+}
+
+// GetIdentity mocks base method.
+func (m *MockIIdentityService) GetIdentity(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdentity", ctx, provider, subject)
+	ret0, _ := ret[0].(*models.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdentity indicates an expected call of GetIdentity.
+func (mr *MockIIdentityServiceMockRecorder) GetIdentity(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdentity", reflect.TypeOf((*MockIIdentityService)(nil).GetIdentity), ctx, provider, subject)
+}
+
+// GetUserIdentities mocks base method.
+func (m *MockIIdentityService) GetUserIdentities(ctx context.Context, userId uuid.UUID) ([]models.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIdentities", ctx, userId)
+	ret0, _ := ret[0].([]models.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIdentities indicates an expected call of GetUserIdentities.
+func (mr *MockIIdentityServiceMockRecorder) GetUserIdentities(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIdentities", reflect.TypeOf((*MockIIdentityService)(nil).GetUserIdentities), ctx, userId)
+}
+
+// LinkCredentials mocks base method.
+func (m *MockIIdentityService) LinkCredentials(ctx context.Context, user *models.User) (*models.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkCredentials", ctx, user)
+	ret0, _ := ret[0].(*models.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LinkCredentials indicates an expected call of LinkCredentials.
+func (mr *MockIIdentityServiceMockRecorder) LinkCredentials(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkCredentials", reflect.TypeOf((*MockIIdentityService)(nil).LinkCredentials), ctx, user)
+}
+
+// LinkIdentity mocks base method.
+func (m *MockIIdentityService) LinkIdentity(ctx context.Context, params repositories.CreateIdentityParams) (*models.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkIdentity", ctx, params)
+	ret0, _ := ret[0].(*models.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LinkIdentity indicates an expected call of LinkIdentity.
+func (mr *MockIIdentityServiceMockRecorder) LinkIdentity(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkIdentity", reflect.TypeOf((*MockIIdentityService)(nil).LinkIdentity), ctx, params)
+}
+
+// RemoveCredentials mocks base method.
+func (m *MockIIdentityService) RemoveCredentials(ctx context.Context, userId uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCredentials", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCredentials indicates an expected call of RemoveCredentials.
+func (mr *MockIIdentityServiceMockRecorder) RemoveCredentials(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCredentials", reflect.TypeOf((*MockIIdentityService)(nil).RemoveCredentials), ctx, userId)
+}
+
+// UnlinkIdentity mocks base method.
+func (m *MockIIdentityService) UnlinkIdentity(ctx context.Context, userId uuid.UUID, id int) (*models.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlinkIdentity", ctx, userId, id)
+	ret0, _ := ret[0].(*models.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnlinkIdentity indicates an expected call of UnlinkIdentity.
+func (mr *MockIIdentityServiceMockRecorder) UnlinkIdentity(ctx, userId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlinkIdentity", reflect.TypeOf((*MockIIdentityService)(nil).UnlinkIdentity), ctx, userId, id)
+}
@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=internal/services/jwt_service.go -destination=internal/mocks/mock_services/mock_jwt_service.go -package=mockservices
+//	mockgen -source=internal/services/jwt_service.go -destination=mocks/mock_services/mock_jwt_service.go -package=mockservices
 //
 
 // Package mockservices is a generated GoMock package.
@@ -13,6 +13,7 @@ import (
 	services "my-go-api/internal/services"
 	reflect "reflect"
 
+	jwt "github.com/golang-jwt/jwt/v5"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -55,6 +56,35 @@ func (mr *MockIJwtServiceMockRecorder) Create(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIJwtService)(nil).Create), arg0)
 }
 
+// JWKS mocks base method.
+func (m *MockIJwtService) JWKS() services.JSONWebKeySet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(services.JSONWebKeySet)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockIJwtServiceMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockIJwtService)(nil).JWKS))
+}
+
+// Sign mocks base method.
+func (m *MockIJwtService) Sign(claims jwt.Claims) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sign", claims)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sign indicates an expected call of Sign.
+func (mr *MockIJwtServiceMockRecorder) Sign(claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockIJwtService)(nil).Sign), claims)
+}
+
 // Verify mocks base method.
 func (m *MockIJwtService) Verify(tokenString string) (services.JWTPayload, error) {
 	m.ctrl.T.Helper()
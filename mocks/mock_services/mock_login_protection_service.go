@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/login_protection_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/login_protection_service.go -destination=mocks/mock_services/mock_login_protection_service.go -package=mockservices
+//
+
+// Package mockservices is a generated GoMock package.
+package mockservices
+
+import (
+	context "context"
+	models "my-go-api/internal/models"
+	services "my-go-api/internal/services"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockILoginProtectionService is a mock of ILoginProtectionService interface.
+type MockILoginProtectionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockILoginProtectionServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockILoginProtectionServiceMockRecorder is the mock recorder for MockILoginProtectionService.
+type MockILoginProtectionServiceMockRecorder struct {
+	mock *MockILoginProtectionService
+}
+
+// NewMockILoginProtectionService creates a new mock instance.
+func NewMockILoginProtectionService(ctrl *gomock.Controller) *MockILoginProtectionService {
+	mock := &MockILoginProtectionService{ctrl: ctrl}
+	mock.recorder = &MockILoginProtectionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILoginProtectionService) EXPECT() *MockILoginProtectionServiceMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockILoginProtectionService) Check(ctx context.Context, subject, ipAddress string) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, subject, ipAddress)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockILoginProtectionServiceMockRecorder) Check(ctx, subject, ipAddress any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockILoginProtectionService)(nil).Check), ctx, subject, ipAddress)
+}
+
+// GetLockState mocks base method.
+func (m *MockILoginProtectionService) GetLockState(ctx context.Context, userId uuid.UUID) (services.LoginLockState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLockState", ctx, userId)
+	ret0, _ := ret[0].(services.LoginLockState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLockState indicates an expected call of GetLockState.
+func (mr *MockILoginProtectionServiceMockRecorder) GetLockState(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLockState", reflect.TypeOf((*MockILoginProtectionService)(nil).GetLockState), ctx, userId)
+}
+
+// RecordFailure mocks base method.
+func (m *MockILoginProtectionService) RecordFailure(ctx context.Context, user *models.User, subject, ipAddress, userAgent string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, user, subject, ipAddress, userAgent)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockILoginProtectionServiceMockRecorder) RecordFailure(ctx, user, subject, ipAddress, userAgent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockILoginProtectionService)(nil).RecordFailure), ctx, user, subject, ipAddress, userAgent)
+}
+
+// RecordSuccess mocks base method.
+func (m *MockILoginProtectionService) RecordSuccess(ctx context.Context, subject string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordSuccess", ctx, subject)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordSuccess indicates an expected call of RecordSuccess.
+func (mr *MockILoginProtectionServiceMockRecorder) RecordSuccess(ctx, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSuccess", reflect.TypeOf((*MockILoginProtectionService)(nil).RecordSuccess), ctx, subject)
+}
+
+// Unlock mocks base method.
+func (m *MockILoginProtectionService) Unlock(ctx context.Context, userId uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockILoginProtectionServiceMockRecorder) Unlock(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockILoginProtectionService)(nil).Unlock), ctx, userId)
+}
@@ -0,0 +1,206 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/mfa_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/mfa_service.go -destination=mocks/mock_services/mock_mfa_service.go -package=mockservices
+//
+
+// Package mockservices is a generated GoMock package.
+package mockservices
+
+import (
+	context "context"
+	models "my-go-api/internal/models"
+	services "my-go-api/internal/services"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIMfaService is a mock of IMfaService interface.
+type MockIMfaService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIMfaServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockIMfaServiceMockRecorder is the mock recorder for MockIMfaService.
+type MockIMfaServiceMockRecorder struct {
+	mock *MockIMfaService
+}
+
+// NewMockIMfaService creates a new mock instance.
+func NewMockIMfaService(ctrl *gomock.Controller) *MockIMfaService {
+	mock := &MockIMfaService{ctrl: ctrl}
+	mock.recorder = &MockIMfaServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIMfaService) EXPECT() *MockIMfaServiceMockRecorder {
+	return m.recorder
+}
+
+// BeginEnrollment mocks base method.
+func (m *MockIMfaService) BeginEnrollment(ctx context.Context, user *models.User) (services.TotpEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginEnrollment", ctx, user)
+	ret0, _ := ret[0].(services.TotpEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginEnrollment indicates an expected call of BeginEnrollment.
+func (mr *MockIMfaServiceMockRecorder) BeginEnrollment(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginEnrollment", reflect.TypeOf((*MockIMfaService)(nil).BeginEnrollment), ctx, user)
+}
+
+// CompleteChallenge mocks base method.
+func (m *MockIMfaService) CompleteChallenge(token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteChallenge", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteChallenge indicates an expected call of CompleteChallenge.
+func (mr *MockIMfaServiceMockRecorder) CompleteChallenge(token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteChallenge", reflect.TypeOf((*MockIMfaService)(nil).CompleteChallenge), token)
+}
+
+// ConfirmEnrollment mocks base method.
+func (m *MockIMfaService) ConfirmEnrollment(ctx context.Context, userId uuid.UUID, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmEnrollment", ctx, userId, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmEnrollment indicates an expected call of ConfirmEnrollment.
+func (mr *MockIMfaServiceMockRecorder) ConfirmEnrollment(ctx, userId, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmEnrollment", reflect.TypeOf((*MockIMfaService)(nil).ConfirmEnrollment), ctx, userId, code)
+}
+
+// CreateChallenge mocks base method.
+func (m *MockIMfaService) CreateChallenge(userId uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChallenge", userId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChallenge indicates an expected call of CreateChallenge.
+func (mr *MockIMfaServiceMockRecorder) CreateChallenge(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChallenge", reflect.TypeOf((*MockIMfaService)(nil).CreateChallenge), userId)
+}
+
+// Disable mocks base method.
+func (m *MockIMfaService) Disable(ctx context.Context, userId uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockIMfaServiceMockRecorder) Disable(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockIMfaService)(nil).Disable), ctx, userId)
+}
+
+// GetStatus mocks base method.
+func (m *MockIMfaService) GetStatus(ctx context.Context, userId uuid.UUID) (services.MfaStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus", ctx, userId)
+	ret0, _ := ret[0].(services.MfaStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockIMfaServiceMockRecorder) GetStatus(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockIMfaService)(nil).GetStatus), ctx, userId)
+}
+
+// IsEnabled mocks base method.
+func (m *MockIMfaService) IsEnabled(ctx context.Context, userId uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEnabled", ctx, userId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsEnabled indicates an expected call of IsEnabled.
+func (mr *MockIMfaServiceMockRecorder) IsEnabled(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEnabled", reflect.TypeOf((*MockIMfaService)(nil).IsEnabled), ctx, userId)
+}
+
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockIMfaService) RegenerateRecoveryCodes(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", ctx, userId)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockIMfaServiceMockRecorder) RegenerateRecoveryCodes(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockIMfaService)(nil).RegenerateRecoveryCodes), ctx, userId)
+}
+
+// UseChallenge mocks base method.
+func (m *MockIMfaService) UseChallenge(token string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseChallenge", token)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseChallenge indicates an expected call of UseChallenge.
+func (mr *MockIMfaServiceMockRecorder) UseChallenge(token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseChallenge", reflect.TypeOf((*MockIMfaService)(nil).UseChallenge), token)
+}
+
+// VerifyChallenge mocks base method.
+func (m *MockIMfaService) VerifyChallenge(ctx context.Context, token, code string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyChallenge", ctx, token, code)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyChallenge indicates an expected call of VerifyChallenge.
+func (mr *MockIMfaServiceMockRecorder) VerifyChallenge(ctx, token, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyChallenge", reflect.TypeOf((*MockIMfaService)(nil).VerifyChallenge), ctx, token, code)
+}
+
+// VerifyCode mocks base method.
+func (m *MockIMfaService) VerifyCode(ctx context.Context, userId uuid.UUID, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyCode", ctx, userId, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyCode indicates an expected call of VerifyCode.
+func (mr *MockIMfaServiceMockRecorder) VerifyCode(ctx, userId, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyCode", reflect.TypeOf((*MockIMfaService)(nil).VerifyCode), ctx, userId, code)
+}
@@ -0,0 +1,189 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/oauth_server_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/oauth_server_service.go -destination=mocks/mock_services/mock_oauth_server_service.go -package=mockservices
+//
+
+// Package mockservices is a generated GoMock package.
+package mockservices
+
+import (
+	context "context"
+	models "my-go-api/internal/models"
+	services "my-go-api/internal/services"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIOAuthServerService is a mock of IOAuthServerService interface.
+type MockIOAuthServerService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOAuthServerServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockIOAuthServerServiceMockRecorder is the mock recorder for MockIOAuthServerService.
+type MockIOAuthServerServiceMockRecorder struct {
+	mock *MockIOAuthServerService
+}
+
+// NewMockIOAuthServerService creates a new mock instance.
+func NewMockIOAuthServerService(ctrl *gomock.Controller) *MockIOAuthServerService {
+	mock := &MockIOAuthServerService{ctrl: ctrl}
+	mock.recorder = &MockIOAuthServerServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOAuthServerService) EXPECT() *MockIOAuthServerServiceMockRecorder {
+	return m.recorder
+}
+
+// Authorize mocks base method.
+func (m *MockIOAuthServerService) Authorize(ctx context.Context, params services.AuthorizeParams) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx, params)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockIOAuthServerServiceMockRecorder) Authorize(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockIOAuthServerService)(nil).Authorize), ctx, params)
+}
+
+// Configuration mocks base method.
+func (m *MockIOAuthServerService) Configuration() services.OpenIDConfiguration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Configuration")
+	ret0, _ := ret[0].(services.OpenIDConfiguration)
+	return ret0
+}
+
+// Configuration indicates an expected call of Configuration.
+func (mr *MockIOAuthServerServiceMockRecorder) Configuration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Configuration", reflect.TypeOf((*MockIOAuthServerService)(nil).Configuration))
+}
+
+// Decide mocks base method.
+func (m *MockIOAuthServerService) Decide(ctx context.Context, requestId string, userId uuid.UUID, approve bool) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decide", ctx, requestId, userId, approve)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decide indicates an expected call of Decide.
+func (mr *MockIOAuthServerServiceMockRecorder) Decide(ctx, requestId, userId, approve any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decide", reflect.TypeOf((*MockIOAuthServerService)(nil).Decide), ctx, requestId, userId, approve)
+}
+
+// DeleteClient mocks base method.
+func (m *MockIOAuthServerService) DeleteClient(ctx context.Context, clientId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClient", ctx, clientId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteClient indicates an expected call of DeleteClient.
+func (mr *MockIOAuthServerServiceMockRecorder) DeleteClient(ctx, clientId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClient", reflect.TypeOf((*MockIOAuthServerService)(nil).DeleteClient), ctx, clientId)
+}
+
+// Exchange mocks base method.
+func (m *MockIOAuthServerService) Exchange(ctx context.Context, params services.OAuthTokenParams) (*services.OAuthTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", ctx, params)
+	ret0, _ := ret[0].(*services.OAuthTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockIOAuthServerServiceMockRecorder) Exchange(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockIOAuthServerService)(nil).Exchange), ctx, params)
+}
+
+// GetAuthorization mocks base method.
+func (m *MockIOAuthServerService) GetAuthorization(ctx context.Context, requestId string, userId uuid.UUID) (*services.OAuthAuthorizationPrompt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorization", ctx, requestId, userId)
+	ret0, _ := ret[0].(*services.OAuthAuthorizationPrompt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorization indicates an expected call of GetAuthorization.
+func (mr *MockIOAuthServerServiceMockRecorder) GetAuthorization(ctx, requestId, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorization", reflect.TypeOf((*MockIOAuthServerService)(nil).GetAuthorization), ctx, requestId, userId)
+}
+
+// ListClients mocks base method.
+func (m *MockIOAuthServerService) ListClients(ctx context.Context) ([]models.OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClients", ctx)
+	ret0, _ := ret[0].([]models.OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClients indicates an expected call of ListClients.
+func (mr *MockIOAuthServerServiceMockRecorder) ListClients(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClients", reflect.TypeOf((*MockIOAuthServerService)(nil).ListClients), ctx)
+}
+
+// RegisterClient mocks base method.
+func (m *MockIOAuthServerService) RegisterClient(ctx context.Context, params services.RegisterOAuthClientParams) (*models.OAuthClient, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterClient", ctx, params)
+	ret0, _ := ret[0].(*models.OAuthClient)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RegisterClient indicates an expected call of RegisterClient.
+func (mr *MockIOAuthServerServiceMockRecorder) RegisterClient(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterClient", reflect.TypeOf((*MockIOAuthServerService)(nil).RegisterClient), ctx, params)
+}
+
+// Start mocks base method.
+func (m *MockIOAuthServerService) Start(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Start", ctx)
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockIOAuthServerServiceMockRecorder) Start(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockIOAuthServerService)(nil).Start), ctx)
+}
+
+// UserInfo mocks base method.
+func (m *MockIOAuthServerService) UserInfo(user *models.User, scope string) map[string]any {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserInfo", user, scope)
+	ret0, _ := ret[0].(map[string]any)
+	return ret0
+}
+
+// UserInfo indicates an expected call of UserInfo.
+func (mr *MockIOAuthServerServiceMockRecorder) UserInfo(user, scope any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserInfo", reflect.TypeOf((*MockIOAuthServerService)(nil).UserInfo), user, scope)
+}
@@ -0,0 +1,170 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/oauth_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/oauth_service.go -destination=mocks/mock_services/mock_oauth_service.go -package=mockservices
+//
+
+// Package mockservices is a generated GoMock package.
+package mockservices
+
+import (
+	context "context"
+	services "my-go-api/internal/services"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIOAuthProvider is a mock of IOAuthProvider interface.
+type MockIOAuthProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOAuthProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockIOAuthProviderMockRecorder is the mock recorder for MockIOAuthProvider.
+type MockIOAuthProviderMockRecorder struct {
+	mock *MockIOAuthProvider
+}
+
+// NewMockIOAuthProvider creates a new mock instance.
+func NewMockIOAuthProvider(ctrl *gomock.Controller) *MockIOAuthProvider {
+	mock := &MockIOAuthProvider{ctrl: ctrl}
+	mock.recorder = &MockIOAuthProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOAuthProvider) EXPECT() *MockIOAuthProviderMockRecorder {
+	return m.recorder
+}
+
+// AuthCodeURL mocks base method.
+func (m *MockIOAuthProvider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthCodeURL", ctx, state, nonce, verifier)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthCodeURL indicates an expected call of AuthCodeURL.
+func (mr *MockIOAuthProviderMockRecorder) AuthCodeURL(ctx, state, nonce, verifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthCodeURL", reflect.TypeOf((*MockIOAuthProvider)(nil).AuthCodeURL), ctx, state, nonce, verifier)
+}
+
+// Exchange mocks base method.
+func (m *MockIOAuthProvider) Exchange(ctx context.Context, code, nonce, verifier string) (services.OAuthProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", ctx, code, nonce, verifier)
+	ret0, _ := ret[0].(services.OAuthProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockIOAuthProviderMockRecorder) Exchange(ctx, code, nonce, verifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockIOAuthProvider)(nil).Exchange), ctx, code, nonce, verifier)
+}
+
+// Name mocks base method.
+func (m *MockIOAuthProvider) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockIOAuthProviderMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockIOAuthProvider)(nil).Name))
+}
+
+// MockIOAuthService is a mock of IOAuthService interface.
+type MockIOAuthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOAuthServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockIOAuthServiceMockRecorder is the mock recorder for MockIOAuthService.
+type MockIOAuthServiceMockRecorder struct {
+	mock *MockIOAuthService
+}
+
+// NewMockIOAuthService creates a new mock instance.
+func NewMockIOAuthService(ctrl *gomock.Controller) *MockIOAuthService {
+	mock := &MockIOAuthService{ctrl: ctrl}
+	mock.recorder = &MockIOAuthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOAuthService) EXPECT() *MockIOAuthServiceMockRecorder {
+	return m.recorder
+}
+
+// AuthCodeURL mocks base method.
+func (m *MockIOAuthService) AuthCodeURL(ctx context.Context, provider string) (services.OAuthFlow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthCodeURL", ctx, provider)
+	ret0, _ := ret[0].(services.OAuthFlow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthCodeURL indicates an expected call of AuthCodeURL.
+func (mr *MockIOAuthServiceMockRecorder) AuthCodeURL(ctx, provider any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthCodeURL", reflect.TypeOf((*MockIOAuthService)(nil).AuthCodeURL), ctx, provider)
+}
+
+// Exchange mocks base method.
+func (m *MockIOAuthService) Exchange(ctx context.Context, provider, state, code, binding string) (services.OAuthExchangeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", ctx, provider, state, code, binding)
+	ret0, _ := ret[0].(services.OAuthExchangeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockIOAuthServiceMockRecorder) Exchange(ctx, provider, state, code, binding any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockIOAuthService)(nil).Exchange), ctx, provider, state, code, binding)
+}
+
+// LinkCodeURL mocks base method.
+func (m *MockIOAuthService) LinkCodeURL(ctx context.Context, provider string, userId uuid.UUID) (services.OAuthFlow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkCodeURL", ctx, provider, userId)
+	ret0, _ := ret[0].(services.OAuthFlow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LinkCodeURL indicates an expected call of LinkCodeURL.
+func (mr *MockIOAuthServiceMockRecorder) LinkCodeURL(ctx, provider, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkCodeURL", reflect.TypeOf((*MockIOAuthService)(nil).LinkCodeURL), ctx, provider, userId)
+}
+
+// Providers mocks base method.
+func (m *MockIOAuthService) Providers() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Providers")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Providers indicates an expected call of Providers.
+func (mr *MockIOAuthServiceMockRecorder) Providers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Providers", reflect.TypeOf((*MockIOAuthService)(nil).Providers))
+}
@@ -3,10 +3,10 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=internal/services/password_service.go -destination=internal/mocks/mock_services/mock_password_service.go -package=mocksservices
+//	mockgen -source=internal/services/password_service.go -destination=mocks/mock_services/mock_password_service.go -package=mockservices
 //
 
-// Package mocksservices is a generated GoMock package.
+// Package mockservices is a generated GoMock package.
 package mockservices
 
 import (
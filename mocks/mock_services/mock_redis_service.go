@@ -12,6 +12,7 @@ package mockservices
 import (
 	services "my-go-api/internal/services"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -40,6 +41,63 @@ func (m *MockIRedisService) EXPECT() *MockIRedisServiceMockRecorder {
 	return m.recorder
 }
 
+// BumpAuthzRevision mocks base method.
+func (m *MockIRedisService) BumpAuthzRevision() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BumpAuthzRevision")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BumpAuthzRevision indicates an expected call of BumpAuthzRevision.
+func (mr *MockIRedisServiceMockRecorder) BumpAuthzRevision() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BumpAuthzRevision", reflect.TypeOf((*MockIRedisService)(nil).BumpAuthzRevision))
+}
+
+// ClaimRefreshToken mocks base method.
+func (m *MockIRedisService) ClaimRefreshToken(hashedToken string, seed *services.RefreshTokenData) (services.RefreshTokenClaim, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimRefreshToken", hashedToken, seed)
+	ret0, _ := ret[0].(services.RefreshTokenClaim)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimRefreshToken indicates an expected call of ClaimRefreshToken.
+func (mr *MockIRedisServiceMockRecorder) ClaimRefreshToken(hashedToken, seed any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimRefreshToken", reflect.TypeOf((*MockIRedisService)(nil).ClaimRefreshToken), hashedToken, seed)
+}
+
+// ClearLoginFailures mocks base method.
+func (m *MockIRedisService) ClearLoginFailures(subject string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearLoginFailures", subject)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearLoginFailures indicates an expected call of ClearLoginFailures.
+func (mr *MockIRedisServiceMockRecorder) ClearLoginFailures(subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearLoginFailures", reflect.TypeOf((*MockIRedisService)(nil).ClearLoginFailures), subject)
+}
+
+// CompleteRefreshTokenRotation mocks base method.
+func (m *MockIRedisService) CompleteRefreshTokenRotation(hashedToken string, params services.RotatedRefreshTokenData, gracePeriod time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteRefreshTokenRotation", hashedToken, params, gracePeriod)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteRefreshTokenRotation indicates an expected call of CompleteRefreshTokenRotation.
+func (mr *MockIRedisServiceMockRecorder) CompleteRefreshTokenRotation(hashedToken, params, gracePeriod any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteRefreshTokenRotation", reflect.TypeOf((*MockIRedisService)(nil).CompleteRefreshTokenRotation), hashedToken, params, gracePeriod)
+}
+
 // DeleteAccessToken mocks base method.
 func (m *MockIRedisService) DeleteAccessToken(jti string) error {
 	m.ctrl.T.Helper()
@@ -54,6 +112,34 @@ func (mr *MockIRedisServiceMockRecorder) DeleteAccessToken(jti any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccessToken", reflect.TypeOf((*MockIRedisService)(nil).DeleteAccessToken), jti)
 }
 
+// DeleteMfaChallenge mocks base method.
+func (m *MockIRedisService) DeleteMfaChallenge(hashedToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMfaChallenge", hashedToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMfaChallenge indicates an expected call of DeleteMfaChallenge.
+func (mr *MockIRedisServiceMockRecorder) DeleteMfaChallenge(hashedToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMfaChallenge", reflect.TypeOf((*MockIRedisService)(nil).DeleteMfaChallenge), hashedToken)
+}
+
+// DeletePasswordResetToken mocks base method.
+func (m *MockIRedisService) DeletePasswordResetToken(hashedToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePasswordResetToken", hashedToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePasswordResetToken indicates an expected call of DeletePasswordResetToken.
+func (mr *MockIRedisServiceMockRecorder) DeletePasswordResetToken(hashedToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePasswordResetToken", reflect.TypeOf((*MockIRedisService)(nil).DeletePasswordResetToken), hashedToken)
+}
+
 // DeleteRefreshToken mocks base method.
 func (m *MockIRedisService) DeleteRefreshToken(hashedToken string) error {
 	m.ctrl.T.Helper()
@@ -97,6 +183,66 @@ func (mr *MockIRedisServiceMockRecorder) GetAccessToken(jti any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessToken", reflect.TypeOf((*MockIRedisService)(nil).GetAccessToken), jti)
 }
 
+// GetAuthzCheck mocks base method.
+func (m *MockIRedisService) GetAuthzCheck(revision, check string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthzCheck", revision, check)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthzCheck indicates an expected call of GetAuthzCheck.
+func (mr *MockIRedisServiceMockRecorder) GetAuthzCheck(revision, check any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthzCheck", reflect.TypeOf((*MockIRedisService)(nil).GetAuthzCheck), revision, check)
+}
+
+// GetAuthzRevision mocks base method.
+func (m *MockIRedisService) GetAuthzRevision() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthzRevision")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthzRevision indicates an expected call of GetAuthzRevision.
+func (mr *MockIRedisServiceMockRecorder) GetAuthzRevision() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthzRevision", reflect.TypeOf((*MockIRedisService)(nil).GetAuthzRevision))
+}
+
+// GetLoginFailures mocks base method.
+func (m *MockIRedisService) GetLoginFailures(subject string) (services.LoginFailureData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginFailures", subject)
+	ret0, _ := ret[0].(services.LoginFailureData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginFailures indicates an expected call of GetLoginFailures.
+func (mr *MockIRedisServiceMockRecorder) GetLoginFailures(subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginFailures", reflect.TypeOf((*MockIRedisService)(nil).GetLoginFailures), subject)
+}
+
+// GetPasswordResetToken mocks base method.
+func (m *MockIRedisService) GetPasswordResetToken(hashedToken string) (services.PasswordResetData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasswordResetToken", hashedToken)
+	ret0, _ := ret[0].(services.PasswordResetData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordResetToken indicates an expected call of GetPasswordResetToken.
+func (mr *MockIRedisServiceMockRecorder) GetPasswordResetToken(hashedToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordResetToken", reflect.TypeOf((*MockIRedisService)(nil).GetPasswordResetToken), hashedToken)
+}
+
 // GetRefreshToken mocks base method.
 func (m *MockIRedisService) GetRefreshToken(hashedToken string) (services.RefreshTokenData, error) {
 	m.ctrl.T.Helper()
@@ -127,6 +273,35 @@ func (mr *MockIRedisServiceMockRecorder) GetVerificationToken(hashedToken any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVerificationToken", reflect.TypeOf((*MockIRedisService)(nil).GetVerificationToken), hashedToken)
 }
 
+// RecordLoginFailure mocks base method.
+func (m *MockIRedisService) RecordLoginFailure(subject string, policy services.LoginFailurePolicy) (services.LoginFailureData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLoginFailure", subject, policy)
+	ret0, _ := ret[0].(services.LoginFailureData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordLoginFailure indicates an expected call of RecordLoginFailure.
+func (mr *MockIRedisServiceMockRecorder) RecordLoginFailure(subject, policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginFailure", reflect.TypeOf((*MockIRedisService)(nil).RecordLoginFailure), subject, policy)
+}
+
+// ReleaseRefreshTokenClaim mocks base method.
+func (m *MockIRedisService) ReleaseRefreshTokenClaim(hashedToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseRefreshTokenClaim", hashedToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseRefreshTokenClaim indicates an expected call of ReleaseRefreshTokenClaim.
+func (mr *MockIRedisServiceMockRecorder) ReleaseRefreshTokenClaim(hashedToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseRefreshTokenClaim", reflect.TypeOf((*MockIRedisService)(nil).ReleaseRefreshTokenClaim), hashedToken)
+}
+
 // SaveAccessToken mocks base method.
 func (m *MockIRedisService) SaveAccessToken(params services.AccessTokenData) error {
 	m.ctrl.T.Helper()
@@ -141,6 +316,76 @@ func (mr *MockIRedisServiceMockRecorder) SaveAccessToken(params any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAccessToken", reflect.TypeOf((*MockIRedisService)(nil).SaveAccessToken), params)
 }
 
+// SaveAuthzCheck mocks base method.
+func (m *MockIRedisService) SaveAuthzCheck(revision, check string, allowed bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAuthzCheck", revision, check, allowed)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAuthzCheck indicates an expected call of SaveAuthzCheck.
+func (mr *MockIRedisServiceMockRecorder) SaveAuthzCheck(revision, check, allowed any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAuthzCheck", reflect.TypeOf((*MockIRedisService)(nil).SaveAuthzCheck), revision, check, allowed)
+}
+
+// SaveMagicLink mocks base method.
+func (m *MockIRedisService) SaveMagicLink(params services.MagicLinkData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveMagicLink", params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveMagicLink indicates an expected call of SaveMagicLink.
+func (mr *MockIRedisServiceMockRecorder) SaveMagicLink(params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMagicLink", reflect.TypeOf((*MockIRedisService)(nil).SaveMagicLink), params)
+}
+
+// SaveMfaChallenge mocks base method.
+func (m *MockIRedisService) SaveMfaChallenge(params services.MfaChallengeData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveMfaChallenge", params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveMfaChallenge indicates an expected call of SaveMfaChallenge.
+func (mr *MockIRedisServiceMockRecorder) SaveMfaChallenge(params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMfaChallenge", reflect.TypeOf((*MockIRedisService)(nil).SaveMfaChallenge), params)
+}
+
+// SaveOAuthState mocks base method.
+func (m *MockIRedisService) SaveOAuthState(params services.OAuthStateData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOAuthState", params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOAuthState indicates an expected call of SaveOAuthState.
+func (mr *MockIRedisServiceMockRecorder) SaveOAuthState(params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuthState", reflect.TypeOf((*MockIRedisService)(nil).SaveOAuthState), params)
+}
+
+// SavePasswordResetToken mocks base method.
+func (m *MockIRedisService) SavePasswordResetToken(params services.PasswordResetData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePasswordResetToken", params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePasswordResetToken indicates an expected call of SavePasswordResetToken.
+func (mr *MockIRedisServiceMockRecorder) SavePasswordResetToken(params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePasswordResetToken", reflect.TypeOf((*MockIRedisService)(nil).SavePasswordResetToken), params)
+}
+
 // SaveRefreshToken mocks base method.
 func (m *MockIRedisService) SaveRefreshToken(params services.RefreshTokenData) error {
 	m.ctrl.T.Helper()
@@ -168,3 +413,107 @@ func (mr *MockIRedisServiceMockRecorder) SaveVerificationToken(params any) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveVerificationToken", reflect.TypeOf((*MockIRedisService)(nil).SaveVerificationToken), params)
 }
+
+// SaveWebauthnSession mocks base method.
+func (m *MockIRedisService) SaveWebauthnSession(params services.WebauthnSessionData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveWebauthnSession", params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveWebauthnSession indicates an expected call of SaveWebauthnSession.
+func (mr *MockIRedisServiceMockRecorder) SaveWebauthnSession(params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWebauthnSession", reflect.TypeOf((*MockIRedisService)(nil).SaveWebauthnSession), params)
+}
+
+// TakeOAuthState mocks base method.
+func (m *MockIRedisService) TakeOAuthState(state string) (services.OAuthStateData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeOAuthState", state)
+	ret0, _ := ret[0].(services.OAuthStateData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeOAuthState indicates an expected call of TakeOAuthState.
+func (mr *MockIRedisServiceMockRecorder) TakeOAuthState(state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeOAuthState", reflect.TypeOf((*MockIRedisService)(nil).TakeOAuthState), state)
+}
+
+// TakeRateLimit mocks base method.
+func (m *MockIRedisService) TakeRateLimit(name, key string, limit int, window time.Duration) (services.RateLimitResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeRateLimit", name, key, limit, window)
+	ret0, _ := ret[0].(services.RateLimitResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeRateLimit indicates an expected call of TakeRateLimit.
+func (mr *MockIRedisServiceMockRecorder) TakeRateLimit(name, key, limit, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeRateLimit", reflect.TypeOf((*MockIRedisService)(nil).TakeRateLimit), name, key, limit, window)
+}
+
+// TakeWebauthnSession mocks base method.
+func (m *MockIRedisService) TakeWebauthnSession(sessionId string) (services.WebauthnSessionData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeWebauthnSession", sessionId)
+	ret0, _ := ret[0].(services.WebauthnSessionData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeWebauthnSession indicates an expected call of TakeWebauthnSession.
+func (mr *MockIRedisServiceMockRecorder) TakeWebauthnSession(sessionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeWebauthnSession", reflect.TypeOf((*MockIRedisService)(nil).TakeWebauthnSession), sessionId)
+}
+
+// UseMagicLink mocks base method.
+func (m *MockIRedisService) UseMagicLink(hashedLinkToken string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseMagicLink", hashedLinkToken)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseMagicLink indicates an expected call of UseMagicLink.
+func (mr *MockIRedisServiceMockRecorder) UseMagicLink(hashedLinkToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseMagicLink", reflect.TypeOf((*MockIRedisService)(nil).UseMagicLink), hashedLinkToken)
+}
+
+// UseMagicLinkCode mocks base method.
+func (m *MockIRedisService) UseMagicLinkCode(hashedToken, code string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseMagicLinkCode", hashedToken, code)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseMagicLinkCode indicates an expected call of UseMagicLinkCode.
+func (mr *MockIRedisServiceMockRecorder) UseMagicLinkCode(hashedToken, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseMagicLinkCode", reflect.TypeOf((*MockIRedisService)(nil).UseMagicLinkCode), hashedToken, code)
+}
+
+// UseMfaChallengeAttempt mocks base method.
+func (m *MockIRedisService) UseMfaChallengeAttempt(hashedToken string) (services.MfaChallengeData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseMfaChallengeAttempt", hashedToken)
+	ret0, _ := ret[0].(services.MfaChallengeData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseMfaChallengeAttempt indicates an expected call of UseMfaChallengeAttempt.
+func (mr *MockIRedisServiceMockRecorder) UseMfaChallengeAttempt(hashedToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseMfaChallengeAttempt", reflect.TypeOf((*MockIRedisService)(nil).UseMfaChallengeAttempt), hashedToken)
+}
@@ -0,0 +1,189 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/role_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/role_service.go -destination=mocks/mock_services/mock_role_service.go -package=mockservices
+//
+
+// Package mockservices is a generated GoMock package.
+package mockservices
+
+import (
+	context "context"
+	models "my-go-api/internal/models"
+	repositories "my-go-api/internal/repositories"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIRoleService is a mock of IRoleService interface.
+type MockIRoleService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRoleServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockIRoleServiceMockRecorder is the mock recorder for MockIRoleService.
+type MockIRoleServiceMockRecorder struct {
+	mock *MockIRoleService
+}
+
+// NewMockIRoleService creates a new mock instance.
+func NewMockIRoleService(ctrl *gomock.Controller) *MockIRoleService {
+	mock := &MockIRoleService{ctrl: ctrl}
+	mock.recorder = &MockIRoleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRoleService) EXPECT() *MockIRoleServiceMockRecorder {
+	return m.recorder
+}
+
+// CheckRoleChange mocks base method.
+func (m *MockIRoleService) CheckRoleChange(ctx context.Context, actorId uuid.UUID, granted, revoked []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckRoleChange", ctx, actorId, granted, revoked)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckRoleChange indicates an expected call of CheckRoleChange.
+func (mr *MockIRoleServiceMockRecorder) CheckRoleChange(ctx, actorId, granted, revoked any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckRoleChange", reflect.TypeOf((*MockIRoleService)(nil).CheckRoleChange), ctx, actorId, granted, revoked)
+}
+
+// CreateRole mocks base method.
+func (m *MockIRoleService) CreateRole(ctx context.Context, actorId uuid.UUID, params repositories.CreateRoleParams) (*models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRole", ctx, actorId, params)
+	ret0, _ := ret[0].(*models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRole indicates an expected call of CreateRole.
+func (mr *MockIRoleServiceMockRecorder) CreateRole(ctx, actorId, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRole", reflect.TypeOf((*MockIRoleService)(nil).CreateRole), ctx, actorId, params)
+}
+
+// DeleteRole mocks base method.
+func (m *MockIRoleService) DeleteRole(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRole", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRole indicates an expected call of DeleteRole.
+func (mr *MockIRoleServiceMockRecorder) DeleteRole(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRole", reflect.TypeOf((*MockIRoleService)(nil).DeleteRole), ctx, id)
+}
+
+// ExpireAccessTokens mocks base method.
+func (m *MockIRoleService) ExpireAccessTokens(ctx context.Context, userId uuid.UUID) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ExpireAccessTokens", ctx, userId)
+}
+
+// ExpireAccessTokens indicates an expected call of ExpireAccessTokens.
+func (mr *MockIRoleServiceMockRecorder) ExpireAccessTokens(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireAccessTokens", reflect.TypeOf((*MockIRoleService)(nil).ExpireAccessTokens), ctx, userId)
+}
+
+// GetPermissions mocks base method.
+func (m *MockIRoleService) GetPermissions(ctx context.Context) ([]models.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPermissions", ctx)
+	ret0, _ := ret[0].([]models.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPermissions indicates an expected call of GetPermissions.
+func (mr *MockIRoleServiceMockRecorder) GetPermissions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermissions", reflect.TypeOf((*MockIRoleService)(nil).GetPermissions), ctx)
+}
+
+// GetRoles mocks base method.
+func (m *MockIRoleService) GetRoles(ctx context.Context) ([]models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoles", ctx)
+	ret0, _ := ret[0].([]models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoles indicates an expected call of GetRoles.
+func (mr *MockIRoleServiceMockRecorder) GetRoles(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoles", reflect.TypeOf((*MockIRoleService)(nil).GetRoles), ctx)
+}
+
+// GetUserPermissions mocks base method.
+func (m *MockIRoleService) GetUserPermissions(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPermissions", ctx, userId)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPermissions indicates an expected call of GetUserPermissions.
+func (mr *MockIRoleServiceMockRecorder) GetUserPermissions(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPermissions", reflect.TypeOf((*MockIRoleService)(nil).GetUserPermissions), ctx, userId)
+}
+
+// GetUserRoles mocks base method.
+func (m *MockIRoleService) GetUserRoles(ctx context.Context, userId uuid.UUID) ([]models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRoles", ctx, userId)
+	ret0, _ := ret[0].([]models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRoles indicates an expected call of GetUserRoles.
+func (mr *MockIRoleServiceMockRecorder) GetUserRoles(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRoles", reflect.TypeOf((*MockIRoleService)(nil).GetUserRoles), ctx, userId)
+}
+
+// SetRolePermissions mocks base method.
+func (m *MockIRoleService) SetRolePermissions(ctx context.Context, actorId uuid.UUID, id int, permissions []string) (*models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRolePermissions", ctx, actorId, id, permissions)
+	ret0, _ := ret[0].(*models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetRolePermissions indicates an expected call of SetRolePermissions.
+func (mr *MockIRoleServiceMockRecorder) SetRolePermissions(ctx, actorId, id, permissions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRolePermissions", reflect.TypeOf((*MockIRoleService)(nil).SetRolePermissions), ctx, actorId, id, permissions)
+}
+
+// SetUserRoles mocks base method.
+func (m *MockIRoleService) SetUserRoles(ctx context.Context, actorId, userId uuid.UUID, roles []string) ([]models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRoles", ctx, actorId, userId, roles)
+	ret0, _ := ret[0].([]models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserRoles indicates an expected call of SetUserRoles.
+func (mr *MockIRoleServiceMockRecorder) SetUserRoles(ctx, actorId, userId, roles any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRoles", reflect.TypeOf((*MockIRoleService)(nil).SetUserRoles), ctx, actorId, userId, roles)
+}
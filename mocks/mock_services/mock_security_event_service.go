@@ -0,0 +1,54 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/security_event_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/security_event_service.go -destination=mocks/mock_services/mock_security_event_service.go -package=mockservices
+//
+
+// Package mockservices is a generated GoMock package.
+package mockservices
+
+import (
+	context "context"
+	services "my-go-api/internal/services"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockISecurityEventService is a mock of ISecurityEventService interface.
+type MockISecurityEventService struct {
+	ctrl     *gomock.Controller
+	recorder *MockISecurityEventServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockISecurityEventServiceMockRecorder is the mock recorder for MockISecurityEventService.
+type MockISecurityEventServiceMockRecorder struct {
+	mock *MockISecurityEventService
+}
+
+// NewMockISecurityEventService creates a new mock instance.
+func NewMockISecurityEventService(ctrl *gomock.Controller) *MockISecurityEventService {
+	mock := &MockISecurityEventService{ctrl: ctrl}
+	mock.recorder = &MockISecurityEventServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISecurityEventService) EXPECT() *MockISecurityEventServiceMockRecorder {
+	return m.recorder
+}
+
+// Emit mocks base method.
+func (m *MockISecurityEventService) Emit(ctx context.Context, event services.SecurityEvent) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Emit", ctx, event)
+}
+
+// Emit indicates an expected call of Emit.
+func (mr *MockISecurityEventServiceMockRecorder) Emit(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Emit", reflect.TypeOf((*MockISecurityEventService)(nil).Emit), ctx, event)
+}
@@ -0,0 +1,208 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/session_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/session_service.go -destination=mocks/mock_services/mock_session_service.go -package=mockservices
+//
+
+// Package mockservices is a generated GoMock package.
+package mockservices
+
+import (
+	context "context"
+	models "my-go-api/internal/models"
+	repositories "my-go-api/internal/repositories"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockISessionService is a mock of ISessionService interface.
+type MockISessionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockISessionServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockISessionServiceMockRecorder is the mock recorder for MockISessionService.
+type MockISessionServiceMockRecorder struct {
+	mock *MockISessionService
+}
+
+// NewMockISessionService creates a new mock instance.
+func NewMockISessionService(ctrl *gomock.Controller) *MockISessionService {
+	mock := &MockISessionService{ctrl: ctrl}
+	mock.recorder = &MockISessionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISessionService) EXPECT() *MockISessionServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateSession mocks base method.
+func (m *MockISessionService) CreateSession(ctx context.Context, params repositories.CreateSessionParams) (*models.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", ctx, params)
+	ret0, _ := ret[0].(*models.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockISessionServiceMockRecorder) CreateSession(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockISessionService)(nil).CreateSession), ctx, params)
+}
+
+// GetActiveSessionByHash mocks base method.
+func (m *MockISessionService) GetActiveSessionByHash(ctx context.Context, hash string) (*models.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSessionByHash", ctx, hash)
+	ret0, _ := ret[0].(*models.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSessionByHash indicates an expected call of GetActiveSessionByHash.
+func (mr *MockISessionServiceMockRecorder) GetActiveSessionByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSessionByHash", reflect.TypeOf((*MockISessionService)(nil).GetActiveSessionByHash), ctx, hash)
+}
+
+// GetActiveSessions mocks base method.
+func (m *MockISessionService) GetActiveSessions(ctx context.Context, userId uuid.UUID) ([]models.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSessions", ctx, userId)
+	ret0, _ := ret[0].([]models.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSessions indicates an expected call of GetActiveSessions.
+func (mr *MockISessionServiceMockRecorder) GetActiveSessions(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSessions", reflect.TypeOf((*MockISessionService)(nil).GetActiveSessions), ctx, userId)
+}
+
+// GetSessionByHash mocks base method.
+func (m *MockISessionService) GetSessionByHash(ctx context.Context, hash string) (*models.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionByHash", ctx, hash)
+	ret0, _ := ret[0].(*models.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionByHash indicates an expected call of GetSessionByHash.
+func (mr *MockISessionServiceMockRecorder) GetSessionByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionByHash", reflect.TypeOf((*MockISessionService)(nil).GetSessionByHash), ctx, hash)
+}
+
+// RevokeClientSessions mocks base method.
+func (m *MockISessionService) RevokeClientSessions(ctx context.Context, clientId string) ([]models.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeClientSessions", ctx, clientId)
+	ret0, _ := ret[0].([]models.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeClientSessions indicates an expected call of RevokeClientSessions.
+func (mr *MockISessionServiceMockRecorder) RevokeClientSessions(ctx, clientId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeClientSessions", reflect.TypeOf((*MockISessionService)(nil).RevokeClientSessions), ctx, clientId)
+}
+
+// RevokeDeviceSessions mocks base method.
+func (m *MockISessionService) RevokeDeviceSessions(ctx context.Context, userId, deviceId uuid.UUID) ([]models.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeDeviceSessions", ctx, userId, deviceId)
+	ret0, _ := ret[0].([]models.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeDeviceSessions indicates an expected call of RevokeDeviceSessions.
+func (mr *MockISessionServiceMockRecorder) RevokeDeviceSessions(ctx, userId, deviceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeDeviceSessions", reflect.TypeOf((*MockISessionService)(nil).RevokeDeviceSessions), ctx, userId, deviceId)
+}
+
+// RevokeOtherSessions mocks base method.
+func (m *MockISessionService) RevokeOtherSessions(ctx context.Context, userId, currentJti uuid.UUID) ([]models.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOtherSessions", ctx, userId, currentJti)
+	ret0, _ := ret[0].([]models.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeOtherSessions indicates an expected call of RevokeOtherSessions.
+func (mr *MockISessionServiceMockRecorder) RevokeOtherSessions(ctx, userId, currentJti any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOtherSessions", reflect.TypeOf((*MockISessionService)(nil).RevokeOtherSessions), ctx, userId, currentJti)
+}
+
+// RevokeSession mocks base method.
+func (m *MockISessionService) RevokeSession(ctx context.Context, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockISessionServiceMockRecorder) RevokeSession(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockISessionService)(nil).RevokeSession), ctx, hash)
+}
+
+// RevokeSessionById mocks base method.
+func (m *MockISessionService) RevokeSessionById(ctx context.Context, userId uuid.UUID, id int) (*models.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessionById", ctx, userId, id)
+	ret0, _ := ret[0].(*models.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSessionById indicates an expected call of RevokeSessionById.
+func (mr *MockISessionServiceMockRecorder) RevokeSessionById(ctx, userId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessionById", reflect.TypeOf((*MockISessionService)(nil).RevokeSessionById), ctx, userId, id)
+}
+
+// RevokeTokenFamily mocks base method.
+func (m *MockISessionService) RevokeTokenFamily(ctx context.Context, familyId uuid.UUID) ([]models.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeTokenFamily", ctx, familyId)
+	ret0, _ := ret[0].([]models.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeTokenFamily indicates an expected call of RevokeTokenFamily.
+func (mr *MockISessionServiceMockRecorder) RevokeTokenFamily(ctx, familyId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeTokenFamily", reflect.TypeOf((*MockISessionService)(nil).RevokeTokenFamily), ctx, familyId)
+}
+
+// RotateSession mocks base method.
+func (m *MockISessionService) RotateSession(ctx context.Context, oldHash string, params repositories.CreateSessionParams) (*models.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSession", ctx, oldHash, params)
+	ret0, _ := ret[0].(*models.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateSession indicates an expected call of RotateSession.
+func (mr *MockISessionServiceMockRecorder) RotateSession(ctx, oldHash, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSession", reflect.TypeOf((*MockISessionService)(nil).RotateSession), ctx, oldHash, params)
+}
@@ -0,0 +1,187 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/signing_key_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/signing_key_service.go -destination=mocks/mock_services/mock_signing_key_service.go -package=mockservices
+//
+
+// Package mockservices is a generated GoMock package.
+package mockservices
+
+import (
+	context "context"
+	models "my-go-api/internal/models"
+	services "my-go-api/internal/services"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockISigningKeyService is a mock of ISigningKeyService interface.
+type MockISigningKeyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockISigningKeyServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockISigningKeyServiceMockRecorder is the mock recorder for MockISigningKeyService.
+type MockISigningKeyServiceMockRecorder struct {
+	mock *MockISigningKeyService
+}
+
+// NewMockISigningKeyService creates a new mock instance.
+func NewMockISigningKeyService(ctrl *gomock.Controller) *MockISigningKeyService {
+	mock := &MockISigningKeyService{ctrl: ctrl}
+	mock.recorder = &MockISigningKeyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISigningKeyService) EXPECT() *MockISigningKeyServiceMockRecorder {
+	return m.recorder
+}
+
+// Introduce mocks base method.
+func (m *MockISigningKeyService) Introduce(ctx context.Context, algorithm string) (*models.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Introduce", ctx, algorithm)
+	ret0, _ := ret[0].(*models.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Introduce indicates an expected call of Introduce.
+func (mr *MockISigningKeyServiceMockRecorder) Introduce(ctx, algorithm any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Introduce", reflect.TypeOf((*MockISigningKeyService)(nil).Introduce), ctx, algorithm)
+}
+
+// JWKS mocks base method.
+func (m *MockISigningKeyService) JWKS() services.JSONWebKeySet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(services.JSONWebKeySet)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockISigningKeyServiceMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockISigningKeyService)(nil).JWKS))
+}
+
+// List mocks base method.
+func (m *MockISigningKeyService) List(ctx context.Context) ([]models.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]models.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockISigningKeyServiceMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockISigningKeyService)(nil).List), ctx)
+}
+
+// Promote mocks base method.
+func (m *MockISigningKeyService) Promote(ctx context.Context, kid string, force bool) (*models.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Promote", ctx, kid, force)
+	ret0, _ := ret[0].(*models.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Promote indicates an expected call of Promote.
+func (mr *MockISigningKeyServiceMockRecorder) Promote(ctx, kid, force any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Promote", reflect.TypeOf((*MockISigningKeyService)(nil).Promote), ctx, kid, force)
+}
+
+// Refresh mocks base method.
+func (m *MockISigningKeyService) Refresh(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockISigningKeyServiceMockRecorder) Refresh(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockISigningKeyService)(nil).Refresh), ctx)
+}
+
+// Retire mocks base method.
+func (m *MockISigningKeyService) Retire(ctx context.Context, kid string, force bool) (*models.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retire", ctx, kid, force)
+	ret0, _ := ret[0].(*models.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Retire indicates an expected call of Retire.
+func (mr *MockISigningKeyServiceMockRecorder) Retire(ctx, kid, force any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retire", reflect.TypeOf((*MockISigningKeyService)(nil).Retire), ctx, kid, force)
+}
+
+// Rotate mocks base method.
+func (m *MockISigningKeyService) Rotate(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockISigningKeyServiceMockRecorder) Rotate(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockISigningKeyService)(nil).Rotate), ctx)
+}
+
+// SigningKey mocks base method.
+func (m *MockISigningKeyService) SigningKey() (*services.JwtKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SigningKey")
+	ret0, _ := ret[0].(*services.JwtKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SigningKey indicates an expected call of SigningKey.
+func (mr *MockISigningKeyServiceMockRecorder) SigningKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SigningKey", reflect.TypeOf((*MockISigningKeyService)(nil).SigningKey))
+}
+
+// Start mocks base method.
+func (m *MockISigningKeyService) Start(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Start", ctx)
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockISigningKeyServiceMockRecorder) Start(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockISigningKeyService)(nil).Start), ctx)
+}
+
+// VerifyingKey mocks base method.
+func (m *MockISigningKeyService) VerifyingKey(kid string) (*services.JwtKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyingKey", kid)
+	ret0, _ := ret[0].(*services.JwtKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyingKey indicates an expected call of VerifyingKey.
+func (mr *MockISigningKeyServiceMockRecorder) VerifyingKey(kid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyingKey", reflect.TypeOf((*MockISigningKeyService)(nil).VerifyingKey), kid)
+}
@@ -13,6 +13,7 @@ import (
 	context "context"
 	models "my-go-api/internal/models"
 	repositories "my-go-api/internal/repositories"
+	services "my-go-api/internal/services"
 	reflect "reflect"
 
 	uuid "github.com/google/uuid"
@@ -43,21 +44,6 @@ func (m *MockIUserService) EXPECT() *MockIUserServiceMockRecorder {
 	return m.recorder
 }
 
-// GetAllUsers mocks base method.
-func (m *MockIUserService) GetAllUsers(ctx context.Context) ([]models.User, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllUsers", ctx)
-	ret0, _ := ret[0].([]models.User)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// GetAllUsers indicates an expected call of GetAllUsers.
-func (mr *MockIUserServiceMockRecorder) GetAllUsers(ctx any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockIUserService)(nil).GetAllUsers), ctx)
-}
-
 // GetUserByEmail mocks base method.
 func (m *MockIUserService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -88,6 +74,21 @@ func (mr *MockIUserServiceMockRecorder) GetUserById(ctx, userId any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockIUserService)(nil).GetUserById), ctx, userId)
 }
 
+// GetUserByIdWithDeleted mocks base method.
+func (m *MockIUserService) GetUserByIdWithDeleted(ctx context.Context, userId uuid.UUID) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByIdWithDeleted", ctx, userId)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByIdWithDeleted indicates an expected call of GetUserByIdWithDeleted.
+func (mr *MockIUserServiceMockRecorder) GetUserByIdWithDeleted(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByIdWithDeleted", reflect.TypeOf((*MockIUserService)(nil).GetUserByIdWithDeleted), ctx, userId)
+}
+
 // GetUserByIdentity mocks base method.
 func (m *MockIUserService) GetUserByIdentity(ctx context.Context, identity string) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -118,6 +119,51 @@ func (mr *MockIUserServiceMockRecorder) GetUserByUsername(ctx, username any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockIUserService)(nil).GetUserByUsername), ctx, username)
 }
 
+// ListUsers mocks base method.
+func (m *MockIUserService) ListUsers(ctx context.Context, params repositories.ListUsersParams, cursor string) (*services.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx, params, cursor)
+	ret0, _ := ret[0].(*services.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockIUserServiceMockRecorder) ListUsers(ctx, params, cursor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockIUserService)(nil).ListUsers), ctx, params, cursor)
+}
+
+// SetDeleted mocks base method.
+func (m *MockIUserService) SetDeleted(ctx context.Context, userId uuid.UUID, deleted bool) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDeleted", ctx, userId, deleted)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDeleted indicates an expected call of SetDeleted.
+func (mr *MockIUserServiceMockRecorder) SetDeleted(ctx, userId, deleted any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeleted", reflect.TypeOf((*MockIUserService)(nil).SetDeleted), ctx, userId, deleted)
+}
+
+// SetLocked mocks base method.
+func (m *MockIUserService) SetLocked(ctx context.Context, userId uuid.UUID, locked bool, reason string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLocked", ctx, userId, locked, reason)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLocked indicates an expected call of SetLocked.
+func (mr *MockIUserServiceMockRecorder) SetLocked(ctx, userId, locked, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLocked", reflect.TypeOf((*MockIUserService)(nil).SetLocked), ctx, userId, locked, reason)
+}
+
 // Store mocks base method.
 func (m *MockIUserService) Store(ctx context.Context, params repositories.CreateOneParams) (*models.User, error) {
 	m.ctrl.T.Helper()
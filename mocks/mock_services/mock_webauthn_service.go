@@ -0,0 +1,177 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/services/webauthn_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/services/webauthn_service.go -destination=mocks/mock_services/mock_webauthn_service.go -package=mockservices
+//
+
+// Package mockservices is a generated GoMock package.
+package mockservices
+
+import (
+	context "context"
+	models "my-go-api/internal/models"
+	services "my-go-api/internal/services"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIWebauthnService is a mock of IWebauthnService interface.
+type MockIWebauthnService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIWebauthnServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockIWebauthnServiceMockRecorder is the mock recorder for MockIWebauthnService.
+type MockIWebauthnServiceMockRecorder struct {
+	mock *MockIWebauthnService
+}
+
+// NewMockIWebauthnService creates a new mock instance.
+func NewMockIWebauthnService(ctrl *gomock.Controller) *MockIWebauthnService {
+	mock := &MockIWebauthnService{ctrl: ctrl}
+	mock.recorder = &MockIWebauthnServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIWebauthnService) EXPECT() *MockIWebauthnServiceMockRecorder {
+	return m.recorder
+}
+
+// BeginLogin mocks base method.
+func (m *MockIWebauthnService) BeginLogin(ctx context.Context) (services.WebauthnCeremony, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginLogin", ctx)
+	ret0, _ := ret[0].(services.WebauthnCeremony)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginLogin indicates an expected call of BeginLogin.
+func (mr *MockIWebauthnServiceMockRecorder) BeginLogin(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginLogin", reflect.TypeOf((*MockIWebauthnService)(nil).BeginLogin), ctx)
+}
+
+// BeginRegistration mocks base method.
+func (m *MockIWebauthnService) BeginRegistration(ctx context.Context, user *models.User) (services.WebauthnCeremony, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginRegistration", ctx, user)
+	ret0, _ := ret[0].(services.WebauthnCeremony)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginRegistration indicates an expected call of BeginRegistration.
+func (mr *MockIWebauthnServiceMockRecorder) BeginRegistration(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginRegistration", reflect.TypeOf((*MockIWebauthnService)(nil).BeginRegistration), ctx, user)
+}
+
+// BeginSecondFactor mocks base method.
+func (m *MockIWebauthnService) BeginSecondFactor(ctx context.Context, userId uuid.UUID) (services.WebauthnCeremony, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginSecondFactor", ctx, userId)
+	ret0, _ := ret[0].(services.WebauthnCeremony)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginSecondFactor indicates an expected call of BeginSecondFactor.
+func (mr *MockIWebauthnServiceMockRecorder) BeginSecondFactor(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginSecondFactor", reflect.TypeOf((*MockIWebauthnService)(nil).BeginSecondFactor), ctx, userId)
+}
+
+// DeleteCredential mocks base method.
+func (m *MockIWebauthnService) DeleteCredential(ctx context.Context, userId uuid.UUID, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCredential", ctx, userId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCredential indicates an expected call of DeleteCredential.
+func (mr *MockIWebauthnServiceMockRecorder) DeleteCredential(ctx, userId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCredential", reflect.TypeOf((*MockIWebauthnService)(nil).DeleteCredential), ctx, userId, id)
+}
+
+// FinishLogin mocks base method.
+func (m *MockIWebauthnService) FinishLogin(ctx context.Context, sessionId string, response []byte) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishLogin", ctx, sessionId, response)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishLogin indicates an expected call of FinishLogin.
+func (mr *MockIWebauthnServiceMockRecorder) FinishLogin(ctx, sessionId, response any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishLogin", reflect.TypeOf((*MockIWebauthnService)(nil).FinishLogin), ctx, sessionId, response)
+}
+
+// FinishRegistration mocks base method.
+func (m *MockIWebauthnService) FinishRegistration(ctx context.Context, user *models.User, sessionId, name string, response []byte) (*models.WebauthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishRegistration", ctx, user, sessionId, name, response)
+	ret0, _ := ret[0].(*models.WebauthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishRegistration indicates an expected call of FinishRegistration.
+func (mr *MockIWebauthnServiceMockRecorder) FinishRegistration(ctx, user, sessionId, name, response any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishRegistration", reflect.TypeOf((*MockIWebauthnService)(nil).FinishRegistration), ctx, user, sessionId, name, response)
+}
+
+// FinishSecondFactor mocks base method.
+func (m *MockIWebauthnService) FinishSecondFactor(ctx context.Context, userId uuid.UUID, sessionId string, response []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishSecondFactor", ctx, userId, sessionId, response)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishSecondFactor indicates an expected call of FinishSecondFactor.
+func (mr *MockIWebauthnServiceMockRecorder) FinishSecondFactor(ctx, userId, sessionId, response any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishSecondFactor", reflect.TypeOf((*MockIWebauthnService)(nil).FinishSecondFactor), ctx, userId, sessionId, response)
+}
+
+// GetCredentials mocks base method.
+func (m *MockIWebauthnService) GetCredentials(ctx context.Context, userId uuid.UUID) ([]models.WebauthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCredentials", ctx, userId)
+	ret0, _ := ret[0].([]models.WebauthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCredentials indicates an expected call of GetCredentials.
+func (mr *MockIWebauthnServiceMockRecorder) GetCredentials(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCredentials", reflect.TypeOf((*MockIWebauthnService)(nil).GetCredentials), ctx, userId)
+}
+
+// HasCredentials mocks base method.
+func (m *MockIWebauthnService) HasCredentials(ctx context.Context, userId uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasCredentials", ctx, userId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasCredentials indicates an expected call of HasCredentials.
+func (mr *MockIWebauthnServiceMockRecorder) HasCredentials(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasCredentials", reflect.TypeOf((*MockIWebauthnService)(nil).HasCredentials), ctx, userId)
+}
@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=internal/utils/utils.go -destination=internal/mocks/mock_utils.go -package=mockutils
+//	mockgen -source=internal/utils/utils.go -destination=mocks/mock_utils.go -package=mockutils
 //
 
 // Package mockutils is a generated GoMock package.
@@ -15,7 +15,6 @@ import (
 	jwt "github.com/golang-jwt/jwt/v5"
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
-	oauth2 "golang.org/x/oauth2"
 )
 
 // MockIUtils is a mock of IUtils interface.
@@ -42,20 +41,6 @@ func (m *MockIUtils) EXPECT() *MockIUtilsMockRecorder {
 	return m.recorder
 }
 
-// CreateGoogleOauth2Config mocks base method.
-func (m *MockIUtils) CreateGoogleOauth2Config() *oauth2.Config {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateGoogleOauth2Config")
-	ret0, _ := ret[0].(*oauth2.Config)
-	return ret0
-}
-
-// CreateGoogleOauth2Config indicates an expected call of CreateGoogleOauth2Config.
-func (mr *MockIUtilsMockRecorder) CreateGoogleOauth2Config() *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGoogleOauth2Config", reflect.TypeOf((*MockIUtils)(nil).CreateGoogleOauth2Config))
-}
-
 // GenerateRandomBytes mocks base method.
 func (m *MockIUtils) GenerateRandomBytes(size int) (string, error) {
 	m.ctrl.T.Helper()
@@ -86,20 +71,6 @@ func (mr *MockIUtilsMockRecorder) GenerateToken(userId, jti any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockIUtils)(nil).GenerateToken), userId, jti)
 }
 
-// GetTokenFromRefreshToken mocks base method.
-func (m *MockIUtils) GetTokenFromRefreshToken(config *oauth2.Config) *oauth2.Token {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTokenFromRefreshToken", config)
-	ret0, _ := ret[0].(*oauth2.Token)
-	return ret0
-}
-
-// GetTokenFromRefreshToken indicates an expected call of GetTokenFromRefreshToken.
-func (mr *MockIUtilsMockRecorder) GetTokenFromRefreshToken(config any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenFromRefreshToken", reflect.TypeOf((*MockIUtils)(nil).GetTokenFromRefreshToken), config)
-}
-
 // HashPassword mocks base method.
 func (m *MockIUtils) HashPassword(password string) (string, error) {
 	m.ctrl.T.Helper()
@@ -129,20 +100,6 @@ func (mr *MockIUtilsMockRecorder) HashWithSHA256(randomStr any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashWithSHA256", reflect.TypeOf((*MockIUtils)(nil).HashWithSHA256), randomStr)
 }
 
-// SendEmailWithGmail mocks base method.
-func (m *MockIUtils) SendEmailWithGmail(subject, body, address string) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SendEmailWithGmail", subject, body, address)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// SendEmailWithGmail indicates an expected call of SendEmailWithGmail.
-func (mr *MockIUtilsMockRecorder) SendEmailWithGmail(subject, body, address any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendEmailWithGmail", reflect.TypeOf((*MockIUtils)(nil).SendEmailWithGmail), subject, body, address)
-}
-
 // ValidateToken mocks base method.
 func (m *MockIUtils) ValidateToken(tokenString string) (*jwt.MapClaims, error) {
 	m.ctrl.T.Helper()